@@ -13,6 +13,8 @@ CREATE TABLE IF NOT EXISTS eventstore.events2 (
     
     , "position" DECIMAL NOT NULL
     , in_tx_order INTEGER NOT NULL
+    , producer_version TEXT
+    , maintenance_window TEXT
 
     , PRIMARY KEY (instance_id, aggregate_type, aggregate_id, "sequence")
 	, INDEX es_active_instances (created_at DESC) STORING ("position")
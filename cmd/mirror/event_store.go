@@ -107,7 +107,7 @@ func copyEvents(ctx context.Context, source, dest *db.DB, bulkSize uint32) {
 			var i uint32
 			for position := range pos {
 				var stmt database.Statement
-				stmt.WriteString("COPY (SELECT instance_id, aggregate_type, aggregate_id, event_type, sequence, revision, created_at, regexp_replace(payload::TEXT, '\\\\u0000', '', 'g')::JSON payload, creator, owner, ")
+				stmt.WriteString("COPY (SELECT instance_id, aggregate_type, aggregate_id, event_type, sequence, revision, created_at, regexp_replace(payload::TEXT, '\\\\u0000', '', 'g')::JSON payload, creator, owner, producer_version, maintenance_window, ")
 				stmt.WriteArg(position)
 				stmt.WriteString(" position, row_number() OVER (PARTITION BY instance_id ORDER BY position, in_tx_order) AS in_tx_order FROM eventstore.events2 ")
 				stmt.WriteString(instanceClause())
@@ -165,7 +165,7 @@ func copyEvents(ctx context.Context, source, dest *db.DB, bulkSize uint32) {
 	errs <- destConn.Raw(func(driverConn interface{}) error {
 		conn := driverConn.(*stdlib.Conn).Conn()
 
-		tag, err := conn.PgConn().CopyFrom(ctx, reader, "COPY eventstore.events2 FROM STDIN")
+		tag, err := conn.PgConn().CopyFrom(ctx, reader, "COPY eventstore.events2 (instance_id, aggregate_type, aggregate_id, event_type, sequence, revision, created_at, payload, creator, owner, producer_version, maintenance_window, position, in_tx_order) FROM STDIN")
 		eventCount = tag.RowsAffected()
 		if err != nil {
 			return zerrors.ThrowUnknown(err, "MIGRA-DTHi7", "unable to copy events into destination")
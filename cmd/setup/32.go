@@ -0,0 +1,27 @@
+package setup
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+var (
+	//go:embed 32.sql
+	addProducerVersionToEvents string
+)
+
+type AddProducerVersionToEvents struct {
+	dbClient *database.DB
+}
+
+func (mig *AddProducerVersionToEvents) Execute(ctx context.Context, _ eventstore.Event) error {
+	_, err := mig.dbClient.ExecContext(ctx, addProducerVersionToEvents)
+	return err
+}
+
+func (mig *AddProducerVersionToEvents) String() string {
+	return "32_add_producer_version_to_events"
+}
@@ -0,0 +1,27 @@
+package setup
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+var (
+	//go:embed 33.sql
+	addMaintenanceWindowToEvents string
+)
+
+type AddMaintenanceWindowToEvents struct {
+	dbClient *database.DB
+}
+
+func (mig *AddMaintenanceWindowToEvents) Execute(ctx context.Context, _ eventstore.Event) error {
+	_, err := mig.dbClient.ExecContext(ctx, addMaintenanceWindowToEvents)
+	return err
+}
+
+func (mig *AddMaintenanceWindowToEvents) String() string {
+	return "33_add_maintenance_window_to_events"
+}
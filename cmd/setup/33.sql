@@ -0,0 +1 @@
+ALTER TABLE eventstore.events2 ADD COLUMN IF NOT EXISTS maintenance_window TEXT;
@@ -0,0 +1,27 @@
+package setup
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+var (
+	//go:embed 34.sql
+	addInstanceOwnerIndex string
+)
+
+type AddInstanceOwnerIndexToEvents struct {
+	dbClient *database.DB
+}
+
+func (mig *AddInstanceOwnerIndexToEvents) Execute(ctx context.Context, _ eventstore.Event) error {
+	_, err := mig.dbClient.ExecContext(ctx, addInstanceOwnerIndex)
+	return err
+}
+
+func (mig *AddInstanceOwnerIndexToEvents) String() string {
+	return "34_add_instance_owner_index_to_events"
+}
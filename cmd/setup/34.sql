@@ -0,0 +1 @@
+CREATE INDEX CONCURRENTLY IF NOT EXISTS es_instance_owner ON eventstore.events2 (instance_id, "owner", "position");
\ No newline at end of file
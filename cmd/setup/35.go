@@ -0,0 +1,27 @@
+package setup
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+var (
+	//go:embed 35.sql
+	addLowerFieldToUsername string
+)
+
+type User13AddLowerFieldToUsername struct {
+	dbClient *database.DB
+}
+
+func (mig *User13AddLowerFieldToUsername) Execute(ctx context.Context, _ eventstore.Event) error {
+	_, err := mig.dbClient.ExecContext(ctx, addLowerFieldToUsername)
+	return err
+}
+
+func (mig *User13AddLowerFieldToUsername) String() string {
+	return "35_user13_add_lower_field_to_username"
+}
@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS projections.users13 ADD COLUMN IF NOT EXISTS username_lower TEXT GENERATED ALWAYS AS (lower(username)) STORED;
+CREATE INDEX IF NOT EXISTS users13_username_search ON projections.users13 (instance_id, username_lower);
@@ -115,6 +115,10 @@ type Steps struct {
 	s29FillFieldsForProjectGrant           *FillFieldsForProjectGrant
 	s30FillFieldsForOrgDomainVerified      *FillFieldsForOrgDomainVerified
 	s31AddAggregateIndexToFields           *AddAggregateIndexToFields
+	s32AddProducerVersionToEvents          *AddProducerVersionToEvents
+	s33AddMaintenanceWindowToEvents        *AddMaintenanceWindowToEvents
+	s34AddInstanceOwnerIndexToEvents       *AddInstanceOwnerIndexToEvents
+	s35User13AddLowerFieldToUsername       *User13AddLowerFieldToUsername
 }
 
 func MustNewSteps(v *viper.Viper) *Steps {
@@ -108,7 +108,7 @@ func Setup(ctx context.Context, config *Config, steps *Steps, masterKey string)
 	logging.OnError(err).Fatal("unable to connect to database")
 
 	config.Eventstore.Querier = old_es.NewCRDB(queryDBClient)
-	esV3 := new_es.NewEventstore(esPusherDBClient)
+	esV3 := new_es.NewEventstore(esPusherDBClient, build.Version())
 	config.Eventstore.Pusher = esV3
 	config.Eventstore.Searcher = esV3
 	eventstoreClient := eventstore.NewEventstore(config.Eventstore)
@@ -160,6 +160,10 @@ func Setup(ctx context.Context, config *Config, steps *Steps, masterKey string)
 	steps.s29FillFieldsForProjectGrant = &FillFieldsForProjectGrant{eventstore: eventstoreClient}
 	steps.s30FillFieldsForOrgDomainVerified = &FillFieldsForOrgDomainVerified{eventstore: eventstoreClient}
 	steps.s31AddAggregateIndexToFields = &AddAggregateIndexToFields{dbClient: esPusherDBClient}
+	steps.s32AddProducerVersionToEvents = &AddProducerVersionToEvents{dbClient: esPusherDBClient}
+	steps.s33AddMaintenanceWindowToEvents = &AddMaintenanceWindowToEvents{dbClient: esPusherDBClient}
+	steps.s34AddInstanceOwnerIndexToEvents = &AddInstanceOwnerIndexToEvents{dbClient: queryDBClient}
+	steps.s35User13AddLowerFieldToUsername = &User13AddLowerFieldToUsername{dbClient: queryDBClient}
 
 	err = projection.Create(ctx, projectionDBClient, eventstoreClient, config.Projections, nil, nil, nil)
 	logging.OnError(err).Fatal("unable to start projections")
@@ -184,6 +188,8 @@ func Setup(ctx context.Context, config *Config, steps *Steps, masterKey string)
 		steps.s2AssetsTable,
 		steps.s28AddFieldTable,
 		steps.s31AddAggregateIndexToFields,
+		steps.s32AddProducerVersionToEvents,
+		steps.s33AddMaintenanceWindowToEvents,
 		steps.FirstInstance,
 		steps.s5LastFailed,
 		steps.s6OwnerRemoveColumns,
@@ -202,6 +208,7 @@ func Setup(ctx context.Context, config *Config, steps *Steps, masterKey string)
 		steps.s26AuthUsers3,
 		steps.s29FillFieldsForProjectGrant,
 		steps.s30FillFieldsForOrgDomainVerified,
+		steps.s34AddInstanceOwnerIndexToEvents,
 	} {
 		mustExecuteMigration(ctx, eventstoreClient, step, "migration failed")
 	}
@@ -216,6 +223,7 @@ func Setup(ctx context.Context, config *Config, steps *Steps, masterKey string)
 		steps.s21AddBlockFieldToLimits,
 		steps.s25User11AddLowerFieldsToVerifiedEmail,
 		steps.s27IDPTemplate6SAMLNameIDFormat,
+		steps.s35User13AddLowerFieldToUsername,
 	} {
 		mustExecuteMigration(ctx, eventstoreClient, step, "migration failed")
 	}
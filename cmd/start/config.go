@@ -35,40 +35,41 @@ import (
 )
 
 type Config struct {
-	Log               *logging.Config
-	Port              uint16
-	ExternalPort      uint16
-	ExternalDomain    string
-	ExternalSecure    bool
-	TLS               network.TLS
-	HTTP2HostHeader   string
-	HTTP1HostHeader   string
-	WebAuthNName      string
-	Database          database.Config
-	Tracing           tracing.Config
-	Metrics           metrics.Config
-	Projections       projection.Config
-	Auth              auth_es.Config
-	Admin             admin_es.Config
-	UserAgentCookie   *middleware.UserAgentCookieConfig
-	OIDC              oidc.Config
-	SAML              saml.Config
-	Login             login.Config
-	Console           console.Config
-	AssetStorage      static_config.AssetStorageConfig
-	InternalAuthZ     internal_authz.Config
-	SystemDefaults    systemdefaults.SystemDefaults
-	EncryptionKeys    *encryption.EncryptionKeyConfig
-	DefaultInstance   command.InstanceSetup
-	AuditLogRetention time.Duration
-	SystemAPIUsers    map[string]*internal_authz.SystemAPIUser
-	CustomerPortal    string
-	Machine           *id.Config
-	Actions           *actions.Config
-	Eventstore        *eventstore.Config
-	LogStore          *logstore.Configs
-	Quotas            *QuotasConfig
-	Telemetry         *handlers.TelemetryPusherConfig
+	Log                 *logging.Config
+	Port                uint16
+	ExternalPort        uint16
+	ExternalDomain      string
+	ExternalSecure      bool
+	TLS                 network.TLS
+	HTTP2HostHeader     string
+	HTTP1HostHeader     string
+	WebAuthNName        string
+	Database            database.Config
+	Tracing             tracing.Config
+	Metrics             metrics.Config
+	Projections         projection.Config
+	Auth                auth_es.Config
+	Admin               admin_es.Config
+	UserAgentCookie     *middleware.UserAgentCookieConfig
+	OIDC                oidc.Config
+	SAML                saml.Config
+	Login               login.Config
+	Console             console.Config
+	AssetStorage        static_config.AssetStorageConfig
+	InternalAuthZ       internal_authz.Config
+	SystemDefaults      systemdefaults.SystemDefaults
+	EncryptionKeys      *encryption.EncryptionKeyConfig
+	DefaultInstance     command.InstanceSetup
+	AuditLogRetention   time.Duration
+	WriteModelCacheSize int
+	SystemAPIUsers      map[string]*internal_authz.SystemAPIUser
+	CustomerPortal      string
+	Machine             *id.Config
+	Actions             *actions.Config
+	Eventstore          *eventstore.Config
+	LogStore            *logstore.Configs
+	Quotas              *QuotasConfig
+	Telemetry           *handlers.TelemetryPusherConfig
 }
 
 type QuotasConfig struct {
@@ -0,0 +1,36 @@
+package start
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/command"
+	"github.com/zitadel/zitadel/internal/query"
+	"github.com/zitadel/zitadel/internal/query/projection"
+)
+
+// orgMemberReadModel implements [command.OrgMemberReadModelStore] using the org members projection
+type orgMemberReadModel struct {
+	queries *query.Queries
+}
+
+func (r *orgMemberReadModel) OrgMemberReadModel(ctx context.Context, orgID, userID string) (*command.OrgMemberReadModel, *projection.ReducedPosition, error) {
+	row, reduced, err := r.queries.OrgMemberRow(ctx, orgID, userID)
+	if err != nil || row == nil {
+		return nil, reduced, err
+	}
+	return &command.OrgMemberReadModel{
+		CreationDate:      row.CreationDate,
+		ChangeDate:        row.ChangeDate,
+		Sequence:          row.Sequence,
+		ResourceOwner:     row.ResourceOwner,
+		UserResourceOwner: row.UserResourceOwner,
+		Roles:             row.Roles,
+		State:             row.State,
+		OrgName:           row.OrgName,
+		AddedBy:           row.AddedBy,
+	}, reduced, nil
+}
+
+func (r *orgMemberReadModel) ReprojectOrgMember(ctx context.Context, orgID, userID string) error {
+	return r.queries.ReprojectOrgMember(ctx, orgID, userID)
+}
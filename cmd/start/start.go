@@ -152,8 +152,8 @@ func startZitadel(ctx context.Context, config *Config, masterKey string, server
 		return err
 	}
 
-	config.Eventstore.Pusher = new_es.NewEventstore(esPusherDBClient)
-	config.Eventstore.Searcher = new_es.NewEventstore(queryDBClient)
+	config.Eventstore.Pusher = new_es.NewEventstore(esPusherDBClient, build.Version())
+	config.Eventstore.Searcher = new_es.NewEventstore(queryDBClient, build.Version())
 	config.Eventstore.Querier = old_es.NewCRDB(queryDBClient)
 	eventstoreClient := eventstore.NewEventstore(config.Eventstore)
 	eventstoreV4 := es_v4.NewEventstoreFromOne(es_v4_pg.New(queryDBClient, &es_v4_pg.Config{
@@ -229,6 +229,8 @@ func startZitadel(ctx context.Context, config *Config, masterKey string, server
 		config.OIDC.DefaultRefreshTokenExpiration,
 		config.OIDC.DefaultRefreshTokenIdleExpiration,
 		config.DefaultInstance.SecretGenerators,
+		command.WithOrgMemberReadModelStore(&orgMemberReadModel{queries: queries}),
+		command.WithWriteModelCache(config.WriteModelCacheSize),
 	)
 	if err != nil {
 		return fmt.Errorf("cannot start commands: %w", err)
@@ -32,6 +32,8 @@ type CtxData struct {
 	PreferredLanguage string
 	ResourceOwner     string
 	SystemMemberships Memberships
+	// TokenRoles restrict the memberships of the user to these roles, nil if the token is not restricted
+	TokenRoles []string
 }
 
 func (ctxData CtxData) IsZero() bool {
@@ -74,14 +76,15 @@ type TokenVerifier interface {
 	SystemTokenVerifier
 }
 
+// AccessTokenVerifier verifies the token and returns the roles the token is restricted to, nil if the token is not restricted
 type AccessTokenVerifier interface {
-	VerifyAccessToken(ctx context.Context, token string) (userID, clientID, agentID, prefLan, resourceOwner string, err error)
+	VerifyAccessToken(ctx context.Context, token string) (userID, clientID, agentID, prefLan, resourceOwner string, tokenRoles []string, err error)
 }
 
 // AccessTokenVerifierFunc implements the SystemTokenVerifier interface so that a function can be used as a AccessTokenVerifier.
-type AccessTokenVerifierFunc func(context.Context, string) (string, string, string, string, string, error)
+type AccessTokenVerifierFunc func(context.Context, string) (string, string, string, string, string, []string, error)
 
-func (a AccessTokenVerifierFunc) VerifyAccessToken(ctx context.Context, token string) (string, string, string, string, string, error) {
+func (a AccessTokenVerifierFunc) VerifyAccessToken(ctx context.Context, token string) (string, string, string, string, string, []string, error) {
 	return a(ctx, token)
 }
 
@@ -103,7 +106,7 @@ func VerifyTokenAndCreateCtxData(ctx context.Context, token, orgID, orgDomain st
 	if err != nil {
 		return CtxData{}, err
 	}
-	userID, clientID, agentID, prefLang, resourceOwner, err := t.VerifyAccessToken(ctx, tokenWOBearer)
+	userID, clientID, agentID, prefLang, resourceOwner, tokenRoles, err := t.VerifyAccessToken(ctx, tokenWOBearer)
 	var sysMemberships Memberships
 	if err != nil && !zerrors.IsUnauthenticated(err) {
 		return CtxData{}, err
@@ -148,6 +151,7 @@ func VerifyTokenAndCreateCtxData(ctx context.Context, token, orgID, orgDomain st
 		PreferredLanguage: prefLang,
 		ResourceOwner:     resourceOwner,
 		SystemMemberships: sysMemberships,
+		TokenRoles:        tokenRoles,
 	}, nil
 }
 
@@ -2,6 +2,7 @@ package authz
 
 import (
 	"context"
+	"slices"
 
 	"github.com/zitadel/zitadel/internal/telemetry/tracing"
 	"github.com/zitadel/zitadel/internal/zerrors"
@@ -49,10 +50,33 @@ func getUserPermissions(ctx context.Context, resolver MembershipsResolver, requi
 			return nil, nil, err
 		}
 	}
+	if ctxData.TokenRoles != nil {
+		memberships = restrictMemberships(memberships, ctxData.TokenRoles)
+	}
 	requestedPermissions, allPermissions = mapMembershipsToPermissions(requiredPerm, memberships, roleMappings)
 	return requestedPermissions, allPermissions, nil
 }
 
+// restrictMemberships removes all roles of the memberships which are not part of the token roles
+func restrictMemberships(memberships []*Membership, tokenRoles []string) []*Membership {
+	restricted := make([]*Membership, 0, len(memberships))
+	for _, membership := range memberships {
+		roles := make([]string, 0, len(membership.Roles))
+		for _, role := range membership.Roles {
+			if slices.Contains(tokenRoles, role) {
+				roles = append(roles, role)
+			}
+		}
+		if len(roles) == 0 {
+			continue
+		}
+		restrictedMembership := *membership
+		restrictedMembership.Roles = roles
+		restricted = append(restricted, &restrictedMembership)
+	}
+	return restricted
+}
+
 // checkUserResourcePermissions checks that if a user i granted either the requested permission globally (project.write)
 // or the specific resource (project.write:123)
 func checkUserResourcePermissions(userPerms []string, resourceID string) error {
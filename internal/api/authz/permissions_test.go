@@ -117,6 +117,46 @@ func Test_GetUserPermissions(t *testing.T) {
 			},
 			result: []string{"project.read"},
 		},
+		{
+			name: "Get Permissions restricted by token roles",
+			args: args{
+				ctxData: CtxData{UserID: "userID", OrgID: "orgID", TokenRoles: []string{"ORG_MEMBER_VIEWER"}},
+				membershipsResolver: membershipsResolverFunc(func(ctx context.Context, orgID string, shouldTriggerBulk bool) ([]*Membership, error) {
+					return []*Membership{
+						{
+							AggregateID: "orgID",
+							ObjectID:    "orgID",
+							MemberType:  MemberTypeOrganization,
+							Roles:       []string{"ORG_OWNER", "ORG_MEMBER_VIEWER"},
+						},
+						{
+							AggregateID: "IAM",
+							ObjectID:    "IAM",
+							MemberType:  MemberTypeIAM,
+							Roles:       []string{"IAM_OWNER"},
+						},
+					}, nil
+				}),
+				requiredPerm: "org.member.read",
+				authConfig: Config{
+					RolePermissionMappings: []RoleMapping{
+						{
+							Role:        "IAM_OWNER",
+							Permissions: []string{"project.read"},
+						},
+						{
+							Role:        "ORG_OWNER",
+							Permissions: []string{"org.read", "org.member.read"},
+						},
+						{
+							Role:        "ORG_MEMBER_VIEWER",
+							Permissions: []string{"org.member.read"},
+						},
+					},
+				},
+			},
+			result: []string{"org.member.read"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -315,6 +315,21 @@ func (s *Server) RemoveOrgMember(ctx context.Context, req *mgmt_pb.RemoveOrgMemb
 	}, nil
 }
 
+func (s *Server) ReconcileOrgMember(ctx context.Context, req *mgmt_pb.ReconcileOrgMemberRequest) (*mgmt_pb.ReconcileOrgMemberResponse, error) {
+	reconciliation, err := s.command.ReconcileOrgMember(ctx, authz.GetCtxData(ctx).OrgID, req.UserId, req.Repair)
+	if err != nil {
+		return nil, err
+	}
+	return &mgmt_pb.ReconcileOrgMemberResponse{
+		Exists:            reconciliation.Exists,
+		ExistsInReadModel: reconciliation.ExistsInReadModel,
+		MissingRoles:      reconciliation.MissingRoles,
+		UnexpectedRoles:   reconciliation.UnexpectedRoles,
+		DivergedColumns:   reconciliation.DivergedColumns,
+		Repaired:          reconciliation.Repaired,
+	}, nil
+}
+
 func (s *Server) getClaimedUserIDsOfOrgDomain(ctx context.Context, orgDomain, orgID string) ([]string, error) {
 	queries := make([]query.SearchQuery, 0, 2)
 	loginName, err := query.NewUserPreferredLoginNameSearchQuery("@"+orgDomain, query.TextEndsWithIgnoreCase)
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"context"
 	errorsAs "errors"
 	"fmt"
 	"io/ioutil"
@@ -32,48 +33,67 @@ func ValidateDomain(domain, token, verifier string, checkType CheckType) error {
 }
 
 func ValidateDomainHTTP(domain, token, verifier string) error {
-	resp, err := http.Get(tokenUrlHTTP(domain, token))
-	if err != nil {
-		return zerrors.ThrowInternal(err, "HTTP-BH42h", "Errors.Internal")
-	}
-	if resp.StatusCode != 200 {
-		if resp.StatusCode == 404 {
-			return zerrors.ThrowNotFound(err, "ORG-F4zhw", "Errors.Org.DomainVerificationHTTPNotFound")
+	return HTTPDomainValidator(http.DefaultClient)(domain, token, verifier)
+}
+
+// HTTPDomainValidator returns a validator which fetches the token from the well-known url of the domain with the client
+func HTTPDomainValidator(client *http.Client) func(domain, token, verifier string) error {
+	return func(domain, token, verifier string) error {
+		resp, err := client.Get(tokenUrlHTTP(domain, token))
+		if err != nil {
+			return zerrors.ThrowInternal(err, "HTTP-BH42h", "Errors.Internal")
 		}
-		return zerrors.ThrowInternal(err, "HTTP-G2zsw", "Errors.Internal")
-	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return zerrors.ThrowInternal(err, "HTTP-HB432", "Errors.Internal")
-	}
-	if string(body) == verifier {
-		return nil
+		if resp.StatusCode != 200 {
+			if resp.StatusCode == 404 {
+				return zerrors.ThrowNotFound(err, "ORG-F4zhw", "Errors.Org.DomainVerificationHTTPNotFound")
+			}
+			return zerrors.ThrowInternal(err, "HTTP-G2zsw", "Errors.Internal")
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return zerrors.ThrowInternal(err, "HTTP-HB432", "Errors.Internal")
+		}
+		if string(body) == verifier {
+			return nil
+		}
+		return zerrors.ThrowNotFound(err, "ORG-GH422", "Errors.Org.DomainVerificationHTTPNoMatch")
 	}
-	return zerrors.ThrowNotFound(err, "ORG-GH422", "Errors.Org.DomainVerificationHTTPNoMatch")
+}
+
+// TXTResolver looks up the TXT records of a name, it's implemented by [net.Resolver]
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
 }
 
 func ValidateDomainDNS(domain, verifier string) error {
-	txtRecords, err := net.LookupTXT(tokenUrlDNS(domain))
-	if err != nil {
-		var dnsError *net.DNSError
-		if errorsAs.As(err, &dnsError) {
-			if dnsError.IsNotFound {
-				return zerrors.ThrowNotFound(err, "ORG-G241f", "Errors.Org.DomainVerificationTXTNotFound")
-			}
-			if dnsError.IsTimeout {
-				return zerrors.ThrowNotFound(err, "ORG-K563l", "Errors.Org.DomainVerificationTimeout")
+	return DNSDomainValidator(net.DefaultResolver)(domain, verifier)
+}
+
+// DNSDomainValidator returns a validator which looks up the TXT records of the challenge subdomain with the resolver
+func DNSDomainValidator(resolver TXTResolver) func(domain, verifier string) error {
+	return func(domain, verifier string) error {
+		txtRecords, err := resolver.LookupTXT(context.Background(), tokenUrlDNS(domain))
+		if err != nil {
+			var dnsError *net.DNSError
+			if errorsAs.As(err, &dnsError) {
+				if dnsError.IsNotFound {
+					return zerrors.ThrowNotFound(err, "ORG-G241f", "Errors.Org.DomainVerificationTXTNotFound")
+				}
+				if dnsError.IsTimeout {
+					return zerrors.ThrowNotFound(err, "ORG-K563l", "Errors.Org.DomainVerificationTimeout")
+				}
 			}
+			return zerrors.ThrowInternal(err, "HTTP-Hwsw2", "Errors.Internal")
 		}
-		return zerrors.ThrowInternal(err, "HTTP-Hwsw2", "Errors.Internal")
-	}
 
-	for _, record := range txtRecords {
-		if record == verifier {
-			return nil
+		for _, record := range txtRecords {
+			if record == verifier {
+				return nil
+			}
 		}
+		return zerrors.ThrowNotFound(err, "ORG-G28if", "Errors.Org.DomainVerificationTXTNoMatch")
 	}
-	return zerrors.ThrowNotFound(err, "ORG-G28if", "Errors.Org.DomainVerificationTXTNoMatch")
 }
 
 func TokenUrl(domain, token string, checkType CheckType) (string, error) {
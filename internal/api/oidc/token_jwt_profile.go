@@ -68,8 +68,9 @@ func (s *Server) verifyJWTProfile(ctx context.Context, req *oidc.JWTProfileGrant
 		user, err = s.query.GetUserByID(ctx, true, jwt.Subject)
 		return err
 	}
+	keyStorage := &jwtProfileKeyStorage{query: s.query}
 	verifier := op.NewJWTProfileVerifier(
-		&jwtProfileKeyStorage{query: s.query},
+		keyStorage,
 		op.IssuerFromContext(ctx),
 		time.Hour, time.Second,
 		op.SubjectCheck(checkSubject),
@@ -78,22 +79,29 @@ func (s *Server) verifyJWTProfile(ctx context.Context, req *oidc.JWTProfileGrant
 	if err != nil {
 		return nil, nil, err
 	}
+	s.command.MachineKeyUsed(ctx, user.ID, user.ResourceOwner, keyStorage.keyID, keyStorage.lastUsed)
 	return user, tokenRequest, nil
 }
 
 type jwtProfileKeyStorage struct {
 	query *query.Queries
+	// keyID is the id of the last returned key, used to record its usage after verification
+	keyID string
+	// lastUsed is the last recorded usage of the returned key, used to throttle the recording
+	lastUsed *time.Time
 }
 
 func (s *jwtProfileKeyStorage) GetKeyByIDAndClientID(ctx context.Context, keyID, userID string) (*jose.JSONWebKey, error) {
-	publicKeyData, err := s.query.GetAuthNKeyPublicKeyByIDAndIdentifier(ctx, keyID, userID)
+	key, err := s.query.GetMachineKeyPublicKeyByIDAndUserID(ctx, keyID, userID)
 	if err != nil {
 		return nil, err
 	}
-	publicKey, err := crypto.BytesToPublicKey(publicKeyData)
+	publicKey, err := crypto.BytesToPublicKey(key.PublicKey)
 	if err != nil {
 		return nil, err
 	}
+	s.keyID = keyID
+	s.lastUsed = key.LastUsed
 	return &jose.JSONWebKey{
 		KeyID: keyID,
 		Use:   "sig",
@@ -624,8 +624,8 @@ func TestCommands_LinkSessionToAuthRequest(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &Commands{
-				eventstore:           tt.fields.eventstore,
-				sessionTokenVerifier: tt.fields.tokenVerifier,
+				eventstore:            tt.fields.eventstore,
+				sessionTokenVerifiers: NewSessionTokenVerifierRegistry(tt.fields.tokenVerifier),
 			}
 			details, got, err := c.LinkSessionToAuthRequest(tt.args.ctx, tt.args.id, tt.args.sessionID, tt.args.sessionToken, tt.args.checkLoginClient)
 			require.ErrorIs(t, err, tt.res.wantErr)
@@ -3,13 +3,15 @@ package command
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"math/big"
+	"net"
 	"net/http"
-	"strconv"
 	"sync"
 	"time"
 
@@ -23,6 +25,8 @@ import (
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/id"
+	"github.com/zitadel/zitadel/internal/notification/channels/smtp"
+	"github.com/zitadel/zitadel/internal/notification/channels/twilio"
 	"github.com/zitadel/zitadel/internal/static"
 	"github.com/zitadel/zitadel/internal/telemetry/tracing"
 	webauthn_helper "github.com/zitadel/zitadel/internal/webauthn"
@@ -51,18 +55,24 @@ type Commands struct {
 	smtpEncryption                  crypto.EncryptionAlgorithm
 	smsEncryption                   crypto.EncryptionAlgorithm
 	userEncryption                  crypto.EncryptionAlgorithm
+	passwordHasherMu                sync.RWMutex
 	userPasswordHasher              *crypto.Hasher
 	secretHasher                    *crypto.Hasher
 	machineKeySize                  int
 	applicationKeySize              int
 	domainVerificationAlg           crypto.EncryptionAlgorithm
 	domainVerificationGenerator     crypto.Generator
-	domainVerificationValidator     func(domain, token, verifier string, checkType api_http.CheckType) error
+	domainVerificationHTTPValidator func(domain, token, verifier string) error
+	domainVerificationDNSValidator  func(domain, verifier string) error
 	sessionTokenCreator             func(sessionID string) (id string, token string, err error)
-	sessionTokenVerifier            func(ctx context.Context, sessionToken, sessionID, tokenID string) (err error)
+	sessionTokenVerifiers           *SessionTokenVerifierRegistry
 	defaultAccessTokenLifetime      time.Duration
 	defaultRefreshTokenLifetime     time.Duration
 	defaultRefreshTokenIdleLifetime time.Duration
+	maxImpersonationSessionLifetime time.Duration
+	machineKeyUsageInterval         time.Duration
+	pushRetries                     int
+	pushRetryMaxDelay               time.Duration
 
 	multifactors            domain.MultifactorConfigs
 	webauthnConfig          *webauthn_helper.Config
@@ -76,6 +86,10 @@ type Commands struct {
 	defaultSecretGenerators *SecretGenerators
 
 	samlCertificateAndKeyGenerator func(id string) ([]byte, []byte, error)
+	orgMemberReadModel             OrgMemberReadModelStore
+	writeModelCache                *WriteModelCache
+	smtpConfigVerifier             func(cfg *smtp.Config, testEmail string) error
+	smsConfigVerifier              func(cfg *twilio.Config, testNumber string) error
 
 	GrpcMethodExisting     func(method string) bool
 	GrpcServiceExisting    func(method string) bool
@@ -103,10 +117,19 @@ func StartCommands(
 	defaultRefreshTokenLifetime,
 	defaultRefreshTokenIdleLifetime time.Duration,
 	defaultSecretGenerators *SecretGenerators,
+	opts ...StartOption,
 ) (repo *Commands, err error) {
 	if externalDomain == "" {
 		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Df21s", "no external domain specified")
 	}
+	if webAuthN != nil {
+		if err = webAuthN.Validate(externalDomain, externalPort); err != nil {
+			return nil, err
+		}
+	}
+	if err = validateKeySizes(defaults); err != nil {
+		return nil, err
+	}
 	idGenerator := id.SonyFlakeGenerator()
 	// reuse the oidcEncryption to be able to handle both tokens in the interceptor later on
 	sessionAlg := oidcEncryption
@@ -142,21 +165,27 @@ func StartCommands(
 		applicationKeySize:              int(defaults.SecretGenerators.ApplicationKeySize),
 		domainVerificationAlg:           domainVerificationEncryption,
 		domainVerificationGenerator:     crypto.NewEncryptionGenerator(defaults.DomainVerification.VerificationGenerator, domainVerificationEncryption),
-		domainVerificationValidator:     api_http.ValidateDomain,
+		domainVerificationDNSValidator:  api_http.DNSDomainValidator(net.DefaultResolver),
 		keyAlgorithm:                    oidcEncryption,
 		certificateAlgorithm:            samlEncryption,
 		webauthnConfig:                  webAuthN,
-		httpClient:                      httpClient,
+		httpClient:                      httpClientWithTimeout(httpClient, defaultHTTPClientTimeout),
 		checkPermission:                 permissionCheck,
 		newEncryptedCode:                newEncryptedCode,
 		newEncryptedCodeWithDefault:     newEncryptedCodeWithDefaultConfig,
 		sessionTokenCreator:             sessionTokenCreator(idGenerator, sessionAlg),
-		sessionTokenVerifier:            sessionTokenVerifier,
+		sessionTokenVerifiers:           NewSessionTokenVerifierRegistry(sessionTokenVerifier),
 		defaultAccessTokenLifetime:      defaultAccessTokenLifetime,
 		defaultRefreshTokenLifetime:     defaultRefreshTokenLifetime,
 		defaultRefreshTokenIdleLifetime: defaultRefreshTokenIdleLifetime,
+		maxImpersonationSessionLifetime: defaults.Impersonation.MaxSessionLifetime,
+		machineKeyUsageInterval:         defaults.MachineKeyUsage.RecordInterval,
+		pushRetries:                     defaultPushRetries,
+		pushRetryMaxDelay:               defaultPushRetryMaxDelay,
 		defaultSecretGenerators:         defaultSecretGenerators,
-		samlCertificateAndKeyGenerator:  samlCertificateAndKeyGenerator(defaults.KeyConfig.CertificateSize, defaults.KeyConfig.CertificateLifetime),
+		samlCertificateAndKeyGenerator:  samlCertificateAndKeyGenerator(defaults.KeyConfig.CertificateSize, defaults.KeyConfig.CertificateLifetime, defaults.KeyConfig.SAMLCertificate),
+		smtpConfigVerifier:              smtp.VerifyConfiguration,
+		smsConfigVerifier:               twilio.TestConfiguration,
 		// always true for now until we can check with an eventlist
 		EventExisting: func(event string) bool { return true },
 		// always true for now until we can check with an eventlist
@@ -168,6 +197,7 @@ func StartCommands(
 			OTP: domain.OTPConfig{
 				CryptoMFA: otpEncryption,
 				Issuer:    defaults.Multifactors.OTP.Issuer,
+				SetupSkew: defaults.Multifactors.OTP.SetupSkew,
 			},
 		},
 		GenerateDomain: domain.NewGeneratedInstanceDomain,
@@ -176,9 +206,81 @@ func StartCommands(
 	if defaultSecretGenerators != nil && defaultSecretGenerators.ClientSecret != nil {
 		repo.newHashedSecret = newHashedSecretWithDefault(secretHasher, defaultSecretGenerators.ClientSecret)
 	}
+	for _, opt := range opts {
+		opt(repo)
+	}
+	repo.domainVerificationHTTPValidator = api_http.HTTPDomainValidator(repo.httpClient)
 	return repo, nil
 }
 
+// minRSAKeySize is the minimal size in bits of the RSA keys generated by the commands
+const minRSAKeySize = 2048
+
+// validateKeySizes makes sure a misconfiguration doesn't silently produce weak RSA keys
+func validateKeySizes(defaults sd.SystemDefaults) error {
+	if defaults.KeyConfig.Size < minRSAKeySize {
+		return zerrors.ThrowInvalidArgument(nil, "COMMAND-Kz2t4", "key size must be at least 2048")
+	}
+	if defaults.KeyConfig.CertificateSize < minRSAKeySize {
+		return zerrors.ThrowInvalidArgument(nil, "COMMAND-Kz3t5", "certificate key size must be at least 2048")
+	}
+	if defaults.SecretGenerators.MachineKeySize < minRSAKeySize {
+		return zerrors.ThrowInvalidArgument(nil, "COMMAND-Kz4t6", "machine key size must be at least 2048")
+	}
+	if defaults.SecretGenerators.ApplicationKeySize < minRSAKeySize {
+		return zerrors.ThrowInvalidArgument(nil, "COMMAND-Kz5t7", "application key size must be at least 2048")
+	}
+	return nil
+}
+
+// defaultHTTPClientTimeout is set on the http client of [StartCommands] if it has no timeout
+const defaultHTTPClientTimeout = 30 * time.Second
+
+// StartOption changes the [Commands] created by [StartCommands]
+type StartOption func(*Commands)
+
+// WithHTTPClientTimeout overrides the timeout of the http client used for calls to external endpoints,
+// e.g. the domain verification and the metadata of SAML providers
+func WithHTTPClientTimeout(timeout time.Duration) StartOption {
+	return func(c *Commands) {
+		client := *c.httpClient
+		client.Timeout = timeout
+		c.httpClient = &client
+	}
+}
+
+// WithDomainVerificationResolver overrides the resolver of the TXT records of the DNS domain verification
+func WithDomainVerificationResolver(resolver api_http.TXTResolver) StartOption {
+	return func(c *Commands) {
+		c.domainVerificationDNSValidator = api_http.DNSDomainValidator(resolver)
+	}
+}
+
+// WithWriteModelCache keeps up to maxEntries reduced write models of frequently read aggregates, see [WriteModelCache].
+// The lookups are counted in the telemetry metrics. The cache is disabled if maxEntries is 0.
+func WithWriteModelCache(maxEntries int) StartOption {
+	return func(c *Commands) {
+		if maxEntries <= 0 {
+			return
+		}
+		c.writeModelCache = NewWriteModelCache(c.eventstore, maxEntries, newWriteModelCacheCounters())
+	}
+}
+
+// httpClientWithTimeout returns a copy of the client with the timeout if the client has none,
+// the client passed by the caller is never changed
+func httpClientWithTimeout(client *http.Client, timeout time.Duration) *http.Client {
+	if client == nil {
+		return &http.Client{Timeout: timeout}
+	}
+	if client.Timeout > 0 {
+		return client
+	}
+	withTimeout := *client
+	withTimeout.Timeout = timeout
+	return &withTimeout
+}
+
 type AppendReducer interface {
 	AppendEvents(...eventstore.Event)
 	// TODO: Why is it allowed to return an error here?
@@ -193,12 +295,43 @@ func (c *Commands) pushAppendAndReduce(ctx context.Context, object AppendReducer
 	return AppendAndReduce(object, events...)
 }
 
+// PreviewCommands reduces the events the commands would create on the object
+// without pushing them to the eventstore.
+func (c *Commands) PreviewCommands(ctx context.Context, object AppendReducer, cmds ...eventstore.Command) error {
+	events, err := c.eventstore.Preview(cmds...)
+	if err != nil {
+		return err
+	}
+	return AppendAndReduce(object, events...)
+}
+
 func AppendAndReduce(object AppendReducer, events ...eventstore.Event) error {
 	object.AppendEvents(events...)
 	return object.Reduce()
 }
 
+// AppendAndReduceMany appends the events to each object and reduces them.
+// The objects ignore the events they don't handle in their reduce.
+// All objects are reduced even if one of them fails, the errors are joined.
+func AppendAndReduceMany(events []eventstore.Event, objects ...AppendReducer) (err error) {
+	for _, object := range objects {
+		err = errors.Join(err, AppendAndReduce(object, events...))
+	}
+	return err
+}
+
+// queryAndReduce reduces the events of the write model.
+// Write models implementing [cachedWriteModel] are read from their cache if no newer events exist.
 func queryAndReduce(ctx context.Context, filter preparation.FilterToQueryReducer, wm eventstore.QueryReducer) error {
+	if cached, ok := wm.(cachedWriteModel); ok {
+		if cache, key := cached.WriteModelCache(); cache != nil {
+			return cache.queryAndReduce(ctx, filter, key, wm)
+		}
+	}
+	return reduceEvents(ctx, filter, wm)
+}
+
+func reduceEvents(ctx context.Context, filter preparation.FilterToQueryReducer, wm eventstore.QueryReducer) error {
 	events, err := filter(ctx, wm.Query())
 	if err != nil {
 		return err
@@ -223,24 +356,41 @@ func exists(ctx context.Context, filter preparation.FilterToQueryReducer, wm exi
 	return wm.Exists(), nil
 }
 
-func samlCertificateAndKeyGenerator(keySize int, lifetime time.Duration) func(id string) ([]byte, []byte, error) {
+// samlCertificateAndKeyGenerator creates the certificates of SAML identity providers,
+// the subject, DNS names and lifetime are taken from the config if it's set
+func samlCertificateAndKeyGenerator(keySize int, lifetime time.Duration, config *sd.SAMLCertificateConfig) func(id string) ([]byte, []byte, error) {
+	organization := "ZITADEL"
+	var commonName string
+	var dnsNames []string
+	if config != nil {
+		if config.Organization != "" {
+			organization = config.Organization
+		}
+		if config.Lifetime > 0 {
+			lifetime = config.Lifetime
+		}
+		commonName = config.CommonName
+		dnsNames = config.DNSNames
+	}
 	return func(id string) ([]byte, []byte, error) {
 		priv, pub, err := crypto.GenerateKeyPair(keySize)
 		if err != nil {
 			return nil, nil, err
 		}
 
-		serial, err := strconv.Atoi(id)
+		serial, err := certificateSerialNumber(id)
 		if err != nil {
 			return nil, nil, err
 		}
 		now := time.Now()
 		template := x509.Certificate{
-			SerialNumber: big.NewInt(int64(serial)),
+			SerialNumber: serial,
 			Subject: pkix.Name{
-				Organization: []string{"ZITADEL"},
+				Organization: []string{organization},
+				CommonName:   commonName,
 				SerialNumber: id,
 			},
+			DNSNames:              dnsNames,
 			NotBefore:             now,
 			NotAfter:              now.Add(lifetime),
 			KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
@@ -259,6 +409,27 @@ func samlCertificateAndKeyGenerator(keySize int, lifetime time.Duration) func(id
 	}
 }
 
+// maxSerialNumberBytes is the maximum length of a certificate serial number defined by RFC 5280
+const maxSerialNumberBytes = 20
+
+// certificateSerialNumber returns the id as serial number if it's a positive decimal number which fits into a serial number,
+// otherwise the serial number is derived from the hash of the id
+func certificateSerialNumber(id string) (*big.Int, error) {
+	if id == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Sr4la", "Errors.IDMissing")
+	}
+	if serial, ok := new(big.Int).SetString(id, 10); ok && serial.Sign() > 0 && len(serial.Bytes()) < maxSerialNumberBytes {
+		return serial, nil
+	}
+	hash := sha256.Sum256([]byte(id))
+	// the serial number must be positive, a leading zero byte would be added to an encoded value with the highest bit set
+	serial := new(big.Int).SetBytes(hash[:maxSerialNumberBytes-1])
+	if serial.Sign() == 0 {
+		return nil, zerrors.ThrowInternal(nil, "COMMAND-Sr5ne", "Errors.Internal")
+	}
+	return serial, nil
+}
+
 // Close blocks until all async jobs are finished,
 // the context expires or after eventstore.PushTimeout.
 func (c *Commands) Close(ctx context.Context) error {
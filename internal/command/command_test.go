@@ -2,8 +2,14 @@ package command
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,9 +17,14 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/text/language"
 
+	api_http "github.com/zitadel/zitadel/internal/api/http"
+	sd "github.com/zitadel/zitadel/internal/config/systemdefaults"
+	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/i18n"
+	"github.com/zitadel/zitadel/internal/repository/org"
 	"github.com/zitadel/zitadel/internal/repository/user"
+	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
 var (
@@ -144,3 +155,314 @@ func TestCommands_asyncPush(t *testing.T) {
 		})
 	}
 }
+
+func Test_samlCertificateAndKeyGenerator(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           *sd.SAMLCertificateConfig
+		wantOrganization string
+		wantCommonName   string
+		wantDNSNames     []string
+		wantLifetime     time.Duration
+	}{
+		{
+			name:             "no config",
+			wantOrganization: "ZITADEL",
+			wantLifetime:     time.Hour,
+		},
+		{
+			name: "config",
+			config: &sd.SAMLCertificateConfig{
+				Organization: "ACME",
+				CommonName:   "idp.acme.ch",
+				DNSNames:     []string{"idp.acme.ch", "saml.acme.ch"},
+				Lifetime:     2 * time.Hour,
+			},
+			wantOrganization: "ACME",
+			wantCommonName:   "idp.acme.ch",
+			wantDNSNames:     []string{"idp.acme.ch", "saml.acme.ch"},
+			wantLifetime:     2 * time.Hour,
+		},
+		{
+			name: "empty config",
+			config: &sd.SAMLCertificateConfig{
+				CommonName: "idp.acme.ch",
+			},
+			wantOrganization: "ZITADEL",
+			wantCommonName:   "idp.acme.ch",
+			wantLifetime:     time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, certPEM, err := samlCertificateAndKeyGenerator(1024, time.Hour, tt.config)("123")
+			require.NoError(t, err)
+			block, _ := pem.Decode(certPEM)
+			require.NotNil(t, block)
+			cert, err := x509.ParseCertificate(block.Bytes)
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{tt.wantOrganization}, cert.Subject.Organization)
+			assert.Equal(t, tt.wantCommonName, cert.Subject.CommonName)
+			assert.Equal(t, "123", cert.Subject.SerialNumber)
+			assert.Equal(t, tt.wantDNSNames, cert.DNSNames)
+			assert.WithinDuration(t, time.Now(), cert.NotBefore, time.Minute)
+			assert.Equal(t, tt.wantLifetime, cert.NotAfter.Sub(cert.NotBefore))
+		})
+	}
+}
+
+func Test_certificateSerialNumber(t *testing.T) {
+	largeID := "123456789012345678901234567890123456789012345678901234567890"
+	tests := []struct {
+		name    string
+		id      string
+		want    *big.Int
+		wantErr bool
+	}{
+		{
+			name: "numeric",
+			id:   "123",
+			want: big.NewInt(123),
+		},
+		{
+			name: "large numeric",
+			id:   "9223372036854775808",
+			want: new(big.Int).Lsh(big.NewInt(1), 63),
+		},
+		{
+			name: "too large numeric",
+			id:   largeID,
+		},
+		{
+			name: "alphanumeric",
+			id:   "user-1",
+		},
+		{
+			name: "zero",
+			id:   "0",
+		},
+		{
+			name:    "empty",
+			id:      "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := certificateSerialNumber(tt.id)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.want != nil {
+				assert.Equal(t, tt.want, got)
+			}
+			assert.Equal(t, 1, got.Sign())
+			assert.Less(t, len(got.Bytes()), maxSerialNumberBytes)
+
+			again, err := certificateSerialNumber(tt.id)
+			require.NoError(t, err)
+			assert.Equal(t, got, again, "serial number must be deterministic")
+
+			_, certPEM, err := samlCertificateAndKeyGenerator(1024, time.Hour, nil)(tt.id)
+			require.NoError(t, err)
+			block, _ := pem.Decode(certPEM)
+			require.NotNil(t, block)
+			cert, err := x509.ParseCertificate(block.Bytes)
+			require.NoError(t, err)
+			assert.Equal(t, got, cert.SerialNumber)
+		})
+	}
+}
+
+func Test_httpClientWithTimeout(t *testing.T) {
+	withTimeout := &http.Client{Timeout: time.Minute}
+	tests := []struct {
+		name   string
+		client *http.Client
+		want   time.Duration
+	}{
+		{
+			name: "no client",
+			want: time.Second,
+		},
+		{
+			name:   "no timeout",
+			client: &http.Client{},
+			want:   time.Second,
+		},
+		{
+			name:   "timeout",
+			client: withTimeout,
+			want:   time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := httpClientWithTimeout(tt.client, time.Second)
+			assert.Equal(t, tt.want, got.Timeout)
+			if tt.client != nil && tt.client.Timeout == 0 {
+				assert.NotSame(t, tt.client, got, "client of the caller must not be changed")
+			}
+		})
+	}
+
+	c := &Commands{httpClient: withTimeout}
+	WithHTTPClientTimeout(time.Second)(c)
+	assert.Equal(t, time.Second, c.httpClient.Timeout)
+	assert.Equal(t, time.Minute, withTimeout.Timeout, "client of the caller must not be changed")
+}
+
+func Test_domainVerification_httpClientTimeout(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	validate := api_http.HTTPDomainValidator(httpClientWithTimeout(server.Client(), 50*time.Millisecond))
+	start := time.Now()
+	err := validate(strings.TrimPrefix(server.URL, "https://"), "token", "verifier")
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+type txtResolverFunc func(ctx context.Context, name string) ([]string, error)
+
+func (f txtResolverFunc) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return f(ctx, name)
+}
+
+func Test_domainVerification_dnsResolver(t *testing.T) {
+	var lookedUp string
+	c := &Commands{}
+	WithDomainVerificationResolver(txtResolverFunc(func(_ context.Context, name string) ([]string, error) {
+		lookedUp = name
+		return []string{"other", "verifier"}, nil
+	}))(c)
+
+	require.NoError(t, c.validateDomainVerification("zitadel.ch", "token", "verifier", api_http.CheckTypeDNS))
+	assert.Equal(t, "_zitadel-challenge.zitadel.ch", lookedUp)
+	assert.Error(t, c.validateDomainVerification("zitadel.ch", "token", "unknown", api_http.CheckTypeDNS))
+}
+
+func Test_validateKeySizes(t *testing.T) {
+	valid := func() sd.SystemDefaults {
+		return sd.SystemDefaults{
+			KeyConfig:        sd.KeyConfig{Size: 2048, CertificateSize: 4096},
+			SecretGenerators: sd.SecretGenerators{MachineKeySize: 2048, ApplicationKeySize: 2048},
+		}
+	}
+	tests := []struct {
+		name     string
+		defaults func() sd.SystemDefaults
+		wantErr  error
+	}{
+		{
+			name:     "valid sizes",
+			defaults: valid,
+		},
+		{
+			name: "key size too small",
+			defaults: func() sd.SystemDefaults {
+				d := valid()
+				d.KeyConfig.Size = 512
+				return d
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-Kz2t4", ""),
+		},
+		{
+			name: "certificate key size too small",
+			defaults: func() sd.SystemDefaults {
+				d := valid()
+				d.KeyConfig.CertificateSize = 1024
+				return d
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-Kz3t5", ""),
+		},
+		{
+			name: "machine key size too small",
+			defaults: func() sd.SystemDefaults {
+				d := valid()
+				d.SecretGenerators.MachineKeySize = 512
+				return d
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-Kz4t6", ""),
+		},
+		{
+			name: "application key size too small",
+			defaults: func() sd.SystemDefaults {
+				d := valid()
+				d.SecretGenerators.ApplicationKeySize = 2047
+				return d
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-Kz5t7", ""),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateKeySizes(tt.defaults())
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+type appendReducerMock struct {
+	events    []eventstore.Event
+	reduced   bool
+	reduceErr error
+}
+
+func (m *appendReducerMock) AppendEvents(events ...eventstore.Event) {
+	m.events = append(m.events, events...)
+}
+
+func (m *appendReducerMock) Reduce() error {
+	m.reduced = true
+	return m.reduceErr
+}
+
+func TestAppendAndReduceMany(t *testing.T) {
+	agg := user.NewAggregate("userID", "orgID")
+	events := []eventstore.Event{
+		eventFromEventPusher(user.NewMachineSecretCheckFailedEvent(context.Background(), &agg.Aggregate)),
+	}
+	errFirst := zerrors.ThrowInternal(nil, "TEST-Mny1", "first")
+	errThird := zerrors.ThrowInternal(nil, "TEST-Mny3", "third")
+	first := &appendReducerMock{reduceErr: errFirst}
+	second := &appendReducerMock{}
+	third := &appendReducerMock{reduceErr: errThird}
+
+	err := AppendAndReduceMany(events, first, second, third)
+	assert.ErrorIs(t, err, errFirst)
+	assert.ErrorIs(t, err, errThird)
+	for _, object := range []*appendReducerMock{first, second, third} {
+		assert.True(t, object.reduced)
+		assert.Equal(t, events, object.events)
+	}
+
+	assert.NoError(t, AppendAndReduceMany(events, &appendReducerMock{}, &appendReducerMock{}))
+}
+
+func TestCommands_PreviewCommands(t *testing.T) {
+	ctx := context.Background()
+	agg := &org.NewAggregate("orgID").Aggregate
+	c := &Commands{
+		// no expectations: pushing any event fails the test
+		eventstore: expectEventstore()(t),
+	}
+	wm := NewOrgWriteModel("orgID")
+
+	err := c.PreviewCommands(ctx, wm,
+		org.NewOrgAddedEvent(ctx, agg, "org"),
+		org.NewOrgChangedEvent(ctx, agg, "org", "renamed"),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", wm.Name)
+	assert.Equal(t, domain.OrgStateActive, wm.State)
+}
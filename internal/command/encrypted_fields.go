@@ -0,0 +1,73 @@
+package command
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/crypto"
+)
+
+// EncryptedFieldType identifies an encrypted field of an event
+type EncryptedFieldType string
+
+const (
+	EncryptedFieldTypeSMTPPassword EncryptedFieldType = "smtp_password"
+	EncryptedFieldTypeSMSToken     EncryptedFieldType = "sms_token"
+	EncryptedFieldTypeOTPSecret    EncryptedFieldType = "otp_secret"
+)
+
+// EncryptedFieldsCount is the amount of values of a field encrypted with the same algorithm and key.
+type EncryptedFieldsCount struct {
+	Algorithm string
+	KeyID     string
+	Count     uint64
+	// Rotate is set if the values are not encrypted with the current encryption key of the algorithm protecting the field
+	Rotate bool
+}
+
+// EncryptedFields counts the current encrypted values of the instance grouped by field type, algorithm and key id.
+// It's used to estimate the scope of a key rotation.
+func (c *Commands) EncryptedFields(ctx context.Context) (map[EncryptedFieldType][]*EncryptedFieldsCount, error) {
+	writeModel := newEncryptedFieldsWriteModel(authz.GetInstance(ctx).InstanceID())
+	if err := c.eventstore.FilterToQueryReducer(ctx, writeModel); err != nil {
+		return nil, err
+	}
+	algorithms := map[EncryptedFieldType]crypto.EncryptionAlgorithm{
+		EncryptedFieldTypeSMTPPassword: c.smtpEncryption,
+		EncryptedFieldTypeSMSToken:     c.smsEncryption,
+		EncryptedFieldTypeOTPSecret:    c.multifactors.OTP.CryptoMFA,
+	}
+	counts := make(map[EncryptedFieldType][]*EncryptedFieldsCount, len(writeModel.Fields))
+	for fieldType, values := range writeModel.Fields {
+		counts[fieldType] = countEncryptedFields(values, algorithms[fieldType])
+	}
+	return counts, nil
+}
+
+func countEncryptedFields(values map[string]*crypto.CryptoValue, alg crypto.EncryptionAlgorithm) []*EncryptedFieldsCount {
+	counts := make([]*EncryptedFieldsCount, 0)
+	for _, value := range values {
+		i := slices.IndexFunc(counts, func(count *EncryptedFieldsCount) bool {
+			return count.Algorithm == value.Algorithm && count.KeyID == value.KeyID
+		})
+		if i >= 0 {
+			counts[i].Count++
+			continue
+		}
+		counts = append(counts, &EncryptedFieldsCount{
+			Algorithm: value.Algorithm,
+			KeyID:     value.KeyID,
+			Count:     1,
+			Rotate:    alg == nil || alg.Algorithm() != value.Algorithm || alg.EncryptionKeyID() != value.KeyID,
+		})
+	}
+	slices.SortFunc(counts, func(a, b *EncryptedFieldsCount) int {
+		if cmp := strings.Compare(a.Algorithm, b.Algorithm); cmp != 0 {
+			return cmp
+		}
+		return strings.Compare(a.KeyID, b.KeyID)
+	})
+	return counts
+}
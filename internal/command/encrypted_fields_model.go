@@ -0,0 +1,90 @@
+package command
+
+import (
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/user"
+)
+
+// encryptedFieldsWriteModel collects the current encrypted values of an instance.
+// Values are identified by the id of the config or the user.
+type encryptedFieldsWriteModel struct {
+	eventstore.WriteModel
+
+	Fields map[EncryptedFieldType]map[string]*crypto.CryptoValue
+}
+
+func newEncryptedFieldsWriteModel(instanceID string) *encryptedFieldsWriteModel {
+	return &encryptedFieldsWriteModel{
+		WriteModel: eventstore.WriteModel{
+			InstanceID: instanceID,
+		},
+		Fields: map[EncryptedFieldType]map[string]*crypto.CryptoValue{
+			EncryptedFieldTypeSMTPPassword: {},
+			EncryptedFieldTypeSMSToken:     {},
+			EncryptedFieldTypeOTPSecret:    {},
+		},
+	}
+}
+
+func (wm *encryptedFieldsWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch e := event.(type) {
+		case *instance.SMTPConfigAddedEvent:
+			wm.set(EncryptedFieldTypeSMTPPassword, e.ID, e.Password)
+		case *instance.SMTPConfigChangedEvent:
+			wm.set(EncryptedFieldTypeSMTPPassword, e.ID, e.Password)
+		case *instance.SMTPConfigPasswordChangedEvent:
+			wm.set(EncryptedFieldTypeSMTPPassword, e.ID, e.Password)
+		case *instance.SMTPConfigRemovedEvent:
+			delete(wm.Fields[EncryptedFieldTypeSMTPPassword], e.ID)
+		case *instance.SMSConfigTwilioAddedEvent:
+			wm.set(EncryptedFieldTypeSMSToken, e.ID, e.Token)
+		case *instance.SMSConfigTwilioTokenChangedEvent:
+			wm.set(EncryptedFieldTypeSMSToken, e.ID, e.Token)
+		case *instance.SMSConfigRemovedEvent:
+			delete(wm.Fields[EncryptedFieldTypeSMSToken], e.ID)
+		case *user.HumanOTPAddedEvent:
+			wm.set(EncryptedFieldTypeOTPSecret, e.Aggregate().ID, e.Secret)
+		case *user.HumanOTPRemovedEvent:
+			delete(wm.Fields[EncryptedFieldTypeOTPSecret], e.Aggregate().ID)
+		case *user.UserRemovedEvent:
+			delete(wm.Fields[EncryptedFieldTypeOTPSecret], e.Aggregate().ID)
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+// set only overwrites the value if it's set, because change events only contain changed fields
+func (wm *encryptedFieldsWriteModel) set(fieldType EncryptedFieldType, id string, value *crypto.CryptoValue) {
+	if value == nil {
+		return
+	}
+	wm.Fields[fieldType][id] = value
+}
+
+func (wm *encryptedFieldsWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		InstanceID(wm.InstanceID).
+		AddQuery().
+		AggregateTypes(instance.AggregateType).
+		AggregateIDs(wm.InstanceID).
+		EventTypes(
+			instance.SMTPConfigAddedEventType,
+			instance.SMTPConfigChangedEventType,
+			instance.SMTPConfigPasswordChangedEventType,
+			instance.SMTPConfigRemovedEventType,
+			instance.SMSConfigTwilioAddedEventType,
+			instance.SMSConfigTwilioTokenChangedEventType,
+			instance.SMSConfigRemovedEventType,
+		).
+		Or().
+		AggregateTypes(user.AggregateType).
+		EventTypes(
+			user.HumanMFAOTPAddedType,
+			user.HumanMFAOTPRemovedType,
+			user.UserRemovedType,
+		).
+		Builder()
+}
@@ -0,0 +1,178 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/user"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestCommands_EncryptedFields(t *testing.T) {
+	cryptoValue := func(keyID string) *crypto.CryptoValue {
+		return &crypto.CryptoValue{
+			CryptoType: crypto.TypeEncryption,
+			Algorithm:  "enc",
+			KeyID:      keyID,
+			Crypted:    []byte("crypted"),
+		}
+	}
+	type fields struct {
+		eventstore func(t *testing.T) *eventstore.Eventstore
+	}
+	type res struct {
+		want map[EncryptedFieldType][]*EncryptedFieldsCount
+		err  func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		res    res
+	}{
+		{
+			name: "filter error",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilterError(zerrors.ThrowInternal(nil, "id", "message")),
+				),
+			},
+			res: res{
+				err: zerrors.IsInternal,
+			},
+		},
+		{
+			name: "no encrypted fields",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+				),
+			},
+			res: res{
+				want: map[EncryptedFieldType][]*EncryptedFieldsCount{
+					EncryptedFieldTypeSMTPPassword: {},
+					EncryptedFieldTypeSMSToken:     {},
+					EncryptedFieldTypeOTPSecret:    {},
+				},
+			},
+		},
+		{
+			name: "count by key",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewSMTPConfigAddedEvent(context.Background(),
+								&instance.NewAggregate("instance1").Aggregate,
+								"smtp1", "description", true, "from", "name", "", "host", "user",
+								cryptoValue("old"),
+							),
+						),
+						eventFromEventPusher(
+							instance.NewSMTPConfigPasswordChangedEvent(context.Background(),
+								&instance.NewAggregate("instance1").Aggregate,
+								"smtp1",
+								cryptoValue("id"),
+							),
+						),
+						eventFromEventPusher(
+							instance.NewSMTPConfigAddedEvent(context.Background(),
+								&instance.NewAggregate("instance1").Aggregate,
+								"smtp2", "description", true, "from", "name", "", "host", "user",
+								cryptoValue("old"),
+							),
+						),
+						eventFromEventPusher(
+							instance.NewSMTPConfigAddedEvent(context.Background(),
+								&instance.NewAggregate("instance1").Aggregate,
+								"smtp3", "description", true, "from", "name", "", "host", "user",
+								cryptoValue("old"),
+							),
+						),
+						eventFromEventPusher(
+							instance.NewSMTPConfigRemovedEvent(context.Background(),
+								&instance.NewAggregate("instance1").Aggregate,
+								"smtp3",
+							),
+						),
+						eventFromEventPusher(
+							instance.NewSMSConfigTwilioAddedEvent(context.Background(),
+								&instance.NewAggregate("instance1").Aggregate,
+								"sms1", "sid", "number",
+								cryptoValue("old"),
+							),
+						),
+						eventFromEventPusher(
+							user.NewHumanOTPAddedEvent(context.Background(),
+								&user.NewAggregate("user1", "org1").Aggregate,
+								cryptoValue("old"),
+							),
+						),
+						eventFromEventPusher(
+							user.NewHumanOTPAddedEvent(context.Background(),
+								&user.NewAggregate("user2", "org1").Aggregate,
+								cryptoValue("old"),
+							),
+						),
+						eventFromEventPusher(
+							user.NewHumanOTPAddedEvent(context.Background(),
+								&user.NewAggregate("user3", "org1").Aggregate,
+								cryptoValue("old"),
+							),
+						),
+						eventFromEventPusher(
+							user.NewHumanOTPRemovedEvent(context.Background(),
+								&user.NewAggregate("user3", "org1").Aggregate,
+							),
+						),
+					),
+				),
+			},
+			res: res{
+				want: map[EncryptedFieldType][]*EncryptedFieldsCount{
+					EncryptedFieldTypeSMTPPassword: {
+						{Algorithm: "enc", KeyID: "id", Count: 1},
+						{Algorithm: "enc", KeyID: "old", Count: 1, Rotate: true},
+					},
+					EncryptedFieldTypeSMSToken: {
+						{Algorithm: "enc", KeyID: "old", Count: 1, Rotate: true},
+					},
+					EncryptedFieldTypeOTPSecret: {
+						{Algorithm: "enc", KeyID: "old", Count: 2, Rotate: true},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore:     tt.fields.eventstore(t),
+				smtpEncryption: crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+				smsEncryption:  crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+				multifactors: domain.MultifactorConfigs{
+					OTP: domain.OTPConfig{
+						CryptoMFA: crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+					},
+				},
+			}
+			got, err := c.EncryptedFields(authz.WithInstanceID(context.Background(), "instance1"))
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package command
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"hash"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	zcrypto "github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// ExportManifest describes the events of an instance at the time of an export.
+// It is signed using the active signing key of the instance
+// and allows to verify on restore that all events were imported unchanged.
+type ExportManifest struct {
+	InstanceID string    `json:"instanceId"`
+	CreatedAt  time.Time `json:"createdAt"`
+	// Position is the highest position of the events of the instance
+	Position float64 `json:"position"`
+	// AggregateCounts contains the count of events per aggregate type,
+	// aggregate types without events are omitted
+	AggregateCounts map[eventstore.AggregateType]uint64 `json:"aggregateCounts"`
+	// EventsHash is the hash of the contents of all events in the order they were exported, see [eventsHasher]
+	EventsHash []byte `json:"eventsHash"`
+	// StateHash is the hash of the unique constraints and fields of the instance, see [hashInstanceState].
+	// It's only set by [Commands.ExportSealedInstance]
+	StateHash []byte `json:"stateHash,omitempty"`
+	KeyID     string `json:"keyId"`
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// GenerateExportManifest counts the events of the current instance per aggregate type, hashes their contents
+// and signs the result with the active signing key of the instance.
+func (c *Commands) GenerateExportManifest(ctx context.Context) (*ExportManifest, error) {
+	return c.generateExportManifest(ctx, nil, nil)
+}
+
+// generateExportManifest reads all events of the current instance to hash them into the manifest,
+// every event is passed to export if it's set. The hash of the state is added to the manifest if it's set.
+func (c *Commands) generateExportManifest(ctx context.Context, export func(eventstore.Event) error, state *eventstore.InstanceState) (_ *ExportManifest, err error) {
+	instanceID := authz.GetInstance(ctx).InstanceID()
+	if instanceID == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Wm4xq", "Errors.IDMissing")
+	}
+
+	keyWriteModel := NewSigningKeyPairsWriteModel(instanceID)
+	if err = c.eventstore.FilterToQueryReducer(ctx, keyWriteModel); err != nil {
+		return nil, err
+	}
+	// the same key as for the signing of tokens is used, revoked keys are never selected
+	key := keyWriteModel.activeKey(time.Now())
+	if key == nil || key.PrivateKey == nil {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "COMMAND-Ty8dn", "Errors.Key.NotFound")
+	}
+
+	manifest := &ExportManifest{
+		InstanceID:      instanceID,
+		CreatedAt:       time.Now().UTC(),
+		AggregateCounts: make(map[eventstore.AggregateType]uint64),
+		KeyID:           key.ID,
+	}
+	manifest.Position, err = c.eventstore.LatestSequence(ctx,
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsMaxSequence).
+			InstanceID(instanceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, typ := range c.eventstore.AggregateTypes() {
+		count, err := c.eventstore.Count(ctx,
+			eventstore.NewSearchQueryBuilder(eventstore.ColumnsCount).
+				InstanceID(instanceID).
+				AddQuery().
+				AggregateTypes(eventstore.AggregateType(typ)).
+				Builder(),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			manifest.AggregateCounts[eventstore.AggregateType(typ)] = count
+		}
+	}
+
+	exporter := &instanceExporter{
+		export: export,
+		counts: make(map[eventstore.AggregateType]uint64, len(manifest.AggregateCounts)),
+		hasher: newEventsHasher(),
+	}
+	if err = c.eventstore.FilterToReducer(ctx, instanceEventsQuery(manifest), exporter); err != nil {
+		return nil, err
+	}
+	// the instance is sealed during an export, so fewer events mean that the result was truncated, e.g. by the result cap of the eventstore
+	if export != nil && !equalAggregateCounts(manifest.AggregateCounts, exporter.counts) {
+		return nil, zerrors.ThrowInternal(nil, "COMMAND-Ex6vd", "Errors.Internal")
+	}
+	manifest.EventsHash = exporter.hasher.Sum()
+	if state != nil {
+		if manifest.StateHash, err = hashInstanceState(state); err != nil {
+			return nil, err
+		}
+	}
+
+	keyData, err := zcrypto.Decrypt(key.PrivateKey, c.keyAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := zcrypto.BytesToPrivateKey(keyData)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := manifest.digest()
+	if err != nil {
+		return nil, err
+	}
+	manifest.Signature, err = rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, digest)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "COMMAND-Hs3pe", "Errors.Internal")
+	}
+	return manifest, nil
+}
+
+// Verify checks the signature of the manifest using the public key of the signing key.
+func (m *ExportManifest) Verify(publicKey *rsa.PublicKey) error {
+	digest, err := m.digest()
+	if err != nil {
+		return err
+	}
+	if err = rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest, m.Signature); err != nil {
+		return zerrors.ThrowPreconditionFailed(err, "COMMAND-Bv7ko", "Errors.Key.InvalidSignature")
+	}
+	return nil
+}
+
+// digest returns the hash of the manifest without the signature
+func (m *ExportManifest) digest() ([]byte, error) {
+	unsigned := *m
+	unsigned.Signature = nil
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "COMMAND-Nq2cz", "Errors.Internal")
+	}
+	hash := sha256.Sum256(data)
+	return hash[:], nil
+}
+
+// eventsHasher hashes all stored fields of the events in the order they are written
+type eventsHasher struct {
+	hash hash.Hash
+}
+
+func newEventsHasher() *eventsHasher {
+	return &eventsHasher{hash: sha256.New()}
+}
+
+// hashedEvent contains the fields of an event which are stored by the import
+type hashedEvent struct {
+	InstanceID        string                   `json:"instanceId"`
+	Owner             string                   `json:"owner"`
+	AggregateType     eventstore.AggregateType `json:"aggregateType"`
+	AggregateID       string                   `json:"aggregateId"`
+	Revision          uint16                   `json:"revision"`
+	Creator           string                   `json:"creator"`
+	Type              eventstore.EventType     `json:"type"`
+	Payload           []byte                   `json:"payload"`
+	Sequence          uint64                   `json:"sequence"`
+	CreatedAt         time.Time                `json:"createdAt"`
+	Position          float64                  `json:"position"`
+	ProducerVersion   string                   `json:"producerVersion"`
+	MaintenanceWindow string                   `json:"maintenanceWindow"`
+}
+
+func (h *eventsHasher) Write(event eventstore.Event) error {
+	producerVersion, maintenanceWindow := eventstore.EventOrigin(event)
+	data, err := json.Marshal(&hashedEvent{
+		InstanceID:        event.Aggregate().InstanceID,
+		Owner:             event.Aggregate().ResourceOwner,
+		AggregateType:     event.Aggregate().Type,
+		AggregateID:       event.Aggregate().ID,
+		Revision:          event.Revision(),
+		Creator:           event.Creator(),
+		Type:              event.Type(),
+		Payload:           event.DataAsBytes(),
+		Sequence:          event.Sequence(),
+		CreatedAt:         event.CreatedAt().UTC(),
+		Position:          event.Position(),
+		ProducerVersion:   producerVersion,
+		MaintenanceWindow: maintenanceWindow,
+	})
+	if err != nil {
+		return zerrors.ThrowInternal(err, "COMMAND-Hs5ev", "Errors.Internal")
+	}
+	// the events are separated by new lines, which never occur in the encoded json
+	h.hash.Write(append(data, '\n'))
+	return nil
+}
+
+func (h *eventsHasher) Sum() []byte {
+	return h.hash.Sum(nil)
+}
+
+// hashInstanceState hashes the unique constraints and fields of the instance in the order they were exported
+func hashInstanceState(state *eventstore.InstanceState) ([]byte, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "COMMAND-Hs8st", "Errors.Internal")
+	}
+	hash := sha256.Sum256(data)
+	return hash[:], nil
+}
@@ -0,0 +1,310 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/keypair"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestCommands_GenerateExportManifest(t *testing.T) {
+	privateKey, publicKey, err := crypto.GenerateKeyPair(1024)
+	require.NoError(t, err)
+	otherKey, _, err := crypto.GenerateKeyPair(1024)
+	require.NoError(t, err)
+
+	keyAggregate := func(keyID string) *eventstore.Aggregate {
+		return eventstore.NewAggregate(context.Background(), keyID, keypair.AggregateType, keypair.AggregateVersion, eventstore.WithResourceOwner("instance1"), eventstore.WithInstanceID("instance1"))
+	}
+	keyAdded := func(keyID string, usage domain.KeyUsage, key []byte, expiry time.Time) eventstore.Event {
+		return eventFromEventPusherWithInstanceID("instance1",
+			keypair.NewAddedEvent(context.Background(),
+				keyAggregate(keyID),
+				usage,
+				"RS256",
+				&crypto.CryptoValue{
+					CryptoType: crypto.TypeEncryption,
+					Algorithm:  "enc",
+					KeyID:      "id",
+					Crypted:    key,
+				},
+				&crypto.CryptoValue{},
+				expiry,
+				expiry,
+			),
+		)
+	}
+	keyAddedEvent := func(keyID string, usage domain.KeyUsage, key []byte, expiry time.Time) expect {
+		return expectFilter(keyAdded(keyID, usage, key, expiry))
+	}
+	// manifestExpects expects the counts per aggregate type followed by the events of the instance which are hashed
+	manifestExpects := func(first uint64, events ...eventstore.Event) []expect {
+		types := (&eventstore.Eventstore{}).AggregateTypes()
+		expects := make([]expect, len(types), len(types)+1)
+		for i := range types {
+			expects[i] = expectCount(0)
+		}
+		expects[0] = expectCount(first)
+		return append(expects, expectFilter(events...))
+	}
+	firstType := eventstore.AggregateType((&eventstore.Eventstore{}).AggregateTypes()[0])
+
+	type fields struct {
+		eventstore func(t *testing.T) *eventstore.Eventstore
+	}
+	type args struct {
+		ctx context.Context
+	}
+	type res struct {
+		manifest *ExportManifest
+		err      func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "no instance, invalid argument",
+			fields: fields{
+				eventstore: expectEventstore(),
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			res: res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			name: "no signing key, precondition failed",
+			fields: fields{
+				eventstore: expectEventstore(
+					keyAddedEvent("key1", domain.KeyUsageSAMLCA, crypto.PrivateKeyToBytes(privateKey), time.Now().Add(time.Hour)),
+				),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+			},
+			res: res{
+				err: zerrors.IsPreconditionFailed,
+			},
+		},
+		{
+			name: "signing key expired, precondition failed",
+			fields: fields{
+				eventstore: expectEventstore(
+					keyAddedEvent("key1", domain.KeyUsageSigning, crypto.PrivateKeyToBytes(privateKey), time.Now().Add(-time.Hour)),
+				),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+			},
+			res: res{
+				err: zerrors.IsPreconditionFailed,
+			},
+		},
+		{
+			name: "newest signing key revoked, older key used",
+			fields: fields{
+				eventstore: expectEventstore(
+					append([]expect{
+						expectFilter(
+							keyAdded("key1", domain.KeyUsageSigning, crypto.PrivateKeyToBytes(privateKey), time.Now().Add(time.Hour)),
+							keyAdded("key2", domain.KeyUsageSigning, crypto.PrivateKeyToBytes(otherKey), time.Now().Add(2*time.Hour)),
+							eventFromEventPusherWithInstanceID("instance1",
+								keypair.NewRevokedEvent(context.Background(), keyAggregate("key2")),
+							),
+						),
+						expectLatestSequence(42.5),
+					}, manifestExpects(3)...)...,
+				),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+			},
+			res: res{
+				manifest: &ExportManifest{
+					InstanceID: "instance1",
+					Position:   42.5,
+					AggregateCounts: map[eventstore.AggregateType]uint64{
+						firstType: 3,
+					},
+					KeyID: "key1",
+				},
+			},
+		},
+		{
+			name: "newest signing key expired early, older key used",
+			fields: fields{
+				eventstore: expectEventstore(
+					append([]expect{
+						expectFilter(
+							keyAdded("key1", domain.KeyUsageSigning, crypto.PrivateKeyToBytes(privateKey), time.Now().Add(time.Hour)),
+							keyAdded("key2", domain.KeyUsageSigning, crypto.PrivateKeyToBytes(otherKey), time.Now().Add(2*time.Hour)),
+							eventFromEventPusherWithCreationDateNow(
+								keypair.NewExpiredEvent(context.Background(), keyAggregate("key2")),
+							),
+						),
+						expectLatestSequence(42.5),
+					}, manifestExpects(3)...)...,
+				),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+			},
+			res: res{
+				manifest: &ExportManifest{
+					InstanceID: "instance1",
+					Position:   42.5,
+					AggregateCounts: map[eventstore.AggregateType]uint64{
+						firstType: 3,
+					},
+					KeyID: "key1",
+				},
+			},
+		},
+		{
+			name: "newest signing key not yet active, older key used",
+			fields: fields{
+				eventstore: expectEventstore(
+					append([]expect{
+						expectFilter(
+							keyAdded("key1", domain.KeyUsageSigning, crypto.PrivateKeyToBytes(privateKey), time.Now().Add(time.Hour)),
+							eventFromEventPusherWithInstanceID("instance1",
+								keypair.NewPendingAddedEvent(context.Background(),
+									keyAggregate("key2"),
+									domain.KeyUsageSigning,
+									"RS256",
+									&crypto.CryptoValue{
+										CryptoType: crypto.TypeEncryption,
+										Algorithm:  "enc",
+										KeyID:      "id",
+										Crypted:    crypto.PrivateKeyToBytes(otherKey),
+									},
+									&crypto.CryptoValue{},
+									time.Now().Add(3*time.Hour),
+									time.Now().Add(3*time.Hour),
+									time.Now().Add(30*time.Minute),
+								),
+							),
+						),
+						expectLatestSequence(42.5),
+					}, manifestExpects(3)...)...,
+				),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+			},
+			res: res{
+				manifest: &ExportManifest{
+					InstanceID: "instance1",
+					Position:   42.5,
+					AggregateCounts: map[eventstore.AggregateType]uint64{
+						firstType: 3,
+					},
+					KeyID: "key1",
+				},
+			},
+		},
+		{
+			name: "only signing key revoked, precondition failed",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						keyAdded("key1", domain.KeyUsageSigning, crypto.PrivateKeyToBytes(privateKey), time.Now().Add(time.Hour)),
+						eventFromEventPusherWithInstanceID("instance1",
+							keypair.NewRevokedEvent(context.Background(), keyAggregate("key1")),
+						),
+					),
+				),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+			},
+			res: res{
+				err: zerrors.IsPreconditionFailed,
+			},
+		},
+		{
+			name: "count failed, error",
+			fields: fields{
+				eventstore: expectEventstore(
+					keyAddedEvent("key1", domain.KeyUsageSigning, crypto.PrivateKeyToBytes(privateKey), time.Now().Add(time.Hour)),
+					expectLatestSequence(42.5),
+					expectCountError(zerrors.ThrowInternal(nil, "ID", "count failed")),
+				),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+			},
+			res: res{
+				err: zerrors.IsInternal,
+			},
+		},
+		{
+			name: "manifest signed, ok",
+			fields: fields{
+				eventstore: expectEventstore(
+					append([]expect{
+						keyAddedEvent("key1", domain.KeyUsageSigning, crypto.PrivateKeyToBytes(privateKey), time.Now().Add(time.Hour)),
+						expectLatestSequence(42.5),
+					}, manifestExpects(3,
+						keyAdded("key1", domain.KeyUsageSigning, crypto.PrivateKeyToBytes(privateKey), time.Now().Add(time.Hour)),
+						keyAdded("key2", domain.KeyUsageSigning, crypto.PrivateKeyToBytes(otherKey), time.Now().Add(time.Hour)),
+					)...)...,
+				),
+			},
+			args: args{
+				ctx: authz.WithInstanceID(context.Background(), "instance1"),
+			},
+			res: res{
+				manifest: &ExportManifest{
+					InstanceID: "instance1",
+					Position:   42.5,
+					AggregateCounts: map[eventstore.AggregateType]uint64{
+						firstType: 3,
+					},
+					KeyID: "key1",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore:   tt.fields.eventstore(t),
+				keyAlgorithm: crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+			}
+			got, err := c.GenerateExportManifest(tt.args.ctx)
+			if tt.res.err != nil {
+				assert.True(t, tt.res.err(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.res.manifest.InstanceID, got.InstanceID)
+			assert.Equal(t, tt.res.manifest.Position, got.Position)
+			assert.Equal(t, tt.res.manifest.AggregateCounts, got.AggregateCounts)
+			assert.Equal(t, tt.res.manifest.KeyID, got.KeyID)
+			assert.NoError(t, got.Verify(publicKey))
+			assert.Error(t, got.Verify(&otherKey.PublicKey))
+
+			got.AggregateCounts[firstType]++
+			assert.Error(t, got.Verify(publicKey))
+			got.AggregateCounts[firstType]--
+			got.EventsHash = []byte("changed")
+			assert.Error(t, got.Verify(publicKey))
+		})
+	}
+}
@@ -52,6 +52,7 @@ type InstanceSetup struct {
 	PasswordAgePolicy struct {
 		ExpireWarnDays uint64
 		MaxAgeDays     uint64
+		HistoryCount   uint64
 	}
 	DomainPolicy struct {
 		UserLoginMustBeDomain                  bool
@@ -302,6 +303,7 @@ func setupInstanceElements(instanceAgg *instance.Aggregate, setup *InstanceSetup
 			instanceAgg,
 			setup.PasswordAgePolicy.ExpireWarnDays,
 			setup.PasswordAgePolicy.MaxAgeDays,
+			setup.PasswordAgePolicy.HistoryCount,
 		),
 		prepareAddDefaultDomainPolicy(
 			instanceAgg,
@@ -570,7 +572,7 @@ func setupAdmins(commands *Commands,
 		human.ID = humanUserID
 
 		*validations = append(*validations,
-			commands.AddHumanCommand(human, orgAgg.ID, commands.userPasswordHasher, commands.userEncryption, true),
+			commands.AddHumanCommand(human, orgAgg.ID, commands.passwordHasher(), commands.userEncryption, true),
 		)
 
 		setupAdminMembers(commands, validations, instanceAgg, orgAgg, humanUserID)
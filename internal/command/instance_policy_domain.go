@@ -61,7 +61,8 @@ func (c *Commands) instanceDomainPolicyWriteModel(ctx context.Context) (policy *
 	defer func() { span.EndWithError(err) }()
 
 	writeModel := NewInstanceDomainPolicyWriteModel(ctx)
-	err = c.eventstore.FilterToQueryReducer(ctx, writeModel)
+	writeModel.cache = c.writeModelCache
+	err = queryAndReduce(ctx, c.eventstore.Filter, writeModel) //nolint:staticcheck
 	if err != nil {
 		return nil, err
 	}
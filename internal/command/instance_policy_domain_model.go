@@ -13,11 +13,13 @@ import (
 
 type InstanceDomainPolicyWriteModel struct {
 	PolicyDomainWriteModel
+
+	cache *WriteModelCache
 }
 
 func NewInstanceDomainPolicyWriteModel(ctx context.Context) *InstanceDomainPolicyWriteModel {
 	return &InstanceDomainPolicyWriteModel{
-		PolicyDomainWriteModel{
+		PolicyDomainWriteModel: PolicyDomainWriteModel{
 			WriteModel: eventstore.WriteModel{
 				AggregateID:   authz.GetInstance(ctx).InstanceID(),
 				ResourceOwner: authz.GetInstance(ctx).InstanceID(),
@@ -53,6 +55,15 @@ func (wm *InstanceDomainPolicyWriteModel) Query() *eventstore.SearchQueryBuilder
 		Builder()
 }
 
+// WriteModelCache implements [cachedWriteModel], the policy is cached if the cache is set
+func (wm *InstanceDomainPolicyWriteModel) WriteModelCache() (*WriteModelCache, WriteModelCacheKey) {
+	return wm.cache, WriteModelCacheKey{
+		InstanceID:    wm.ResourceOwner,
+		AggregateType: instance.AggregateType,
+		AggregateID:   wm.AggregateID,
+	}
+}
+
 func (wm *InstanceDomainPolicyWriteModel) NewChangedEvent(
 	ctx context.Context,
 	aggregate *eventstore.Aggregate,
@@ -23,6 +23,7 @@ func TestCommandSide_AddDefaultPasswordAgePolicy(t *testing.T) {
 		ctx            context.Context
 		maxAgeDays     uint64
 		expireWarnDays uint64
+		historyCount   uint64
 	}
 	type res struct {
 		want *domain.ObjectDetails
@@ -45,6 +46,7 @@ func TestCommandSide_AddDefaultPasswordAgePolicy(t *testing.T) {
 								&instance.NewAggregate("INSTANCE").Aggregate,
 								365,
 								10,
+								0,
 							),
 						),
 					),
@@ -70,6 +72,7 @@ func TestCommandSide_AddDefaultPasswordAgePolicy(t *testing.T) {
 							&instance.NewAggregate("INSTANCE").Aggregate,
 							365,
 							10,
+							5,
 						),
 					),
 				),
@@ -78,6 +81,7 @@ func TestCommandSide_AddDefaultPasswordAgePolicy(t *testing.T) {
 				ctx:            authz.WithInstanceID(context.Background(), "INSTANCE"),
 				expireWarnDays: 365,
 				maxAgeDays:     10,
+				historyCount:   5,
 			},
 			res: res{
 				want: &domain.ObjectDetails{
@@ -91,7 +95,7 @@ func TestCommandSide_AddDefaultPasswordAgePolicy(t *testing.T) {
 			r := &Commands{
 				eventstore: tt.fields.eventstore,
 			}
-			got, err := r.AddDefaultPasswordAgePolicy(tt.args.ctx, tt.args.expireWarnDays, tt.args.maxAgeDays)
+			got, err := r.AddDefaultPasswordAgePolicy(tt.args.ctx, tt.args.expireWarnDays, tt.args.maxAgeDays, tt.args.historyCount)
 			if tt.res.err == nil {
 				assert.NoError(t, err)
 			}
@@ -153,6 +157,7 @@ func TestCommandSide_ChangeDefaultPasswordAgePolicy(t *testing.T) {
 								&instance.NewAggregate("INSTANCE").Aggregate,
 								365,
 								10,
+								0,
 							),
 						),
 					),
@@ -180,6 +185,7 @@ func TestCommandSide_ChangeDefaultPasswordAgePolicy(t *testing.T) {
 								&instance.NewAggregate("INSTANCE").Aggregate,
 								365,
 								10,
+								0,
 							),
 						),
 					),
@@ -0,0 +1,214 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// SealInstance makes the current instance read-only.
+// As long as the instance is sealed the eventstore rejects all pushes to it except [Commands.UnsealInstance],
+// so the events of the instance can be exported consistently using [Commands.ExportSealedInstance].
+// Instances can only be sealed if RejectSealedInstances is enabled in the config of the eventstore.
+func (c *Commands) SealInstance(ctx context.Context) (_ *domain.ObjectDetails, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if !c.eventstore.RejectsSealedInstances() {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "COMMAND-Sl5rd", "Errors.Instance.SealingDisabled")
+	}
+	writeModel, err := c.instanceSealWriteModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if writeModel.Sealed {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "COMMAND-Sl3ma", "Errors.Instance.Sealed")
+	}
+	pushedEvents, err := c.eventstore.Push(ctx, instance.NewInstanceSealedEvent(ctx, InstanceAggregateFromWriteModel(&writeModel.WriteModel)))
+	if err != nil {
+		return nil, err
+	}
+	return pushedEventsToObjectDetails(pushedEvents), nil
+}
+
+// UnsealInstance accepts commands for the sealed current instance again,
+// e.g. if the migration was cancelled or on the importing deployment after [Commands.ImportInstance].
+func (c *Commands) UnsealInstance(ctx context.Context) (_ *domain.ObjectDetails, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	writeModel, err := c.instanceSealWriteModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !writeModel.Sealed {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "COMMAND-Sl8xn", "Errors.Instance.NotSealed")
+	}
+	pushedEvents, err := c.eventstore.Push(ctx, instance.NewInstanceUnsealedEvent(ctx, InstanceAggregateFromWriteModel(&writeModel.WriteModel)))
+	if err != nil {
+		return nil, err
+	}
+	return pushedEventsToObjectDetails(pushedEvents), nil
+}
+
+// ExportSealedInstance calls export for every event of the sealed current instance in the order they were pushed
+// and returns the unique constraints and fields of the instance.
+// The returned manifest is signed by the instance and must be passed to [Commands.ImportInstance] with the events and the state.
+// The export is refused if the eventstore doesn't reject pushes to sealed instances, because the events could change during the export.
+func (c *Commands) ExportSealedInstance(ctx context.Context, export func(eventstore.Event) error) (_ *ExportManifest, _ *eventstore.InstanceState, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if !c.eventstore.RejectsSealedInstances() {
+		return nil, nil, zerrors.ThrowPreconditionFailed(nil, "COMMAND-Ex4rd", "Errors.Instance.SealingDisabled")
+	}
+	writeModel, err := c.instanceSealWriteModel(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !writeModel.Sealed {
+		return nil, nil, zerrors.ThrowPreconditionFailed(nil, "COMMAND-Ex2ko", "Errors.Instance.NotSealed")
+	}
+	state, err := c.instanceState(ctx, writeModel.InstanceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	manifest, err := c.generateExportManifest(ctx, export, state)
+	if err != nil {
+		return nil, nil, err
+	}
+	return manifest, state, nil
+}
+
+// instanceState returns the unique constraints and fields of the instance
+// including the global unique constraints of its domains
+func (c *Commands) instanceState(ctx context.Context, instanceID string) (*eventstore.InstanceState, error) {
+	state, err := c.eventstore.InstanceState(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	domains := NewInstanceDomainsWriteModel(instanceID)
+	if err = c.eventstore.FilterToQueryReducer(ctx, domains); err != nil {
+		return nil, err
+	}
+	for _, instanceDomain := range domains.Domains {
+		state.UniqueConstraints = append(state.UniqueConstraints, instance.NewAddInstanceDomainUniqueConstraint(instanceDomain))
+	}
+	return state, nil
+}
+
+// ImportInstance stores the events and the state exported by [Commands.ExportSealedInstance] of another deployment.
+// The manifest is verified using the public key of the exporting instance
+// and the events and the state must match the counts and the hashes of the manifest.
+// The unique constraints and fields of the state are stored in the same transaction as the events.
+// The imported instance stays sealed until [Commands.UnsealInstance] is called on it.
+func (c *Commands) ImportInstance(ctx context.Context, manifest *ExportManifest, publicKey *rsa.PublicKey, state *eventstore.InstanceState, events []eventstore.Event) (err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if manifest == nil || publicKey == nil || state == nil {
+		return zerrors.ThrowInvalidArgument(nil, "COMMAND-Im2ba", "Errors.Invalid.Argument")
+	}
+	if err = manifest.Verify(publicKey); err != nil {
+		return err
+	}
+	counts := make(map[eventstore.AggregateType]uint64, len(manifest.AggregateCounts))
+	hasher := newEventsHasher()
+	for _, event := range events {
+		if event.Aggregate().InstanceID != manifest.InstanceID {
+			return zerrors.ThrowInvalidArgument(nil, "COMMAND-Im5qw", "Errors.Invalid.Argument")
+		}
+		counts[event.Aggregate().Type]++
+		if err = hasher.Write(event); err != nil {
+			return err
+		}
+	}
+	if !equalAggregateCounts(manifest.AggregateCounts, counts) {
+		return zerrors.ThrowPreconditionFailed(nil, "COMMAND-Im8ze", "Errors.Instance.ImportIncomplete")
+	}
+	// the events must not be changed after the export
+	if !bytes.Equal(manifest.EventsHash, hasher.Sum()) {
+		return zerrors.ThrowPreconditionFailed(nil, "COMMAND-Im3hv", "Errors.Instance.ImportIncomplete")
+	}
+	stateHash, err := hashInstanceState(state)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(manifest.StateHash, stateHash) {
+		return zerrors.ThrowPreconditionFailed(nil, "COMMAND-Im6sh", "Errors.Instance.ImportIncomplete")
+	}
+	return c.eventstore.Import(ctx, state, events...)
+}
+
+func (c *Commands) instanceSealWriteModel(ctx context.Context) (*instanceSealWriteModel, error) {
+	writeModel := newInstanceSealWriteModel(authz.GetInstance(ctx).InstanceID())
+	if err := c.eventstore.FilterToQueryReducer(ctx, writeModel); err != nil {
+		return nil, err
+	}
+	return writeModel, nil
+}
+
+// instanceEventsQuery queries all events of the instance of the manifest up to its position,
+// including their origin so the import keeps all stored fields
+func instanceEventsQuery(manifest *ExportManifest) *eventstore.SearchQueryBuilder {
+	types := make([]eventstore.AggregateType, 0, len(manifest.AggregateCounts))
+	for typ := range manifest.AggregateCounts {
+		types = append(types, typ)
+	}
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		InstanceID(manifest.InstanceID).
+		AwaitOpenTransactions().
+		IncludeOrigin().
+		AddQuery().
+		AggregateTypes(types...).
+		Builder()
+}
+
+// instanceExporter passes the filtered events to the export func if it's set,
+// counts them per aggregate type and hashes their contents
+type instanceExporter struct {
+	export func(eventstore.Event) error
+	counts map[eventstore.AggregateType]uint64
+	hasher *eventsHasher
+	events []eventstore.Event
+}
+
+func (e *instanceExporter) AppendEvents(events ...eventstore.Event) {
+	e.events = append(e.events, events...)
+}
+
+func (e *instanceExporter) Reduce() error {
+	for _, event := range e.events {
+		e.counts[event.Aggregate().Type]++
+		if err := e.hasher.Write(event); err != nil {
+			return err
+		}
+		if e.export == nil {
+			continue
+		}
+		if err := e.export(event); err != nil {
+			return err
+		}
+	}
+	e.events = e.events[:0]
+	return nil
+}
+
+func equalAggregateCounts(expected, actual map[eventstore.AggregateType]uint64) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	for typ, count := range expected {
+		if actual[typ] != count {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package command
+
+import (
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+)
+
+type instanceSealWriteModel struct {
+	eventstore.WriteModel
+
+	Sealed bool
+}
+
+func newInstanceSealWriteModel(instanceID string) *instanceSealWriteModel {
+	return &instanceSealWriteModel{
+		WriteModel: eventstore.WriteModel{
+			AggregateID:   instanceID,
+			ResourceOwner: instanceID,
+			InstanceID:    instanceID,
+		},
+	}
+}
+
+func (wm *instanceSealWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch event.(type) {
+		case *instance.InstanceSealedEvent:
+			wm.Sealed = true
+		case *instance.InstanceUnsealedEvent:
+			wm.Sealed = false
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *instanceSealWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		ResourceOwner(wm.ResourceOwner).
+		AddQuery().
+		AggregateTypes(instance.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(
+			instance.InstanceSealedEventType,
+			instance.InstanceUnsealedEventType,
+		).
+		Builder()
+}
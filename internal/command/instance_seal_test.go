@@ -0,0 +1,232 @@
+package command
+
+import (
+	"context"
+	stdcrypto "crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/eventstore/repository/mock"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestCommands_SealInstance(t *testing.T) {
+	aggregate := &instance.NewAggregate("INSTANCE").Aggregate
+	tests := []struct {
+		name       string
+		eventstore func(*testing.T) *eventstore.Eventstore
+		unseal     bool
+		want       *domain.ObjectDetails
+		wantErr    error
+	}{
+		{
+			name:       "sealing disabled, error",
+			eventstore: expectEventstore(),
+			wantErr:    zerrors.ThrowPreconditionFailed(nil, "COMMAND-Sl5rd", "Errors.Instance.SealingDisabled"),
+		},
+		{
+			name: "seal",
+			eventstore: expectSealingEventstore(
+				expectFilter(),
+				// the seal of the instance is checked by the push
+				expectFilter(),
+				expectPush(instance.NewInstanceSealedEvent(context.Background(), aggregate)),
+			),
+			want: &domain.ObjectDetails{ResourceOwner: "INSTANCE"},
+		},
+		{
+			name: "seal sealed, error",
+			eventstore: expectSealingEventstore(
+				expectFilter(eventFromEventPusher(instance.NewInstanceSealedEvent(context.Background(), aggregate))),
+			),
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "COMMAND-Sl3ma", "Errors.Instance.Sealed"),
+		},
+		{
+			name: "seal unsealed",
+			eventstore: expectSealingEventstore(
+				expectFilter(
+					eventFromEventPusher(instance.NewInstanceSealedEvent(context.Background(), aggregate)),
+					eventFromEventPusher(instance.NewInstanceUnsealedEvent(context.Background(), aggregate)),
+				),
+				expectFilter(eventFromEventPusher(instance.NewInstanceUnsealedEvent(context.Background(), aggregate))),
+				expectPush(instance.NewInstanceSealedEvent(context.Background(), aggregate)),
+			),
+			want: &domain.ObjectDetails{ResourceOwner: "INSTANCE"},
+		},
+		{
+			name: "unseal",
+			eventstore: expectSealingEventstore(
+				expectFilter(eventFromEventPusher(instance.NewInstanceSealedEvent(context.Background(), aggregate))),
+				expectPush(instance.NewInstanceUnsealedEvent(context.Background(), aggregate)),
+			),
+			unseal: true,
+			want:   &domain.ObjectDetails{ResourceOwner: "INSTANCE"},
+		},
+		{
+			name: "unseal not sealed, error",
+			eventstore: expectSealingEventstore(
+				expectFilter(),
+			),
+			unseal:  true,
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "COMMAND-Sl8xn", "Errors.Instance.NotSealed"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.eventstore(t),
+			}
+			ctx := authz.WithInstanceID(context.Background(), "INSTANCE")
+			var (
+				got *domain.ObjectDetails
+				err error
+			)
+			if tt.unseal {
+				got, err = c.UnsealInstance(ctx)
+			} else {
+				got, err = c.SealInstance(ctx)
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCommands_ExportSealedInstance(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventstore func(*testing.T) *eventstore.Eventstore
+		wantErr    error
+	}{
+		{
+			name:       "sealing disabled, error",
+			eventstore: expectEventstore(),
+			wantErr:    zerrors.ThrowPreconditionFailed(nil, "COMMAND-Ex4rd", "Errors.Instance.SealingDisabled"),
+		},
+		{
+			name: "not sealed, error",
+			eventstore: expectSealingEventstore(
+				expectFilter(),
+			),
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "COMMAND-Ex2ko", "Errors.Instance.NotSealed"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.eventstore(t),
+			}
+			_, _, err := c.ExportSealedInstance(authz.WithInstanceID(context.Background(), "INSTANCE"), func(eventstore.Event) error { return nil })
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCommands_ImportInstance_changed(t *testing.T) {
+	privateKey, publicKey, err := crypto.GenerateKeyPair(1024)
+	require.NoError(t, err)
+	aggregate := &instance.NewAggregate("INSTANCE").Aggregate
+	events := func() []eventstore.Event {
+		return []eventstore.Event{
+			eventFromEventPusherWithInstanceID("INSTANCE", instance.NewInstanceSealedEvent(context.Background(), aggregate)),
+		}
+	}
+	state := func() *eventstore.InstanceState {
+		return &eventstore.InstanceState{
+			UniqueConstraints: []*eventstore.UniqueConstraint{
+				instance.NewAddInstanceDomainUniqueConstraint("instance.domain"),
+			},
+		}
+	}
+	hasher := newEventsHasher()
+	for _, event := range events() {
+		require.NoError(t, hasher.Write(event))
+	}
+	stateHash, err := hashInstanceState(state())
+	require.NoError(t, err)
+	manifest := &ExportManifest{
+		InstanceID:      "INSTANCE",
+		AggregateCounts: map[eventstore.AggregateType]uint64{instance.AggregateType: 1},
+		EventsHash:      hasher.Sum(),
+		StateHash:       stateHash,
+	}
+	digest, err := manifest.digest()
+	require.NoError(t, err)
+	manifest.Signature, err = rsa.SignPKCS1v15(rand.Reader, privateKey, stdcrypto.SHA256, digest)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		state   *eventstore.InstanceState
+		events  []eventstore.Event
+		wantErr error
+	}{
+		{
+			name:    "state missing, error",
+			events:  events(),
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-Im2ba", "Errors.Invalid.Argument"),
+		},
+		{
+			name:  "events changed, error",
+			state: state(),
+			events: func() []eventstore.Event {
+				changed := events()
+				changed[0].(*repository.Event).Data = []byte(`{"changed":true}`)
+				return changed
+			}(),
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "COMMAND-Im3hv", "Errors.Instance.ImportIncomplete"),
+		},
+		{
+			name: "state changed, error",
+			state: func() *eventstore.InstanceState {
+				changed := state()
+				changed.UniqueConstraints = append(changed.UniqueConstraints, instance.NewAddInstanceDomainUniqueConstraint("other.domain"))
+				return changed
+			}(),
+			events:  events(),
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "COMMAND-Im6sh", "Errors.Instance.ImportIncomplete"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: expectEventstore()(t),
+			}
+			err := c.ImportInstance(context.Background(), manifest, publicKey, tt.state, tt.events)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+// expectSealingEventstore is like [expectEventstore] but the eventstore rejects pushes to sealed instances
+func expectSealingEventstore(expects ...expect) func(*testing.T) *eventstore.Eventstore {
+	return func(t *testing.T) *eventstore.Eventstore {
+		m := mock.NewRepo(t)
+		for _, e := range expects {
+			e(m)
+		}
+		return eventstore.NewEventstore(
+			&eventstore.Config{
+				Querier:               m.MockQuerier,
+				Pusher:                m.MockPusher,
+				RejectSealedInstances: true,
+			},
+		)
+	}
+}
+
+func Test_equalAggregateCounts(t *testing.T) {
+	assert.True(t, equalAggregateCounts(map[eventstore.AggregateType]uint64{"user": 2}, map[eventstore.AggregateType]uint64{"user": 2}))
+	assert.False(t, equalAggregateCounts(map[eventstore.AggregateType]uint64{"user": 2}, map[eventstore.AggregateType]uint64{"user": 1}))
+	assert.False(t, equalAggregateCounts(map[eventstore.AggregateType]uint64{"user": 2}, map[eventstore.AggregateType]uint64{"user": 2, "org": 1}))
+}
@@ -202,7 +202,7 @@ func instancePoliciesEvents(ctx context.Context, instanceID string) []eventstore
 	instanceAgg := instance.NewAggregate(instanceID)
 	return []eventstore.Command{
 		instance.NewPasswordComplexityPolicyAddedEvent(ctx, &instanceAgg.Aggregate, 8, true, true, true, true),
-		instance.NewPasswordAgePolicyAddedEvent(ctx, &instanceAgg.Aggregate, 0, 0),
+		instance.NewPasswordAgePolicyAddedEvent(ctx, &instanceAgg.Aggregate, 0, 0, 0),
 		instance.NewDomainPolicyAddedEvent(ctx, &instanceAgg.Aggregate, false, false, false),
 		instance.NewLoginPolicyAddedEvent(ctx, &instanceAgg.Aggregate, true, true, true, false, false, false, false, true, false, false, domain.PasswordlessTypeAllowed, "", 240*time.Hour, 240*time.Hour, 720*time.Hour, 18*time.Hour, 12*time.Hour),
 		instance.NewLoginPolicySecondFactorAddedEvent(ctx, &instanceAgg.Aggregate, domain.SecondFactorTypeTOTP),
@@ -228,7 +228,8 @@ func instanceSetupPoliciesConfig() *InstanceSetup {
 		PasswordAgePolicy: struct {
 			ExpireWarnDays uint64
 			MaxAgeDays     uint64
-		}{0, 0},
+			HistoryCount   uint64
+		}{0, 0, 0},
 		DomainPolicy: struct {
 			UserLoginMustBeDomain                  bool
 			ValidateOrgDomains                     bool
@@ -545,6 +546,15 @@ func adminMemberFilters(orgID, userID string) []expect {
 			addHumanEvent(context.Background(), orgID, userID),
 		),
 		expectFilter(),
+		expectFilter(
+			org.NewDomainPolicyAddedEvent(
+				context.Background(),
+				&org.NewAggregate(orgID).Aggregate,
+				true,
+				true,
+				true,
+			),
+		),
 		expectFilter(
 			addHumanEvent(context.Background(), orgID, userID),
 		),
@@ -12,6 +12,8 @@ import (
 	"github.com/zitadel/zitadel/internal/crypto"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/repository/keypair"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
 func (c *Commands) GenerateSigningKeyPair(ctx context.Context, algorithm string) error {
@@ -39,6 +41,76 @@ func (c *Commands) GenerateSigningKeyPair(ctx context.Context, algorithm string)
 	return err
 }
 
+// PregenerateSigningKeyPair generates the next signing key pair if the active one expires within ahead.
+// The new key pair is only used for signing once the active one expires,
+// its public key is already published to allow relying parties to pick it up before the switchover.
+// It returns true if a key pair was generated.
+func (c *Commands) PregenerateSigningKeyPair(ctx context.Context, algorithm string, ahead time.Duration) (generated bool, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	writeModel := NewSigningKeyPairsWriteModel(authz.GetInstance(ctx).InstanceID())
+	if err = c.eventstore.FilterToQueryReducer(ctx, writeModel); err != nil {
+		return false, err
+	}
+	now := time.Now().UTC()
+	active := writeModel.activeKey(now)
+	// without an active key, a new one is generated on demand
+	if active == nil || writeModel.hasPendingKey(active, now) || active.PrivateExpiry.After(now.Add(ahead)) {
+		return false, nil
+	}
+	privateCrypto, publicCrypto, err := crypto.GenerateEncryptedKeyPair(c.keySize, c.keyAlgorithm)
+	if err != nil {
+		return false, err
+	}
+	keyID, err := c.idGenerator.Next()
+	if err != nil {
+		return false, err
+	}
+
+	switchover := active.PrivateExpiry
+	keyPairWriteModel := NewKeyPairWriteModel(keyID, authz.GetInstance(ctx).InstanceID())
+	keyAgg := KeyPairAggregateFromWriteModel(&keyPairWriteModel.WriteModel)
+	_, err = c.eventstore.Push(ctx, keypair.NewPendingAddedEvent(
+		ctx,
+		keyAgg,
+		domain.KeyUsageSigning,
+		algorithm,
+		privateCrypto, publicCrypto,
+		switchover.Add(c.privateKeyLifetime), switchover.Add(c.publicKeyLifetime),
+		switchover))
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RevokeKeyPair immediately invalidates the key pair of the instance, e.g. if its private key was compromised.
+// Other than the expiry of a key pair, which is part of the scheduled rotation,
+// the keys are removed from the signing and the published keys right away.
+func (c *Commands) RevokeKeyPair(ctx context.Context, keyID string) (_ *domain.ObjectDetails, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if keyID == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Rk2vq", "Errors.IDMissing")
+	}
+	writeModel := NewKeyPairWriteModel(keyID, authz.GetInstance(ctx).InstanceID())
+	if err = c.eventstore.FilterToQueryReducer(ctx, writeModel); err != nil {
+		return nil, err
+	}
+	if writeModel.PublicKey == nil {
+		return nil, zerrors.ThrowNotFound(nil, "COMMAND-Rk3wr", "Errors.Key.NotFound")
+	}
+	if writeModel.Revoked {
+		return writeModelToObjectDetails(&writeModel.WriteModel), nil
+	}
+	if err = c.pushAppendAndReduce(ctx, writeModel, keypair.NewRevokedEvent(ctx, KeyPairAggregateFromWriteModel(&writeModel.WriteModel))); err != nil {
+		return nil, err
+	}
+	return writeModelToObjectDetails(&writeModel.WriteModel), nil
+}
+
 func (c *Commands) GenerateSAMLCACertificate(ctx context.Context, algorithm string) error {
 	now := time.Now().UTC()
 	after := now.Add(c.certificateLifetime)
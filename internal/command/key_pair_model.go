@@ -1,6 +1,10 @@
 package command
 
 import (
+	"slices"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/crypto"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/repository/keypair"
@@ -14,6 +18,10 @@ type KeyPairWriteModel struct {
 	PrivateKey  *domain.Key
 	PublicKey   *domain.Key
 	Certificate *domain.Key
+	// Expired is set if the key pair was expired before the expiry of its keys
+	Expired bool
+	// Revoked is set if the key pair must not be used anymore, e.g. because it was compromised
+	Revoked bool
 }
 
 func NewKeyPairWriteModel(aggregateID, resourceOwner string) *KeyPairWriteModel {
@@ -48,6 +56,15 @@ func (wm *KeyPairWriteModel) Reduce() error {
 				Key:    e.Certificate.Key,
 				Expiry: e.Certificate.Expiry,
 			}
+		case *keypair.ExpiredEvent:
+			wm.Expired = true
+			for _, key := range []*domain.Key{wm.PrivateKey, wm.PublicKey, wm.Certificate} {
+				if key != nil && key.Expiry.After(e.CreatedAt()) {
+					key.Expiry = e.CreatedAt()
+				}
+			}
+		case *keypair.RevokedEvent:
+			wm.Revoked = true
 		}
 	}
 	return wm.WriteModel.Reduce()
@@ -59,10 +76,100 @@ func (wm *KeyPairWriteModel) Query() *eventstore.SearchQueryBuilder {
 		AddQuery().
 		AggregateTypes(keypair.AggregateType).
 		AggregateIDs(wm.AggregateID).
-		EventTypes(keypair.AddedEventType, keypair.AddedCertificateEventType).
+		EventTypes(keypair.AddedEventType, keypair.AddedCertificateEventType, keypair.ExpiredEventType, keypair.RevokedEventType).
 		Builder()
 }
 
 func KeyPairAggregateFromWriteModel(wm *eventstore.WriteModel) *eventstore.Aggregate {
 	return eventstore.AggregateFromWriteModel(wm, keypair.AggregateType, keypair.AggregateVersion)
 }
+
+// SigningKeyPairsWriteModel lists the signing key pairs of an instance
+type SigningKeyPairsWriteModel struct {
+	eventstore.WriteModel
+
+	Keys []*signingKeyPair
+}
+
+type signingKeyPair struct {
+	ID            string
+	PrivateKey    *crypto.CryptoValue
+	PrivateExpiry time.Time
+	NotBefore     time.Time
+}
+
+func NewSigningKeyPairsWriteModel(instanceID string) *SigningKeyPairsWriteModel {
+	return &SigningKeyPairsWriteModel{
+		WriteModel: eventstore.WriteModel{
+			ResourceOwner: instanceID,
+			InstanceID:    instanceID,
+		},
+	}
+}
+
+func (wm *SigningKeyPairsWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch e := event.(type) {
+		case *keypair.AddedEvent:
+			if e.Usage != domain.KeyUsageSigning || e.PrivateKey == nil {
+				continue
+			}
+			key := &signingKeyPair{
+				ID:            e.Aggregate().ID,
+				PrivateKey:    e.PrivateKey.Key,
+				PrivateExpiry: e.PrivateKey.Expiry,
+			}
+			if e.NotBefore != nil {
+				key.NotBefore = *e.NotBefore
+			}
+			wm.Keys = append(wm.Keys, key)
+		case *keypair.ExpiredEvent:
+			// the key pair expired before the expiry of its keys
+			for _, key := range wm.Keys {
+				if key.ID == e.Aggregate().ID && key.PrivateExpiry.After(e.CreatedAt()) {
+					key.PrivateExpiry = e.CreatedAt()
+				}
+			}
+		case *keypair.RevokedEvent:
+			// revoked keys are never active again
+			wm.Keys = slices.DeleteFunc(wm.Keys, func(key *signingKeyPair) bool {
+				return key.ID == e.Aggregate().ID
+			})
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *SigningKeyPairsWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		ResourceOwner(wm.ResourceOwner).
+		AddQuery().
+		AggregateTypes(keypair.AggregateType).
+		EventTypes(keypair.AddedEventType, keypair.ExpiredEventType, keypair.RevokedEventType).
+		Builder()
+}
+
+// activeKey returns the key used for signing at the time,
+// which is the one expiring last of all keys already switched over to
+func (wm *SigningKeyPairsWriteModel) activeKey(now time.Time) *signingKeyPair {
+	var active *signingKeyPair
+	for _, key := range wm.Keys {
+		if !key.PrivateExpiry.After(now) || key.NotBefore.After(now) {
+			continue
+		}
+		if active == nil || key.PrivateExpiry.After(active.PrivateExpiry) {
+			active = key
+		}
+	}
+	return active
+}
+
+// hasPendingKey returns true if a key will take over after the active key
+func (wm *SigningKeyPairsWriteModel) hasPendingKey(active *signingKeyPair, now time.Time) bool {
+	for _, key := range wm.Keys {
+		if key.NotBefore.After(now) || key.PrivateExpiry.After(active.PrivateExpiry) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,256 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/id"
+	"github.com/zitadel/zitadel/internal/id/mock"
+	"github.com/zitadel/zitadel/internal/repository/keypair"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestCommands_PregenerateSigningKeyPair(t *testing.T) {
+	now := time.Now().UTC()
+	signingKeyAdded := func(keyID string, expiry time.Time) eventstore.Event {
+		return eventFromEventPusher(keypair.NewAddedEvent(context.Background(),
+			eventstore.NewAggregate(authz.WithInstanceID(context.Background(), "instance1"), keyID, keypair.AggregateType, keypair.AggregateVersion),
+			domain.KeyUsageSigning, "RS256", &crypto.CryptoValue{}, &crypto.CryptoValue{}, expiry, expiry,
+		))
+	}
+	pendingKeyAdded := func(keyID string, notBefore, expiry time.Time) eventstore.Event {
+		return eventFromEventPusher(keypair.NewPendingAddedEvent(context.Background(),
+			eventstore.NewAggregate(authz.WithInstanceID(context.Background(), "instance1"), keyID, keypair.AggregateType, keypair.AggregateVersion),
+			domain.KeyUsageSigning, "RS256", &crypto.CryptoValue{}, &crypto.CryptoValue{}, expiry, expiry, notBefore,
+		))
+	}
+	type fields struct {
+		eventstore  func(*testing.T) *eventstore.Eventstore
+		idGenerator id.Generator
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		ahead   time.Duration
+		want    bool
+		wantErr error
+	}{
+		{
+			name: "no active key, not generated",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						signingKeyAdded("key1", now.Add(-time.Hour)),
+					),
+				),
+			},
+			ahead: time.Hour,
+		},
+		{
+			name: "active key not expiring soon, not generated",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						signingKeyAdded("key1", now.Add(5*time.Hour)),
+					),
+				),
+			},
+			ahead: time.Hour,
+		},
+		{
+			name: "pending key exists, not generated",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						signingKeyAdded("key1", now.Add(30*time.Minute)),
+						pendingKeyAdded("key2", now.Add(30*time.Minute), now.Add(6*time.Hour)),
+					),
+				),
+			},
+			ahead: time.Hour,
+		},
+		{
+			name: "active key expiring soon, id generator fails",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						signingKeyAdded("key1", now.Add(30*time.Minute)),
+					),
+				),
+				idGenerator: mock.NewIDGeneratorExpectError(t, zerrors.ThrowInternal(nil, "id", "generator failed")),
+			},
+			ahead:   time.Hour,
+			wantErr: zerrors.ThrowInternal(nil, "id", "generator failed"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore:   tt.fields.eventstore(t),
+				idGenerator:  tt.fields.idGenerator,
+				keySize:      1024,
+				keyAlgorithm: crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+			}
+			got, err := c.PregenerateSigningKeyPair(authz.WithInstanceID(context.Background(), "instance1"), "RS256", tt.ahead)
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSigningKeyPairsWriteModel_activeKey(t *testing.T) {
+	now := time.Now()
+	wm := &SigningKeyPairsWriteModel{
+		Keys: []*signingKeyPair{
+			{ID: "expired", PrivateExpiry: now.Add(-time.Hour)},
+			{ID: "current", PrivateExpiry: now.Add(time.Hour)},
+			{ID: "older", PrivateExpiry: now.Add(30 * time.Minute)},
+			{ID: "pending", PrivateExpiry: now.Add(6 * time.Hour), NotBefore: now.Add(time.Hour)},
+		},
+	}
+	active := wm.activeKey(now)
+	require.NotNil(t, active)
+	assert.Equal(t, "current", active.ID)
+	assert.True(t, wm.hasPendingKey(active, now))
+
+	active = wm.activeKey(now.Add(2 * time.Hour))
+	require.NotNil(t, active)
+	assert.Equal(t, "pending", active.ID)
+}
+
+func TestKeyPairWriteModel_Reduce_expired(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	agg := eventstore.NewAggregate(authz.WithInstanceID(ctx, "instance-id"), "key-id", keypair.AggregateType, keypair.AggregateVersion)
+	expired := keypair.NewExpiredEvent(ctx, agg)
+	expired.Creation = now
+
+	wm := NewKeyPairWriteModel("key-id", "instance-id")
+	wm.AppendEvents(
+		keypair.NewAddedEvent(ctx, agg, domain.KeyUsageSigning, "RS256", nil, nil, now.Add(time.Hour), now.Add(2*time.Hour)),
+		expired,
+	)
+	require.NoError(t, wm.Reduce())
+	assert.True(t, wm.Expired)
+	assert.Equal(t, domain.KeyUsageSigning, wm.Usage)
+	assert.Equal(t, "RS256", wm.Algorithm)
+	assert.Equal(t, now, wm.PrivateKey.Expiry)
+	assert.Equal(t, now, wm.PublicKey.Expiry)
+}
+
+func TestCommands_RevokeKeyPair(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance1")
+	agg := eventstore.NewAggregate(ctx, "key1", keypair.AggregateType, keypair.AggregateVersion, eventstore.WithResourceOwner("instance1"))
+	keyAdded := eventFromEventPusher(keypair.NewAddedEvent(ctx, agg,
+		domain.KeyUsageSigning, "RS256", &crypto.CryptoValue{}, &crypto.CryptoValue{}, time.Now().Add(time.Hour), time.Now().Add(time.Hour),
+	))
+	tests := []struct {
+		name       string
+		eventstore func(*testing.T) *eventstore.Eventstore
+		keyID      string
+		want       *domain.ObjectDetails
+		wantErr    error
+	}{
+		{
+			name:       "missing id",
+			eventstore: expectEventstore(),
+			wantErr:    zerrors.ThrowInvalidArgument(nil, "COMMAND-Rk2vq", "Errors.IDMissing"),
+		},
+		{
+			name: "not found",
+			eventstore: expectEventstore(
+				expectFilter(),
+			),
+			keyID:   "key1",
+			wantErr: zerrors.ThrowNotFound(nil, "COMMAND-Rk3wr", "Errors.Key.NotFound"),
+		},
+		{
+			name: "already revoked",
+			eventstore: expectEventstore(
+				expectFilter(
+					keyAdded,
+					eventFromEventPusher(keypair.NewRevokedEvent(ctx, agg)),
+				),
+			),
+			keyID: "key1",
+			want:  &domain.ObjectDetails{ResourceOwner: "instance1"},
+		},
+		{
+			name: "revoked",
+			eventstore: expectEventstore(
+				expectFilter(
+					keyAdded,
+				),
+				expectPush(
+					keypair.NewRevokedEvent(ctx, agg),
+				),
+			),
+			keyID: "key1",
+			want:  &domain.ObjectDetails{ResourceOwner: "instance1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.eventstore(t),
+			}
+			got, err := c.RevokeKeyPair(ctx, tt.keyID)
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSigningKeyPairsWriteModel_Reduce_revoked(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance1")
+	now := time.Now()
+	signingKeyAdded := func(keyID string, expiry time.Time) eventstore.Event {
+		return keypair.NewAddedEvent(ctx,
+			eventstore.NewAggregate(ctx, keyID, keypair.AggregateType, keypair.AggregateVersion),
+			domain.KeyUsageSigning, "RS256", &crypto.CryptoValue{}, &crypto.CryptoValue{}, expiry, expiry,
+		)
+	}
+	wm := NewSigningKeyPairsWriteModel("instance1")
+	wm.AppendEvents(
+		signingKeyAdded("key1", now.Add(time.Hour)),
+		signingKeyAdded("key2", now.Add(2*time.Hour)),
+		keypair.NewRevokedEvent(ctx, eventstore.NewAggregate(ctx, "key2", keypair.AggregateType, keypair.AggregateVersion)),
+	)
+	require.NoError(t, wm.Reduce())
+	active := wm.activeKey(now)
+	require.NotNil(t, active)
+	assert.Equal(t, "key1", active.ID)
+}
+
+func TestSigningKeyPairsWriteModel_Reduce_expired(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance1")
+	now := time.Now()
+	signingKeyAdded := func(keyID string, expiry time.Time) eventstore.Event {
+		return keypair.NewAddedEvent(ctx,
+			eventstore.NewAggregate(ctx, keyID, keypair.AggregateType, keypair.AggregateVersion),
+			domain.KeyUsageSigning, "RS256", &crypto.CryptoValue{}, &crypto.CryptoValue{}, expiry, expiry,
+		)
+	}
+	expired := keypair.NewExpiredEvent(ctx, eventstore.NewAggregate(ctx, "key2", keypair.AggregateType, keypair.AggregateVersion))
+	expired.Creation = now.Add(-time.Minute)
+
+	wm := NewSigningKeyPairsWriteModel("instance1")
+	wm.AppendEvents(
+		signingKeyAdded("key1", now.Add(time.Hour)),
+		signingKeyAdded("key2", now.Add(2*time.Hour)),
+		expired,
+	)
+	require.NoError(t, wm.Reduce())
+	active := wm.activeKey(now)
+	require.NotNil(t, active)
+	assert.Equal(t, "key1", active.ID)
+}
@@ -28,7 +28,7 @@ func (wm *limitsBulkWriteModel) addWriteModel(instanceID string) {
 
 func (wm *limitsBulkWriteModel) Query() *eventstore.SearchQueryBuilder {
 	query := eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
-		InstanceIDs(wm.filterInstanceIDs).
+		InstanceIDs(wm.filterInstanceIDs...).
 		AddQuery().
 		AggregateTypes(limits.AggregateType).
 		EventTypes(
@@ -109,6 +109,24 @@ func expectFilterError(err error) expect {
 	}
 }
 
+func expectCount(count uint64) expect {
+	return func(m *mock.MockRepository) {
+		m.ExpectCount(count)
+	}
+}
+
+func expectCountError(err error) expect {
+	return func(m *mock.MockRepository) {
+		m.ExpectCountError(err)
+	}
+}
+
+func expectLatestSequence(position float64) expect {
+	return func(m *mock.MockRepository) {
+		m.ExpectLatestSequence(position)
+	}
+}
+
 func expectFilterOrgDomainNotFound() expect {
 	return func(m *mock.MockRepository) {
 		m.ExpectFilterNoEventsNoError()
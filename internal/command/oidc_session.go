@@ -107,8 +107,16 @@ func (c *Commands) CreateOIDCSessionFromAuthRequest(ctx context.Context, authReq
 		sessionModel.UserAgent,
 	)
 
+	reason := domain.TokenReasonAuthRequest
+	var actor *domain.TokenActor
+	if sessionModel.IsImpersonated() {
+		// impersonated sessions are short-lived, so no refresh token is issued
+		reason, actor = domain.TokenReasonImpersonation, &domain.TokenActor{UserID: sessionModel.ImpersonatorID}
+		needRefreshToken = false
+		cmd.UserImpersonated(ctx, sessionModel.UserID, sessionModel.UserResourceOwner, authReqModel.ClientID, actor)
+	}
 	if authReqModel.ResponseType != domain.OIDCResponseTypeIDToken {
-		if err = cmd.AddAccessToken(ctx, authReqModel.Scope, sessionModel.UserID, sessionModel.UserResourceOwner, domain.TokenReasonAuthRequest, nil); err != nil {
+		if err = cmd.AddAccessToken(ctx, authReqModel.Scope, sessionModel.UserID, sessionModel.UserResourceOwner, reason, actor); err != nil {
 			return nil, "", err
 		}
 	}
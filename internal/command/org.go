@@ -101,7 +101,7 @@ func (c *orgSetupCommands) setupOrgAdmin(admin *OrgSetupAdmin, allowInitialMail
 	}
 	if admin.Human != nil {
 		admin.Human.ID = userID
-		c.validations = append(c.validations, c.commands.AddHumanCommand(admin.Human, c.aggregate.ID, c.commands.userPasswordHasher, c.commands.userEncryption, allowInitialMail))
+		c.validations = append(c.validations, c.commands.AddHumanCommand(admin.Human, c.aggregate.ID, c.commands.passwordHasher(), c.commands.userEncryption, allowInitialMail))
 	} else if admin.Machine != nil {
 		admin.Machine.Machine.AggregateID = userID
 		if err = c.setupOrgAdminMachine(c.aggregate, admin.Machine); err != nil {
@@ -232,6 +232,83 @@ func (c *Commands) SetUpOrg(ctx context.Context, o *OrgSetup, allowInitialMail b
 	return c.setUpOrgWithIDs(ctx, o, orgID, allowInitialMail, userIDs...)
 }
 
+// OrgSpec describes an organization created by [Commands.BulkCreateOrgs]
+type OrgSpec struct {
+	Name         string
+	CustomDomain string
+	// Members are existing users added as members of the organization,
+	// members without roles become owners
+	Members []OrgSpecMember
+}
+
+type OrgSpecMember struct {
+	UserID string
+	Roles  []string
+}
+
+// OrgResult is the outcome of an [OrgSpec], Err is set if the organization wasn't created
+type OrgResult struct {
+	Name    string
+	OrgID   string
+	Details *domain.ObjectDetails
+	Err     error
+}
+
+// BulkCreateOrgs creates an organization for each spec in the instance of the context.
+// Each organization is pushed separately, so a failing spec doesn't prevent the creation of the others.
+// The results are in the order of the specs.
+func (c *Commands) BulkCreateOrgs(ctx context.Context, specs []OrgSpec) (_ []OrgResult, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if authz.GetInstance(ctx).InstanceID() == "" {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "ORG-Qn5bv", "Errors.Instance.NotFound")
+	}
+	if len(specs) == 0 {
+		return nil, zerrors.ThrowInvalidArgument(nil, "ORG-Dv3ll", "Errors.Invalid.Argument")
+	}
+	results := make([]OrgResult, len(specs))
+	names := make(map[string]struct{}, len(specs))
+	for i, spec := range specs {
+		results[i].Name = strings.TrimSpace(spec.Name)
+		if !domain.IsValidOrgName(results[i].Name) {
+			results[i].Err = zerrors.ThrowInvalidArgument(nil, "ORG-Kv7rb", "Errors.Org.Invalid")
+			continue
+		}
+		name := strings.ToLower(results[i].Name)
+		if _, ok := names[name]; ok {
+			results[i].Err = zerrors.ThrowAlreadyExists(nil, "ORG-Gm8ts", "Errors.Org.AlreadyExisting")
+			continue
+		}
+		names[name] = struct{}{}
+		results[i].OrgID, results[i].Details, results[i].Err = c.createOrgFromSpec(ctx, spec)
+	}
+	return results, nil
+}
+
+func (c *Commands) createOrgFromSpec(ctx context.Context, spec OrgSpec) (orgID string, _ *domain.ObjectDetails, err error) {
+	orgID, err = c.idGenerator.Next()
+	if err != nil {
+		return "", nil, err
+	}
+	setup := &OrgSetup{
+		Name:         spec.Name,
+		CustomDomain: spec.CustomDomain,
+		Admins:       make([]*OrgSetupAdmin, len(spec.Members)),
+	}
+	for i, member := range spec.Members {
+		if member.UserID == "" {
+			return "", nil, zerrors.ThrowInvalidArgument(nil, "ORG-Zl2wc", "Errors.User.UserIDMissing")
+		}
+		setup.Admins[i] = &OrgSetupAdmin{ID: member.UserID, Roles: member.Roles}
+	}
+	createdOrg, err := c.setUpOrgWithIDs(ctx, setup, orgID, false)
+	if err != nil {
+		return "", nil, err
+	}
+	return orgID, createdOrg.ObjectDetails, nil
+}
+
 // AddOrgCommand defines the commands to create a new org,
 // this includes the verified default domain
 func AddOrgCommand(ctx context.Context, a *org.Aggregate, name string) preparation.Validation {
@@ -239,6 +316,9 @@ func AddOrgCommand(ctx context.Context, a *org.Aggregate, name string) preparati
 		if name = strings.TrimSpace(name); name == "" {
 			return nil, zerrors.ThrowInvalidArgument(nil, "ORG-mruNY", "Errors.Invalid.Argument")
 		}
+		if !domain.IsValidOrgName(name) {
+			return nil, zerrors.ThrowInvalidArgument(nil, "ORG-Kv9qe", "Errors.Org.Invalid")
+		}
 		defaultDomain, err := domain.NewIAMDomainName(name, authz.GetInstance(ctx).RequestedDomain())
 		if err != nil {
 			return nil, err
@@ -292,7 +372,7 @@ func (c *Commands) AddOrgWithID(ctx context.Context, name, userID, resourceOwner
 }
 
 func (c *Commands) AddOrg(ctx context.Context, name, userID, resourceOwner string, claimedUserIDs []string) (*domain.Org, error) {
-	if name = strings.TrimSpace(name); name == "" {
+	if name = strings.TrimSpace(name); !domain.IsValidOrgName(name) {
 		return nil, zerrors.ThrowInvalidArgument(nil, "EVENT-Mf9sd", "Errors.Org.Invalid")
 	}
 
@@ -338,28 +418,54 @@ func (c *Commands) ChangeOrg(ctx context.Context, orgID, name string) (*domain.O
 	if orgID == "" || name == "" {
 		return nil, zerrors.ThrowInvalidArgument(nil, "EVENT-Mf9sd", "Errors.Org.Invalid")
 	}
+	if !domain.IsValidOrgName(name) {
+		return nil, zerrors.ThrowInvalidArgument(nil, "ORG-Kv8ny", "Errors.Org.Invalid")
+	}
 
+	var orgWriteModel *OrgWriteModel
+	// the default domain is derived from the name, a concurrent rename must be taken into account
+	err := c.pushAppendAndReduceRetry(ctx, func() (_ AppendReducer, _ []eventstore.Command, err error) {
+		orgWriteModel, err = c.getOrgWriteModelByID(ctx, orgID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !isOrgStateExists(orgWriteModel.State) {
+			return nil, nil, zerrors.ThrowNotFound(nil, "ORG-1MRds", "Errors.Org.NotFound")
+		}
+		if orgWriteModel.Name == name {
+			return nil, nil, zerrors.ThrowPreconditionFailed(nil, "ORG-4VSdf", "Errors.Org.NotChanged")
+		}
+		orgAgg := OrgAggregateFromWriteModel(&orgWriteModel.WriteModel)
+		events := make([]eventstore.Command, 0)
+		events = append(events, org.NewOrgChangedEvent(ctx, orgAgg, orgWriteModel.Name, name))
+		changeDomainEvents, err := c.changeDefaultDomain(ctx, orgID, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(changeDomainEvents) > 0 {
+			events = append(events, changeDomainEvents...)
+		}
+		return orgWriteModel, events, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return writeModelToObjectDetails(&orgWriteModel.WriteModel), nil
+}
+
+func (c *Commands) DeactivateOrg(ctx context.Context, orgID string) (*domain.ObjectDetails, error) {
 	orgWriteModel, err := c.getOrgWriteModelByID(ctx, orgID)
 	if err != nil {
 		return nil, err
 	}
 	if !isOrgStateExists(orgWriteModel.State) {
-		return nil, zerrors.ThrowNotFound(nil, "ORG-1MRds", "Errors.Org.NotFound")
+		return nil, zerrors.ThrowNotFound(nil, "ORG-oL9nT", "Errors.Org.NotFound")
 	}
-	if orgWriteModel.Name == name {
-		return nil, zerrors.ThrowPreconditionFailed(nil, "ORG-4VSdf", "Errors.Org.NotChanged")
+	if orgWriteModel.State == domain.OrgStateInactive {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "EVENT-Dbs2g", "Errors.Org.AlreadyDeactivated")
 	}
 	orgAgg := OrgAggregateFromWriteModel(&orgWriteModel.WriteModel)
-	events := make([]eventstore.Command, 0)
-	events = append(events, org.NewOrgChangedEvent(ctx, orgAgg, orgWriteModel.Name, name))
-	changeDomainEvents, err := c.changeDefaultDomain(ctx, orgID, name)
-	if err != nil {
-		return nil, err
-	}
-	if len(changeDomainEvents) > 0 {
-		events = append(events, changeDomainEvents...)
-	}
-	pushedEvents, err := c.eventstore.Push(ctx, events...)
+	pushedEvents, err := c.eventstore.Push(ctx, org.NewOrgDeactivatedEvent(ctx, orgAgg))
 	if err != nil {
 		return nil, err
 	}
@@ -370,19 +476,32 @@ func (c *Commands) ChangeOrg(ctx context.Context, orgID, name string) (*domain.O
 	return writeModelToObjectDetails(&orgWriteModel.WriteModel), nil
 }
 
-func (c *Commands) DeactivateOrg(ctx context.Context, orgID string) (*domain.ObjectDetails, error) {
+// SuspendOrg deactivates the org.
+// If deactivateMembers is set, all active memberships of the org are deactivated in the same transaction.
+// The memberships are activated again by [Commands.ReactivateOrg].
+func (c *Commands) SuspendOrg(ctx context.Context, orgID string, deactivateMembers bool) (*domain.ObjectDetails, error) {
 	orgWriteModel, err := c.getOrgWriteModelByID(ctx, orgID)
 	if err != nil {
 		return nil, err
 	}
 	if !isOrgStateExists(orgWriteModel.State) {
-		return nil, zerrors.ThrowNotFound(nil, "ORG-oL9nT", "Errors.Org.NotFound")
+		return nil, zerrors.ThrowNotFound(nil, "ORG-Qf5ns", "Errors.Org.NotFound")
 	}
 	if orgWriteModel.State == domain.OrgStateInactive {
-		return nil, zerrors.ThrowPreconditionFailed(nil, "EVENT-Dbs2g", "Errors.Org.AlreadyDeactivated")
+		return nil, zerrors.ThrowPreconditionFailed(nil, "ORG-Rk3vd", "Errors.Org.AlreadyDeactivated")
 	}
 	orgAgg := OrgAggregateFromWriteModel(&orgWriteModel.WriteModel)
-	pushedEvents, err := c.eventstore.Push(ctx, org.NewOrgDeactivatedEvent(ctx, orgAgg))
+	events := []eventstore.Command{org.NewOrgDeactivatedEvent(ctx, orgAgg)}
+	if deactivateMembers {
+		membersWriteModel := NewOrgMembersStateWriteModel(orgID)
+		if err = c.eventstore.FilterToQueryReducer(ctx, membersWriteModel); err != nil {
+			return nil, err
+		}
+		for _, userID := range membersWriteModel.MembersInState(domain.MemberStateActive) {
+			events = append(events, org.NewMemberDeactivatedEvent(ctx, orgAgg, userID))
+		}
+	}
+	pushedEvents, err := c.eventstore.Push(ctx, events...)
 	if err != nil {
 		return nil, err
 	}
@@ -393,6 +512,8 @@ func (c *Commands) DeactivateOrg(ctx context.Context, orgID string) (*domain.Obj
 	return writeModelToObjectDetails(&orgWriteModel.WriteModel), nil
 }
 
+// ReactivateOrg activates the org again.
+// Memberships deactivated by [Commands.SuspendOrg] are activated in the same transaction.
 func (c *Commands) ReactivateOrg(ctx context.Context, orgID string) (*domain.ObjectDetails, error) {
 	orgWriteModel, err := c.getOrgWriteModelByID(ctx, orgID)
 	if err != nil {
@@ -405,7 +526,15 @@ func (c *Commands) ReactivateOrg(ctx context.Context, orgID string) (*domain.Obj
 		return nil, zerrors.ThrowPreconditionFailed(nil, "EVENT-bfnrh", "Errors.Org.AlreadyActive")
 	}
 	orgAgg := OrgAggregateFromWriteModel(&orgWriteModel.WriteModel)
-	pushedEvents, err := c.eventstore.Push(ctx, org.NewOrgReactivatedEvent(ctx, orgAgg))
+	membersWriteModel := NewOrgMembersStateWriteModel(orgID)
+	if err = c.eventstore.FilterToQueryReducer(ctx, membersWriteModel); err != nil {
+		return nil, err
+	}
+	events := []eventstore.Command{org.NewOrgReactivatedEvent(ctx, orgAgg)}
+	for _, userID := range membersWriteModel.MembersInState(domain.MemberStateInactive) {
+		events = append(events, org.NewMemberReactivatedEvent(ctx, orgAgg, userID))
+	}
+	pushedEvents, err := c.eventstore.Push(ctx, events...)
 	if err != nil {
 		return nil, err
 	}
@@ -481,7 +610,11 @@ func (c *Commands) prepareRemoveOrg(a *org.Aggregate) preparation.Validation {
 			if err != nil {
 				return nil, err
 			}
-			return []eventstore.Command{org.NewOrgRemovedEvent(ctx, &a.Aggregate, writeModel.Name, usernames, domainPolicy.UserLoginMustBeDomain, domains, links, entityIds)}, nil
+			memberEmails := newOrgMemberUniqueEmailsWriteModel(a.ID)
+			if err = queryAndReduce(ctx, filter, memberEmails); err != nil {
+				return nil, err
+			}
+			return []eventstore.Command{org.NewOrgRemovedEvent(ctx, &a.Aggregate, writeModel.Name, usernames, domainPolicy.UserLoginMustBeDomain, domains, links, entityIds, memberEmails.UniqueEmails())}, nil
 		}, nil
 	}
 }
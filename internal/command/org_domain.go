@@ -200,7 +200,7 @@ func (c *Commands) ValidateOrgDomain(ctx context.Context, orgDomain *domain.OrgD
 		return nil, err
 	}
 	checkType, _ := domainWriteModel.ValidationType.CheckType()
-	err = c.domainVerificationValidator(domainWriteModel.Domain, validationCode, validationCode, checkType)
+	err = c.validateDomainVerification(domainWriteModel.Domain, validationCode, validationCode, checkType)
 	orgAgg := OrgAggregateFromWriteModel(&domainWriteModel.WriteModel)
 	var events []eventstore.Command
 	if err == nil {
@@ -232,6 +232,18 @@ func (c *Commands) ValidateOrgDomain(ctx context.Context, orgDomain *domain.OrgD
 	return nil, err
 }
 
+// validateDomainVerification validates the verification code of the domain with the validator of the check type
+func (c *Commands) validateDomainVerification(domain, token, verifier string, checkType http_utils.CheckType) error {
+	switch checkType {
+	case http_utils.CheckTypeHTTP:
+		return c.domainVerificationHTTPValidator(domain, token, verifier)
+	case http_utils.CheckTypeDNS:
+		return c.domainVerificationDNSValidator(domain, verifier)
+	default:
+		return zerrors.ThrowInvalidArgument(nil, "ORG-Dv4lk", "Errors.Org.DomainVerificationTypeInvalid")
+	}
+}
+
 func (c *Commands) SetPrimaryOrgDomain(ctx context.Context, orgDomain *domain.OrgDomain) (_ *domain.ObjectDetails, err error) {
 	ctx, span := tracing.NewSpan(ctx)
 	defer func() { span.EndWithError(err) }()
@@ -9,7 +9,6 @@ import (
 	"golang.org/x/text/language"
 
 	"github.com/zitadel/zitadel/internal/api/authz"
-	"github.com/zitadel/zitadel/internal/api/http"
 	"github.com/zitadel/zitadel/internal/command/preparation"
 	"github.com/zitadel/zitadel/internal/crypto"
 	"github.com/zitadel/zitadel/internal/domain"
@@ -673,11 +672,11 @@ func TestCommandSide_GenerateOrgDomainValidation(t *testing.T) {
 
 func TestCommandSide_ValidateOrgDomain(t *testing.T) {
 	type fields struct {
-		eventstore           *eventstore.Eventstore
-		idGenerator          id.Generator
-		secretGenerator      crypto.Generator
-		alg                  crypto.EncryptionAlgorithm
-		domainValidationFunc func(domain, token, verifier string, checkType http.CheckType) error
+		eventstore        *eventstore.Eventstore
+		idGenerator       id.Generator
+		secretGenerator   crypto.Generator
+		alg               crypto.EncryptionAlgorithm
+		dnsValidationFunc func(domain, verifier string) error
 	}
 	type args struct {
 		ctx            context.Context
@@ -874,8 +873,8 @@ func TestCommandSide_ValidateOrgDomain(t *testing.T) {
 						),
 					),
 				),
-				alg:                  crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
-				domainValidationFunc: invalidDomainVerification,
+				alg:               crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+				dnsValidationFunc: invalidDomainVerification,
 			},
 			args: args{
 				ctx: context.Background(),
@@ -930,8 +929,8 @@ func TestCommandSide_ValidateOrgDomain(t *testing.T) {
 						),
 					),
 				),
-				alg:                  crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
-				domainValidationFunc: validDomainVerification,
+				alg:               crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+				dnsValidationFunc: validDomainVerification,
 			},
 			args: args{
 				ctx: context.Background(),
@@ -989,8 +988,8 @@ func TestCommandSide_ValidateOrgDomain(t *testing.T) {
 						),
 					),
 				),
-				alg:                  crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
-				domainValidationFunc: validDomainVerification,
+				alg:               crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+				dnsValidationFunc: validDomainVerification,
 			},
 			args: args{
 				ctx: context.Background(),
@@ -1075,9 +1074,9 @@ func TestCommandSide_ValidateOrgDomain(t *testing.T) {
 						),
 					),
 				),
-				alg:                  crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
-				domainValidationFunc: validDomainVerification,
-				idGenerator:          id_mock.NewIDGeneratorExpectIDs(t, "tempid"),
+				alg:               crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+				dnsValidationFunc: validDomainVerification,
+				idGenerator:       id_mock.NewIDGeneratorExpectIDs(t, "tempid"),
 			},
 			args: args{
 				ctx: context.Background(),
@@ -1100,11 +1099,11 @@ func TestCommandSide_ValidateOrgDomain(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &Commands{
-				eventstore:                  tt.fields.eventstore,
-				domainVerificationGenerator: tt.fields.secretGenerator,
-				domainVerificationAlg:       tt.fields.alg,
-				domainVerificationValidator: tt.fields.domainValidationFunc,
-				idGenerator:                 tt.fields.idGenerator,
+				eventstore:                     tt.fields.eventstore,
+				domainVerificationGenerator:    tt.fields.secretGenerator,
+				domainVerificationAlg:          tt.fields.alg,
+				domainVerificationDNSValidator: tt.fields.dnsValidationFunc,
+				idGenerator:                    tt.fields.idGenerator,
 			}
 			got, err := r.ValidateOrgDomain(authz.WithRequestedDomain(tt.args.ctx, "zitadel.ch"), tt.args.domain, tt.args.claimedUserIDs)
 			if tt.res.err == nil {
@@ -1544,10 +1543,10 @@ func TestCommandSide_RemoveOrgDomain(t *testing.T) {
 	}
 }
 
-func invalidDomainVerification(domain, token, verifier string, checkType http.CheckType) error {
+func invalidDomainVerification(domain, verifier string) error {
 	return zerrors.ThrowInvalidArgument(nil, "HTTP-GH422", "Errors.Internal")
 }
 
-func validDomainVerification(domain, token, verifier string, checkType http.CheckType) error {
+func validDomainVerification(domain, verifier string) error {
 	return nil
 }
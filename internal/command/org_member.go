@@ -3,6 +3,8 @@ package command
 import (
 	"context"
 	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/zitadel/zitadel/internal/command/preparation"
 	"github.com/zitadel/zitadel/internal/domain"
@@ -34,7 +36,13 @@ func (c *Commands) AddOrgMemberCommand(a *org.Aggregate, userID string, roles ..
 				if isMember, err := IsOrgMember(ctx, filter, a.ID, userID); err != nil || isMember {
 					return nil, zerrors.ThrowAlreadyExists(err, "ORG-poWwe", "Errors.Org.Member.AlreadyExists")
 				}
-				return []eventstore.Command{org.NewMemberAddedEvent(ctx, &a.Aggregate, userID, roles...)}, nil
+				uniqueEmail, err := orgMemberUniqueEmail(ctx, filter, a.ID, userID, "")
+				if err != nil {
+					return nil, err
+				}
+				added := org.NewMemberAddedEvent(ctx, &a.Aggregate, userID, roles...)
+				added.UniqueEmail = uniqueEmail
+				return []eventstore.Command{added}, nil
 			},
 			nil
 	}
@@ -111,8 +119,13 @@ func (c *Commands) addOrgMember(ctx context.Context, orgAgg *eventstore.Aggregat
 	if addedMember.State == domain.MemberStateActive {
 		return nil, zerrors.ThrowAlreadyExists(nil, "Org-PtXi1", "Errors.Org.Member.AlreadyExists")
 	}
-
-	return org.NewMemberAddedEvent(ctx, orgAgg, member.UserID, member.Roles...), nil
+	uniqueEmail, err := orgMemberUniqueEmail(ctx, c.eventstore.Filter, orgAgg.ID, member.UserID, "") //nolint:staticcheck
+	if err != nil {
+		return nil, err
+	}
+	added := org.NewMemberAddedEvent(ctx, orgAgg, member.UserID, member.Roles...)
+	added.UniqueEmail = uniqueEmail
+	return added, nil
 }
 
 // ChangeOrgMember updates an existing member
@@ -145,6 +158,73 @@ func (c *Commands) ChangeOrgMember(ctx context.Context, member *domain.Member) (
 	return memberWriteModelToMember(&existingMember.MemberWriteModel), nil
 }
 
+// orgMemberUniqueEmail returns the email of the user to reserve as member of the organization,
+// it's empty if the domain policy of the organization doesn't require unique member emails or the user has no email.
+// If email is empty, the current email of the user is reserved.
+// The reservation is a unique constraint, so concurrently added members can't share an email.
+// Members added before the policy was enabled have no reservation, an error is returned if one of them has the same email.
+func orgMemberUniqueEmail(ctx context.Context, filter preparation.FilterToQueryReducer, orgID, userID string, email domain.EmailAddress) (_ string, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	policy, err := domainPolicyWriteModel(ctx, filter, orgID)
+	if err != nil {
+		return "", err
+	}
+	if !policy.MemberEmailsUnique {
+		return "", nil
+	}
+	members := newOrgMembersWriteModel(orgID)
+	if err = queryAndReduce(ctx, filter, members); err != nil {
+		return "", err
+	}
+	otherMembers := slices.DeleteFunc(members.UserIDs, func(memberID string) bool {
+		return memberID == userID
+	})
+	emails := newUserEmailsWriteModel(append(otherMembers, userID)...)
+	if err = queryAndReduce(ctx, filter, emails); err != nil {
+		return "", err
+	}
+	if email == "" {
+		email = emails.Emails[userID]
+	}
+	if email == "" {
+		return "", nil
+	}
+	for _, memberID := range otherMembers {
+		if memberEmail, ok := emails.Emails[memberID]; ok && strings.EqualFold(string(memberEmail), string(email)) {
+			return "", zerrors.ThrowAlreadyExists(nil, "ORG-Hc6nq", "Errors.Org.MemberEmailAlreadyExists")
+		}
+	}
+	return string(email), nil
+}
+
+// orgMemberEmailChangedEvents moves the emails the user reserved as member of organizations to the changed email.
+// The reservation is released if the domain policy of the organization doesn't require unique member emails anymore.
+// The events must be pushed together with the change of the email.
+func (c *Commands) orgMemberEmailChangedEvents(ctx context.Context, userID string, email domain.EmailAddress) (_ []eventstore.Command, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	reservations := newUserMemberUniqueEmailsWriteModel(userID)
+	if err = c.eventstore.FilterToQueryReducer(ctx, reservations); err != nil {
+		return nil, err
+	}
+	cmds := make([]eventstore.Command, 0, len(reservations.Emails))
+	for _, orgID := range reservations.OrgIDs() {
+		previousEmail := reservations.Emails[orgID]
+		if strings.EqualFold(previousEmail, string(email)) {
+			continue
+		}
+		uniqueEmail, err := orgMemberUniqueEmail(ctx, c.eventstore.Filter, orgID, userID, email) //nolint:staticcheck
+		if err != nil {
+			return nil, err
+		}
+		cmds = append(cmds, org.NewMemberEmailChangedEvent(ctx, &org.NewAggregate(orgID).Aggregate, userID, uniqueEmail, previousEmail))
+	}
+	return cmds, nil
+}
+
 func (c *Commands) RemoveOrgMember(ctx context.Context, orgID, userID string) (*domain.ObjectDetails, error) {
 	m, err := c.orgMemberWriteModelByID(ctx, orgID, userID)
 	if err != nil && !zerrors.IsNotFound(err) {
@@ -156,7 +236,7 @@ func (c *Commands) RemoveOrgMember(ctx context.Context, orgID, userID string) (*
 	}
 
 	orgAgg := OrgAggregateFromWriteModel(&m.MemberWriteModel.WriteModel)
-	removeEvent := c.removeOrgMember(ctx, orgAgg, userID, false)
+	removeEvent := c.removeOrgMember(ctx, orgAgg, userID, m.UniqueEmail, false)
 	pushedEvents, err := c.eventstore.Push(ctx, removeEvent)
 	if err != nil {
 		return nil, err
@@ -168,14 +248,18 @@ func (c *Commands) RemoveOrgMember(ctx context.Context, orgID, userID string) (*
 	return writeModelToObjectDetails(&m.WriteModel), nil
 }
 
-func (c *Commands) removeOrgMember(ctx context.Context, orgAgg *eventstore.Aggregate, userID string, cascade bool) eventstore.Command {
+func (c *Commands) removeOrgMember(ctx context.Context, orgAgg *eventstore.Aggregate, userID, uniqueEmail string, cascade bool) eventstore.Command {
 	if cascade {
-		return org.NewMemberCascadeRemovedEvent(
+		removed := org.NewMemberCascadeRemovedEvent(
 			ctx,
 			orgAgg,
 			userID)
+		removed.UniqueEmail = uniqueEmail
+		return removed
 	} else {
-		return org.NewMemberRemovedEvent(ctx, orgAgg, userID)
+		removed := org.NewMemberRemovedEvent(ctx, orgAgg, userID)
+		removed.UniqueEmail = uniqueEmail
+		return removed
 	}
 }
 
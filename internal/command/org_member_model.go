@@ -1,17 +1,25 @@
 package command
 
 import (
+	"slices"
+
+	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/query/projection"
 	"github.com/zitadel/zitadel/internal/repository/org"
+	"github.com/zitadel/zitadel/internal/repository/user"
 )
 
 type OrgMemberWriteModel struct {
 	MemberWriteModel
+
+	// UniqueEmail is the email reserved for the member in the org
+	UniqueEmail string
 }
 
 func NewOrgMemberWriteModel(orgID, userID string) *OrgMemberWriteModel {
 	return &OrgMemberWriteModel{
-		MemberWriteModel{
+		MemberWriteModel: MemberWriteModel{
 			WriteModel: eventstore.WriteModel{
 				AggregateID:   orgID,
 				ResourceOwner: orgID,
@@ -28,6 +36,7 @@ func (wm *OrgMemberWriteModel) AppendEvents(events ...eventstore.Event) {
 			if e.UserID != wm.MemberWriteModel.UserID {
 				continue
 			}
+			wm.UniqueEmail = e.UniqueEmail
 			wm.MemberWriteModel.AppendEvents(&e.MemberAddedEvent)
 		case *org.MemberChangedEvent:
 			if e.UserID != wm.MemberWriteModel.UserID {
@@ -38,12 +47,19 @@ func (wm *OrgMemberWriteModel) AppendEvents(events ...eventstore.Event) {
 			if e.UserID != wm.MemberWriteModel.UserID {
 				continue
 			}
+			wm.UniqueEmail = ""
 			wm.MemberWriteModel.AppendEvents(&e.MemberRemovedEvent)
 		case *org.MemberCascadeRemovedEvent:
 			if e.UserID != wm.MemberWriteModel.UserID {
 				continue
 			}
+			wm.UniqueEmail = ""
 			wm.MemberWriteModel.AppendEvents(&e.MemberCascadeRemovedEvent)
+		case *org.MemberEmailChangedEvent:
+			if e.UserID != wm.MemberWriteModel.UserID {
+				continue
+			}
+			wm.UniqueEmail = e.UniqueEmail
 		}
 	}
 }
@@ -62,6 +78,438 @@ func (wm *OrgMemberWriteModel) Query() *eventstore.SearchQueryBuilder {
 			org.MemberAddedEventType,
 			org.MemberChangedEventType,
 			org.MemberRemovedEventType,
+			org.MemberCascadeRemovedEventType,
+			org.MemberEmailChangedEventType).
+		Builder()
+}
+
+// OrgMembersStateWriteModel collects the state of all members of an org.
+// It's used to (de)activate all memberships of an org at once.
+type OrgMembersStateWriteModel struct {
+	eventstore.WriteModel
+
+	Members map[string]domain.MemberState
+}
+
+func NewOrgMembersStateWriteModel(orgID string) *OrgMembersStateWriteModel {
+	return &OrgMembersStateWriteModel{
+		WriteModel: eventstore.WriteModel{
+			AggregateID:   orgID,
+			ResourceOwner: orgID,
+		},
+		Members: make(map[string]domain.MemberState),
+	}
+}
+
+func (wm *OrgMembersStateWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch e := event.(type) {
+		case *org.MemberAddedEvent:
+			wm.Members[e.UserID] = domain.MemberStateActive
+		case *org.MemberRemovedEvent:
+			delete(wm.Members, e.UserID)
+		case *org.MemberCascadeRemovedEvent:
+			delete(wm.Members, e.UserID)
+		case *org.MemberDeactivatedEvent:
+			wm.Members[e.UserID] = domain.MemberStateInactive
+		case *org.MemberReactivatedEvent:
+			wm.Members[e.UserID] = domain.MemberStateActive
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *OrgMembersStateWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		ResourceOwner(wm.ResourceOwner).
+		AddQuery().
+		AggregateTypes(org.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(
+			org.MemberAddedEventType,
+			org.MemberRemovedEventType,
+			org.MemberCascadeRemovedEventType,
+			org.MemberDeactivatedEventType,
+			org.MemberReactivatedEventType).
+		Builder()
+}
+
+// MembersInState returns the ids of all members in the given state, sorted to keep the order of the events stable.
+func (wm *OrgMembersStateWriteModel) MembersInState(state domain.MemberState) []string {
+	userIDs := make([]string, 0, len(wm.Members))
+	for userID, memberState := range wm.Members {
+		if memberState == state {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	slices.Sort(userIDs)
+	return userIDs
+}
+
+// orgMembersWriteModel collects the user ids of the members of an organization
+type orgMembersWriteModel struct {
+	eventstore.WriteModel
+
+	UserIDs []string
+}
+
+func newOrgMembersWriteModel(orgID string) *orgMembersWriteModel {
+	return &orgMembersWriteModel{
+		WriteModel: eventstore.WriteModel{
+			AggregateID:   orgID,
+			ResourceOwner: orgID,
+		},
+	}
+}
+
+func (wm *orgMembersWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch e := event.(type) {
+		case *org.MemberAddedEvent:
+			wm.UserIDs = append(wm.UserIDs, e.UserID)
+		case *org.MemberRemovedEvent:
+			wm.removeUser(e.UserID)
+		case *org.MemberCascadeRemovedEvent:
+			wm.removeUser(e.UserID)
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *orgMembersWriteModel) removeUser(userID string) {
+	wm.UserIDs = slices.DeleteFunc(wm.UserIDs, func(id string) bool {
+		return id == userID
+	})
+}
+
+func (wm *orgMembersWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		ResourceOwner(wm.ResourceOwner).
+		AddQuery().
+		AggregateTypes(org.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(
+			org.MemberAddedEventType,
+			org.MemberRemovedEventType,
+			org.MemberCascadeRemovedEventType,
+		).
+		Builder()
+}
+
+// orgMemberUniqueEmailsWriteModel collects the emails reserved by the members of an organization
+type orgMemberUniqueEmailsWriteModel struct {
+	eventstore.WriteModel
+
+	Emails map[string]string
+}
+
+func newOrgMemberUniqueEmailsWriteModel(orgID string) *orgMemberUniqueEmailsWriteModel {
+	return &orgMemberUniqueEmailsWriteModel{
+		WriteModel: eventstore.WriteModel{
+			AggregateID:   orgID,
+			ResourceOwner: orgID,
+		},
+		Emails: make(map[string]string),
+	}
+}
+
+func (wm *orgMemberUniqueEmailsWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch e := event.(type) {
+		case *org.MemberAddedEvent:
+			wm.setEmail(e.UserID, e.UniqueEmail)
+		case *org.MemberEmailChangedEvent:
+			wm.setEmail(e.UserID, e.UniqueEmail)
+		case *org.MemberRemovedEvent:
+			delete(wm.Emails, e.UserID)
+		case *org.MemberCascadeRemovedEvent:
+			delete(wm.Emails, e.UserID)
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *orgMemberUniqueEmailsWriteModel) setEmail(userID, email string) {
+	if email == "" {
+		delete(wm.Emails, userID)
+		return
+	}
+	wm.Emails[userID] = email
+}
+
+func (wm *orgMemberUniqueEmailsWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		ResourceOwner(wm.ResourceOwner).
+		AddQuery().
+		AggregateTypes(org.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(
+			org.MemberAddedEventType,
+			org.MemberEmailChangedEventType,
+			org.MemberRemovedEventType,
+			org.MemberCascadeRemovedEventType,
+		).
+		Builder()
+}
+
+// UniqueEmails returns the reserved emails, sorted to keep the order of the unique constraints stable.
+func (wm *orgMemberUniqueEmailsWriteModel) UniqueEmails() []string {
+	emails := make([]string, 0, len(wm.Emails))
+	for _, email := range wm.Emails {
+		emails = append(emails, email)
+	}
+	slices.Sort(emails)
+	return emails
+}
+
+// userMemberUniqueEmailsWriteModel collects the emails reserved by a user in the organizations the user is member of
+type userMemberUniqueEmailsWriteModel struct {
+	eventstore.WriteModel
+
+	userID string
+	// Emails maps the id of the organization to the reserved email
+	Emails map[string]string
+}
+
+func newUserMemberUniqueEmailsWriteModel(userID string) *userMemberUniqueEmailsWriteModel {
+	return &userMemberUniqueEmailsWriteModel{
+		userID: userID,
+		Emails: make(map[string]string),
+	}
+}
+
+func (wm *userMemberUniqueEmailsWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch e := event.(type) {
+		case *org.MemberAddedEvent:
+			wm.setEmail(e.Aggregate().ID, e.UserID, e.UniqueEmail)
+		case *org.MemberEmailChangedEvent:
+			wm.setEmail(e.Aggregate().ID, e.UserID, e.UniqueEmail)
+		case *org.MemberRemovedEvent:
+			wm.setEmail(e.Aggregate().ID, e.UserID, "")
+		case *org.MemberCascadeRemovedEvent:
+			wm.setEmail(e.Aggregate().ID, e.UserID, "")
+		case *org.OrgRemovedEvent:
+			delete(wm.Emails, e.Aggregate().ID)
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *userMemberUniqueEmailsWriteModel) setEmail(orgID, userID, email string) {
+	if userID != wm.userID {
+		return
+	}
+	if email == "" {
+		delete(wm.Emails, orgID)
+		return
+	}
+	wm.Emails[orgID] = email
+}
+
+func (wm *userMemberUniqueEmailsWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		AggregateTypes(org.AggregateType).
+		EventTypes(
+			org.MemberAddedEventType,
+			org.MemberEmailChangedEventType,
+			org.MemberRemovedEventType,
+			org.MemberCascadeRemovedEventType,
+		).
+		EventData(map[string]interface{}{"userId": wm.userID}).
+		Or().
+		AggregateTypes(org.AggregateType).
+		EventTypes(org.OrgRemovedEventType).
+		Builder()
+}
+
+// OrgIDs returns the ids of the organizations with a reservation, sorted to keep the order of the events stable.
+func (wm *userMemberUniqueEmailsWriteModel) OrgIDs() []string {
+	orgIDs := make([]string, 0, len(wm.Emails))
+	for orgID := range wm.Emails {
+		orgIDs = append(orgIDs, orgID)
+	}
+	slices.Sort(orgIDs)
+	return orgIDs
+}
+
+// userEmailsWriteModel collects the emails of human users, the users can belong to different organizations
+type userEmailsWriteModel struct {
+	eventstore.WriteModel
+
+	userIDs []string
+	Emails  map[string]domain.EmailAddress
+}
+
+func newUserEmailsWriteModel(userIDs ...string) *userEmailsWriteModel {
+	return &userEmailsWriteModel{
+		userIDs: userIDs,
+		Emails:  make(map[string]domain.EmailAddress, len(userIDs)),
+	}
+}
+
+func (wm *userEmailsWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch e := event.(type) {
+		case *user.HumanAddedEvent:
+			wm.Emails[e.Aggregate().ID] = e.EmailAddress
+		case *user.HumanRegisteredEvent:
+			wm.Emails[e.Aggregate().ID] = e.EmailAddress
+		case *user.HumanEmailChangedEvent:
+			wm.Emails[e.Aggregate().ID] = e.EmailAddress
+		case *user.UserRemovedEvent:
+			delete(wm.Emails, e.Aggregate().ID)
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *userEmailsWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		AggregateTypes(user.AggregateType).
+		AggregateIDs(wm.userIDs...).
+		EventTypes(
+			user.HumanAddedType,
+			user.HumanRegisteredType,
+			user.HumanEmailChangedType,
+			user.UserV1AddedType,
+			user.UserV1RegisteredType,
+			user.UserV1EmailChangedType,
+			user.UserRemovedType,
+		).
+		Builder()
+}
+
+// orgMemberRowWriteModel computes the row of an org member in the read model from the events,
+// it reduces the events like the org members projection up to the last event the projection reduced.
+type orgMemberRowWriteModel struct {
+	eventstore.WriteModel
+
+	userID  string
+	reduced *projection.ReducedPosition
+	Exists  bool
+	Row     OrgMemberReadModel
+}
+
+func newOrgMemberRowWriteModel(instanceID, orgID, userID string, reduced *projection.ReducedPosition) *orgMemberRowWriteModel {
+	return &orgMemberRowWriteModel{
+		WriteModel: eventstore.WriteModel{
+			AggregateID: orgID,
+			InstanceID:  instanceID,
+		},
+		userID:  userID,
+		reduced: reduced,
+	}
+}
+
+// AppendEvents skips the events the projection didn't reduce yet,
+// the events the projection only looks up while reducing a member are always appended
+func (wm *orgMemberRowWriteModel) AppendEvents(events ...eventstore.Event) {
+	for _, event := range events {
+		switch event.Type() {
+		case org.OrgAddedEventType, user.HumanAddedType, user.HumanRegisteredType, user.MachineAddedEventType:
+		default:
+			if !wm.reduced.Reduced(event) {
+				continue
+			}
+		}
+		wm.WriteModel.AppendEvents(event)
+	}
+}
+
+func (wm *orgMemberRowWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch e := event.(type) {
+		case *org.OrgAddedEvent:
+			wm.Row.OrgName = e.Name
+		case *org.OrgChangedEvent:
+			if e.Name != "" {
+				wm.Row.OrgName = e.Name
+			}
+		case *org.OrgRemovedEvent:
+			wm.removed()
+		case *org.MemberAddedEvent:
+			if e.UserID != wm.userID {
+				continue
+			}
+			wm.Exists = true
+			wm.Row.Roles = e.Roles
+			wm.Row.State = domain.MemberStateActive
+			wm.Row.CreationDate = e.CreatedAt()
+			wm.Row.ResourceOwner = e.Aggregate().ResourceOwner
+			wm.Row.AddedBy = e.Creator()
+			wm.changed(e)
+		case *org.MemberChangedEvent:
+			if e.UserID != wm.userID {
+				continue
+			}
+			wm.Row.Roles = e.Roles
+			wm.changed(e)
+		case *org.MemberDeactivatedEvent:
+			if e.UserID != wm.userID {
+				continue
+			}
+			wm.Row.State = domain.MemberStateInactive
+			wm.changed(e)
+		case *org.MemberReactivatedEvent:
+			if e.UserID != wm.userID {
+				continue
+			}
+			wm.Row.State = domain.MemberStateActive
+			wm.changed(e)
+		case *org.MemberRemovedEvent:
+			if e.UserID == wm.userID {
+				wm.removed()
+			}
+		case *org.MemberCascadeRemovedEvent:
+			if e.UserID == wm.userID {
+				wm.removed()
+			}
+		case *user.HumanAddedEvent, *user.HumanRegisteredEvent, *user.MachineAddedEvent:
+			wm.Row.UserResourceOwner = e.Aggregate().ResourceOwner
+		case *user.UserRemovedEvent:
+			wm.removed()
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *orgMemberRowWriteModel) changed(event eventstore.Event) {
+	wm.Row.ChangeDate = event.CreatedAt()
+	wm.Row.Sequence = event.Sequence()
+}
+
+func (wm *orgMemberRowWriteModel) removed() {
+	wm.Exists = false
+	wm.Row.Roles = nil
+	wm.Row.State = domain.MemberStateRemoved
+}
+
+func (wm *orgMemberRowWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		InstanceID(wm.InstanceID).
+		AddQuery().
+		AggregateTypes(org.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(
+			org.OrgAddedEventType,
+			org.OrgChangedEventType,
+			org.OrgRemovedEventType,
+			org.MemberAddedEventType,
+			org.MemberChangedEventType,
+			org.MemberDeactivatedEventType,
+			org.MemberReactivatedEventType,
+			org.MemberRemovedEventType,
 			org.MemberCascadeRemovedEventType).
+		Or().
+		AggregateTypes(user.AggregateType).
+		AggregateIDs(wm.userID).
+		EventTypes(
+			user.HumanAddedType,
+			user.HumanRegisteredType,
+			user.MachineAddedEventType,
+			user.UserRemovedType).
 		Builder()
 }
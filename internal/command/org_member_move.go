@@ -0,0 +1,65 @@
+package command
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/repository/org"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// MoveOrgMember moves the membership of the user from one org to another with the same roles.
+// The removal from the previous org and the addition to the target org are pushed together,
+// so the read models never contain the member in both or none of the orgs.
+//
+// Only the membership is moved, the user aggregate keeps its resource owner
+// because the eventstore doesn't allow to change the owner of an aggregate and the user grants are kept.
+func (c *Commands) MoveOrgMember(ctx context.Context, userID, fromOrgID, toOrgID string) (_ *domain.Member, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if userID == "" || fromOrgID == "" || toOrgID == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "ORG-Mv2ka", "Errors.IDMissing")
+	}
+	if fromOrgID == toOrgID {
+		return nil, zerrors.ThrowInvalidArgument(nil, "ORG-Mv7sd", "Errors.Invalid.Argument")
+	}
+	if err = c.checkUserExists(ctx, userID, ""); err != nil {
+		return nil, err
+	}
+	if err = c.checkOrgExists(ctx, toOrgID); err != nil {
+		return nil, err
+	}
+	member, err := c.orgMemberWriteModelByID(ctx, fromOrgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = c.orgMemberWriteModelByID(ctx, toOrgID, userID)
+	if err == nil {
+		return nil, zerrors.ThrowAlreadyExists(nil, "ORG-Mv4nf", "Errors.Org.Member.AlreadyExists")
+	}
+	if !zerrors.IsNotFound(err) {
+		return nil, err
+	}
+	uniqueEmail, err := orgMemberUniqueEmail(ctx, c.eventstore.Filter, toOrgID, userID, "") //nolint:staticcheck
+	if err != nil {
+		return nil, err
+	}
+	added := org.NewMemberAddedEvent(ctx, &org.NewAggregate(toOrgID).Aggregate, userID, member.Roles...)
+	added.UniqueEmail = uniqueEmail
+
+	pushedEvents, err := c.eventstore.Push(ctx,
+		c.removeOrgMember(ctx, OrgAggregateFromWriteModel(&member.MemberWriteModel.WriteModel), userID, member.UniqueEmail, false),
+		added,
+	)
+	if err != nil {
+		return nil, err
+	}
+	movedMember := NewOrgMemberWriteModel(toOrgID, userID)
+	// the write model doesn't filter by org, only the added event is reduced
+	if err = AppendAndReduce(movedMember, pushedEvents[len(pushedEvents)-1]); err != nil {
+		return nil, err
+	}
+	return memberWriteModelToMember(&movedMember.MemberWriteModel), nil
+}
@@ -0,0 +1,234 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/v1/models"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/org"
+	"github.com/zitadel/zitadel/internal/repository/policy"
+	"github.com/zitadel/zitadel/internal/repository/user"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestCommands_MoveOrgMember(t *testing.T) {
+	userAdded := func() eventstore.Event {
+		return eventFromEventPusher(
+			user.NewHumanAddedEvent(context.Background(),
+				&user.NewAggregate("user1", "org1").Aggregate,
+				"username1",
+				"firstname1",
+				"lastname1",
+				"nickname1",
+				"displayname1",
+				language.German,
+				domain.GenderMale,
+				"email1",
+				true,
+			),
+		)
+	}
+	targetOrgAdded := func() eventstore.Event {
+		return eventFromEventPusher(
+			org.NewOrgAddedEvent(context.Background(), &org.NewAggregate("org2").Aggregate, "org2"),
+		)
+	}
+	memberAdded := func(orgID string, roles ...string) eventstore.Event {
+		return eventFromEventPusher(
+			org.NewMemberAddedEvent(context.Background(), &org.NewAggregate(orgID).Aggregate, "user1", roles...),
+		)
+	}
+	domainPolicyAdded := func() eventstore.Event {
+		return eventFromEventPusher(
+			instance.NewDomainPolicyAddedEvent(context.Background(),
+				&instance.NewAggregate("instance1").Aggregate,
+				false,
+				false,
+				false,
+			),
+		)
+	}
+	type args struct {
+		userID    string
+		fromOrgID string
+		toOrgID   string
+	}
+	tests := []struct {
+		name       string
+		eventstore func(*testing.T) *eventstore.Eventstore
+		args       args
+		want       *domain.Member
+		wantErr    error
+	}{
+		{
+			name:       "missing target org, error",
+			eventstore: expectEventstore(),
+			args: args{
+				userID:    "user1",
+				fromOrgID: "org1",
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "ORG-Mv2ka", "Errors.IDMissing"),
+		},
+		{
+			name:       "same org, error",
+			eventstore: expectEventstore(),
+			args: args{
+				userID:    "user1",
+				fromOrgID: "org1",
+				toOrgID:   "org1",
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "ORG-Mv7sd", "Errors.Invalid.Argument"),
+		},
+		{
+			name: "user not found, error",
+			eventstore: expectEventstore(
+				expectFilter(),
+			),
+			args: args{
+				userID:    "user1",
+				fromOrgID: "org1",
+				toOrgID:   "org2",
+			},
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "COMMAND-uXHNj", "Errors.User.NotFound"),
+		},
+		{
+			name: "target org not found, error",
+			eventstore: expectEventstore(
+				expectFilter(userAdded()),
+				expectFilter(),
+			),
+			args: args{
+				userID:    "user1",
+				fromOrgID: "org1",
+				toOrgID:   "org2",
+			},
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "COMMAND-QXPGs", "Errors.Org.NotFound"),
+		},
+		{
+			name: "not a member, error",
+			eventstore: expectEventstore(
+				expectFilter(userAdded()),
+				expectFilter(targetOrgAdded()),
+				expectFilter(),
+			),
+			args: args{
+				userID:    "user1",
+				fromOrgID: "org1",
+				toOrgID:   "org2",
+			},
+			wantErr: zerrors.ThrowNotFound(nil, "Org-D8JxR", "Errors.NotFound"),
+		},
+		{
+			name: "already member of target org, error",
+			eventstore: expectEventstore(
+				expectFilter(userAdded()),
+				expectFilter(targetOrgAdded()),
+				expectFilter(memberAdded("org1", "ORG_OWNER")),
+				expectFilter(memberAdded("org2", "ORG_OWNER")),
+			),
+			args: args{
+				userID:    "user1",
+				fromOrgID: "org1",
+				toOrgID:   "org2",
+			},
+			wantErr: zerrors.ThrowAlreadyExists(nil, "ORG-Mv4nf", "Errors.Org.Member.AlreadyExists"),
+		},
+		{
+			name: "moved with the same roles",
+			eventstore: expectEventstore(
+				expectFilter(userAdded()),
+				expectFilter(targetOrgAdded()),
+				expectFilter(memberAdded("org1", "ORG_OWNER", "ORG_USER_MANAGER")),
+				expectFilter(),
+				expectFilter(),
+				expectFilter(domainPolicyAdded()),
+				expectPush(
+					org.NewMemberRemovedEvent(context.Background(), &org.NewAggregate("org1").Aggregate, "user1"),
+					org.NewMemberAddedEvent(context.Background(), &org.NewAggregate("org2").Aggregate, "user1", "ORG_OWNER", "ORG_USER_MANAGER"),
+				),
+			),
+			args: args{
+				userID:    "user1",
+				fromOrgID: "org1",
+				toOrgID:   "org2",
+			},
+			want: &domain.Member{
+				ObjectRoot: models.ObjectRoot{
+					ResourceOwner: "org2",
+					AggregateID:   "org2",
+				},
+				UserID: "user1",
+				Roles:  []string{"ORG_OWNER", "ORG_USER_MANAGER"},
+			},
+		},
+		{
+			name: "moved, unique email released and reserved",
+			eventstore: expectEventstore(
+				expectFilter(userAdded()),
+				expectFilter(targetOrgAdded()),
+				expectFilter(
+					eventFromEventPusher(
+						func() eventstore.Command {
+							event := org.NewMemberAddedEvent(context.Background(), &org.NewAggregate("org1").Aggregate, "user1", "ORG_OWNER")
+							event.UniqueEmail = "email1"
+							return event
+						}(),
+					),
+				),
+				expectFilter(),
+				expectFilter(
+					eventFromEventPusher(
+						org.NewDomainPolicyAddedEvent(context.Background(), &org.NewAggregate("org2").Aggregate, false, false, false),
+					),
+					eventFromEventPusher(
+						newDomainPolicyChangedEvent(context.Background(), "org2", policy.ChangeMemberEmailsUnique(true)),
+					),
+				),
+				expectFilter(),
+				expectFilter(userAdded()),
+				expectPush(
+					func() eventstore.Command {
+						event := org.NewMemberRemovedEvent(context.Background(), &org.NewAggregate("org1").Aggregate, "user1")
+						event.UniqueEmail = "email1"
+						return event
+					}(),
+					func() eventstore.Command {
+						event := org.NewMemberAddedEvent(context.Background(), &org.NewAggregate("org2").Aggregate, "user1", "ORG_OWNER")
+						event.UniqueEmail = "email1"
+						return event
+					}(),
+				),
+			),
+			args: args{
+				userID:    "user1",
+				fromOrgID: "org1",
+				toOrgID:   "org2",
+			},
+			want: &domain.Member{
+				ObjectRoot: models.ObjectRoot{
+					ResourceOwner: "org2",
+					AggregateID:   "org2",
+				},
+				UserID: "user1",
+				Roles:  []string{"ORG_OWNER"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.eventstore(t),
+			}
+			got, err := c.MoveOrgMember(context.Background(), tt.args.userID, tt.args.fromOrgID, tt.args.toOrgID)
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
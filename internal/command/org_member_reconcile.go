@@ -0,0 +1,152 @@
+package command
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/query/projection"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// OrgMemberReadModel is the row of an org member in the read model
+type OrgMemberReadModel struct {
+	CreationDate      time.Time
+	ChangeDate        time.Time
+	Sequence          uint64
+	ResourceOwner     string
+	UserResourceOwner string
+	Roles             []string
+	State             domain.MemberState
+	OrgName           string
+	AddedBy           string
+}
+
+// OrgMemberReadModelStore reads and repairs the rows of org members in the read model
+type OrgMemberReadModelStore interface {
+	// OrgMemberReadModel returns nil if the read model contains no row for the member.
+	// The position of the last event reduced by the read model is read from the same snapshot, it's nil if the read model didn't reduce any event yet.
+	OrgMemberReadModel(ctx context.Context, orgID, userID string) (*OrgMemberReadModel, *projection.ReducedPosition, error)
+	// ReprojectOrgMember projects the events of the member again to replace its row
+	ReprojectOrgMember(ctx context.Context, orgID, userID string) error
+}
+
+// WithOrgMemberReadModelStore sets the read model used by [Commands.ReconcileOrgMember]
+func WithOrgMemberReadModelStore(store OrgMemberReadModelStore) StartOption {
+	return func(c *Commands) {
+		c.orgMemberReadModel = store
+	}
+}
+
+// OrgMemberReconciliation is the result of comparing the events of an org member with its read model
+type OrgMemberReconciliation struct {
+	// Exists is true if the member exists according to the events
+	Exists bool
+	// ExistsInReadModel is true if the read model contains the member
+	ExistsInReadModel bool
+	// MissingRoles are granted by the events but missing in the read model
+	MissingRoles []string
+	// UnexpectedRoles are in the read model but not granted by the events
+	UnexpectedRoles []string
+	// DivergedColumns are the other columns of the read model which disagree with the events
+	DivergedColumns []string
+	// Repaired is true if the member was projected again
+	Repaired bool
+}
+
+// Diverged returns true if the read model disagrees with the events
+func (r *OrgMemberReconciliation) Diverged() bool {
+	return r.Exists != r.ExistsInReadModel || len(r.MissingRoles) > 0 || len(r.UnexpectedRoles) > 0 || len(r.DivergedColumns) > 0
+}
+
+// ReconcileOrgMember compares the org member computed from the events with its row of the read model.
+// Only the events already reduced by the read model are compared, so a lagging read model doesn't diverge.
+// The read model is only changed if repair is set and the member diverged, the member is projected again to fix it.
+func (c *Commands) ReconcileOrgMember(ctx context.Context, orgID, userID string, repair bool) (_ *OrgMemberReconciliation, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if orgID == "" || userID == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "ORG-Wc5rg", "Errors.Org.InvalidMember")
+	}
+	if c.orgMemberReadModel == nil {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "ORG-Jx4ne", "Errors.Internal")
+	}
+	readModel, reduced, err := c.orgMemberReadModel.OrgMemberReadModel(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	writeModel := newOrgMemberRowWriteModel(authz.GetInstance(ctx).InstanceID(), orgID, userID, reduced)
+	if err = c.eventstore.FilterToQueryReducer(ctx, writeModel); err != nil {
+		return nil, err
+	}
+	result := &OrgMemberReconciliation{
+		Exists:            writeModel.Exists,
+		ExistsInReadModel: readModel != nil,
+	}
+	var roles, readModelRoles []string
+	if writeModel.Exists {
+		roles = writeModel.Row.Roles
+	}
+	if readModel != nil {
+		readModelRoles = readModel.Roles
+	}
+	result.MissingRoles = missingRoles(roles, readModelRoles)
+	result.UnexpectedRoles = missingRoles(readModelRoles, roles)
+	if writeModel.Exists && readModel != nil {
+		result.DivergedColumns = divergedOrgMemberColumns(&writeModel.Row, readModel)
+	}
+
+	if !repair || !result.Diverged() {
+		return result, nil
+	}
+	if err = c.orgMemberReadModel.ReprojectOrgMember(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+	result.Repaired = true
+	return result, nil
+}
+
+// missingRoles returns the roles which are not contained in other
+func missingRoles(roles, other []string) []string {
+	var missing []string
+	for _, role := range roles {
+		if !slices.Contains(other, role) {
+			missing = append(missing, role)
+		}
+	}
+	return missing
+}
+
+// divergedOrgMemberColumns returns the columns of the projection other than the roles which differ
+func divergedOrgMemberColumns(expected, actual *OrgMemberReadModel) []string {
+	var columns []string
+	if !expected.CreationDate.Equal(actual.CreationDate) {
+		columns = append(columns, projection.MemberCreationDate)
+	}
+	if !expected.ChangeDate.Equal(actual.ChangeDate) {
+		columns = append(columns, projection.MemberChangeDate)
+	}
+	if expected.Sequence != actual.Sequence {
+		columns = append(columns, projection.MemberSequence)
+	}
+	if expected.ResourceOwner != actual.ResourceOwner {
+		columns = append(columns, projection.MemberResourceOwner)
+	}
+	if expected.UserResourceOwner != actual.UserResourceOwner {
+		columns = append(columns, projection.MemberUserResourceOwner)
+	}
+	if expected.State != actual.State {
+		columns = append(columns, projection.OrgMemberStateCol)
+	}
+	if expected.OrgName != actual.OrgName {
+		columns = append(columns, projection.OrgMemberOrgNameCol)
+	}
+	if expected.AddedBy != actual.AddedBy {
+		columns = append(columns, projection.OrgMemberAddedByCol)
+	}
+	return columns
+}
@@ -0,0 +1,311 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/query/projection"
+	"github.com/zitadel/zitadel/internal/repository/org"
+	"github.com/zitadel/zitadel/internal/repository/user"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+type testOrgMemberReadModelStore struct {
+	t           *testing.T
+	readModel   *OrgMemberReadModel
+	notReduced  bool
+	readErr     error
+	reprojected bool
+	reprojErr   error
+}
+
+func (s *testOrgMemberReadModelStore) OrgMemberReadModel(_ context.Context, orgID, userID string) (*OrgMemberReadModel, *projection.ReducedPosition, error) {
+	assert.Equal(s.t, "org1", orgID)
+	assert.Equal(s.t, "user1", userID)
+	if s.notReduced {
+		return s.readModel, nil, s.readErr
+	}
+	// the events of the test are at position 0 and the lagging ones after 1
+	return s.readModel, &projection.ReducedPosition{Position: 1}, s.readErr
+}
+
+func (s *testOrgMemberReadModelStore) ReprojectOrgMember(_ context.Context, orgID, userID string) error {
+	s.reprojected = true
+	assert.Equal(s.t, "org1", orgID)
+	assert.Equal(s.t, "user1", userID)
+	return s.reprojErr
+}
+
+func TestCommands_ReconcileOrgMember(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance1")
+	added := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	deactivated := added.Add(time.Hour)
+	orgAgg := &org.NewAggregate("org1").Aggregate
+	eventAt := func(creationDate time.Time, sequence uint64, event eventstore.Command) *repository.Event {
+		e := eventFromEventPusherWithInstanceID("instance1", event)
+		e.CreationDate = creationDate
+		e.Seq = sequence
+		e.EditorUser = "admin1"
+		return e
+	}
+	memberEvents := func() []eventstore.Event {
+		return []eventstore.Event{
+			eventAt(added, 1, org.NewOrgAddedEvent(ctx, orgAgg, "org")),
+			eventAt(added, 1, user.NewMachineAddedEvent(ctx, &user.NewAggregate("user1", "org2").Aggregate, "machine", "name", "", false, domain.OIDCTokenTypeBearer)),
+			eventAt(added, 2, org.NewMemberAddedEvent(ctx, orgAgg, "user1", "ORG_OWNER", "ORG_USER_MANAGER")),
+			eventAt(added, 3, org.NewOrgChangedEvent(ctx, orgAgg, "org", "renamed")),
+			eventAt(deactivated, 4, org.NewMemberDeactivatedEvent(ctx, orgAgg, "user1")),
+		}
+	}
+	inSync := func() *OrgMemberReadModel {
+		return &OrgMemberReadModel{
+			CreationDate:      added,
+			ChangeDate:        deactivated,
+			Sequence:          4,
+			ResourceOwner:     "org1",
+			UserResourceOwner: "org2",
+			Roles:             []string{"ORG_USER_MANAGER", "ORG_OWNER"},
+			State:             domain.MemberStateInactive,
+			OrgName:           "renamed",
+			AddedBy:           "admin1",
+		}
+	}
+	type args struct {
+		orgID  string
+		userID string
+		repair bool
+	}
+	tests := []struct {
+		name            string
+		eventstore      func(*testing.T) *eventstore.Eventstore
+		store           *testOrgMemberReadModelStore
+		noStore         bool
+		args            args
+		want            *OrgMemberReconciliation
+		wantReprojected bool
+		wantErr         error
+	}{
+		{
+			name:       "missing user id, error",
+			eventstore: expectEventstore(),
+			store:      &testOrgMemberReadModelStore{},
+			args: args{
+				orgID: "org1",
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "ORG-Wc5rg", "Errors.Org.InvalidMember"),
+		},
+		{
+			name:       "no read model, error",
+			eventstore: expectEventstore(),
+			noStore:    true,
+			args: args{
+				orgID:  "org1",
+				userID: "user1",
+			},
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "ORG-Jx4ne", "Errors.Internal"),
+		},
+		{
+			name: "in sync",
+			eventstore: expectEventstore(
+				expectFilter(memberEvents()...),
+			),
+			store: &testOrgMemberReadModelStore{
+				readModel: inSync(),
+			},
+			args: args{
+				orgID:  "org1",
+				userID: "user1",
+				repair: true,
+			},
+			want: &OrgMemberReconciliation{
+				Exists:            true,
+				ExistsInReadModel: true,
+			},
+		},
+		{
+			name: "diverged roles, report only",
+			eventstore: expectEventstore(
+				expectFilter(memberEvents()...),
+			),
+			store: &testOrgMemberReadModelStore{
+				readModel: func() *OrgMemberReadModel {
+					readModel := inSync()
+					readModel.Roles = []string{"ORG_OWNER", "ORG_OWNER_VIEWER"}
+					return readModel
+				}(),
+			},
+			args: args{
+				orgID:  "org1",
+				userID: "user1",
+			},
+			want: &OrgMemberReconciliation{
+				Exists:            true,
+				ExistsInReadModel: true,
+				MissingRoles:      []string{"ORG_USER_MANAGER"},
+				UnexpectedRoles:   []string{"ORG_OWNER_VIEWER"},
+			},
+		},
+		{
+			name: "diverged state and org name, repaired",
+			eventstore: expectEventstore(
+				expectFilter(memberEvents()...),
+			),
+			store: &testOrgMemberReadModelStore{
+				readModel: func() *OrgMemberReadModel {
+					readModel := inSync()
+					readModel.State = domain.MemberStateActive
+					readModel.OrgName = "org"
+					return readModel
+				}(),
+			},
+			args: args{
+				orgID:  "org1",
+				userID: "user1",
+				repair: true,
+			},
+			want: &OrgMemberReconciliation{
+				Exists:            true,
+				ExistsInReadModel: true,
+				DivergedColumns:   []string{"state", "org_name"},
+				Repaired:          true,
+			},
+			wantReprojected: true,
+		},
+		{
+			name: "missing in read model, repaired",
+			eventstore: expectEventstore(
+				expectFilter(memberEvents()...),
+			),
+			store: &testOrgMemberReadModelStore{},
+			args: args{
+				orgID:  "org1",
+				userID: "user1",
+				repair: true,
+			},
+			want: &OrgMemberReconciliation{
+				Exists:       true,
+				MissingRoles: []string{"ORG_OWNER", "ORG_USER_MANAGER"},
+				Repaired:     true,
+			},
+			wantReprojected: true,
+		},
+		{
+			name: "removed member in read model, repaired",
+			eventstore: expectEventstore(
+				expectFilter(
+					append(memberEvents(),
+						eventAt(deactivated, 5, org.NewMemberRemovedEvent(ctx, orgAgg, "user1")),
+					)...,
+				),
+			),
+			store: &testOrgMemberReadModelStore{
+				readModel: inSync(),
+			},
+			args: args{
+				orgID:  "org1",
+				userID: "user1",
+				repair: true,
+			},
+			want: &OrgMemberReconciliation{
+				ExistsInReadModel: true,
+				UnexpectedRoles:   []string{"ORG_USER_MANAGER", "ORG_OWNER"},
+				Repaired:          true,
+			},
+			wantReprojected: true,
+		},
+		{
+			name: "events not reduced by the read model yet, in sync",
+			eventstore: expectEventstore(
+				expectFilter(
+					append(memberEvents(),
+						func() eventstore.Event {
+							e := eventAt(deactivated, 5, org.NewMemberRemovedEvent(ctx, orgAgg, "user1"))
+							e.Pos = 2
+							return e
+						}(),
+					)...,
+				),
+			),
+			store: &testOrgMemberReadModelStore{
+				readModel: inSync(),
+			},
+			args: args{
+				orgID:  "org1",
+				userID: "user1",
+				repair: true,
+			},
+			want: &OrgMemberReconciliation{
+				Exists:            true,
+				ExistsInReadModel: true,
+			},
+		},
+		{
+			name: "read model didn't reduce events yet, in sync",
+			eventstore: expectEventstore(
+				expectFilter(memberEvents()...),
+			),
+			store: &testOrgMemberReadModelStore{
+				notReduced: true,
+			},
+			args: args{
+				orgID:  "org1",
+				userID: "user1",
+				repair: true,
+			},
+			want: &OrgMemberReconciliation{},
+		},
+		{
+			name:       "read model failed, error",
+			eventstore: expectEventstore(),
+			store: &testOrgMemberReadModelStore{
+				readErr: zerrors.ThrowInternal(nil, "id", "read failed"),
+			},
+			args: args{
+				orgID:  "org1",
+				userID: "user1",
+			},
+			wantErr: zerrors.ThrowInternal(nil, "id", "read failed"),
+		},
+		{
+			name: "reproject failed, error",
+			eventstore: expectEventstore(
+				expectFilter(memberEvents()...),
+			),
+			store: &testOrgMemberReadModelStore{
+				reprojErr: zerrors.ThrowInternal(nil, "id", "reproject failed"),
+			},
+			args: args{
+				orgID:  "org1",
+				userID: "user1",
+				repair: true,
+			},
+			wantReprojected: true,
+			wantErr:         zerrors.ThrowInternal(nil, "id", "reproject failed"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.eventstore(t),
+			}
+			if !tt.noStore {
+				tt.store.t = t
+				c.orgMemberReadModel = tt.store
+			}
+			got, err := c.ReconcileOrgMember(ctx, tt.args.orgID, tt.args.userID, tt.args.repair)
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+			if !tt.noStore {
+				assert.Equal(t, tt.wantReprojected, tt.store.reprojected)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/repository/org"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// SetOrgMFAGracePeriod sets the duration after the creation of a user in which the users of the organization
+// have to enroll a multi factor. A grace period of zero disables the enforcement, which is the default.
+func (c *Commands) SetOrgMFAGracePeriod(ctx context.Context, orgID string, gracePeriod time.Duration) (*domain.ObjectDetails, error) {
+	if orgID == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "ORG-Gp3kd", "Errors.Org.Empty")
+	}
+	if gracePeriod < 0 {
+		return nil, zerrors.ThrowInvalidArgument(nil, "ORG-Gp6wn", "Errors.Org.Invalid")
+	}
+	writeModel, err := c.orgMFAGracePeriodWriteModel(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if writeModel.State != domain.OrgStateActive {
+		return nil, zerrors.ThrowNotFound(nil, "ORG-Gp8sa", "Errors.Org.NotFound")
+	}
+	if writeModel.GracePeriod == gracePeriod {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "ORG-Gp2lq", "Errors.Org.NotChanged")
+	}
+	err = c.pushAppendAndReduce(ctx, writeModel,
+		org.NewMFAGracePeriodSetEvent(ctx, OrgAggregateFromWriteModel(&writeModel.WriteModel), gracePeriod),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return writeModelToObjectDetails(&writeModel.WriteModel), nil
+}
+
+// MFAGracePeriodStatus checks if the human user has enrolled a multi factor
+// inside the grace period of its organization, which starts at the creation of the user.
+// The login flow must force the enrollment if [domain.MFAGracePeriodStateExpired] is returned.
+func (c *Commands) MFAGracePeriodStatus(ctx context.Context, userID string) (*domain.MFAGracePeriodStatus, error) {
+	if userID == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Gp4fu", "Errors.IDMissing")
+	}
+	enrollment := NewHumanMFAEnrollmentWriteModel(userID, "")
+	if err := c.eventstore.FilterToQueryReducer(ctx, enrollment); err != nil {
+		return nil, err
+	}
+	if enrollment.UserState == domain.UserStateUnspecified || enrollment.UserState == domain.UserStateDeleted {
+		return nil, zerrors.ThrowNotFound(nil, "COMMAND-Gp9ve", "Errors.User.NotFound")
+	}
+	policy, err := c.orgMFAGracePeriodWriteModel(ctx, enrollment.ResourceOwner)
+	if err != nil {
+		return nil, err
+	}
+	return mfaGracePeriodStatus(enrollment, policy.GracePeriod, time.Now()), nil
+}
+
+func mfaGracePeriodStatus(enrollment *HumanMFAEnrollmentWriteModel, gracePeriod time.Duration, now time.Time) *domain.MFAGracePeriodStatus {
+	if gracePeriod == 0 {
+		return &domain.MFAGracePeriodStatus{State: domain.MFAGracePeriodStateDisabled}
+	}
+	if enrollment.HasMFA() {
+		return &domain.MFAGracePeriodStatus{State: domain.MFAGracePeriodStateEnrolled}
+	}
+	status := &domain.MFAGracePeriodStatus{
+		State:          domain.MFAGracePeriodStateActive,
+		ExpirationDate: enrollment.CreationDate.Add(gracePeriod),
+	}
+	if !now.Before(status.ExpirationDate) {
+		status.State = domain.MFAGracePeriodStateExpired
+	}
+	return status
+}
+
+func (c *Commands) orgMFAGracePeriodWriteModel(ctx context.Context, orgID string) (*OrgMFAGracePeriodWriteModel, error) {
+	writeModel := NewOrgMFAGracePeriodWriteModel(orgID)
+	if err := c.eventstore.FilterToQueryReducer(ctx, writeModel); err != nil {
+		return nil, err
+	}
+	return writeModel, nil
+}
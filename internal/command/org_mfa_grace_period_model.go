@@ -0,0 +1,52 @@
+package command
+
+import (
+	"time"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/org"
+)
+
+type OrgMFAGracePeriodWriteModel struct {
+	eventstore.WriteModel
+
+	GracePeriod time.Duration
+	State       domain.OrgState
+}
+
+func NewOrgMFAGracePeriodWriteModel(orgID string) *OrgMFAGracePeriodWriteModel {
+	return &OrgMFAGracePeriodWriteModel{
+		WriteModel: eventstore.WriteModel{
+			AggregateID:   orgID,
+			ResourceOwner: orgID,
+		},
+	}
+}
+
+func (wm *OrgMFAGracePeriodWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch e := event.(type) {
+		case *org.OrgAddedEvent:
+			wm.State = domain.OrgStateActive
+		case *org.OrgRemovedEvent:
+			wm.State = domain.OrgStateRemoved
+		case *org.MFAGracePeriodSetEvent:
+			wm.GracePeriod = e.GracePeriod
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *OrgMFAGracePeriodWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		ResourceOwner(wm.ResourceOwner).
+		AddQuery().
+		AggregateTypes(org.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(
+			org.OrgAddedEventType,
+			org.OrgRemovedEventType,
+			org.MFAGracePeriodSetEventType).
+		Builder()
+}
@@ -0,0 +1,236 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/repository/org"
+	"github.com/zitadel/zitadel/internal/repository/user"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestCommands_SetOrgMFAGracePeriod(t *testing.T) {
+	ctx := authz.NewMockContext("instance1", "org1", "")
+	agg := org.NewAggregate("org1")
+	type args struct {
+		orgID       string
+		gracePeriod time.Duration
+	}
+	tests := []struct {
+		name       string
+		eventstore func(*testing.T) *eventstore.Eventstore
+		args       args
+		want       *domain.ObjectDetails
+		wantErr    error
+	}{
+		{
+			name:       "missing org, error",
+			eventstore: expectEventstore(),
+			args: args{
+				gracePeriod: time.Hour,
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "ORG-Gp3kd", "Errors.Org.Empty"),
+		},
+		{
+			name:       "negative grace period, error",
+			eventstore: expectEventstore(),
+			args: args{
+				orgID:       "org1",
+				gracePeriod: -time.Hour,
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "ORG-Gp6wn", "Errors.Org.Invalid"),
+		},
+		{
+			name: "org not found, error",
+			eventstore: expectEventstore(
+				expectFilter(),
+			),
+			args: args{
+				orgID:       "org1",
+				gracePeriod: time.Hour,
+			},
+			wantErr: zerrors.ThrowNotFound(nil, "ORG-Gp8sa", "Errors.Org.NotFound"),
+		},
+		{
+			name: "not changed, error",
+			eventstore: expectEventstore(
+				expectFilter(
+					eventFromEventPusher(org.NewOrgAddedEvent(ctx, &agg.Aggregate, "org")),
+					eventFromEventPusher(org.NewMFAGracePeriodSetEvent(ctx, &agg.Aggregate, time.Hour)),
+				),
+			),
+			args: args{
+				orgID:       "org1",
+				gracePeriod: time.Hour,
+			},
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "ORG-Gp2lq", "Errors.Org.NotChanged"),
+		},
+		{
+			name: "disabled by default, not changed, error",
+			eventstore: expectEventstore(
+				expectFilter(
+					eventFromEventPusher(org.NewOrgAddedEvent(ctx, &agg.Aggregate, "org")),
+				),
+			),
+			args: args{
+				orgID:       "org1",
+				gracePeriod: 0,
+			},
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "ORG-Gp2lq", "Errors.Org.NotChanged"),
+		},
+		{
+			name: "grace period set",
+			eventstore: expectEventstore(
+				expectFilter(
+					eventFromEventPusher(org.NewOrgAddedEvent(ctx, &agg.Aggregate, "org")),
+				),
+				expectPush(
+					org.NewMFAGracePeriodSetEvent(ctx, &agg.Aggregate, 7*24*time.Hour),
+				),
+			),
+			args: args{
+				orgID:       "org1",
+				gracePeriod: 7 * 24 * time.Hour,
+			},
+			want: &domain.ObjectDetails{
+				ResourceOwner: "org1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.eventstore(t),
+			}
+			got, err := c.SetOrgMFAGracePeriod(ctx, tt.args.orgID, tt.args.gracePeriod)
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCommands_MFAGracePeriodStatus(t *testing.T) {
+	ctx := authz.NewMockContext("instance1", "org1", "")
+	userAgg := user.NewAggregate("user1", "org1")
+	orgAgg := org.NewAggregate("org1")
+	humanAdded := func(event func(eventstore.Command) *repository.Event) eventstore.Event {
+		return event(user.NewHumanAddedEvent(ctx,
+			&userAgg.Aggregate,
+			"username",
+			"firstname",
+			"lastname",
+			"nickname",
+			"displayname",
+			language.German,
+			domain.GenderUnspecified,
+			"email@test.ch",
+			true,
+		))
+	}
+	gracePeriodSet := eventFromEventPusher(org.NewMFAGracePeriodSetEvent(ctx, &orgAgg.Aggregate, time.Hour))
+	tests := []struct {
+		name       string
+		eventstore func(*testing.T) *eventstore.Eventstore
+		userID     string
+		want       domain.MFAGracePeriodState
+		wantErr    error
+	}{
+		{
+			name:       "missing user id, error",
+			eventstore: expectEventstore(),
+			wantErr:    zerrors.ThrowInvalidArgument(nil, "COMMAND-Gp4fu", "Errors.IDMissing"),
+		},
+		{
+			name: "user not found, error",
+			eventstore: expectEventstore(
+				expectFilter(),
+			),
+			userID:  "user1",
+			wantErr: zerrors.ThrowNotFound(nil, "COMMAND-Gp9ve", "Errors.User.NotFound"),
+		},
+		{
+			name: "disabled",
+			eventstore: expectEventstore(
+				expectFilter(humanAdded(eventFromEventPusher)),
+				expectFilter(
+					eventFromEventPusher(org.NewOrgAddedEvent(ctx, &orgAgg.Aggregate, "org")),
+				),
+			),
+			userID: "user1",
+			want:   domain.MFAGracePeriodStateDisabled,
+		},
+		{
+			name: "enrolled",
+			eventstore: expectEventstore(
+				expectFilter(
+					humanAdded(eventFromEventPusher),
+					eventFromEventPusher(user.NewHumanOTPSMSAddedEvent(ctx, &userAgg.Aggregate)),
+				),
+				expectFilter(gracePeriodSet),
+			),
+			userID: "user1",
+			want:   domain.MFAGracePeriodStateEnrolled,
+		},
+		{
+			name: "active",
+			eventstore: expectEventstore(
+				expectFilter(humanAdded(eventFromEventPusherWithCreationDateNow)),
+				expectFilter(gracePeriodSet),
+			),
+			userID: "user1",
+			want:   domain.MFAGracePeriodStateActive,
+		},
+		{
+			name: "factor removed, expired",
+			eventstore: expectEventstore(
+				expectFilter(
+					humanAdded(eventFromEventPusher),
+					eventFromEventPusher(user.NewHumanOTPSMSAddedEvent(ctx, &userAgg.Aggregate)),
+					eventFromEventPusher(user.NewHumanOTPSMSRemovedEvent(ctx, &userAgg.Aggregate)),
+				),
+				expectFilter(gracePeriodSet),
+			),
+			userID: "user1",
+			want:   domain.MFAGracePeriodStateExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.eventstore(t),
+			}
+			got, err := c.MFAGracePeriodStatus(ctx, tt.userID)
+			require.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got.State)
+		})
+	}
+}
+
+func Test_mfaGracePeriodStatus(t *testing.T) {
+	created := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	enrollment := &HumanMFAEnrollmentWriteModel{CreationDate: created}
+	assert.Equal(t,
+		&domain.MFAGracePeriodStatus{State: domain.MFAGracePeriodStateActive, ExpirationDate: created.Add(time.Hour)},
+		mfaGracePeriodStatus(enrollment, time.Hour, created.Add(time.Hour-time.Nanosecond)),
+	)
+	assert.Equal(t,
+		&domain.MFAGracePeriodStatus{State: domain.MFAGracePeriodStateExpired, ExpirationDate: created.Add(time.Hour)},
+		mfaGracePeriodStatus(enrollment, time.Hour, created.Add(time.Hour)),
+	)
+	enrollment.U2FTokenIDs = []string{"token1"}
+	assert.Equal(t,
+		&domain.MFAGracePeriodStatus{State: domain.MFAGracePeriodStateEnrolled},
+		mfaGracePeriodStatus(enrollment, time.Hour, created.Add(time.Hour)),
+	)
+}
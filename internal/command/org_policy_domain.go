@@ -7,6 +7,7 @@ import (
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/repository/org"
+	"github.com/zitadel/zitadel/internal/repository/policy"
 	"github.com/zitadel/zitadel/internal/telemetry/tracing"
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
@@ -46,6 +47,39 @@ func (c *Commands) ChangeOrgDomainPolicy(ctx context.Context, resourceOwner stri
 	return pushedEventsToObjectDetails(pushedEvents), nil
 }
 
+// SetOrgMemberEmailsUnique enables or disables the check for unique emails of the members of the organization.
+// The organization must have a custom domain policy.
+func (c *Commands) SetOrgMemberEmailsUnique(ctx context.Context, orgID string, unique bool) (_ *domain.ObjectDetails, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if orgID == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "Org-Ud7fk", "Errors.ResourceOwnerMissing")
+	}
+	writeModel, err := c.orgDomainPolicyWriteModel(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !writeModel.State.Exists() {
+		return nil, zerrors.ThrowNotFound(nil, "ORG-Mz3ra", "Errors.Org.DomainPolicy.NotFound")
+	}
+	if writeModel.MemberEmailsUnique == unique {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "ORG-Bk5wy", "Errors.NoChangesFound")
+	}
+	changedEvent, err := org.NewDomainPolicyChangedEvent(ctx,
+		OrgAggregateFromWriteModel(&writeModel.WriteModel),
+		[]policy.DomainPolicyChanges{policy.ChangeMemberEmailsUnique(unique)},
+	)
+	if err != nil {
+		return nil, err
+	}
+	pushedEvents, err := c.eventstore.Push(ctx, changedEvent)
+	if err != nil {
+		return nil, err
+	}
+	return pushedEventsToObjectDetails(pushedEvents), nil
+}
+
 func (c *Commands) RemoveOrgDomainPolicy(ctx context.Context, orgID string) (*domain.ObjectDetails, error) {
 	if orgID == "" {
 		return nil, zerrors.ThrowInvalidArgument(nil, "Org-3H8fs", "Errors.ResourceOwnerMissing")
@@ -62,6 +62,7 @@ func TestCommandSide_AddPasswordAgePolicy(t *testing.T) {
 								&org.NewAggregate("org1").Aggregate,
 								365,
 								10,
+								0,
 							),
 						),
 					),
@@ -90,6 +91,7 @@ func TestCommandSide_AddPasswordAgePolicy(t *testing.T) {
 							&org.NewAggregate("org1").Aggregate,
 							10,
 							365,
+							0,
 						),
 					),
 				),
@@ -201,6 +203,7 @@ func TestCommandSide_ChangePasswordAgePolicy(t *testing.T) {
 								&org.NewAggregate("org1").Aggregate,
 								10,
 								365,
+								0,
 							),
 						),
 					),
@@ -229,6 +232,7 @@ func TestCommandSide_ChangePasswordAgePolicy(t *testing.T) {
 								&org.NewAggregate("org1").Aggregate,
 								10,
 								365,
+								0,
 							),
 						),
 					),
@@ -335,6 +339,7 @@ func TestCommandSide_RemovePasswordAgePolicy(t *testing.T) {
 								&org.NewAggregate("org1").Aggregate,
 								10,
 								365,
+								0,
 							),
 						),
 					),
@@ -2,10 +2,12 @@ package command
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	openid "github.com/zitadel/oidc/v3/pkg/oidc"
 	"go.uber.org/mock/gomock"
 	"golang.org/x/text/language"
@@ -17,7 +19,9 @@ import (
 	"github.com/zitadel/zitadel/internal/eventstore/v1/models"
 	"github.com/zitadel/zitadel/internal/id"
 	id_mock "github.com/zitadel/zitadel/internal/id/mock"
+	"github.com/zitadel/zitadel/internal/repository/instance"
 	"github.com/zitadel/zitadel/internal/repository/org"
+	"github.com/zitadel/zitadel/internal/repository/policy"
 	"github.com/zitadel/zitadel/internal/repository/project"
 	"github.com/zitadel/zitadel/internal/repository/user"
 	"github.com/zitadel/zitadel/internal/zerrors"
@@ -47,6 +51,16 @@ func TestAddOrg(t *testing.T) {
 				ValidationErr: zerrors.ThrowInvalidArgument(nil, "ORG-mruNY", "Errors.Invalid.Argument"),
 			},
 		},
+		{
+			name: "invalid name",
+			args: args{
+				a:    agg,
+				name: "caos\nag",
+			},
+			want: Want{
+				ValidationErr: zerrors.ThrowInvalidArgument(nil, "ORG-Kv9qe", "Errors.Org.Invalid"),
+			},
+		},
 		{
 			name: "correct",
 			args: args{
@@ -126,6 +140,23 @@ func TestCommandSide_AddOrg(t *testing.T) {
 				err: zerrors.IsErrorInvalidArgument,
 			},
 		},
+		{
+			name: "invalid org (too long), error",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+				),
+			},
+			args: args{
+				ctx:           context.Background(),
+				userID:        "user1",
+				resourceOwner: "org1",
+				name:          strings.Repeat("a", domain.OrgNameMaxLength+1),
+			},
+			res: res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
 		{
 			name: "user removed, error",
 			fields: fields{
@@ -193,6 +224,17 @@ func TestCommandSide_AddOrg(t *testing.T) {
 						),
 					),
 					expectFilterOrgMemberNotFound(),
+					expectFilter(),
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewDomainPolicyAddedEvent(context.Background(),
+								&instance.NewAggregate("INSTANCE").Aggregate,
+								true,
+								true,
+								true,
+							),
+						),
+					),
 					expectPushFailed(zerrors.ThrowAlreadyExists(nil, "id", "internal"),
 						org.NewOrgAddedEvent(
 							context.Background(),
@@ -261,6 +303,17 @@ func TestCommandSide_AddOrg(t *testing.T) {
 						),
 					),
 					expectFilterOrgMemberNotFound(),
+					expectFilter(),
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewDomainPolicyAddedEvent(context.Background(),
+								&instance.NewAggregate("INSTANCE").Aggregate,
+								true,
+								true,
+								true,
+							),
+						),
+					),
 					expectPushFailed(zerrors.ThrowInternal(nil, "id", "internal"),
 						org.NewOrgAddedEvent(
 							context.Background(),
@@ -329,6 +382,17 @@ func TestCommandSide_AddOrg(t *testing.T) {
 						),
 					),
 					expectFilterOrgMemberNotFound(),
+					expectFilter(),
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewDomainPolicyAddedEvent(context.Background(),
+								&instance.NewAggregate("INSTANCE").Aggregate,
+								true,
+								true,
+								true,
+							),
+						),
+					),
 					expectPush(
 						org.NewOrgAddedEvent(context.Background(),
 							&org.NewAggregate("org2").Aggregate,
@@ -377,6 +441,118 @@ func TestCommandSide_AddOrg(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "add org, member email reserved",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilterOrgDomainNotFound(),
+					expectFilter(
+						eventFromEventPusher(
+							user.NewHumanAddedEvent(context.Background(),
+								&user.NewAggregate("user1", "org1").Aggregate,
+								"username1",
+								"firstname1",
+								"lastname1",
+								"nickname1",
+								"displayname1",
+								language.English,
+								domain.GenderMale,
+								"email1",
+								true,
+							),
+						),
+					),
+					expectFilterOrgMemberNotFound(),
+					expectFilter(),
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewDomainPolicyAddedEvent(context.Background(),
+								&instance.NewAggregate("INSTANCE").Aggregate,
+								true,
+								true,
+								true,
+							),
+						),
+						eventFromEventPusher(
+							func() eventstore.Command {
+								event, _ := instance.NewDomainPolicyChangedEvent(context.Background(),
+									&instance.NewAggregate("INSTANCE").Aggregate,
+									[]policy.DomainPolicyChanges{policy.ChangeMemberEmailsUnique(true)},
+								)
+								return event
+							}(),
+						),
+					),
+					expectFilter(),
+					expectFilter(
+						eventFromEventPusher(
+							user.NewHumanAddedEvent(context.Background(),
+								&user.NewAggregate("user1", "org1").Aggregate,
+								"username1",
+								"firstname1",
+								"lastname1",
+								"nickname1",
+								"displayname1",
+								language.English,
+								domain.GenderMale,
+								"email1",
+								true,
+							),
+						),
+					),
+					expectPush(
+						org.NewOrgAddedEvent(context.Background(),
+							&org.NewAggregate("org2").Aggregate,
+							"Org",
+						),
+						org.NewDomainAddedEvent(context.Background(),
+							&org.NewAggregate("org2").Aggregate, "org.iam-domain",
+						),
+						org.NewDomainVerifiedEvent(context.Background(),
+							&org.NewAggregate("org2").Aggregate,
+							"org.iam-domain",
+						),
+						org.NewDomainPrimarySetEvent(context.Background(),
+							&org.NewAggregate("org2").Aggregate,
+							"org.iam-domain",
+						),
+						func() eventstore.Command {
+							event := org.NewMemberAddedEvent(context.Background(),
+								&org.NewAggregate("org2").Aggregate,
+								"user1",
+								domain.RoleOrgOwner,
+							)
+							event.UniqueEmail = "email1"
+							return event
+						}(),
+					),
+				),
+				idGenerator: id_mock.NewIDGeneratorExpectIDs(t, "org2"),
+				zitadelRoles: []authz.RoleMapping{
+					{
+						Role: "ORG_OWNER",
+					},
+				},
+			},
+			args: args{
+				ctx:           authz.WithRequestedDomain(context.Background(), "iam-domain"),
+				name:          "Org",
+				userID:        "user1",
+				resourceOwner: "org1",
+			},
+			res: res{
+				want: &domain.Org{
+					ObjectRoot: models.ObjectRoot{
+						AggregateID:   "org2",
+						ResourceOwner: "org2",
+					},
+					Name:          "Org",
+					State:         domain.OrgStateActive,
+					PrimaryDomain: "org.iam-domain",
+				},
+			},
+		},
 		{
 			name: "add org (remove spaces), no error",
 			fields: fields{
@@ -400,6 +576,17 @@ func TestCommandSide_AddOrg(t *testing.T) {
 						),
 					),
 					expectFilterOrgMemberNotFound(),
+					expectFilter(),
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewDomainPolicyAddedEvent(context.Background(),
+								&instance.NewAggregate("INSTANCE").Aggregate,
+								true,
+								true,
+								true,
+							),
+						),
+					),
 					expectPush(
 						org.NewOrgAddedEvent(context.Background(),
 							&org.NewAggregate("org2").Aggregate,
@@ -519,6 +706,38 @@ func TestCommandSide_ChangeOrg(t *testing.T) {
 				err: zerrors.IsErrorInvalidArgument,
 			},
 		},
+		{
+			name: "name too long, invalid argument error",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+				),
+			},
+			args: args{
+				ctx:   context.Background(),
+				orgID: "org1",
+				name:  strings.Repeat("a", domain.OrgNameMaxLength+1),
+			},
+			res: res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			name: "name with control character, invalid argument error",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+				),
+			},
+			args: args{
+				ctx:   context.Background(),
+				orgID: "org1",
+				name:  "org\nname",
+			},
+			res: res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
 		{
 			name: "org not found, error",
 			fields: fields{
@@ -926,6 +1145,7 @@ func TestCommandSide_ReactivateOrg(t *testing.T) {
 							),
 						),
 					),
+					expectFilter(),
 					expectPushFailed(
 						zerrors.ThrowInternal(nil, "id", "message"),
 						org.NewOrgReactivatedEvent(context.Background(),
@@ -958,10 +1178,61 @@ func TestCommandSide_ReactivateOrg(t *testing.T) {
 								&org.NewAggregate("org1").Aggregate),
 						),
 					),
+					expectFilter(),
+					expectPush(
+						org.NewOrgReactivatedEvent(context.Background(),
+							&org.NewAggregate("org1").Aggregate,
+						),
+					),
+				),
+			},
+			args: args{
+				ctx:   context.Background(),
+				orgID: "org1",
+			},
+			res: res{},
+		},
+		{
+			name: "reactivate suspended org, reactivate members",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(
+						eventFromEventPusher(
+							org.NewOrgAddedEvent(context.Background(),
+								&org.NewAggregate("org1").Aggregate,
+								"org"),
+						),
+						eventFromEventPusher(
+							org.NewOrgDeactivatedEvent(context.Background(),
+								&org.NewAggregate("org1").Aggregate),
+						),
+					),
+					expectFilter(
+						eventFromEventPusher(
+							org.NewMemberAddedEvent(context.Background(),
+								&org.NewAggregate("org1").Aggregate,
+								"user1", "ORG_OWNER"),
+						),
+						eventFromEventPusher(
+							org.NewMemberAddedEvent(context.Background(),
+								&org.NewAggregate("org1").Aggregate,
+								"user2", "ORG_OWNER"),
+						),
+						eventFromEventPusher(
+							org.NewMemberDeactivatedEvent(context.Background(),
+								&org.NewAggregate("org1").Aggregate,
+								"user1"),
+						),
+					),
 					expectPush(
 						org.NewOrgReactivatedEvent(context.Background(),
 							&org.NewAggregate("org1").Aggregate,
 						),
+						org.NewMemberReactivatedEvent(context.Background(),
+							&org.NewAggregate("org1").Aggregate,
+							"user1",
+						),
 					),
 				),
 			},
@@ -989,14 +1260,14 @@ func TestCommandSide_ReactivateOrg(t *testing.T) {
 	}
 }
 
-func TestCommandSide_RemoveOrg(t *testing.T) {
+func TestCommandSide_SuspendOrg(t *testing.T) {
 	type fields struct {
-		eventstore  *eventstore.Eventstore
-		idGenerator id.Generator
+		eventstore *eventstore.Eventstore
 	}
 	type args struct {
-		ctx   context.Context
-		orgID string
+		ctx               context.Context
+		orgID             string
+		deactivateMembers bool
 	}
 	type res struct {
 		err func(error) bool
@@ -1008,42 +1279,230 @@ func TestCommandSide_RemoveOrg(t *testing.T) {
 		res    res
 	}{
 		{
-			name: "default org, error",
+			name: "org not found, error",
 			fields: fields{
 				eventstore: eventstoreExpect(
 					t,
+					expectFilter(),
 				),
 			},
 			args: args{
-				ctx:   authz.WithInstance(context.Background(), &mockInstance{}),
-				orgID: "defaultOrgID",
+				ctx:   context.Background(),
+				orgID: "org1",
 			},
 			res: res{
-				err: zerrors.IsPreconditionFailed,
+				err: zerrors.IsNotFound,
 			},
 		},
 		{
-			name: "zitadel org, error",
+			name: "org already inactive, error",
 			fields: fields{
 				eventstore: eventstoreExpect(
 					t,
 					expectFilter(
 						eventFromEventPusher(
-							project.NewProjectAddedEvent(context.Background(),
-								&project.NewAggregate("projectID", "org1").Aggregate,
-								"ZITADEL",
-								false,
-								false,
-								false,
-								domain.PrivateLabelingSettingUnspecified,
-							),
-						)),
-				),
-			},
-			args: args{
-				ctx:   context.Background(),
-				orgID: "org1",
-			},
+							org.NewOrgAddedEvent(context.Background(),
+								&org.NewAggregate("org1").Aggregate,
+								"org"),
+						),
+						eventFromEventPusher(
+							org.NewOrgDeactivatedEvent(context.Background(),
+								&org.NewAggregate("org1").Aggregate),
+						),
+					),
+				),
+			},
+			args: args{
+				ctx:   context.Background(),
+				orgID: "org1",
+			},
+			res: res{
+				err: zerrors.IsPreconditionFailed,
+			},
+		},
+		{
+			name: "suspend org without members",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(
+						eventFromEventPusher(
+							org.NewOrgAddedEvent(context.Background(),
+								&org.NewAggregate("org1").Aggregate,
+								"org"),
+						),
+					),
+					expectPush(
+						org.NewOrgDeactivatedEvent(context.Background(),
+							&org.NewAggregate("org1").Aggregate,
+						),
+					),
+				),
+			},
+			args: args{
+				ctx:   context.Background(),
+				orgID: "org1",
+			},
+			res: res{},
+		},
+		{
+			name: "suspend org, deactivate members",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(
+						eventFromEventPusher(
+							org.NewOrgAddedEvent(context.Background(),
+								&org.NewAggregate("org1").Aggregate,
+								"org"),
+						),
+					),
+					expectFilter(
+						eventFromEventPusher(
+							org.NewMemberAddedEvent(context.Background(),
+								&org.NewAggregate("org1").Aggregate,
+								"user2", "ORG_OWNER"),
+						),
+						eventFromEventPusher(
+							org.NewMemberAddedEvent(context.Background(),
+								&org.NewAggregate("org1").Aggregate,
+								"user1", "ORG_OWNER"),
+						),
+						eventFromEventPusher(
+							org.NewMemberAddedEvent(context.Background(),
+								&org.NewAggregate("org1").Aggregate,
+								"user3", "ORG_OWNER"),
+						),
+						eventFromEventPusher(
+							org.NewMemberRemovedEvent(context.Background(),
+								&org.NewAggregate("org1").Aggregate,
+								"user3"),
+						),
+					),
+					expectPush(
+						org.NewOrgDeactivatedEvent(context.Background(),
+							&org.NewAggregate("org1").Aggregate,
+						),
+						org.NewMemberDeactivatedEvent(context.Background(),
+							&org.NewAggregate("org1").Aggregate,
+							"user1",
+						),
+						org.NewMemberDeactivatedEvent(context.Background(),
+							&org.NewAggregate("org1").Aggregate,
+							"user2",
+						),
+					),
+				),
+			},
+			args: args{
+				ctx:               context.Background(),
+				orgID:             "org1",
+				deactivateMembers: true,
+			},
+			res: res{},
+		},
+		{
+			name: "push failed, error",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(
+						eventFromEventPusher(
+							org.NewOrgAddedEvent(context.Background(),
+								&org.NewAggregate("org1").Aggregate,
+								"org"),
+						),
+					),
+					expectFilter(),
+					expectPushFailed(
+						zerrors.ThrowInternal(nil, "id", "message"),
+						org.NewOrgDeactivatedEvent(context.Background(),
+							&org.NewAggregate("org1").Aggregate,
+						),
+					),
+				),
+			},
+			args: args{
+				ctx:               context.Background(),
+				orgID:             "org1",
+				deactivateMembers: true,
+			},
+			res: res{
+				err: zerrors.IsInternal,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Commands{
+				eventstore: tt.fields.eventstore,
+			}
+			_, err := r.SuspendOrg(tt.args.ctx, tt.args.orgID, tt.args.deactivateMembers)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+		})
+	}
+}
+
+func TestCommandSide_RemoveOrg(t *testing.T) {
+	type fields struct {
+		eventstore  *eventstore.Eventstore
+		idGenerator id.Generator
+	}
+	type args struct {
+		ctx   context.Context
+		orgID string
+	}
+	type res struct {
+		err func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "default org, error",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+				),
+			},
+			args: args{
+				ctx:   authz.WithInstance(context.Background(), &mockInstance{}),
+				orgID: "defaultOrgID",
+			},
+			res: res{
+				err: zerrors.IsPreconditionFailed,
+			},
+		},
+		{
+			name: "zitadel org, error",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(
+						eventFromEventPusher(
+							project.NewProjectAddedEvent(context.Background(),
+								&project.NewAggregate("projectID", "org1").Aggregate,
+								"ZITADEL",
+								false,
+								false,
+								false,
+								domain.PrivateLabelingSettingUnspecified,
+							),
+						)),
+				),
+			},
+			args: args{
+				ctx:   context.Background(),
+				orgID: "org1",
+			},
 			res: res{
 				err: zerrors.IsPreconditionFailed,
 			},
@@ -1092,10 +1551,11 @@ func TestCommandSide_RemoveOrg(t *testing.T) {
 					expectFilter(),
 					expectFilter(),
 					expectFilter(),
+					expectFilter(),
 					expectPushFailed(
 						zerrors.ThrowInternal(nil, "id", "message"),
 						org.NewOrgRemovedEvent(
-							context.Background(), &org.NewAggregate("org1").Aggregate, "org", []string{}, false, []string{}, []*domain.UserIDPLink{}, []string{},
+							context.Background(), &org.NewAggregate("org1").Aggregate, "org", []string{}, false, []string{}, []*domain.UserIDPLink{}, []string{}, []string{},
 						),
 					),
 				),
@@ -1135,9 +1595,10 @@ func TestCommandSide_RemoveOrg(t *testing.T) {
 					expectFilter(),
 					expectFilter(),
 					expectFilter(),
+					expectFilter(),
 					expectPush(
 						org.NewOrgRemovedEvent(
-							context.Background(), &org.NewAggregate("org1").Aggregate, "org", []string{}, false, []string{}, []*domain.UserIDPLink{}, []string{},
+							context.Background(), &org.NewAggregate("org1").Aggregate, "org", []string{}, false, []string{}, []*domain.UserIDPLink{}, []string{}, []string{},
 						),
 					),
 				),
@@ -1221,6 +1682,18 @@ func TestCommandSide_RemoveOrg(t *testing.T) {
 							project.NewSAMLConfigAddedEvent(context.Background(), &project.NewAggregate("project2", "org1").Aggregate, "app2", "entity2", []byte{}, ""),
 						),
 					),
+					expectFilter(
+						eventFromEventPusher(
+							func() eventstore.Command {
+								event := org.NewMemberAddedEvent(context.Background(), &org.NewAggregate("org1").Aggregate, "user1", domain.RoleOrgOwner)
+								event.UniqueEmail = "user1@example.com"
+								return event
+							}(),
+						),
+						eventFromEventPusher(
+							org.NewMemberAddedEvent(context.Background(), &org.NewAggregate("org1").Aggregate, "user2", domain.RoleOrgOwner),
+						),
+					),
 					expectPush(
 						org.NewOrgRemovedEvent(context.Background(), &org.NewAggregate("org1").Aggregate, "org",
 							[]string{"user1", "user2"},
@@ -1228,6 +1701,7 @@ func TestCommandSide_RemoveOrg(t *testing.T) {
 							[]string{"domain1", "domain2"},
 							[]*domain.UserIDPLink{{IDPConfigID: "config1", ExternalUserID: "id1", DisplayName: "display1"}, {IDPConfigID: "config2", ExternalUserID: "id2", DisplayName: "display2"}},
 							[]string{"entity1", "entity2"},
+							[]string{"user1@example.com"},
 						),
 					),
 				),
@@ -1381,6 +1855,16 @@ func TestCommandSide_SetUpOrg(t *testing.T) {
 							),
 						),
 					),
+					expectFilter(
+						eventFromEventPusher(
+							org.NewDomainPolicyAddedEvent(context.Background(),
+								&org.NewAggregate("orgID").Aggregate,
+								true,
+								true,
+								true,
+							),
+						),
+					),
 					expectPush(
 						eventFromEventPusher(org.NewOrgAddedEvent(context.Background(),
 							&org.NewAggregate("orgID").Aggregate,
@@ -1495,6 +1979,16 @@ func TestCommandSide_SetUpOrg(t *testing.T) {
 						),
 					),
 					expectFilter(), // org member check
+					expectFilter(
+						eventFromEventPusher(
+							org.NewDomainPolicyAddedEvent(context.Background(),
+								&org.NewAggregate("orgID").Aggregate,
+								true,
+								true,
+								true,
+							),
+						),
+					),
 					expectPush(
 						eventFromEventPusher(org.NewOrgAddedEvent(context.Background(),
 							&org.NewAggregate("orgID").Aggregate,
@@ -1575,6 +2069,16 @@ func TestCommandSide_SetUpOrg(t *testing.T) {
 							),
 						),
 					),
+					expectFilter(
+						eventFromEventPusher(
+							org.NewDomainPolicyAddedEvent(context.Background(),
+								&org.NewAggregate("orgID").Aggregate,
+								true,
+								true,
+								true,
+							),
+						),
+					),
 					expectPush(
 						eventFromEventPusher(org.NewOrgAddedEvent(context.Background(),
 							&org.NewAggregate("orgID").Aggregate,
@@ -1686,3 +2190,207 @@ func TestCommandSide_SetUpOrg(t *testing.T) {
 		})
 	}
 }
+
+func TestCommandSide_BulkCreateOrgs(t *testing.T) {
+	ctx := authz.WithRequestedDomain(authz.WithInstanceID(context.Background(), "instance1"), "iam-domain")
+	userAdded := func(userID string) eventstore.Event {
+		return eventFromEventPusher(
+			user.NewHumanAddedEvent(context.Background(),
+				&user.NewAggregate(userID, "org1").Aggregate,
+				"username",
+				"firstname",
+				"lastname",
+				"",
+				"firstname lastname",
+				language.English,
+				domain.GenderUnspecified,
+				"email@test.ch",
+				true,
+			),
+		)
+	}
+	type fields struct {
+		eventstore  func(t *testing.T) *eventstore.Eventstore
+		idGenerator id.Generator
+	}
+	type args struct {
+		ctx   context.Context
+		specs []OrgSpec
+	}
+	type res struct {
+		results []OrgResult
+		err     error
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "no instance, error",
+			fields: fields{
+				eventstore: expectEventstore(),
+			},
+			args: args{
+				ctx:   context.Background(),
+				specs: []OrgSpec{{Name: "Org"}},
+			},
+			res: res{
+				err: zerrors.ThrowPreconditionFailed(nil, "ORG-Qn5bv", "Errors.Instance.NotFound"),
+			},
+		},
+		{
+			name: "no specs, error",
+			fields: fields{
+				eventstore: expectEventstore(),
+			},
+			args: args{
+				ctx: ctx,
+			},
+			res: res{
+				err: zerrors.ThrowInvalidArgument(nil, "ORG-Dv3ll", "Errors.Invalid.Argument"),
+			},
+		},
+		{
+			name: "invalid names",
+			fields: fields{
+				eventstore: expectEventstore(),
+			},
+			args: args{
+				ctx: ctx,
+				specs: []OrgSpec{
+					{Name: " "},
+					{Name: "Org\x00"},
+					{Name: strings.Repeat("a", domain.OrgNameMaxLength+1)},
+				},
+			},
+			res: res{
+				results: []OrgResult{
+					{
+						Name: "",
+						Err:  zerrors.ThrowInvalidArgument(nil, "ORG-Kv7rb", "Errors.Org.Invalid"),
+					},
+					{
+						Name: "Org\x00",
+						Err:  zerrors.ThrowInvalidArgument(nil, "ORG-Kv7rb", "Errors.Org.Invalid"),
+					},
+					{
+						Name: strings.Repeat("a", domain.OrgNameMaxLength+1),
+						Err:  zerrors.ThrowInvalidArgument(nil, "ORG-Kv7rb", "Errors.Org.Invalid"),
+					},
+				},
+			},
+		},
+		{
+			name: "results per spec",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(userAdded("user1")),
+					expectFilter(), // org member check
+					expectFilter(),
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewDomainPolicyAddedEvent(context.Background(),
+								&instance.NewAggregate("instance1").Aggregate,
+								true,
+								true,
+								true,
+							),
+						),
+					),
+					expectPush(
+						org.NewOrgAddedEvent(context.Background(),
+							&org.NewAggregate("org1").Aggregate,
+							"Org",
+						),
+						org.NewDomainAddedEvent(context.Background(),
+							&org.NewAggregate("org1").Aggregate, "org.iam-domain",
+						),
+						org.NewDomainVerifiedEvent(context.Background(),
+							&org.NewAggregate("org1").Aggregate,
+							"org.iam-domain",
+						),
+						org.NewDomainPrimarySetEvent(context.Background(),
+							&org.NewAggregate("org1").Aggregate,
+							"org.iam-domain",
+						),
+						org.NewMemberAddedEvent(context.Background(),
+							&org.NewAggregate("org1").Aggregate,
+							"user1",
+							domain.RoleOrgOwner,
+						),
+					),
+					expectFilter(), // user2 doesn't exist
+				),
+				idGenerator: id_mock.NewIDGeneratorExpectIDs(t, "org1", "org2", "org3"),
+			},
+			args: args{
+				ctx: ctx,
+				specs: []OrgSpec{
+					{
+						Name:    "Org",
+						Members: []OrgSpecMember{{UserID: "user1"}},
+					},
+					{
+						Name:    " org ",
+						Members: []OrgSpecMember{{UserID: "user1"}},
+					},
+					{
+						Name:    "Other",
+						Members: []OrgSpecMember{{UserID: "user2"}},
+					},
+					{
+						Name:    "Invalid Role",
+						Members: []OrgSpecMember{{UserID: "user1", Roles: []string{"PROJECT_OWNER"}}},
+					},
+				},
+			},
+			res: res{
+				results: []OrgResult{
+					{
+						Name:  "Org",
+						OrgID: "org1",
+						Details: &domain.ObjectDetails{
+							ResourceOwner: "org1",
+						},
+					},
+					{
+						Name: "org",
+						Err:  zerrors.ThrowAlreadyExists(nil, "ORG-Gm8ts", "Errors.Org.AlreadyExisting"),
+					},
+					{
+						Name: "Other",
+						Err:  zerrors.ThrowPreconditionFailed(nil, "ORG-GoXOn", "Errors.User.NotFound"),
+					},
+					{
+						Name: "Invalid Role",
+						Err:  zerrors.ThrowInvalidArgument(nil, "Org-4N8es", "Errors.Org.MemberInvalid"),
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Commands{
+				eventstore:  tt.fields.eventstore(t),
+				idGenerator: tt.fields.idGenerator,
+				zitadelRoles: []authz.RoleMapping{
+					{
+						Role: domain.RoleOrgOwner,
+					},
+				},
+			}
+			got, err := r.BulkCreateOrgs(tt.args.ctx, tt.args.specs)
+			require.ErrorIs(t, err, tt.res.err)
+			require.Len(t, got, len(tt.res.results))
+			for i, want := range tt.res.results {
+				assert.Equal(t, want.Name, got[i].Name)
+				assert.Equal(t, want.OrgID, got[i].OrgID)
+				assert.Equal(t, want.Details, got[i].Details)
+				assert.ErrorIs(t, got[i].Err, want.Err)
+			}
+		})
+	}
+}
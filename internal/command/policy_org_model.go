@@ -17,6 +17,7 @@ type PolicyDomainWriteModel struct {
 	UserLoginMustBeDomain                  bool
 	ValidateOrgDomains                     bool
 	SMTPSenderAddressMatchesInstanceDomain bool
+	MemberEmailsUnique                     bool
 	State                                  domain.PolicyState
 }
 
@@ -27,6 +28,7 @@ func (wm *PolicyDomainWriteModel) Reduce() error {
 			wm.UserLoginMustBeDomain = e.UserLoginMustBeDomain
 			wm.ValidateOrgDomains = e.ValidateOrgDomains
 			wm.SMTPSenderAddressMatchesInstanceDomain = e.SMTPSenderAddressMatchesInstanceDomain
+			wm.MemberEmailsUnique = e.MemberEmailsUnique
 			wm.State = domain.PolicyStateActive
 		case *policy.DomainPolicyChangedEvent:
 			if e.UserLoginMustBeDomain != nil {
@@ -38,6 +40,9 @@ func (wm *PolicyDomainWriteModel) Reduce() error {
 			if e.SMTPSenderAddressMatchesInstanceDomain != nil {
 				wm.SMTPSenderAddressMatchesInstanceDomain = *e.SMTPSenderAddressMatchesInstanceDomain
 			}
+			if e.MemberEmailsUnique != nil {
+				wm.MemberEmailsUnique = *e.MemberEmailsUnique
+			}
 		case *policy.DomainPolicyRemovedEvent:
 			wm.State = domain.PolicyStateRemoved
 		}
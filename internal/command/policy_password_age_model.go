@@ -11,6 +11,7 @@ type PasswordAgePolicyWriteModel struct {
 
 	ExpireWarnDays uint64
 	MaxAgeDays     uint64
+	HistoryCount   uint64
 	State          domain.PolicyState
 }
 
@@ -20,6 +21,7 @@ func (wm *PasswordAgePolicyWriteModel) Reduce() error {
 		case *policy.PasswordAgePolicyAddedEvent:
 			wm.ExpireWarnDays = e.ExpireWarnDays
 			wm.MaxAgeDays = e.MaxAgeDays
+			wm.HistoryCount = e.HistoryCount
 			wm.State = domain.PolicyStateActive
 		case *policy.PasswordAgePolicyChangedEvent:
 			if e.ExpireWarnDays != nil {
@@ -28,6 +30,9 @@ func (wm *PasswordAgePolicyWriteModel) Reduce() error {
 			if e.MaxAgeDays != nil {
 				wm.MaxAgeDays = *e.MaxAgeDays
 			}
+			if e.HistoryCount != nil {
+				wm.HistoryCount = *e.HistoryCount
+			}
 		case *policy.PasswordAgePolicyRemovedEvent:
 			wm.State = domain.PolicyStateRemoved
 		}
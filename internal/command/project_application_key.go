@@ -3,7 +3,12 @@ package command
 import (
 	"context"
 
+	"github.com/zitadel/logging"
+
 	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/handler/v2"
+	"github.com/zitadel/zitadel/internal/query/projection"
 	"github.com/zitadel/zitadel/internal/repository/project"
 	"github.com/zitadel/zitadel/internal/telemetry/tracing"
 	"github.com/zitadel/zitadel/internal/zerrors"
@@ -121,6 +126,46 @@ func (c *Commands) RemoveApplicationKey(ctx context.Context, projectID, applicat
 	return writeModelToObjectDetails(&keyWriteModel.WriteModel), nil
 }
 
+// RevokeAllAppKeys removes all keys of the application in a single push and returns the amount of removed keys.
+// The keys projection is triggered afterwards, so authentication with the removed keys fails immediately.
+func (c *Commands) RevokeAllAppKeys(ctx context.Context, projectID, appID, resourceOwner string) (revoked int, err error) {
+	revoked, err = c.revokeAllAppKeys(ctx, projectID, appID, resourceOwner)
+	if err != nil || revoked == 0 {
+		return revoked, err
+	}
+	_, err = projection.AuthNKeyProjection.Trigger(ctx, handler.WithAwaitRunning())
+	logging.WithFields("projectID", projectID, "appID", appID).OnError(err).Warn("unable to trigger authn keys projection")
+	return revoked, nil
+}
+
+func (c *Commands) revokeAllAppKeys(ctx context.Context, projectID, appID, resourceOwner string) (_ int, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if projectID == "" || appID == "" {
+		return 0, zerrors.ThrowInvalidArgument(nil, "COMMAND-Rk3vd", "Errors.IDMissing")
+	}
+	writeModel := NewApplicationKeysWriteModel(projectID, appID, resourceOwner)
+	if err = c.eventstore.FilterToQueryReducer(ctx, writeModel); err != nil {
+		return 0, err
+	}
+	if !writeModel.AppState.Exists() {
+		return 0, zerrors.ThrowNotFound(nil, "COMMAND-Rk8pw", "Errors.Project.App.NotFound")
+	}
+	if len(writeModel.KeyIDs) == 0 {
+		return 0, nil
+	}
+	aggregate := ProjectAggregateFromWriteModel(&writeModel.WriteModel)
+	events := make([]eventstore.Command, len(writeModel.KeyIDs))
+	for i, keyID := range writeModel.KeyIDs {
+		events[i] = project.NewApplicationKeyRemovedEvent(ctx, aggregate, keyID)
+	}
+	if _, err = c.eventstore.Push(ctx, events...); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
+
 func (c *Commands) applicationKeyWriteModelByID(ctx context.Context, projectID, appID, keyID, resourceOwner string) (writeModel *ApplicationKeyWriteModel, err error) {
 	if appID == "" {
 		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-029sn", "Errors.Project.App.NotFound")
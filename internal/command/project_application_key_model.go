@@ -1,6 +1,7 @@
 package command
 
 import (
+	"slices"
 	"time"
 
 	"github.com/zitadel/zitadel/internal/domain"
@@ -142,3 +143,85 @@ func (wm *ApplicationKeyWriteModel) Query() *eventstore.SearchQueryBuilder {
 			project.ProjectRemovedType).
 		Builder()
 }
+
+// ApplicationKeysWriteModel collects the ids of the keys of an application which are not removed
+type ApplicationKeysWriteModel struct {
+	eventstore.WriteModel
+
+	AppID  string
+	KeyIDs []string
+
+	AppState domain.AppState
+}
+
+func NewApplicationKeysWriteModel(projectID, appID, resourceOwner string) *ApplicationKeysWriteModel {
+	return &ApplicationKeysWriteModel{
+		WriteModel: eventstore.WriteModel{
+			AggregateID:   projectID,
+			ResourceOwner: resourceOwner,
+		},
+		AppID: appID,
+	}
+}
+
+func (wm *ApplicationKeysWriteModel) AppendEvents(events ...eventstore.Event) {
+	for _, event := range events {
+		switch e := event.(type) {
+		case *project.ApplicationAddedEvent:
+			if e.AppID != wm.AppID {
+				continue
+			}
+			wm.WriteModel.AppendEvents(e)
+		case *project.ApplicationRemovedEvent:
+			if e.AppID != wm.AppID {
+				continue
+			}
+			wm.WriteModel.AppendEvents(e)
+		case *project.ApplicationKeyAddedEvent:
+			if e.AppID != wm.AppID {
+				continue
+			}
+			wm.WriteModel.AppendEvents(e)
+		case *project.ApplicationKeyRemovedEvent,
+			*project.ProjectRemovedEvent:
+			wm.WriteModel.AppendEvents(e)
+		}
+	}
+}
+
+func (wm *ApplicationKeysWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch e := event.(type) {
+		case *project.ApplicationAddedEvent:
+			wm.AppState = domain.AppStateActive
+		case *project.ApplicationRemovedEvent:
+			wm.AppState = domain.AppStateRemoved
+			wm.KeyIDs = nil
+		case *project.ApplicationKeyAddedEvent:
+			wm.KeyIDs = append(wm.KeyIDs, e.KeyID)
+		case *project.ApplicationKeyRemovedEvent:
+			wm.KeyIDs = slices.DeleteFunc(wm.KeyIDs, func(keyID string) bool {
+				return keyID == e.KeyID
+			})
+		case *project.ProjectRemovedEvent:
+			wm.AppState = domain.AppStateRemoved
+			wm.KeyIDs = nil
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *ApplicationKeysWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		ResourceOwner(wm.ResourceOwner).
+		AddQuery().
+		AggregateTypes(project.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(
+			project.ApplicationAddedType,
+			project.ApplicationRemovedType,
+			project.ApplicationKeyAddedEventType,
+			project.ApplicationKeyRemovedEventType,
+			project.ProjectRemovedType).
+		Builder()
+}
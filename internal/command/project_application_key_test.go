@@ -3,6 +3,7 @@ package command
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -200,3 +201,140 @@ func TestCommandSide_AddAPIApplicationKey(t *testing.T) {
 		})
 	}
 }
+
+func TestCommandSide_revokeAllAppKeys(t *testing.T) {
+	keyAdded := func(keyID string) eventstore.Event {
+		return eventFromEventPusher(
+			project.NewApplicationKeyAddedEvent(context.Background(),
+				&project.NewAggregate("project1", "org1").Aggregate,
+				"app1",
+				"client1@project",
+				keyID,
+				domain.AuthNKeyTypeJSON,
+				time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC),
+				[]byte("public"),
+			),
+		)
+	}
+	type args struct {
+		projectID string
+		appID     string
+	}
+	type res struct {
+		revoked int
+		err     func(error) bool
+	}
+	tests := []struct {
+		name       string
+		eventstore *eventstore.Eventstore
+		args       args
+		res        res
+	}{
+		{
+			name:       "no appid, invalid argument error",
+			eventstore: eventstoreExpect(t),
+			args: args{
+				projectID: "project1",
+			},
+			res: res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			name: "app not existing, not found error",
+			eventstore: eventstoreExpect(t,
+				expectFilter(),
+			),
+			args: args{
+				projectID: "project1",
+				appID:     "app1",
+			},
+			res: res{
+				err: zerrors.IsNotFound,
+			},
+		},
+		{
+			name: "no keys, ok",
+			eventstore: eventstoreExpect(t,
+				expectFilter(
+					eventFromEventPusher(
+						project.NewApplicationAddedEvent(context.Background(),
+							&project.NewAggregate("project1", "org1").Aggregate,
+							"app1",
+							"app",
+						),
+					),
+					keyAdded("key1"),
+					eventFromEventPusher(
+						project.NewApplicationKeyRemovedEvent(context.Background(),
+							&project.NewAggregate("project1", "org1").Aggregate,
+							"key1",
+						),
+					),
+				),
+			),
+			args: args{
+				projectID: "project1",
+				appID:     "app1",
+			},
+			res: res{
+				revoked: 0,
+			},
+		},
+		{
+			name: "keys revoked, ok",
+			eventstore: eventstoreExpect(t,
+				expectFilter(
+					eventFromEventPusher(
+						project.NewApplicationAddedEvent(context.Background(),
+							&project.NewAggregate("project1", "org1").Aggregate,
+							"app1",
+							"app",
+						),
+					),
+					keyAdded("key1"),
+					keyAdded("key2"),
+					keyAdded("key3"),
+					eventFromEventPusher(
+						project.NewApplicationKeyRemovedEvent(context.Background(),
+							&project.NewAggregate("project1", "org1").Aggregate,
+							"key2",
+						),
+					),
+				),
+				expectPush(
+					project.NewApplicationKeyRemovedEvent(context.Background(),
+						&project.NewAggregate("project1", "org1").Aggregate,
+						"key1",
+					),
+					project.NewApplicationKeyRemovedEvent(context.Background(),
+						&project.NewAggregate("project1", "org1").Aggregate,
+						"key3",
+					),
+				),
+			),
+			args: args{
+				projectID: "project1",
+				appID:     "app1",
+			},
+			res: res{
+				revoked: 2,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Commands{
+				eventstore: tt.eventstore,
+			}
+			got, err := r.revokeAllAppKeys(context.Background(), tt.args.projectID, tt.args.appID, "org1")
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			assert.Equal(t, tt.res.revoked, got)
+		})
+	}
+}
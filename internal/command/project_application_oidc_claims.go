@@ -0,0 +1,103 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	project_repo "github.com/zitadel/zitadel/internal/repository/project"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// SetOIDCClaimsMapping replaces the custom claims mapping of the OIDC application.
+// The templates may reference the attributes of human users ([domain.OIDCClaimAttributes])
+// and, if a user schema is passed, the properties of the schema.
+// Passing no mappings removes the custom claims.
+func (c *Commands) SetOIDCClaimsMapping(ctx context.Context, projectID, appID, resourceOwner, userSchemaID string, mappings []*domain.OIDCClaimMapping) (*domain.ObjectDetails, error) {
+	if projectID == "" || appID == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Cm4ra", "Errors.IDMissing")
+	}
+	attributes := domain.OIDCClaimAttributes
+	if userSchemaID != "" {
+		schemaAttributes, err := c.userSchemaAttributes(ctx, userSchemaID)
+		if err != nil {
+			return nil, err
+		}
+		attributes = append(slices.Clip(attributes), schemaAttributes...)
+	}
+	if err := validateOIDCClaimsMapping(mappings, attributes); err != nil {
+		return nil, err
+	}
+
+	app, err := c.getOIDCAppWriteModel(ctx, projectID, appID, resourceOwner)
+	if err != nil {
+		return nil, err
+	}
+	if !app.State.Exists() {
+		return nil, zerrors.ThrowNotFound(nil, "COMMAND-Jt8xq", "Errors.Project.App.NotExisting")
+	}
+	if !app.IsOIDC() {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Ob2vs", "Errors.Project.App.IsNotOIDC")
+	}
+	err = c.pushAppendAndReduce(ctx, app,
+		project_repo.NewOIDCConfigClaimsMappingSetEvent(ctx, ProjectAggregateFromWriteModel(&app.WriteModel), appID, userSchemaID, mappings),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return writeModelToObjectDetails(&app.WriteModel), nil
+}
+
+// validateOIDCClaimsMapping checks the claim names and that the templates only reference existing attributes
+func validateOIDCClaimsMapping(mappings []*domain.OIDCClaimMapping, attributes []string) error {
+	claims := make([]string, 0, len(mappings))
+	for _, mapping := range mappings {
+		if mapping == nil || !domain.ValidOIDCClaimName(mapping.Claim) || slices.Contains(claims, mapping.Claim) {
+			return zerrors.ThrowInvalidArgument(nil, "COMMAND-Vd3ny", "Errors.Project.App.ClaimsMappingInvalid")
+		}
+		claims = append(claims, mapping.Claim)
+		referenced, ok := domain.OIDCClaimTemplateAttributes(mapping.Template)
+		if !ok {
+			return zerrors.ThrowInvalidArgument(nil, "COMMAND-Ky6wp", "Errors.Project.App.ClaimsMappingInvalid")
+		}
+		for _, attribute := range referenced {
+			if !slices.Contains(attributes, attribute) {
+				return zerrors.ThrowInvalidArgument(nil, "COMMAND-Qe9zb", "Errors.Project.App.ClaimsMappingInvalid")
+			}
+		}
+	}
+	return nil
+}
+
+// userSchemaAttributes returns the property paths of the active user schema
+func (c *Commands) userSchemaAttributes(ctx context.Context, userSchemaID string) ([]string, error) {
+	writeModel := NewUserSchemaWriteModel(userSchemaID, authz.GetInstance(ctx).InstanceID())
+	if err := c.eventstore.FilterToQueryReducer(ctx, writeModel); err != nil {
+		return nil, err
+	}
+	if writeModel.State != domain.UserSchemaStateActive {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "COMMAND-Uw5fk", "Errors.UserSchema.NotActive")
+	}
+	var userSchema jsonSchemaProperties
+	if err := json.Unmarshal(writeModel.Schema, &userSchema); err != nil {
+		return nil, zerrors.ThrowInternal(err, "COMMAND-Hs2le", "Errors.UserSchema.Schema.Invalid")
+	}
+	return userSchema.paths(""), nil
+}
+
+// jsonSchemaProperties is the part of a JSON schema describing the (nested) properties of an object
+type jsonSchemaProperties struct {
+	Properties map[string]jsonSchemaProperties `json:"properties"`
+}
+
+func (p jsonSchemaProperties) paths(prefix string) []string {
+	paths := make([]string, 0, len(p.Properties))
+	for name, property := range p.Properties {
+		paths = append(paths, prefix+name)
+		paths = append(paths, property.paths(prefix+name+".")...)
+	}
+	slices.Sort(paths)
+	return paths
+}
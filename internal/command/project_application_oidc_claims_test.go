@@ -0,0 +1,203 @@
+package command
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/project"
+	"github.com/zitadel/zitadel/internal/repository/user/schema"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestCommands_SetOIDCClaimsMapping(t *testing.T) {
+	ctx := authz.NewMockContext("instance1", "org1", "")
+	agg := project.NewAggregate("project1", "org1")
+	oidcAppEvents := func() []eventstore.Event {
+		return []eventstore.Event{
+			eventFromEventPusher(
+				project.NewApplicationAddedEvent(ctx, &agg.Aggregate, "app1", "app"),
+			),
+			eventFromEventPusher(
+				project.NewOIDCConfigAddedEvent(ctx, &agg.Aggregate,
+					domain.OIDCVersionV1,
+					"app1",
+					"clientID",
+					"",
+					[]string{"https://test.ch"},
+					[]domain.OIDCResponseType{domain.OIDCResponseTypeCode},
+					[]domain.OIDCGrantType{domain.OIDCGrantTypeAuthorizationCode},
+					domain.OIDCApplicationTypeWeb,
+					domain.OIDCAuthMethodTypeNone,
+					nil,
+					false,
+					domain.OIDCTokenTypeBearer,
+					false,
+					false,
+					false,
+					0,
+					nil,
+					false,
+				),
+			),
+		}
+	}
+	schemaCreated := eventFromEventPusher(
+		schema.NewCreatedEvent(ctx,
+			&schema.NewAggregate("schema1", "instance1").Aggregate,
+			"employee",
+			json.RawMessage(`{"type":"object","properties":{"department":{"type":"string"},"address":{"type":"object","properties":{"city":{"type":"string"}}}}}`),
+			nil,
+		),
+	)
+	type args struct {
+		userSchemaID string
+		mappings     []*domain.OIDCClaimMapping
+	}
+	tests := []struct {
+		name       string
+		eventstore func(*testing.T) *eventstore.Eventstore
+		args       args
+		want       *domain.ObjectDetails
+		wantErr    error
+	}{
+		{
+			name:       "reserved claim, error",
+			eventstore: expectEventstore(),
+			args: args{
+				mappings: []*domain.OIDCClaimMapping{{Claim: "sub", Template: "{{ email }}"}},
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-Vd3ny", "Errors.Project.App.ClaimsMappingInvalid"),
+		},
+		{
+			name:       "duplicate claim, error",
+			eventstore: expectEventstore(),
+			args: args{
+				mappings: []*domain.OIDCClaimMapping{
+					{Claim: "mail", Template: "{{ email }}"},
+					{Claim: "mail", Template: "{{ username }}"},
+				},
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-Vd3ny", "Errors.Project.App.ClaimsMappingInvalid"),
+		},
+		{
+			name:       "malformed template, error",
+			eventstore: expectEventstore(),
+			args: args{
+				mappings: []*domain.OIDCClaimMapping{{Claim: "mail", Template: "{{ email }"}},
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-Ky6wp", "Errors.Project.App.ClaimsMappingInvalid"),
+		},
+		{
+			name:       "unknown attribute, error",
+			eventstore: expectEventstore(),
+			args: args{
+				mappings: []*domain.OIDCClaimMapping{{Claim: "department", Template: "{{ department }}"}},
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-Qe9zb", "Errors.Project.App.ClaimsMappingInvalid"),
+		},
+		{
+			name: "user schema not active, error",
+			eventstore: expectEventstore(
+				expectFilter(),
+			),
+			args: args{
+				userSchemaID: "schema1",
+				mappings:     []*domain.OIDCClaimMapping{{Claim: "department", Template: "{{ department }}"}},
+			},
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "COMMAND-Uw5fk", "Errors.UserSchema.NotActive"),
+		},
+		{
+			name: "app not existing, error",
+			eventstore: expectEventstore(
+				expectFilter(),
+			),
+			args: args{
+				mappings: []*domain.OIDCClaimMapping{{Claim: "mail", Template: "{{ email }}"}},
+			},
+			wantErr: zerrors.ThrowNotFound(nil, "COMMAND-Jt8xq", "Errors.Project.App.NotExisting"),
+		},
+		{
+			name: "app not oidc, error",
+			eventstore: expectEventstore(
+				expectFilter(
+					eventFromEventPusher(
+						project.NewApplicationAddedEvent(ctx, &agg.Aggregate, "app1", "app"),
+					),
+				),
+			),
+			args: args{
+				mappings: []*domain.OIDCClaimMapping{{Claim: "mail", Template: "{{ email }}"}},
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-Ob2vs", "Errors.Project.App.IsNotOIDC"),
+		},
+		{
+			name: "mapping set",
+			eventstore: expectEventstore(
+				expectFilter(oidcAppEvents()...),
+				expectPush(
+					project.NewOIDCConfigClaimsMappingSetEvent(ctx, &agg.Aggregate, "app1", "",
+						[]*domain.OIDCClaimMapping{
+							{Claim: "name", Template: "{{ given_name }} {{family_name}}"},
+							{Claim: "https://example.com/claims/mail", Template: "{{ email }}"},
+						},
+					),
+				),
+			),
+			args: args{
+				mappings: []*domain.OIDCClaimMapping{
+					{Claim: "name", Template: "{{ given_name }} {{family_name}}"},
+					{Claim: "https://example.com/claims/mail", Template: "{{ email }}"},
+				},
+			},
+			want: &domain.ObjectDetails{
+				ResourceOwner: "org1",
+			},
+		},
+		{
+			name: "mapping with user schema attributes set",
+			eventstore: expectEventstore(
+				expectFilter(schemaCreated),
+				expectFilter(oidcAppEvents()...),
+				expectPush(
+					project.NewOIDCConfigClaimsMappingSetEvent(ctx, &agg.Aggregate, "app1", "schema1",
+						[]*domain.OIDCClaimMapping{
+							{Claim: "location", Template: "{{ department }}, {{ address.city }}"},
+						},
+					),
+				),
+			),
+			args: args{
+				userSchemaID: "schema1",
+				mappings: []*domain.OIDCClaimMapping{
+					{Claim: "location", Template: "{{ department }}, {{ address.city }}"},
+				},
+			},
+			want: &domain.ObjectDetails{
+				ResourceOwner: "org1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.eventstore(t),
+			}
+			got, err := c.SetOIDCClaimsMapping(ctx, "project1", "app1", "org1", tt.args.userSchemaID, tt.args.mappings)
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_jsonSchemaProperties_paths(t *testing.T) {
+	var properties jsonSchemaProperties
+	err := json.Unmarshal([]byte(`{"properties":{"b":{"properties":{"c":{}}},"a":{}}}`), &properties)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "b.c"}, properties.paths(""))
+}
@@ -36,6 +36,8 @@ type OIDCApplicationWriteModel struct {
 	State                    domain.AppState
 	AdditionalOrigins        []string
 	SkipNativeAppSuccessPage bool
+	ClaimsUserSchemaID       string
+	ClaimsMappings           []*domain.OIDCClaimMapping
 	oidc                     bool
 }
 
@@ -105,6 +107,11 @@ func (wm *OIDCApplicationWriteModel) AppendEvents(events ...eventstore.Event) {
 				continue
 			}
 			wm.WriteModel.AppendEvents(e)
+		case *project.OIDCConfigClaimsMappingSetEvent:
+			if e.AppID != wm.AppID {
+				continue
+			}
+			wm.WriteModel.AppendEvents(e)
 		case *project.ProjectRemovedEvent:
 			wm.WriteModel.AppendEvents(e)
 		}
@@ -139,6 +146,9 @@ func (wm *OIDCApplicationWriteModel) Reduce() error {
 			wm.HashedSecret = crypto.SecretOrEncodedHash(e.ClientSecret, e.HashedSecret)
 		case *project.OIDCConfigSecretHashUpdatedEvent:
 			wm.HashedSecret = e.HashedSecret
+		case *project.OIDCConfigClaimsMappingSetEvent:
+			wm.ClaimsUserSchemaID = e.UserSchemaID
+			wm.ClaimsMappings = e.Mappings
 		case *project.ProjectRemovedEvent:
 			wm.State = domain.AppStateRemoved
 		}
@@ -231,6 +241,7 @@ func (wm *OIDCApplicationWriteModel) Query() *eventstore.SearchQueryBuilder {
 			project.OIDCConfigChangedType,
 			project.OIDCConfigSecretChangedType,
 			project.OIDCConfigSecretHashUpdatedType,
+			project.OIDCConfigClaimsMappingSetType,
 			project.ProjectRemovedType,
 		).Builder()
 }
@@ -0,0 +1,71 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+const (
+	// defaultPushRetries is the amount of retries of [Commands.pushAppendAndReduceRetry] after a concurrent push
+	defaultPushRetries = 3
+	// defaultPushRetryMaxDelay is the upper limit of the backoff between the retries of [Commands.pushAppendAndReduceRetry]
+	defaultPushRetryMaxDelay = 200 * time.Millisecond
+	// pushRetryBaseDelay is doubled on every retry until the max delay is reached
+	pushRetryBaseDelay = 10 * time.Millisecond
+)
+
+// pushAppendAndReduceRetry pushes the commands returned by prepare like [Commands.pushAppendAndReduce].
+// prepare loads the current state of the object and builds the commands from it.
+// If the push failed because of a concurrent push to the same aggregate,
+// prepare is called again after a jittered backoff, so the commands are validated against the reloaded state.
+// The error of the last push is returned if all retries failed.
+func (c *Commands) pushAppendAndReduceRetry(ctx context.Context, prepare func() (AppendReducer, []eventstore.Command, error)) error {
+	for retry := 0; ; retry++ {
+		object, cmds, err := prepare()
+		if err != nil {
+			return err
+		}
+		err = c.pushAppendAndReduce(ctx, object, cmds...)
+		if err == nil || retry >= c.pushRetries || !isConcurrentPushError(err) {
+			return err
+		}
+		logging.WithError(err).WithField("retry", retry+1).Info("retry push after concurrent push")
+		if err = c.waitPushRetry(ctx, retry); err != nil {
+			return err
+		}
+	}
+}
+
+// waitPushRetry waits a random duration up to the exponential backoff of the retry
+func (c *Commands) waitPushRetry(ctx context.Context, retry int) error {
+	backoff := min(pushRetryBaseDelay<<retry, c.pushRetryMaxDelay)
+	if backoff <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(time.Duration(rand.Int63n(int64(backoff)) + 1))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isConcurrentPushError returns true if the push failed because events were pushed to the same aggregate in the meantime:
+// the sequence of the aggregate was taken by another push or the transaction was aborted by the database
+func isConcurrentPushError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return (pgErr.SQLState() == "23505" && pgErr.ConstraintName == "events2_pkey") ||
+		pgErr.SQLState() == "40001"
+}
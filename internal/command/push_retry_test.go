@@ -0,0 +1,200 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/org"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestCommands_pushAppendAndReduceRetry(t *testing.T) {
+	concurrentPush := zerrors.ThrowInternal(&pgconn.PgError{
+		ConstraintName: "events2_pkey",
+		Code:           "23505",
+	}, "V3-VGnZY", "Errors.Internal")
+	orgAdded := func() eventstore.Event {
+		return eventFromEventPusher(org.NewOrgAddedEvent(context.Background(), &org.NewAggregate("org1").Aggregate, "org"))
+	}
+	orgChanged := func(oldName, newName string) *org.OrgChangedEvent {
+		return org.NewOrgChangedEvent(context.Background(), &org.NewAggregate("org1").Aggregate, oldName, newName)
+	}
+	type res struct {
+		prepared int
+		name     string
+		err      func(error) bool
+	}
+	tests := []struct {
+		name       string
+		eventstore func(*testing.T) *eventstore.Eventstore
+		retries    int
+		res        res
+	}{
+		{
+			name: "no conflict",
+			eventstore: expectEventstore(
+				expectFilter(orgAdded()),
+				expectPush(orgChanged("org", "neworg")),
+			),
+			retries: 3,
+			res: res{
+				prepared: 1,
+				name:     "neworg",
+			},
+		},
+		{
+			name: "conflict, commands built from reloaded state",
+			eventstore: expectEventstore(
+				expectFilter(orgAdded()),
+				expectPushFailed(concurrentPush, orgChanged("org", "neworg")),
+				expectFilter(orgAdded(), eventFromEventPusher(orgChanged("org", "other"))),
+				expectPush(orgChanged("other", "neworg")),
+			),
+			retries: 3,
+			res: res{
+				prepared: 2,
+				name:     "neworg",
+			},
+		},
+		{
+			name: "conflict, validation fails on reloaded state",
+			eventstore: expectEventstore(
+				expectFilter(orgAdded()),
+				expectPushFailed(concurrentPush, orgChanged("org", "neworg")),
+				expectFilter(orgAdded(), eventFromEventPusher(orgChanged("org", "neworg"))),
+			),
+			retries: 3,
+			res: res{
+				prepared: 2,
+				err:      zerrors.IsPreconditionFailed,
+			},
+		},
+		{
+			name: "retries exhausted",
+			eventstore: expectEventstore(
+				expectFilter(orgAdded()),
+				expectPushFailed(concurrentPush, orgChanged("org", "neworg")),
+				expectFilter(orgAdded()),
+				expectPushFailed(concurrentPush, orgChanged("org", "neworg")),
+			),
+			retries: 1,
+			res: res{
+				prepared: 2,
+				err:      zerrors.IsInternal,
+			},
+		},
+		{
+			name: "other error not retried",
+			eventstore: expectEventstore(
+				expectFilter(orgAdded()),
+				expectPushFailed(zerrors.ThrowInternal(nil, "id", "message"), orgChanged("org", "neworg")),
+			),
+			retries: 3,
+			res: res{
+				prepared: 1,
+				err:      zerrors.IsInternal,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore:        tt.eventstore(t),
+				pushRetries:       tt.retries,
+				pushRetryMaxDelay: time.Millisecond,
+			}
+			var (
+				writeModel *OrgWriteModel
+				prepared   int
+			)
+			err := c.pushAppendAndReduceRetry(context.Background(), func() (_ AppendReducer, _ []eventstore.Command, err error) {
+				prepared++
+				writeModel, err = c.getOrgWriteModelByID(context.Background(), "org1")
+				if err != nil {
+					return nil, nil, err
+				}
+				if writeModel.Name == "neworg" {
+					return nil, nil, zerrors.ThrowPreconditionFailed(nil, "id", "message")
+				}
+				return writeModel, []eventstore.Command{orgChanged(writeModel.Name, "neworg")}, nil
+			})
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			assert.Equal(t, tt.res.prepared, prepared)
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.name, writeModel.Name)
+			}
+		})
+	}
+}
+
+func TestCommands_ChangeOrg_retry(t *testing.T) {
+	concurrentPush := zerrors.ThrowInternal(&pgconn.PgError{
+		ConstraintName: "events2_pkey",
+		Code:           "23505",
+	}, "V3-VGnZY", "Errors.Internal")
+	orgAgg := &org.NewAggregate("org1").Aggregate
+	c := &Commands{
+		eventstore: expectEventstore(
+			expectFilter(eventFromEventPusher(org.NewOrgAddedEvent(context.Background(), orgAgg, "org"))),
+			expectFilter(),
+			expectPushFailed(concurrentPush, org.NewOrgChangedEvent(context.Background(), orgAgg, "org", "neworg")),
+			// the org was renamed in the meantime
+			expectFilter(
+				eventFromEventPusher(org.NewOrgAddedEvent(context.Background(), orgAgg, "org")),
+				eventFromEventPusher(org.NewOrgChangedEvent(context.Background(), orgAgg, "org", "other")),
+			),
+			expectFilter(),
+			expectPush(org.NewOrgChangedEvent(context.Background(), orgAgg, "other", "neworg")),
+		)(t),
+		pushRetries:       1,
+		pushRetryMaxDelay: time.Millisecond,
+	}
+	got, err := c.ChangeOrg(context.Background(), "org1", "neworg")
+	require.NoError(t, err)
+	assert.Equal(t, "org1", got.ResourceOwner)
+}
+
+func Test_isConcurrentPushError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "sequence taken",
+			err:  zerrors.ThrowInternal(&pgconn.PgError{ConstraintName: "events2_pkey", Code: "23505"}, "id", "message"),
+			want: true,
+		},
+		{
+			name: "serialization failure",
+			err:  &pgconn.PgError{Code: "40001"},
+			want: true,
+		},
+		{
+			name: "other unique constraint",
+			err:  &pgconn.PgError{ConstraintName: "unique_constraints_pkey", Code: "23505"},
+			want: false,
+		},
+		{
+			name: "no database error",
+			err:  zerrors.ThrowInternal(nil, "id", "message"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isConcurrentPushError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/repository/schedule"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// ScheduledAction is an action which is executed by a worker once its due date is reached,
+// see [Commands.ExecuteScheduledAction].
+type ScheduledAction struct {
+	// ID is generated if empty on [Commands.ScheduleAction]
+	ID string
+	// Action is the name of the action the worker executes, e.g. org.deactivate
+	Action string
+	// TargetID is the id of the aggregate the action is executed on
+	TargetID   string
+	DueDate    time.Time
+	Parameters json.RawMessage
+}
+
+func (a *ScheduledAction) IsValid() error {
+	if a.Action == "" || a.DueDate.IsZero() {
+		return zerrors.ThrowInvalidArgument(nil, "COMMAND-Sd3kq", "Errors.Schedule.Invalid")
+	}
+	if len(a.Parameters) > 0 && !json.Valid(a.Parameters) {
+		return zerrors.ThrowInvalidArgument(nil, "COMMAND-Sd8wn", "Errors.Schedule.Invalid")
+	}
+	return nil
+}
+
+// ScheduleAction records the action to be executed on or after its due date in the instance of the context.
+func (c *Commands) ScheduleAction(ctx context.Context, add *ScheduledAction) (_ *domain.ObjectDetails, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if err := add.IsValid(); err != nil {
+		return nil, err
+	}
+	if add.ID == "" {
+		add.ID, err = c.idGenerator.Next()
+		if err != nil {
+			return nil, err
+		}
+	}
+	wm, err := c.getScheduledActionWriteModel(ctx, add.ID)
+	if err != nil {
+		return nil, err
+	}
+	if wm.State.Exists() {
+		return nil, zerrors.ThrowAlreadyExists(nil, "COMMAND-Sd5pe", "Errors.Schedule.AlreadyExists")
+	}
+	pushedEvents, err := c.eventstore.Push(ctx, schedule.NewScheduledEvent(
+		ctx,
+		schedule.NewAggregate(wm.AggregateID, wm.InstanceID),
+		add.Action,
+		add.TargetID,
+		add.DueDate,
+		add.Parameters,
+	))
+	if err != nil {
+		return nil, err
+	}
+	if err := AppendAndReduce(wm, pushedEvents...); err != nil {
+		return nil, err
+	}
+	return writeModelToObjectDetails(&wm.WriteModel), nil
+}
+
+// CancelScheduledAction prevents the execution of a pending scheduled action.
+// Cancelling an already cancelled action succeeds, cancelling an executed action fails.
+func (c *Commands) CancelScheduledAction(ctx context.Context, id string) (_ *domain.ObjectDetails, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if id == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Sd2vm", "Errors.IDMissing")
+	}
+	wm, err := c.getScheduledActionWriteModel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch wm.State {
+	case domain.ScheduledActionStateUnspecified:
+		return nil, zerrors.ThrowNotFound(nil, "COMMAND-Sd6yt", "Errors.Schedule.NotFound")
+	case domain.ScheduledActionStateCancelled:
+		return writeModelToObjectDetails(&wm.WriteModel), nil
+	case domain.ScheduledActionStateExecuted:
+		return nil, zerrors.ThrowPreconditionFailed(nil, "COMMAND-Sd9fh", "Errors.Schedule.AlreadyCompleted")
+	}
+	pushedEvents, err := c.eventstore.Push(ctx, schedule.NewCancelledEvent(ctx, schedule.NewAggregate(wm.AggregateID, wm.InstanceID)))
+	if err != nil {
+		return nil, err
+	}
+	if err := AppendAndReduce(wm, pushedEvents...); err != nil {
+		return nil, err
+	}
+	return writeModelToObjectDetails(&wm.WriteModel), nil
+}
+
+// ExecuteScheduledAction calls execute for a pending scheduled action which is due and marks it as executed.
+// The execution is idempotent: an action which is already executed is not passed to execute again
+// and only one of concurrent executions or a cancellation is recorded.
+// If execute fails, the action stays pending and is returned by the next poll of the due actions
+// on the scheduled actions projection.
+// As the action is marked after execute returned, execute must tolerate being called again
+// if marking the action fails.
+func (c *Commands) ExecuteScheduledAction(ctx context.Context, id string, execute func(ctx context.Context, action *ScheduledAction) error) (_ *domain.ObjectDetails, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if id == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Sd4jb", "Errors.IDMissing")
+	}
+	wm, err := c.getScheduledActionWriteModel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch wm.State {
+	case domain.ScheduledActionStateUnspecified:
+		return nil, zerrors.ThrowNotFound(nil, "COMMAND-Sd7rx", "Errors.Schedule.NotFound")
+	case domain.ScheduledActionStateExecuted:
+		return writeModelToObjectDetails(&wm.WriteModel), nil
+	case domain.ScheduledActionStateCancelled:
+		return nil, zerrors.ThrowPreconditionFailed(nil, "COMMAND-Sd1ug", "Errors.Schedule.AlreadyCompleted")
+	}
+	if wm.DueDate.After(time.Now()) {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "COMMAND-Sd5lc", "Errors.Schedule.NotDue")
+	}
+	if err = execute(ctx, wm.scheduledAction()); err != nil {
+		return nil, err
+	}
+	pushedEvents, err := c.eventstore.Push(ctx, schedule.NewExecutedEvent(ctx, schedule.NewAggregate(wm.AggregateID, wm.InstanceID)))
+	if zerrors.IsErrorAlreadyExists(err) {
+		// the action was completed concurrently
+		return c.completedScheduledAction(ctx, id, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := AppendAndReduce(wm, pushedEvents...); err != nil {
+		return nil, err
+	}
+	return writeModelToObjectDetails(&wm.WriteModel), nil
+}
+
+// completedScheduledAction returns the details of the action if it was executed
+// and the passed error if it was cancelled
+func (c *Commands) completedScheduledAction(ctx context.Context, id string, pushErr error) (*domain.ObjectDetails, error) {
+	wm, err := c.getScheduledActionWriteModel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if wm.State != domain.ScheduledActionStateExecuted {
+		return nil, pushErr
+	}
+	return writeModelToObjectDetails(&wm.WriteModel), nil
+}
+
+func (c *Commands) getScheduledActionWriteModel(ctx context.Context, id string) (*ScheduledActionWriteModel, error) {
+	wm := NewScheduledActionWriteModel(id, authz.GetInstance(ctx).InstanceID())
+	if err := c.eventstore.FilterToQueryReducer(ctx, wm); err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
@@ -0,0 +1,73 @@
+package command
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/schedule"
+)
+
+type ScheduledActionWriteModel struct {
+	eventstore.WriteModel
+
+	Action     string
+	TargetID   string
+	DueDate    time.Time
+	Parameters json.RawMessage
+
+	State domain.ScheduledActionState
+}
+
+func NewScheduledActionWriteModel(id, instanceID string) *ScheduledActionWriteModel {
+	return &ScheduledActionWriteModel{
+		WriteModel: eventstore.WriteModel{
+			AggregateID:   id,
+			ResourceOwner: instanceID,
+			InstanceID:    instanceID,
+		},
+	}
+}
+
+func (wm *ScheduledActionWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch e := event.(type) {
+		case *schedule.ScheduledEvent:
+			wm.Action = e.Action
+			wm.TargetID = e.TargetID
+			wm.DueDate = e.DueDate
+			wm.Parameters = e.Parameters
+			wm.State = domain.ScheduledActionStatePending
+		case *schedule.ExecutedEvent:
+			wm.State = domain.ScheduledActionStateExecuted
+		case *schedule.CancelledEvent:
+			wm.State = domain.ScheduledActionStateCancelled
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *ScheduledActionWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		ResourceOwner(wm.ResourceOwner).
+		AddQuery().
+		AggregateTypes(schedule.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(
+			schedule.ScheduledEventType,
+			schedule.ExecutedEventType,
+			schedule.CancelledEventType,
+		).
+		Builder()
+}
+
+func (wm *ScheduledActionWriteModel) scheduledAction() *ScheduledAction {
+	return &ScheduledAction{
+		ID:         wm.AggregateID,
+		Action:     wm.Action,
+		TargetID:   wm.TargetID,
+		DueDate:    wm.DueDate,
+		Parameters: wm.Parameters,
+	}
+}
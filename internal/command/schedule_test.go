@@ -0,0 +1,339 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/id"
+	"github.com/zitadel/zitadel/internal/id/mock"
+	"github.com/zitadel/zitadel/internal/repository/schedule"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+var (
+	scheduleDuePast   = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	scheduleDueFuture = time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+)
+
+func scheduledEvent(id string, dueDate time.Time) *schedule.ScheduledEvent {
+	return schedule.NewScheduledEvent(context.Background(),
+		schedule.NewAggregate(id, "instance"),
+		"org.deactivate",
+		"org1",
+		dueDate,
+		json.RawMessage(`{"reason":"trial"}`),
+	)
+}
+
+func TestCommands_ScheduleAction(t *testing.T) {
+	type fields struct {
+		eventstore  func(t *testing.T) *eventstore.Eventstore
+		idGenerator id.Generator
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		add     *ScheduledAction
+		want    *domain.ObjectDetails
+		wantErr error
+	}{
+		{
+			name: "missing action, error",
+			fields: fields{
+				eventstore: expectEventstore(),
+			},
+			add:     &ScheduledAction{DueDate: scheduleDueFuture},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-Sd3kq", "Errors.Schedule.Invalid"),
+		},
+		{
+			name: "missing due date, error",
+			fields: fields{
+				eventstore: expectEventstore(),
+			},
+			add:     &ScheduledAction{Action: "org.deactivate"},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-Sd3kq", "Errors.Schedule.Invalid"),
+		},
+		{
+			name: "invalid parameters, error",
+			fields: fields{
+				eventstore: expectEventstore(),
+			},
+			add:     &ScheduledAction{Action: "org.deactivate", DueDate: scheduleDueFuture, Parameters: json.RawMessage("{")},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-Sd8wn", "Errors.Schedule.Invalid"),
+		},
+		{
+			name: "already existing, error",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(scheduledEvent("schedule1", scheduleDueFuture)),
+					),
+				),
+			},
+			add:     &ScheduledAction{ID: "schedule1", Action: "org.deactivate", DueDate: scheduleDueFuture},
+			wantErr: zerrors.ThrowAlreadyExists(nil, "COMMAND-Sd5pe", "Errors.Schedule.AlreadyExists"),
+		},
+		{
+			name: "scheduled",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+					expectPush(scheduledEvent("schedule1", scheduleDueFuture)),
+				),
+				idGenerator: mock.ExpectID(t, "schedule1"),
+			},
+			add: &ScheduledAction{
+				Action:     "org.deactivate",
+				TargetID:   "org1",
+				DueDate:    scheduleDueFuture,
+				Parameters: json.RawMessage(`{"reason":"trial"}`),
+			},
+			want: &domain.ObjectDetails{
+				ResourceOwner: "instance",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore:  tt.fields.eventstore(t),
+				idGenerator: tt.fields.idGenerator,
+			}
+			got, err := c.ScheduleAction(authz.WithInstanceID(context.Background(), "instance"), tt.add)
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCommands_CancelScheduledAction(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventstore func(t *testing.T) *eventstore.Eventstore
+		id         string
+		want       *domain.ObjectDetails
+		wantErr    error
+	}{
+		{
+			name:       "missing id, error",
+			eventstore: expectEventstore(),
+			wantErr:    zerrors.ThrowInvalidArgument(nil, "COMMAND-Sd2vm", "Errors.IDMissing"),
+		},
+		{
+			name: "not found, error",
+			eventstore: expectEventstore(
+				expectFilter(),
+			),
+			id:      "schedule1",
+			wantErr: zerrors.ThrowNotFound(nil, "COMMAND-Sd6yt", "Errors.Schedule.NotFound"),
+		},
+		{
+			name: "already executed, error",
+			eventstore: expectEventstore(
+				expectFilter(
+					eventFromEventPusher(scheduledEvent("schedule1", scheduleDuePast)),
+					eventFromEventPusher(schedule.NewExecutedEvent(context.Background(), schedule.NewAggregate("schedule1", "instance"))),
+				),
+			),
+			id:      "schedule1",
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "COMMAND-Sd9fh", "Errors.Schedule.AlreadyCompleted"),
+		},
+		{
+			name: "already cancelled, ok",
+			eventstore: expectEventstore(
+				expectFilter(
+					eventFromEventPusher(scheduledEvent("schedule1", scheduleDueFuture)),
+					eventFromEventPusher(schedule.NewCancelledEvent(context.Background(), schedule.NewAggregate("schedule1", "instance"))),
+				),
+			),
+			id: "schedule1",
+			want: &domain.ObjectDetails{
+				ResourceOwner: "instance",
+			},
+		},
+		{
+			name: "cancelled",
+			eventstore: expectEventstore(
+				expectFilter(
+					eventFromEventPusher(scheduledEvent("schedule1", scheduleDueFuture)),
+				),
+				expectPush(
+					schedule.NewCancelledEvent(context.Background(), schedule.NewAggregate("schedule1", "instance")),
+				),
+			),
+			id: "schedule1",
+			want: &domain.ObjectDetails{
+				ResourceOwner: "instance",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.eventstore(t),
+			}
+			got, err := c.CancelScheduledAction(authz.WithInstanceID(context.Background(), "instance"), tt.id)
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCommands_ExecuteScheduledAction(t *testing.T) {
+	executeErr := zerrors.ThrowInternal(nil, "TEST-Sd2xa", "execution failed")
+	tests := []struct {
+		name         string
+		eventstore   func(t *testing.T) *eventstore.Eventstore
+		id           string
+		executeErr   error
+		wantExecuted bool
+		want         *domain.ObjectDetails
+		wantErr      error
+	}{
+		{
+			name:       "missing id, error",
+			eventstore: expectEventstore(),
+			wantErr:    zerrors.ThrowInvalidArgument(nil, "COMMAND-Sd4jb", "Errors.IDMissing"),
+		},
+		{
+			name: "not found, error",
+			eventstore: expectEventstore(
+				expectFilter(),
+			),
+			id:      "schedule1",
+			wantErr: zerrors.ThrowNotFound(nil, "COMMAND-Sd7rx", "Errors.Schedule.NotFound"),
+		},
+		{
+			name: "cancelled, error",
+			eventstore: expectEventstore(
+				expectFilter(
+					eventFromEventPusher(scheduledEvent("schedule1", scheduleDuePast)),
+					eventFromEventPusher(schedule.NewCancelledEvent(context.Background(), schedule.NewAggregate("schedule1", "instance"))),
+				),
+			),
+			id:      "schedule1",
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "COMMAND-Sd1ug", "Errors.Schedule.AlreadyCompleted"),
+		},
+		{
+			name: "not due, error",
+			eventstore: expectEventstore(
+				expectFilter(
+					eventFromEventPusher(scheduledEvent("schedule1", scheduleDueFuture)),
+				),
+			),
+			id:      "schedule1",
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "COMMAND-Sd5lc", "Errors.Schedule.NotDue"),
+		},
+		{
+			name: "already executed, not executed again",
+			eventstore: expectEventstore(
+				expectFilter(
+					eventFromEventPusher(scheduledEvent("schedule1", scheduleDuePast)),
+					eventFromEventPusher(schedule.NewExecutedEvent(context.Background(), schedule.NewAggregate("schedule1", "instance"))),
+				),
+			),
+			id: "schedule1",
+			want: &domain.ObjectDetails{
+				ResourceOwner: "instance",
+			},
+		},
+		{
+			name: "execution failed, stays pending",
+			eventstore: expectEventstore(
+				expectFilter(
+					eventFromEventPusher(scheduledEvent("schedule1", scheduleDuePast)),
+				),
+			),
+			id:           "schedule1",
+			executeErr:   executeErr,
+			wantExecuted: true,
+			wantErr:      executeErr,
+		},
+		{
+			name: "executed",
+			eventstore: expectEventstore(
+				expectFilter(
+					eventFromEventPusher(scheduledEvent("schedule1", scheduleDuePast)),
+				),
+				expectPush(
+					schedule.NewExecutedEvent(context.Background(), schedule.NewAggregate("schedule1", "instance")),
+				),
+			),
+			id:           "schedule1",
+			wantExecuted: true,
+			want: &domain.ObjectDetails{
+				ResourceOwner: "instance",
+			},
+		},
+		{
+			name: "executed concurrently, ok",
+			eventstore: expectEventstore(
+				expectFilter(
+					eventFromEventPusher(scheduledEvent("schedule1", scheduleDuePast)),
+				),
+				expectPushFailed(
+					zerrors.ThrowAlreadyExists(nil, "V3-DKcAT", "Errors.Schedule.AlreadyCompleted"),
+					schedule.NewExecutedEvent(context.Background(), schedule.NewAggregate("schedule1", "instance")),
+				),
+				expectFilter(
+					eventFromEventPusher(scheduledEvent("schedule1", scheduleDuePast)),
+					eventFromEventPusher(schedule.NewExecutedEvent(context.Background(), schedule.NewAggregate("schedule1", "instance"))),
+				),
+			),
+			id:           "schedule1",
+			wantExecuted: true,
+			want: &domain.ObjectDetails{
+				ResourceOwner: "instance",
+			},
+		},
+		{
+			name: "cancelled concurrently, error",
+			eventstore: expectEventstore(
+				expectFilter(
+					eventFromEventPusher(scheduledEvent("schedule1", scheduleDuePast)),
+				),
+				expectPushFailed(
+					zerrors.ThrowAlreadyExists(nil, "V3-DKcAT", "Errors.Schedule.AlreadyCompleted"),
+					schedule.NewExecutedEvent(context.Background(), schedule.NewAggregate("schedule1", "instance")),
+				),
+				expectFilter(
+					eventFromEventPusher(scheduledEvent("schedule1", scheduleDuePast)),
+					eventFromEventPusher(schedule.NewCancelledEvent(context.Background(), schedule.NewAggregate("schedule1", "instance"))),
+				),
+			),
+			id:           "schedule1",
+			wantExecuted: true,
+			wantErr:      zerrors.ThrowAlreadyExists(nil, "V3-DKcAT", "Errors.Schedule.AlreadyCompleted"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.eventstore(t),
+			}
+			var executed bool
+			got, err := c.ExecuteScheduledAction(authz.WithInstanceID(context.Background(), "instance"), tt.id, func(_ context.Context, action *ScheduledAction) error {
+				executed = true
+				assert.Equal(t, &ScheduledAction{
+					ID:         "schedule1",
+					Action:     "org.deactivate",
+					TargetID:   "org1",
+					DueDate:    scheduleDuePast,
+					Parameters: json.RawMessage(`{"reason":"trial"}`),
+				}, action)
+				return tt.executeErr
+			})
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantExecuted, executed)
+		})
+	}
+}
@@ -45,7 +45,7 @@ func (c *Commands) NewSessionCommands(cmds []SessionCommand, session *SessionWri
 		sessionCommands:   cmds,
 		sessionWriteModel: session,
 		eventstore:        c.eventstore,
-		hasher:            c.userPasswordHasher,
+		hasher:            c.passwordHasher(),
 		intentAlg:         c.idpConfigEncryption,
 		totpAlg:           c.multifactors.OTP.CryptoMFA,
 		otpAlg:            c.userEncryption,
@@ -241,6 +241,10 @@ func (s *SessionCommands) SetLifetime(ctx context.Context, lifetime time.Duratio
 	if lifetime == 0 {
 		return nil
 	}
+	// the lifetime of an impersonation session is capped when it's created and must not be extended afterwards
+	if s.sessionWriteModel.IsImpersonated() {
+		return zerrors.ThrowPreconditionFailed(nil, "COMMAND-Lf6vx", "Errors.Session.Impersonation.LifetimeFixed")
+	}
 	s.eventCommands = append(s.eventCommands, session.NewLifetimeSetEvent(ctx, s.sessionWriteModel.aggregate, lifetime))
 	return nil
 }
@@ -373,7 +377,7 @@ func (c *Commands) updateSession(ctx context.Context, checks *SessionCommands, m
 // is granted the "session.delete" permission on the resource owner of the authenticated user.
 func (c *Commands) checkSessionTerminationPermission(ctx context.Context, model *SessionWriteModel, token string) error {
 	if token != "" {
-		return c.sessionTokenVerifier(ctx, token, model.AggregateID, model.TokenID)
+		return c.sessionTokenVerifiers.Verify(ctx, token, model.AggregateID, model.TokenID)
 	}
 	if model.UserID != "" && model.UserID == authz.GetCtxData(ctx).UserID {
 		return nil
@@ -0,0 +1,98 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/session"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// defaultMaxImpersonationSessionLifetime is used if no maximum lifetime for impersonation sessions is configured
+const defaultMaxImpersonationSessionLifetime = time.Hour
+
+// CreateImpersonationSession creates a session for the user on behalf of the operator of the context.
+// The lifetime of the session is capped by the configured maximum, the session expires automatically afterwards.
+func (c *Commands) CreateImpersonationSession(ctx context.Context, userID string, lifetime time.Duration, userAgent *domain.UserAgent) (_ *SessionChanged, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if userID == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Wn4ke", "Errors.IDMissing")
+	}
+	operator := authz.GetCtxData(ctx)
+	if operator.UserID == "" {
+		return nil, zerrors.ThrowUnauthenticated(nil, "COMMAND-Jq8fo", "Errors.User.UserIDMissing")
+	}
+	if operator.UserID == userID {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Rx2ud", "Errors.Session.Impersonation.Self")
+	}
+	user, err := c.userStateWriteModel(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isUserStateExists(user.UserState) {
+		return nil, zerrors.ThrowNotFound(nil, "COMMAND-Ev7ps", "Errors.User.NotFound")
+	}
+	if !hasUserState(user.UserState, domain.UserStateActive, domain.UserStateInitial) {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "COMMAND-Kc3yt", "Errors.User.ShouldBeActiveOrInitial")
+	}
+	policy := NewInstanceSecurityPolicyWriteModel(ctx)
+	if err = c.eventstore.FilterToQueryReducer(ctx, policy); err != nil {
+		return nil, err
+	}
+	if !policy.EnableImpersonation {
+		return nil, zerrors.ThrowPermissionDenied(nil, "COMMAND-Hu5mb", "Errors.TokenExchange.Impersonation.PolicyDisabled")
+	}
+	if err = c.checkPermission(ctx, "impersonation", user.ResourceOwner, userID); err != nil {
+		return nil, err
+	}
+	lifetime = c.impersonationSessionLifetime(lifetime)
+
+	sessionID, err := c.idGenerator.Next()
+	if err != nil {
+		return nil, err
+	}
+	sessionWriteModel := NewSessionWriteModel(sessionID, authz.GetInstance(ctx).InstanceID())
+	if err = c.eventstore.FilterToQueryReducer(ctx, sessionWriteModel); err != nil {
+		return nil, err
+	}
+	cmd := c.NewSessionCommands([]SessionCommand{
+		impersonateUser(user.AggregateID, user.ResourceOwner, operator.UserID, operator.ResourceOwner, lifetime),
+	}, sessionWriteModel)
+	cmd.Start(ctx, userAgent)
+	return c.updateSession(ctx, cmd, nil, lifetime)
+}
+
+// impersonationSessionLifetime caps the requested lifetime by the configured maximum,
+// which is also used if no lifetime is requested.
+func (c *Commands) impersonationSessionLifetime(lifetime time.Duration) time.Duration {
+	maxLifetime := c.maxImpersonationSessionLifetime
+	if maxLifetime <= 0 {
+		maxLifetime = defaultMaxImpersonationSessionLifetime
+	}
+	if lifetime <= 0 || lifetime > maxLifetime {
+		return maxLifetime
+	}
+	return lifetime
+}
+
+// impersonateUser sets the user of the session and records the operator acting as the user
+func impersonateUser(userID, userResourceOwner, operatorID, operatorResourceOwner string, lifetime time.Duration) SessionCommand {
+	return func(ctx context.Context, cmd *SessionCommands) ([]eventstore.Command, error) {
+		if err := cmd.UserChecked(ctx, userID, userResourceOwner, cmd.now(), nil); err != nil {
+			return nil, err
+		}
+		cmd.ImpersonationStarted(ctx, operatorID, operatorResourceOwner, lifetime)
+		return nil, nil
+	}
+}
+
+func (s *SessionCommands) ImpersonationStarted(ctx context.Context, operatorID, operatorResourceOwner string, lifetime time.Duration) {
+	s.eventCommands = append(s.eventCommands, session.NewImpersonationStartedEvent(ctx, s.sessionWriteModel.aggregate,
+		s.sessionWriteModel.UserID, s.sessionWriteModel.UserResourceOwner, operatorID, operatorResourceOwner, lifetime))
+}
@@ -0,0 +1,329 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/id"
+	"github.com/zitadel/zitadel/internal/id/mock"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/session"
+	"github.com/zitadel/zitadel/internal/repository/user"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func newImpersonationSecurityPolicySetEvent(t *testing.T, enabled bool) *instance.SecurityPolicySetEvent {
+	event, err := instance.NewSecurityPolicySetEvent(context.Background(),
+		&instance.NewAggregate("instance1").Aggregate,
+		[]instance.SecurityPolicyChanges{instance.ChangeSecurityPolicyEnableImpersonation(enabled)},
+	)
+	require.NoError(t, err)
+	return event
+}
+
+func TestCommands_CreateImpersonationSession(t *testing.T) {
+	operatorCtx := authz.SetCtxData(authz.NewMockContext("instance1", "org1", ""), authz.CtxData{UserID: "operator1", ResourceOwner: "org1"})
+	userAddedEvent := user.NewHumanAddedEvent(context.Background(),
+		&user.NewAggregate("user1", "org1").Aggregate,
+		"username",
+		"firstname",
+		"lastname",
+		"nickname",
+		"displayname",
+		language.German,
+		domain.GenderUnspecified,
+		"email@test.ch",
+		true,
+	)
+	type fields struct {
+		eventstore      func(*testing.T) *eventstore.Eventstore
+		idGenerator     id.Generator
+		checkPermission domain.PermissionCheck
+	}
+	type args struct {
+		ctx      context.Context
+		userID   string
+		lifetime time.Duration
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+	}{
+		{
+			name: "missing user id",
+			fields: fields{
+				eventstore: expectEventstore(),
+			},
+			args: args{
+				ctx: operatorCtx,
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-Wn4ke", "Errors.IDMissing"),
+		},
+		{
+			name: "missing operator",
+			fields: fields{
+				eventstore: expectEventstore(),
+			},
+			args: args{
+				ctx:    authz.NewMockContext("instance1", "org1", ""),
+				userID: "user1",
+			},
+			wantErr: zerrors.ThrowUnauthenticated(nil, "COMMAND-Jq8fo", "Errors.User.UserIDMissing"),
+		},
+		{
+			name: "self impersonation",
+			fields: fields{
+				eventstore: expectEventstore(),
+			},
+			args: args{
+				ctx:    operatorCtx,
+				userID: "operator1",
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-Rx2ud", "Errors.Session.Impersonation.Self"),
+		},
+		{
+			name: "user not found",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+				),
+			},
+			args: args{
+				ctx:    operatorCtx,
+				userID: "user1",
+			},
+			wantErr: zerrors.ThrowNotFound(nil, "COMMAND-Ev7ps", "Errors.User.NotFound"),
+		},
+		{
+			name: "user locked",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(userAddedEvent),
+						eventFromEventPusher(
+							user.NewUserLockedEvent(context.Background(), &user.NewAggregate("user1", "org1").Aggregate),
+						),
+					),
+				),
+			},
+			args: args{
+				ctx:    operatorCtx,
+				userID: "user1",
+			},
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "COMMAND-Kc3yt", "Errors.User.ShouldBeActiveOrInitial"),
+		},
+		{
+			name: "policy disabled",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(userAddedEvent),
+					),
+					expectFilter(
+						eventFromEventPusher(newImpersonationSecurityPolicySetEvent(t, false)),
+					),
+				),
+			},
+			args: args{
+				ctx:    operatorCtx,
+				userID: "user1",
+			},
+			wantErr: zerrors.ThrowPermissionDenied(nil, "COMMAND-Hu5mb", "Errors.TokenExchange.Impersonation.PolicyDisabled"),
+		},
+		{
+			name: "permission denied",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(userAddedEvent),
+					),
+					expectFilter(
+						eventFromEventPusher(newImpersonationSecurityPolicySetEvent(t, true)),
+					),
+				),
+				checkPermission: newMockPermissionCheckNotAllowed(),
+			},
+			args: args{
+				ctx:    operatorCtx,
+				userID: "user1",
+			},
+			wantErr: zerrors.ThrowPermissionDenied(nil, "AUTHZ-HKJD33", "Errors.PermissionDenied"),
+		},
+		{
+			name: "id generator fails",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(userAddedEvent),
+					),
+					expectFilter(
+						eventFromEventPusher(newImpersonationSecurityPolicySetEvent(t, true)),
+					),
+				),
+				checkPermission: newMockPermissionCheckAllowed(),
+				idGenerator:     mock.NewIDGeneratorExpectError(t, zerrors.ThrowInternal(nil, "id", "generator failed")),
+			},
+			args: args{
+				ctx:    operatorCtx,
+				userID: "user1",
+			},
+			wantErr: zerrors.ThrowInternal(nil, "id", "generator failed"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore:      tt.fields.eventstore(t),
+				idGenerator:     tt.fields.idGenerator,
+				checkPermission: tt.fields.checkPermission,
+			}
+			_, err := c.CreateImpersonationSession(tt.args.ctx, tt.args.userID, tt.args.lifetime, nil)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCommands_impersonationSessionLifetime(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxLifetime time.Duration
+		lifetime    time.Duration
+		want        time.Duration
+	}{
+		{
+			name:        "no lifetime, max",
+			maxLifetime: 30 * time.Minute,
+			want:        30 * time.Minute,
+		},
+		{
+			name:        "lifetime exceeds max, max",
+			maxLifetime: 30 * time.Minute,
+			lifetime:    2 * time.Hour,
+			want:        30 * time.Minute,
+		},
+		{
+			name:        "lifetime within max",
+			maxLifetime: 30 * time.Minute,
+			lifetime:    10 * time.Minute,
+			want:        10 * time.Minute,
+		},
+		{
+			name:     "no max configured, default",
+			lifetime: 2 * time.Hour,
+			want:     defaultMaxImpersonationSessionLifetime,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				maxImpersonationSessionLifetime: tt.maxLifetime,
+			}
+			assert.Equal(t, tt.want, c.impersonationSessionLifetime(tt.lifetime))
+		})
+	}
+}
+
+func TestCommands_updateSession_impersonation(t *testing.T) {
+	testNow := time.Now()
+	ctx := authz.NewMockContext("instance1", "", "")
+	c := &Commands{
+		eventstore: expectEventstore(
+			expectPush(
+				session.NewUserCheckedEvent(ctx, &session.NewAggregate("sessionID", "instance1").Aggregate,
+					"user1", "org1", testNow, nil,
+				),
+				session.NewImpersonationStartedEvent(ctx, &session.NewAggregate("sessionID", "instance1").Aggregate,
+					"user1", "org1", "operator1", "org2", 10*time.Minute,
+				),
+				session.NewLifetimeSetEvent(ctx, &session.NewAggregate("sessionID", "instance1").Aggregate, 10*time.Minute),
+				session.NewTokenSetEvent(ctx, &session.NewAggregate("sessionID", "instance1").Aggregate,
+					"tokenID",
+				),
+			),
+		)(t),
+	}
+	checks := &SessionCommands{
+		sessionWriteModel: NewSessionWriteModel("sessionID", "instance1"),
+		sessionCommands: []SessionCommand{
+			impersonateUser("user1", "org1", "operator1", "org2", 10*time.Minute),
+		},
+		createToken: func(sessionID string) (string, string, error) {
+			return "tokenID", "token", nil
+		},
+		now: func() time.Time {
+			return testNow
+		},
+	}
+	got, err := c.updateSession(ctx, checks, nil, 10*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "token", got.NewToken)
+	assert.True(t, checks.sessionWriteModel.IsImpersonated())
+	assert.Equal(t, "operator1", checks.sessionWriteModel.ImpersonatorID)
+	assert.Equal(t, "org2", checks.sessionWriteModel.ImpersonatorResourceOwner)
+}
+
+func TestCommands_UpdateSession_impersonationLifetime(t *testing.T) {
+	ctx := authz.NewMockContext("instance1", "", "")
+	impersonatedSession := func() expect {
+		return expectFilter(
+			eventFromEventPusher(
+				session.NewUserCheckedEvent(ctx, &session.NewAggregate("sessionID", "instance1").Aggregate,
+					"user1", "org1", time.Now(), nil,
+				),
+			),
+			eventFromEventPusher(
+				session.NewImpersonationStartedEvent(ctx, &session.NewAggregate("sessionID", "instance1").Aggregate,
+					"user1", "org1", "operator1", "org2", 10*time.Minute,
+				),
+			),
+			eventFromEventPusherWithCreationDateNow(
+				session.NewLifetimeSetEvent(ctx, &session.NewAggregate("sessionID", "instance1").Aggregate, 10*time.Minute),
+			),
+		)
+	}
+	tests := []struct {
+		name       string
+		eventstore func(*testing.T) *eventstore.Eventstore
+		lifetime   time.Duration
+		wantErr    func(error) bool
+	}{
+		{
+			name: "lifetime extended, precondition failed",
+			eventstore: expectEventstore(
+				impersonatedSession(),
+			),
+			lifetime: 24 * time.Hour,
+			wantErr:  zerrors.IsPreconditionFailed,
+		},
+		{
+			name: "lifetime unchanged, ok",
+			eventstore: expectEventstore(
+				impersonatedSession(),
+			),
+			lifetime: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.eventstore(t),
+			}
+			_, err := c.UpdateSession(ctx, "sessionID", nil, nil, tt.lifetime)
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
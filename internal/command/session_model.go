@@ -56,6 +56,9 @@ type SessionWriteModel struct {
 	UserAgent            *domain.UserAgent
 	Expiration           time.Time
 
+	ImpersonatorID            string
+	ImpersonatorResourceOwner string
+
 	WebAuthNChallenge     *WebAuthNChallengeModel
 	OTPSMSCodeChallenge   *OTPCode
 	OTPEmailCodeChallenge *OTPCode
@@ -102,6 +105,8 @@ func (wm *SessionWriteModel) Reduce() error {
 			wm.reduceTokenSet(e)
 		case *session.LifetimeSetEvent:
 			wm.reduceLifetimeSet(e)
+		case *session.ImpersonationStartedEvent:
+			wm.reduceImpersonationStarted(e)
 		case *session.TerminateEvent:
 			wm.reduceTerminate()
 		}
@@ -129,6 +134,7 @@ func (wm *SessionWriteModel) Query() *eventstore.SearchQueryBuilder {
 			session.TokenSetType,
 			session.MetadataSetType,
 			session.LifetimeSetType,
+			session.ImpersonationStartedType,
 			session.TerminateType,
 		).
 		Builder()
@@ -212,10 +218,20 @@ func (wm *SessionWriteModel) reduceLifetimeSet(e *session.LifetimeSetEvent) {
 	wm.Expiration = e.CreationDate().Add(e.Lifetime)
 }
 
+func (wm *SessionWriteModel) reduceImpersonationStarted(e *session.ImpersonationStartedEvent) {
+	wm.ImpersonatorID = e.OperatorID
+	wm.ImpersonatorResourceOwner = e.OperatorResourceOwner
+}
+
 func (wm *SessionWriteModel) reduceTerminate() {
 	wm.State = domain.SessionStateTerminated
 }
 
+// IsImpersonated returns true if the session was started by an operator to act as the user
+func (wm *SessionWriteModel) IsImpersonated() bool {
+	return wm.ImpersonatorID != ""
+}
+
 // AuthenticationTime returns the time the user authenticated using the latest time of all checks
 func (wm *SessionWriteModel) AuthenticationTime() time.Time {
 	var authTime time.Time
@@ -0,0 +1,63 @@
+package command
+
+import (
+	"context"
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// SessionTokenVerifier verifies that the session token was issued for the token of the session
+type SessionTokenVerifier func(ctx context.Context, sessionToken, sessionID, tokenID string) (err error)
+
+const (
+	// DefaultSessionTokenScheme is the scheme of session tokens without a scheme prefix
+	DefaultSessionTokenScheme = ""
+	// sessionTokenSchemeSeparator separates the scheme from the token,
+	// it's not part of the base64 url encoding of the default session tokens
+	sessionTokenSchemeSeparator = ":"
+)
+
+// SessionTokenVerifierRegistry routes the verification of a session token to the verifier of its scheme.
+// A token "<scheme>:<token>" is verified by the verifier of the scheme without the prefix,
+// a token without a scheme by the verifier of the [DefaultSessionTokenScheme].
+// Verifiers must be registered before the registry is used.
+type SessionTokenVerifierRegistry struct {
+	verifiers map[string]SessionTokenVerifier
+}
+
+// NewSessionTokenVerifierRegistry creates a registry with the verifier of the [DefaultSessionTokenScheme]
+func NewSessionTokenVerifierRegistry(defaultVerifier SessionTokenVerifier) *SessionTokenVerifierRegistry {
+	registry := &SessionTokenVerifierRegistry{
+		verifiers: make(map[string]SessionTokenVerifier),
+	}
+	if defaultVerifier != nil {
+		registry.Register(DefaultSessionTokenScheme, defaultVerifier)
+	}
+	return registry
+}
+
+// Register sets the verifier of the scheme, an existing verifier of the scheme is replaced
+func (r *SessionTokenVerifierRegistry) Register(scheme string, verifier SessionTokenVerifier) {
+	r.verifiers[scheme] = verifier
+}
+
+// Verify verifies the session token with the verifier of its scheme
+func (r *SessionTokenVerifierRegistry) Verify(ctx context.Context, sessionToken, sessionID, tokenID string) error {
+	scheme, token := DefaultSessionTokenScheme, sessionToken
+	if prefix, rest, ok := strings.Cut(sessionToken, sessionTokenSchemeSeparator); ok {
+		scheme, token = prefix, rest
+	}
+	verifier, ok := r.verifiers[scheme]
+	if !ok {
+		return zerrors.ThrowInvalidArgument(nil, "COMMAND-Sv3ko", "Errors.Session.Token.Invalid")
+	}
+	return verifier(ctx, token, sessionID, tokenID)
+}
+
+// WithSessionTokenVerifier registers the verifier of an additional session token scheme
+func WithSessionTokenVerifier(scheme string, verifier SessionTokenVerifier) StartOption {
+	return func(c *Commands) {
+		c.sessionTokenVerifiers.Register(scheme, verifier)
+	}
+}
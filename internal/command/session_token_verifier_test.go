@@ -0,0 +1,66 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestSessionTokenVerifierRegistry_Verify(t *testing.T) {
+	verifier := func(scheme string, verified *string) SessionTokenVerifier {
+		return func(_ context.Context, sessionToken, sessionID, tokenID string) error {
+			*verified = scheme + "/" + sessionToken + "/" + sessionID + "/" + tokenID
+			return nil
+		}
+	}
+	tests := []struct {
+		name         string
+		token        string
+		withDefault  bool
+		wantVerified string
+		wantErr      error
+	}{
+		{
+			name:         "default scheme",
+			token:        "c2Vzc19zZXNzaW9uSUQ_dG9rZW5JRA",
+			withDefault:  true,
+			wantVerified: "default/c2Vzc19zZXNzaW9uSUQ_dG9rZW5JRA/sessionID/tokenID",
+		},
+		{
+			name:         "registered scheme",
+			token:        "jwt:eyJhbGciOiJSUzI1NiJ9",
+			withDefault:  true,
+			wantVerified: "jwt/eyJhbGciOiJSUzI1NiJ9/sessionID/tokenID",
+		},
+		{
+			name:        "unknown scheme, error",
+			token:       "unknown:token",
+			withDefault: true,
+			wantErr:     zerrors.ThrowInvalidArgument(nil, "COMMAND-Sv3ko", "Errors.Session.Token.Invalid"),
+		},
+		{
+			name:    "no default verifier, error",
+			token:   "token",
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-Sv3ko", "Errors.Session.Token.Invalid"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var verified string
+			var defaultVerifier SessionTokenVerifier
+			if tt.withDefault {
+				defaultVerifier = verifier("default", &verified)
+			}
+			registry := NewSessionTokenVerifierRegistry(defaultVerifier)
+			registry.Register("jwt", verifier("jwt", &verified))
+
+			err := registry.Verify(context.Background(), tt.token, "sessionID", "tokenID")
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantVerified, verified)
+		})
+	}
+}
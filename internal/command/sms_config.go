@@ -110,6 +110,55 @@ func (c *Commands) ChangeSMSConfigTwilioToken(ctx context.Context, instanceID, i
 	return writeModelToObjectDetails(&smsConfigWriteModel.WriteModel), nil
 }
 
+// AddSMSConfigTwilioWithVerification adds a new Twilio SMS configuration.
+// If testNumber is set, a test message is sent to the number and the configuration is only stored if it was delivered.
+func (c *Commands) AddSMSConfigTwilioWithVerification(ctx context.Context, instanceID string, config *twilio.Config, testNumber string) (string, *domain.ObjectDetails, error) {
+	if testNumber != "" {
+		if err := c.verifySMSConfigTwilio(ctx, instanceID, "", config, testNumber); err != nil {
+			return "", nil, err
+		}
+	}
+	return c.AddSMSConfigTwilio(ctx, instanceID, config)
+}
+
+// ChangeSMSConfigTwilioWithVerification changes an existing Twilio SMS configuration.
+// If testNumber is set, a test message is sent to the number using the stored token
+// and the configuration is only changed if it was delivered.
+func (c *Commands) ChangeSMSConfigTwilioWithVerification(ctx context.Context, instanceID, id string, config *twilio.Config, testNumber string) (*domain.ObjectDetails, error) {
+	if testNumber != "" {
+		if err := c.verifySMSConfigTwilio(ctx, instanceID, id, config, testNumber); err != nil {
+			return nil, err
+		}
+	}
+	return c.ChangeSMSConfigTwilio(ctx, instanceID, id, config)
+}
+
+func (c *Commands) verifySMSConfigTwilio(ctx context.Context, instanceID, id string, config *twilio.Config, testNumber string) error {
+	// copy the config so the decrypted token of a stored configuration is not passed back to the caller
+	verifyConfig := *config
+	if id != "" {
+		smsConfigWriteModel, err := c.getSMSConfig(ctx, instanceID, id)
+		if err != nil {
+			return err
+		}
+		if !smsConfigWriteModel.State.Exists() || smsConfigWriteModel.Twilio == nil {
+			return zerrors.ThrowNotFound(nil, "SMS-Tw2nf", "Errors.SMSConfig.NotFound")
+		}
+		verifyConfig.Token, err = crypto.DecryptString(smsConfigWriteModel.Twilio.Token, c.smsEncryption)
+		if err != nil {
+			return err
+		}
+	}
+	if !verifyConfig.IsValid() {
+		return zerrors.ThrowInvalidArgument(nil, "SMS-Tw5ia", "Errors.Invalid.Argument")
+	}
+	// the error of the provider is only wrapped, it never contains the token
+	if err := c.smsConfigVerifier(&verifyConfig, testNumber); err != nil {
+		return zerrors.ThrowPreconditionFailed(err, "SMS-Tw8vf", "Errors.SMSConfig.VerificationFailed")
+	}
+	return nil
+}
+
 func (c *Commands) ActivateSMSConfig(ctx context.Context, instanceID, id string) (*domain.ObjectDetails, error) {
 	if id == "" {
 		return nil, zerrors.ThrowInvalidArgument(nil, "SMS-dn93n", "Errors.IDMissing")
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"github.com/zitadel/zitadel/internal/crypto"
@@ -599,3 +600,170 @@ func newSMSConfigTwilioChangedEvent(ctx context.Context, id, sid, senderName str
 	)
 	return event
 }
+
+func TestCommandSide_AddSMSConfigTwilioWithVerification(t *testing.T) {
+	config := &twilio.Config{
+		SID:          "sid",
+		Token:        "token",
+		SenderNumber: "senderName",
+	}
+	addedEvent := instance.NewSMSConfigTwilioAddedEvent(
+		context.Background(),
+		&instance.NewAggregate("INSTANCE").Aggregate,
+		"providerid",
+		"sid",
+		"senderName",
+		&crypto.CryptoValue{
+			CryptoType: crypto.TypeEncryption,
+			Algorithm:  "enc",
+			KeyID:      "id",
+			Crypted:    []byte("token"),
+		},
+	)
+	type fields struct {
+		eventstore  func(*testing.T) *eventstore.Eventstore
+		idGenerator func(*testing.T) id.Generator
+		verifier    func(*twilio.Config, string) error
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		testNumber string
+		want       *domain.ObjectDetails
+		wantErr    error
+	}{
+		{
+			name: "verification failed, precondition error",
+			fields: fields{
+				eventstore:  expectEventstore(),
+				idGenerator: func(t *testing.T) id.Generator { return id_mock.NewIDGeneratorExpectIDs(t) },
+				verifier: func(*twilio.Config, string) error {
+					return zerrors.ThrowInternal(nil, "TWILI-osk3S", "could not send message")
+				},
+			},
+			testNumber: "+41791234567",
+			wantErr:    zerrors.ThrowPreconditionFailed(nil, "SMS-Tw8vf", "Errors.SMSConfig.VerificationFailed"),
+		},
+		{
+			name: "verified, ok",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+					expectPush(addedEvent),
+				),
+				idGenerator: func(t *testing.T) id.Generator { return id_mock.NewIDGeneratorExpectIDs(t, "providerid") },
+				verifier: func(cfg *twilio.Config, testNumber string) error {
+					if cfg.Token != "token" || testNumber != "+41791234567" {
+						return zerrors.ThrowInternal(nil, "TWILI-osk3S", "could not send message")
+					}
+					return nil
+				},
+			},
+			testNumber: "+41791234567",
+			want: &domain.ObjectDetails{
+				ResourceOwner: "INSTANCE",
+			},
+		},
+		{
+			name: "no test number, not verified",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+					expectPush(addedEvent),
+				),
+				idGenerator: func(t *testing.T) id.Generator { return id_mock.NewIDGeneratorExpectIDs(t, "providerid") },
+				verifier: func(*twilio.Config, string) error {
+					return zerrors.ThrowInternal(nil, "TWILI-osk3S", "could not send message")
+				},
+			},
+			want: &domain.ObjectDetails{
+				ResourceOwner: "INSTANCE",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Commands{
+				eventstore:        tt.fields.eventstore(t),
+				idGenerator:       tt.fields.idGenerator(t),
+				smsEncryption:     crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+				smsConfigVerifier: tt.fields.verifier,
+			}
+			_, got, err := r.AddSMSConfigTwilioWithVerification(context.Background(), "INSTANCE", config, tt.testNumber)
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCommandSide_ChangeSMSConfigTwilioWithVerification(t *testing.T) {
+	addedEvent := eventFromEventPusher(
+		instance.NewSMSConfigTwilioAddedEvent(
+			context.Background(),
+			&instance.NewAggregate("INSTANCE").Aggregate,
+			"providerid",
+			"sid",
+			"senderName",
+			&crypto.CryptoValue{
+				CryptoType: crypto.TypeEncryption,
+				Algorithm:  "enc",
+				KeyID:      "id",
+				Crypted:    []byte("token"),
+			},
+		),
+	)
+	tests := []struct {
+		name       string
+		eventstore func(*testing.T) *eventstore.Eventstore
+		want       *domain.ObjectDetails
+		wantErr    error
+	}{
+		{
+			name: "sms not existing, not found error",
+			eventstore: expectEventstore(
+				expectFilter(),
+			),
+			wantErr: zerrors.ThrowNotFound(nil, "SMS-Tw2nf", "Errors.SMSConfig.NotFound"),
+		},
+		{
+			name: "verified with stored token, ok",
+			eventstore: expectEventstore(
+				expectFilter(addedEvent),
+				expectFilter(addedEvent),
+				expectPush(
+					newSMSConfigTwilioChangedEvent(
+						context.Background(),
+						"providerid",
+						"sid2",
+						"senderName2",
+					),
+				),
+			),
+			want: &domain.ObjectDetails{
+				ResourceOwner: "INSTANCE",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &twilio.Config{
+				SID:          "sid2",
+				SenderNumber: "senderName2",
+			}
+			r := &Commands{
+				eventstore:    tt.eventstore(t),
+				smsEncryption: crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+				smsConfigVerifier: func(cfg *twilio.Config, _ string) error {
+					if cfg.Token != "token" {
+						return zerrors.ThrowInternal(nil, "TWILI-osk3S", "could not send message")
+					}
+					return nil
+				},
+			}
+			got, err := r.ChangeSMSConfigTwilioWithVerification(context.Background(), "INSTANCE", "providerid", config, "+41791234567")
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+			assert.Empty(t, config.Token, "the stored token must not be passed back")
+		})
+	}
+}
@@ -285,6 +285,57 @@ func (c *Commands) RemoveSMTPConfig(ctx context.Context, instanceID, id string)
 	return writeModelToObjectDetails(&smtpConfigWriteModel.WriteModel), nil
 }
 
+// AddSMTPConfigWithVerification adds a new SMTP configuration.
+// If verify is set, the connection and authentication to the SMTP server are checked before the configuration is stored.
+// If additionally testEmail is set, a test email is sent to that address.
+func (c *Commands) AddSMTPConfigWithVerification(ctx context.Context, instanceID string, config *smtp.Config, verify bool, testEmail string) (string, *domain.ObjectDetails, error) {
+	if verify {
+		if err := c.verifySMTPConfig(ctx, instanceID, "", config, testEmail); err != nil {
+			return "", nil, err
+		}
+	}
+	return c.AddSMTPConfig(ctx, instanceID, config)
+}
+
+// ChangeSMTPConfigWithVerification changes an existing SMTP configuration.
+// If verify is set, the connection and authentication to the SMTP server are checked before the configuration is stored.
+// If the password is not set, the stored password is used for the verification.
+// If additionally testEmail is set, a test email is sent to that address.
+func (c *Commands) ChangeSMTPConfigWithVerification(ctx context.Context, instanceID, id string, config *smtp.Config, verify bool, testEmail string) (*domain.ObjectDetails, error) {
+	if verify {
+		if err := c.verifySMTPConfig(ctx, instanceID, id, config, testEmail); err != nil {
+			return nil, err
+		}
+	}
+	return c.ChangeSMTPConfig(ctx, instanceID, id, config)
+}
+
+func (c *Commands) verifySMTPConfig(ctx context.Context, instanceID, id string, config *smtp.Config, testEmail string) error {
+	// copy the config so the decrypted password of a stored configuration is not passed back to the caller
+	verifyConfig := *config
+	if id != "" && verifyConfig.SMTP.Password == "" {
+		smtpConfigWriteModel, err := c.getSMTPConfig(ctx, instanceID, id, "")
+		if err != nil {
+			return err
+		}
+		if !smtpConfigWriteModel.State.Exists() {
+			return zerrors.ThrowNotFound(nil, "SMTP-Vk2rs", "Errors.SMTPConfig.NotFound")
+		}
+		verifyConfig.SMTP.Password, err = crypto.DecryptString(smtpConfigWriteModel.Password, c.smtpEncryption)
+		if err != nil {
+			return err
+		}
+	}
+	verifyConfig.SMTP.Host = strings.TrimSpace(verifyConfig.SMTP.Host)
+	if _, _, err := net.SplitHostPort(verifyConfig.SMTP.Host); err != nil {
+		return zerrors.ThrowInvalidArgument(nil, "SMTP-Bf3qr", "Errors.Invalid.Argument")
+	}
+	if err := c.smtpConfigVerifier(&verifyConfig, testEmail); err != nil {
+		return zerrors.ThrowPreconditionFailed(err, "SMTP-Nq8wf", "Errors.SMTPConfig.VerificationFailed")
+	}
+	return nil
+}
+
 func (c *Commands) TestSMTPConfig(ctx context.Context, instanceID, id, email string, config *smtp.Config) error {
 	password := config.SMTP.Password
 
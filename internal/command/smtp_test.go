@@ -1536,3 +1536,184 @@ func newSMTPConfigChangedEvent(ctx context.Context, id, description string, tls
 	)
 	return event
 }
+
+func TestCommandSide_AddSMTPConfigWithVerification(t *testing.T) {
+	type fields struct {
+		eventstore  *eventstore.Eventstore
+		idGenerator id.Generator
+		alg         crypto.EncryptionAlgorithm
+		verifier    func(cfg *smtp.Config, testEmail string) error
+	}
+	type args struct {
+		ctx       context.Context
+		smtp      *smtp.Config
+		verify    bool
+		testEmail string
+	}
+	type res struct {
+		want *domain.ObjectDetails
+		err  func(error) bool
+	}
+	config := &smtp.Config{
+		Description: "test",
+		Tls:         true,
+		From:        "from@domain.ch",
+		FromName:    "name",
+		SMTP: smtp.SMTP{
+			Host:     "host:587",
+			User:     "user",
+			Password: "password",
+		},
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "verification failed, precondition error",
+			fields: fields{
+				eventstore:  eventstoreExpect(t),
+				idGenerator: id_mock.NewIDGeneratorExpectIDs(t),
+				alg:         crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+				verifier: func(*smtp.Config, string) error {
+					return zerrors.ThrowInternal(nil, "EMAIL-s9kfs", "Errors.SMTP.CouldNotAuth")
+				},
+			},
+			args: args{
+				ctx:    authz.WithInstanceID(context.Background(), "INSTANCE"),
+				smtp:   config,
+				verify: true,
+			},
+			res: res{
+				err: zerrors.IsPreconditionFailed,
+			},
+		},
+		{
+			name: "verification with test email, ok",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewDomainPolicyAddedEvent(context.Background(),
+								&instance.NewAggregate("INSTANCE").Aggregate,
+								true, true, false,
+							),
+						),
+					),
+					expectPush(
+						instance.NewSMTPConfigAddedEvent(
+							context.Background(),
+							&instance.NewAggregate("INSTANCE").Aggregate,
+							"configid",
+							"test",
+							true,
+							"from@domain.ch",
+							"name",
+							"",
+							"host:587",
+							"user",
+							&crypto.CryptoValue{
+								CryptoType: crypto.TypeEncryption,
+								Algorithm:  "enc",
+								KeyID:      "id",
+								Crypted:    []byte("password"),
+							},
+						),
+					),
+				),
+				idGenerator: id_mock.NewIDGeneratorExpectIDs(t, "configid"),
+				alg:         crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+				verifier: func(cfg *smtp.Config, testEmail string) error {
+					if testEmail != "test@domain.ch" || cfg.SMTP.Password != "password" {
+						return zerrors.ThrowInternal(nil, "EMAIL-s9kfs", "Errors.SMTP.CouldNotAuth")
+					}
+					return nil
+				},
+			},
+			args: args{
+				ctx:       authz.WithInstanceID(context.Background(), "INSTANCE"),
+				smtp:      config,
+				verify:    true,
+				testEmail: "test@domain.ch",
+			},
+			res: res{
+				want: &domain.ObjectDetails{
+					ResourceOwner: "INSTANCE",
+				},
+			},
+		},
+		{
+			name: "no verification, ok",
+			fields: fields{
+				eventstore: eventstoreExpect(
+					t,
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewDomainPolicyAddedEvent(context.Background(),
+								&instance.NewAggregate("INSTANCE").Aggregate,
+								true, true, false,
+							),
+						),
+					),
+					expectPush(
+						instance.NewSMTPConfigAddedEvent(
+							context.Background(),
+							&instance.NewAggregate("INSTANCE").Aggregate,
+							"configid",
+							"test",
+							true,
+							"from@domain.ch",
+							"name",
+							"",
+							"host:587",
+							"user",
+							&crypto.CryptoValue{
+								CryptoType: crypto.TypeEncryption,
+								Algorithm:  "enc",
+								KeyID:      "id",
+								Crypted:    []byte("password"),
+							},
+						),
+					),
+				),
+				idGenerator: id_mock.NewIDGeneratorExpectIDs(t, "configid"),
+				alg:         crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+				verifier: func(*smtp.Config, string) error {
+					return zerrors.ThrowInternal(nil, "EMAIL-s9kfs", "Errors.SMTP.CouldNotAuth")
+				},
+			},
+			args: args{
+				ctx:  authz.WithInstanceID(context.Background(), "INSTANCE"),
+				smtp: config,
+			},
+			res: res{
+				want: &domain.ObjectDetails{
+					ResourceOwner: "INSTANCE",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Commands{
+				eventstore:         tt.fields.eventstore,
+				idGenerator:        tt.fields.idGenerator,
+				smtpEncryption:     tt.fields.alg,
+				smtpConfigVerifier: tt.fields.verifier,
+			}
+			_, got, err := r.AddSMTPConfigWithVerification(tt.args.ctx, "", tt.args.smtp, tt.args.verify, tt.args.testEmail)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.want, got)
+			}
+		})
+	}
+}
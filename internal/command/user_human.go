@@ -146,7 +146,7 @@ func (c *Commands) AddHuman(ctx context.Context, resourceOwner string, human *Ad
 		c.AddHumanCommand(
 			human,
 			resourceOwner,
-			c.userPasswordHasher,
+			c.passwordHasher(),
 			c.userEncryption,
 			allowInitMail,
 		))
@@ -520,7 +520,7 @@ func (c *Commands) createHuman(ctx context.Context, orgID string, human *domain.
 
 	human.EnsureDisplayName()
 	if human.Password != nil {
-		if err := human.HashPasswordIfExisting(ctx, pwPolicy, c.userPasswordHasher, human.Password.ChangeRequired); err != nil {
+		if err := human.HashPasswordIfExisting(ctx, pwPolicy, c.passwordHasher(), human.Password.ChangeRequired); err != nil {
 			return nil, nil, err
 		}
 	}
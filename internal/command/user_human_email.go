@@ -41,7 +41,12 @@ func (c *Commands) ChangeHumanEmail(ctx context.Context, email *domain.Email, em
 
 	events := make([]eventstore.Command, 0)
 	if hasChanged {
+		memberEmailEvents, err := c.orgMemberEmailChangedEvents(ctx, email.AggregateID, email.EmailAddress)
+		if err != nil {
+			return nil, err
+		}
 		events = append(events, changedEvent)
+		events = append(events, memberEmailEvents...)
 	}
 	if email.IsEmailVerified {
 		events = append(events, user.NewHumanEmailVerifiedEvent(ctx, userAgg))
@@ -21,6 +21,11 @@ type HumanEmailWriteModel struct {
 	CodeExpiry       time.Duration
 	AuthRequestID    string
 
+	PendingEmail            domain.EmailAddress
+	PendingCode             *crypto.CryptoValue
+	PendingCodeCreationDate time.Time
+	PendingCodeExpiry       time.Duration
+
 	UserState domain.UserState
 }
 
@@ -50,6 +55,7 @@ func (wm *HumanEmailWriteModel) Reduce() error {
 			wm.Email = e.EmailAddress
 			wm.IsEmailVerified = false
 			wm.Code = nil
+			wm.resetPendingEmail()
 		case *user.HumanEmailCodeAddedEvent:
 			wm.Code = e.Code
 			wm.CodeCreationDate = e.CreationDate()
@@ -58,6 +64,13 @@ func (wm *HumanEmailWriteModel) Reduce() error {
 		case *user.HumanEmailVerifiedEvent:
 			wm.IsEmailVerified = true
 			wm.Code = nil
+		case *user.HumanEmailSwapRequestedEvent:
+			wm.PendingEmail = e.EmailAddress
+			wm.PendingCode = e.Code
+			wm.PendingCodeCreationDate = e.CreationDate()
+			wm.PendingCodeExpiry = e.Expiry
+		case *user.HumanEmailSwapCancelledEvent:
+			wm.resetPendingEmail()
 		case *user.UserRemovedEvent:
 			wm.UserState = domain.UserStateDeleted
 		}
@@ -65,6 +78,13 @@ func (wm *HumanEmailWriteModel) Reduce() error {
 	return wm.WriteModel.Reduce()
 }
 
+func (wm *HumanEmailWriteModel) resetPendingEmail() {
+	wm.PendingEmail = ""
+	wm.PendingCode = nil
+	wm.PendingCodeCreationDate = time.Time{}
+	wm.PendingCodeExpiry = 0
+}
+
 func (wm *HumanEmailWriteModel) Query() *eventstore.SearchQueryBuilder {
 	query := eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
 		AddQuery().
@@ -84,6 +104,8 @@ func (wm *HumanEmailWriteModel) Query() *eventstore.SearchQueryBuilder {
 			user.HumanEmailCodeAddedType,
 			user.UserV1EmailVerifiedType,
 			user.HumanEmailVerifiedType,
+			user.HumanEmailSwapRequestedType,
+			user.HumanEmailSwapCancelledType,
 			user.UserRemovedType).
 		Builder()
 
@@ -12,6 +12,8 @@ import (
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/eventstore/v1/models"
+	"github.com/zitadel/zitadel/internal/repository/org"
+	"github.com/zitadel/zitadel/internal/repository/policy"
 	"github.com/zitadel/zitadel/internal/repository/user"
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
@@ -174,6 +176,7 @@ func TestCommandSide_ChangeHumanEmail(t *testing.T) {
 							),
 						),
 					),
+					expectFilter(), // no org member emails reserved
 					expectPush(
 						user.NewHumanEmailChangedEvent(context.Background(),
 							&user.NewAggregate("user1", "org1").Aggregate,
@@ -207,6 +210,125 @@ func TestCommandSide_ChangeHumanEmail(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "verified email changed, member emails moved",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							user.NewHumanAddedEvent(context.Background(),
+								&user.NewAggregate("user1", "org1").Aggregate,
+								"username",
+								"firstname",
+								"lastname",
+								"nickname",
+								"displayname",
+								language.German,
+								domain.GenderUnspecified,
+								"email@test.ch",
+								true,
+							),
+						),
+					),
+					expectFilter(
+						eventFromEventPusher(
+							func() eventstore.Command {
+								event := org.NewMemberAddedEvent(context.Background(), &org.NewAggregate("org1").Aggregate, "user1", domain.RoleOrgOwner)
+								event.UniqueEmail = "email@test.ch"
+								return event
+							}(),
+						),
+						eventFromEventPusher(
+							func() eventstore.Command {
+								event := org.NewMemberAddedEvent(context.Background(), &org.NewAggregate("org2").Aggregate, "user1", domain.RoleOrgOwner)
+								event.UniqueEmail = "email@test.ch"
+								return event
+							}(),
+						),
+					),
+					expectFilter(
+						eventFromEventPusher(
+							org.NewDomainPolicyAddedEvent(context.Background(), &org.NewAggregate("org1").Aggregate, false, false, false),
+						),
+						eventFromEventPusher(
+							newDomainPolicyChangedEvent(context.Background(), "org1", policy.ChangeMemberEmailsUnique(true)),
+						),
+					),
+					expectFilter(
+						eventFromEventPusher(
+							func() eventstore.Command {
+								event := org.NewMemberAddedEvent(context.Background(), &org.NewAggregate("org1").Aggregate, "user1", domain.RoleOrgOwner)
+								event.UniqueEmail = "email@test.ch"
+								return event
+							}(),
+						),
+					),
+					expectFilter(
+						eventFromEventPusher(
+							user.NewHumanAddedEvent(context.Background(),
+								&user.NewAggregate("user1", "org1").Aggregate,
+								"username",
+								"firstname",
+								"lastname",
+								"nickname",
+								"displayname",
+								language.German,
+								domain.GenderUnspecified,
+								"email@test.ch",
+								true,
+							),
+						),
+					),
+					expectFilter(
+						eventFromEventPusher(
+							org.NewDomainPolicyAddedEvent(context.Background(), &org.NewAggregate("org2").Aggregate, false, false, false),
+						),
+					),
+					expectPush(
+						user.NewHumanEmailChangedEvent(context.Background(),
+							&user.NewAggregate("user1", "org1").Aggregate,
+							"email-changed@test.ch",
+						),
+						org.NewMemberEmailChangedEvent(context.Background(),
+							&org.NewAggregate("org1").Aggregate,
+							"user1",
+							"email-changed@test.ch",
+							"email@test.ch",
+						),
+						org.NewMemberEmailChangedEvent(context.Background(),
+							&org.NewAggregate("org2").Aggregate,
+							"user1",
+							"",
+							"email@test.ch",
+						),
+						user.NewHumanEmailVerifiedEvent(context.Background(),
+							&user.NewAggregate("user1", "org1").Aggregate,
+						),
+					),
+				),
+			},
+			args: args{
+				ctx: context.Background(),
+				email: &domain.Email{
+					ObjectRoot: models.ObjectRoot{
+						AggregateID: "user1",
+					},
+					EmailAddress:    "email-changed@test.ch",
+					IsEmailVerified: true,
+				},
+				resourceOwner: "org1",
+			},
+			res: res{
+				want: &domain.Email{
+					ObjectRoot: models.ObjectRoot{
+						AggregateID:   "user1",
+						ResourceOwner: "org1",
+					},
+					EmailAddress:    "email-changed@test.ch",
+					IsEmailVerified: true,
+				},
+			},
+		},
 		{
 			name: "email verified, ok",
 			fields: fields{
@@ -325,6 +447,7 @@ func TestCommandSide_ChangeHumanEmail(t *testing.T) {
 							),
 						),
 					),
+					expectFilter(), // no org member emails reserved
 					expectPush(
 						user.NewHumanEmailChangedEvent(context.Background(),
 							&user.NewAggregate("user1", "org1").Aggregate,
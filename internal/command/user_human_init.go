@@ -32,7 +32,12 @@ func (c *Commands) ResendInitialMail(ctx context.Context, userID string, email d
 	userAgg := UserAggregateFromWriteModel(&existingCode.WriteModel)
 	if email != "" && existingCode.Email != email {
 		changedEvent, _ := existingCode.NewChangedEvent(ctx, userAgg, email)
+		memberEmailEvents, err := c.orgMemberEmailChangedEvents(ctx, userID, email)
+		if err != nil {
+			return nil, err
+		}
 		events = append(events, changedEvent)
+		events = append(events, memberEmailEvents...)
 	}
 	initCode, err := domain.NewInitUserCode(initCodeGenerator)
 	if err != nil {
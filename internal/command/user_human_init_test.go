@@ -348,6 +348,7 @@ func TestCommandSide_ResendInitialMail(t *testing.T) {
 							),
 						),
 					),
+					expectFilter(), // no org member emails reserved
 					expectPush(
 						user.NewHumanEmailChangedEvent(context.Background(),
 							&user.NewAggregate("user1", "org1").Aggregate,
@@ -0,0 +1,100 @@
+package command
+
+import (
+	"slices"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/user"
+)
+
+// HumanMFAEnrollmentWriteModel reduces the creation date of a human user and its enrolled multi factors
+type HumanMFAEnrollmentWriteModel struct {
+	eventstore.WriteModel
+
+	CreationDate time.Time
+	OTP          bool
+	OTPSMS       bool
+	OTPEmail     bool
+	U2FTokenIDs  []string
+
+	UserState domain.UserState
+}
+
+func NewHumanMFAEnrollmentWriteModel(userID, resourceOwner string) *HumanMFAEnrollmentWriteModel {
+	return &HumanMFAEnrollmentWriteModel{
+		WriteModel: eventstore.WriteModel{
+			AggregateID:   userID,
+			ResourceOwner: resourceOwner,
+		},
+	}
+}
+
+func (wm *HumanMFAEnrollmentWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		switch e := event.(type) {
+		case *user.HumanAddedEvent:
+			wm.CreationDate = e.CreationDate()
+			wm.UserState = domain.UserStateActive
+		case *user.HumanRegisteredEvent:
+			wm.CreationDate = e.CreationDate()
+			wm.UserState = domain.UserStateActive
+		case *user.HumanOTPVerifiedEvent:
+			wm.OTP = true
+		case *user.HumanOTPRemovedEvent:
+			wm.OTP = false
+		case *user.HumanOTPSMSAddedEvent:
+			wm.OTPSMS = true
+		case *user.HumanOTPSMSRemovedEvent:
+			wm.OTPSMS = false
+		case *user.HumanOTPEmailAddedEvent:
+			wm.OTPEmail = true
+		case *user.HumanOTPEmailRemovedEvent:
+			wm.OTPEmail = false
+		case *user.HumanU2FVerifiedEvent:
+			wm.U2FTokenIDs = append(wm.U2FTokenIDs, e.WebAuthNTokenID)
+		case *user.HumanU2FRemovedEvent:
+			wm.U2FTokenIDs = slices.DeleteFunc(wm.U2FTokenIDs, func(id string) bool {
+				return id == e.WebAuthNTokenID
+			})
+		case *user.UserRemovedEvent:
+			wm.UserState = domain.UserStateDeleted
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *HumanMFAEnrollmentWriteModel) Query() *eventstore.SearchQueryBuilder {
+	query := eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		AggregateTypes(user.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(
+			user.UserV1AddedType,
+			user.HumanAddedType,
+			user.UserV1RegisteredType,
+			user.HumanRegisteredType,
+			user.UserV1MFAOTPVerifiedType,
+			user.HumanMFAOTPVerifiedType,
+			user.UserV1MFAOTPRemovedType,
+			user.HumanMFAOTPRemovedType,
+			user.HumanOTPSMSAddedType,
+			user.HumanOTPSMSRemovedType,
+			user.HumanOTPEmailAddedType,
+			user.HumanOTPEmailRemovedType,
+			user.HumanU2FTokenVerifiedType,
+			user.HumanU2FTokenRemovedType,
+			user.UserRemovedType).
+		Builder()
+
+	if wm.ResourceOwner != "" {
+		query.ResourceOwner(wm.ResourceOwner)
+	}
+	return query
+}
+
+// HasMFA returns true if the user has enrolled at least one multi factor
+func (wm *HumanMFAEnrollmentWriteModel) HasMFA() bool {
+	return wm.OTP || wm.OTPSMS || wm.OTPEmail || len(wm.U2FTokenIDs) > 0
+}
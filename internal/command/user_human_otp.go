@@ -127,6 +127,8 @@ func (c *Commands) createHumanTOTP(ctx context.Context, userID, resourceOwner st
 	}, nil
 }
 
+// HumanCheckMFATOTPSetup verifies the code against the secret of the not yet ready TOTP
+// and only then marks it ready. Codes of the periods within the configured [domain.OTPConfig.SetupSkew] are accepted.
 func (c *Commands) HumanCheckMFATOTPSetup(ctx context.Context, userID, code, userAgentID, resourceOwner string) (*domain.ObjectDetails, error) {
 	if userID == "" {
 		return nil, zerrors.ThrowPreconditionFailed(nil, "COMMAND-8N9ds", "Errors.User.UserIDMissing")
@@ -147,7 +149,7 @@ func (c *Commands) HumanCheckMFATOTPSetup(ctx context.Context, userID, code, use
 	if existingOTP.State == domain.MFAStateReady {
 		return nil, zerrors.ThrowPreconditionFailed(nil, "COMMAND-qx4ls", "Errors.Users.MFA.OTP.AlreadyReady")
 	}
-	if err := domain.VerifyTOTP(code, existingOTP.Secret, c.multifactors.OTP.CryptoMFA); err != nil {
+	if err := domain.VerifyTOTPWithSkew(code, existingOTP.Secret, c.multifactors.OTP.CryptoMFA, c.multifactors.OTP.TOTPSetupSkew()); err != nil {
 		return nil, err
 	}
 	userAgg := UserAggregateFromWriteModel(&existingOTP.WriteModel)
@@ -629,10 +629,14 @@ func TestCommands_HumanCheckMFATOTPSetup(t *testing.T) {
 
 	code, err := totp.GenerateCode(key.Secret(), time.Now())
 	require.NoError(t, err)
+	// the code of two periods ago
+	oldCode, err := totp.GenerateCode(key.Secret(), time.Now().Add(-60*time.Second))
+	require.NoError(t, err)
 
 	type fields struct {
 		eventstore      func(t *testing.T) *eventstore.Eventstore
 		checkPermission domain.PermissionCheck
+		setupSkew       uint
 	}
 	type args struct {
 		userID        string
@@ -746,6 +750,49 @@ func TestCommands_HumanCheckMFATOTPSetup(t *testing.T) {
 			},
 			wantErr: zerrors.ThrowInvalidArgument(nil, "EVENT-8isk2", "Errors.User.MFA.OTP.InvalidCode"),
 		},
+		{
+			name: "code outside of skew error",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							user.NewHumanOTPAddedEvent(ctx, userAgg, secret),
+						),
+					),
+				),
+			},
+			args: args{
+				resourceOwner: "org1",
+				code:          oldCode,
+				userID:        "user1",
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "EVENT-8isk2", "Errors.User.MFA.OTP.InvalidCode"),
+		},
+		{
+			name: "code within configured skew",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							user.NewHumanOTPAddedEvent(ctx, userAgg, secret),
+						),
+					),
+					expectPush(
+						user.NewHumanOTPVerifiedEvent(ctx,
+							userAgg,
+							"agent1",
+						),
+					),
+				),
+				setupSkew: 2,
+			},
+			args: args{
+				resourceOwner: "org1",
+				code:          oldCode,
+				userID:        "user1",
+			},
+			want: true,
+		},
 		{
 			name: "push error",
 			fields: fields{
@@ -826,6 +873,7 @@ func TestCommands_HumanCheckMFATOTPSetup(t *testing.T) {
 				multifactors: domain.MultifactorConfigs{
 					OTP: domain.OTPConfig{
 						CryptoMFA: cryptoAlg,
+						SetupSkew: tt.fields.setupSkew,
 					},
 				},
 			}
@@ -16,6 +16,25 @@ import (
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
+// SetPasswordHasher replaces the hasher of user passwords, e.g. after the hash cost or algorithm has changed.
+// Passwords hashed with an outdated configuration are rehashed on the next successful password check.
+func (c *Commands) SetPasswordHasher(hasher *crypto.Hasher) error {
+	if hasher == nil || hasher.Swapper == nil {
+		return zerrors.ThrowInvalidArgument(nil, "COMMAND-Ph3sw", "Errors.Internal")
+	}
+	c.passwordHasherMu.Lock()
+	defer c.passwordHasherMu.Unlock()
+	c.userPasswordHasher = hasher
+	return nil
+}
+
+// passwordHasher returns the current hasher of user passwords, see [Commands.SetPasswordHasher]
+func (c *Commands) passwordHasher() *crypto.Hasher {
+	c.passwordHasherMu.RLock()
+	defer c.passwordHasherMu.RUnlock()
+	return c.userPasswordHasher
+}
+
 func (c *Commands) SetPassword(ctx context.Context, orgID, userID, password string, oneTime bool) (objectDetails *domain.ObjectDetails, err error) {
 	ctx, span := tracing.NewSpan(ctx)
 	defer func() { span.EndWithError(err) }()
@@ -124,7 +143,7 @@ func (c *Commands) checkCurrentPassword(
 	if newEncodedPassword != "" {
 		return func(ctx context.Context) (_ string, err error) {
 			_, spanPasswap := tracing.NewNamedSpan(ctx, "passwap.Verify")
-			_, err = c.userPasswordHasher.Verify(currentEncodePassword, currentPassword)
+			_, err = c.passwordHasher().Verify(currentEncodePassword, currentPassword)
 			spanPasswap.EndWithError(err)
 			return "", convertPasswapErr(err)
 		}
@@ -149,6 +168,9 @@ func (c *Commands) setPassword(
 	if err != nil {
 		return nil, err
 	}
+	if err = c.checkPasswordHistory(ctx, wm, password); err != nil {
+		return nil, err
+	}
 	err = c.pushAppendAndReduce(ctx, wm, command)
 	if err != nil {
 		return nil, err
@@ -189,7 +211,7 @@ func (c *Commands) setPasswordCommand(ctx context.Context, agg *eventstore.Aggre
 	// In case only a plain password was passed, we need to hash it.
 	if encodedPassword == "" {
 		_, span := tracing.NewNamedSpan(ctx, "passwap.Hash")
-		encodedPassword, err = c.userPasswordHasher.Hash(password)
+		encodedPassword, err = c.passwordHasher().Hash(password)
 		span.EndWithError(err)
 		if err = convertPasswapErr(err); err != nil {
 			return nil, err
@@ -206,11 +228,38 @@ func (c *Commands) verifyAndUpdatePassword(ctx context.Context, encodedHash, old
 	}
 
 	_, spanPasswap := tracing.NewNamedSpan(ctx, "passwap.Verify")
-	updated, err := c.userPasswordHasher.VerifyAndUpdate(encodedHash, oldPassword, newPassword)
+	updated, err := c.passwordHasher().VerifyAndUpdate(encodedHash, oldPassword, newPassword)
 	spanPasswap.EndWithError(err)
 	return updated, convertPasswapErr(err)
 }
 
+// maxPasswordHistoryCount is the maximum number of previous passwords checked for reuse,
+// higher values of the password age policy are capped
+const maxPasswordHistoryCount = 24
+
+// checkPasswordHistory rejects the password if it matches one of the latest passwords of the user
+// as defined by the history count of the password age policy.
+// Encoded passwords cannot be compared and are not checked.
+func (c *Commands) checkPasswordHistory(ctx context.Context, wm *HumanPasswordWriteModel, password string) (err error) {
+	if password == "" || len(wm.PasswordHistory) == 0 {
+		return nil
+	}
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	policy, err := c.getOrgPasswordAgePolicy(ctx, wm.ResourceOwner)
+	if err != nil {
+		return err
+	}
+	count := min(int(policy.HistoryCount), maxPasswordHistoryCount, len(wm.PasswordHistory))
+	for _, encodedHash := range wm.PasswordHistory[len(wm.PasswordHistory)-count:] {
+		if _, err := c.passwordHasher().Verify(encodedHash, password); err == nil {
+			return zerrors.ThrowInvalidArgument(nil, "COMMAND-Pw7hs", "Errors.User.Password.AlreadyUsed")
+		}
+	}
+	return nil
+}
+
 // checkPasswordComplexity checks uf the given password can be used to be the password of a user
 func (c *Commands) checkPasswordComplexity(ctx context.Context, newPassword string, resourceOwner string) (err error) {
 	ctx, span := tracing.NewSpan(ctx)
@@ -314,7 +363,7 @@ func (c *Commands) HumanCheckPassword(ctx context.Context, orgID, userID, passwo
 	if !loginPolicy.AllowUsernamePassword {
 		return zerrors.ThrowPreconditionFailed(err, "COMMAND-Dft32", "Errors.Org.LoginPolicy.UsernamePasswordNotAllowed")
 	}
-	commands, err := checkPassword(ctx, userID, password, c.eventstore, c.userPasswordHasher, authRequestDomainToAuthRequestInfo(authRequest))
+	commands, err := checkPassword(ctx, userID, password, c.eventstore, c.passwordHasher(), authRequestDomainToAuthRequestInfo(authRequest))
 	if len(commands) == 0 {
 		return err
 	}
@@ -14,6 +14,9 @@ type HumanPasswordWriteModel struct {
 
 	EncodedHash          string
 	SecretChangeRequired bool
+	// PasswordHistory contains the hashes of the latest passwords including the current one, the newest last.
+	// It is bounded to [maxPasswordHistoryCount].
+	PasswordHistory []string
 
 	Code                     *crypto.CryptoValue
 	CodeCreationDate         time.Time
@@ -37,10 +40,12 @@ func (wm *HumanPasswordWriteModel) Reduce() error {
 		switch e := event.(type) {
 		case *user.HumanAddedEvent:
 			wm.EncodedHash = crypto.SecretOrEncodedHash(e.Secret, e.EncodedHash)
+			wm.appendPasswordHistory(wm.EncodedHash)
 			wm.SecretChangeRequired = e.ChangeRequired
 			wm.UserState = domain.UserStateActive
 		case *user.HumanRegisteredEvent:
 			wm.EncodedHash = crypto.SecretOrEncodedHash(e.Secret, e.EncodedHash)
+			wm.appendPasswordHistory(wm.EncodedHash)
 			wm.SecretChangeRequired = e.ChangeRequired
 			wm.UserState = domain.UserStateActive
 		case *user.HumanInitialCodeAddedEvent:
@@ -49,6 +54,7 @@ func (wm *HumanPasswordWriteModel) Reduce() error {
 			wm.UserState = domain.UserStateActive
 		case *user.HumanPasswordChangedEvent:
 			wm.EncodedHash = crypto.SecretOrEncodedHash(e.Secret, e.EncodedHash)
+			wm.appendPasswordHistory(wm.EncodedHash)
 			wm.SecretChangeRequired = e.ChangeRequired
 			wm.Code = nil
 			wm.PasswordCheckFailedCount = 0
@@ -75,11 +81,26 @@ func (wm *HumanPasswordWriteModel) Reduce() error {
 			wm.UserState = domain.UserStateDeleted
 		case *user.HumanPasswordHashUpdatedEvent:
 			wm.EncodedHash = e.EncodedHash
+			// the hash of the current password was updated, the password itself did not change
+			if len(wm.PasswordHistory) > 0 {
+				wm.PasswordHistory[len(wm.PasswordHistory)-1] = e.EncodedHash
+			}
 		}
 	}
 	return wm.WriteModel.Reduce()
 }
 
+// appendPasswordHistory adds the hash to the history and prunes the hashes exceeding [maxPasswordHistoryCount]
+func (wm *HumanPasswordWriteModel) appendPasswordHistory(encodedHash string) {
+	if encodedHash == "" {
+		return
+	}
+	wm.PasswordHistory = append(wm.PasswordHistory, encodedHash)
+	if len(wm.PasswordHistory) > maxPasswordHistoryCount {
+		wm.PasswordHistory = wm.PasswordHistory[len(wm.PasswordHistory)-maxPasswordHistoryCount:]
+	}
+}
+
 func (wm *HumanPasswordWriteModel) Query() *eventstore.SearchQueryBuilder {
 	query := eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
 		AddQuery().
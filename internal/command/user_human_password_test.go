@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"io"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/zitadel/passwap"
 	"go.uber.org/mock/gomock"
 	"golang.org/x/text/language"
@@ -15,6 +17,7 @@ import (
 	"github.com/zitadel/zitadel/internal/crypto"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
 	"github.com/zitadel/zitadel/internal/repository/org"
 	"github.com/zitadel/zitadel/internal/repository/user"
 	"github.com/zitadel/zitadel/internal/zerrors"
@@ -883,6 +886,163 @@ func TestCommandSide_ChangePassword(t *testing.T) {
 				err: zerrors.IsErrorInvalidArgument,
 			},
 		},
+		{
+			name: "password already used, invalid argument error",
+			fields: fields{
+				userPasswordHasher: mockPasswordHasher("x"),
+			},
+			args: args{
+				ctx:           context.Background(),
+				userID:        "user1",
+				resourceOwner: "org1",
+				oldPassword:   "password",
+				newPassword:   "password1",
+			},
+			expect: []expect{
+				expectFilter(
+					eventFromEventPusher(
+						user.NewHumanAddedEvent(context.Background(),
+							&user.NewAggregate("user1", "org1").Aggregate,
+							"username",
+							"firstname",
+							"lastname",
+							"nickname",
+							"displayname",
+							language.German,
+							domain.GenderUnspecified,
+							"email@test.ch",
+							true,
+						),
+					),
+					eventFromEventPusher(
+						user.NewHumanEmailVerifiedEvent(context.Background(),
+							&user.NewAggregate("user1", "org1").Aggregate,
+						),
+					),
+					eventFromEventPusher(
+						user.NewHumanPasswordChangedEvent(context.Background(),
+							&user.NewAggregate("user1", "org1").Aggregate,
+							"$plain$x$password1",
+							false,
+							"")),
+					eventFromEventPusher(
+						user.NewHumanPasswordChangedEvent(context.Background(),
+							&user.NewAggregate("user1", "org1").Aggregate,
+							"$plain$x$password",
+							false,
+							"")),
+				),
+				expectFilter(
+					eventFromEventPusher(
+						org.NewPasswordComplexityPolicyAddedEvent(context.Background(),
+							&org.NewAggregate("org1").Aggregate,
+							1,
+							false,
+							false,
+							false,
+							false,
+						),
+					),
+				),
+				expectFilter(
+					eventFromEventPusher(
+						org.NewPasswordAgePolicyAddedEvent(context.Background(),
+							&org.NewAggregate("org1").Aggregate,
+							0,
+							0,
+							2,
+						),
+					),
+				),
+			},
+			res: res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			name: "password used before history, ok",
+			fields: fields{
+				userPasswordHasher: mockPasswordHasher("x"),
+			},
+			args: args{
+				ctx:           context.Background(),
+				userID:        "user1",
+				resourceOwner: "org1",
+				oldPassword:   "password",
+				newPassword:   "password1",
+			},
+			expect: []expect{
+				expectFilter(
+					eventFromEventPusher(
+						user.NewHumanAddedEvent(context.Background(),
+							&user.NewAggregate("user1", "org1").Aggregate,
+							"username",
+							"firstname",
+							"lastname",
+							"nickname",
+							"displayname",
+							language.German,
+							domain.GenderUnspecified,
+							"email@test.ch",
+							true,
+						),
+					),
+					eventFromEventPusher(
+						user.NewHumanEmailVerifiedEvent(context.Background(),
+							&user.NewAggregate("user1", "org1").Aggregate,
+						),
+					),
+					eventFromEventPusher(
+						user.NewHumanPasswordChangedEvent(context.Background(),
+							&user.NewAggregate("user1", "org1").Aggregate,
+							"$plain$x$password1",
+							false,
+							"")),
+					eventFromEventPusher(
+						user.NewHumanPasswordChangedEvent(context.Background(),
+							&user.NewAggregate("user1", "org1").Aggregate,
+							"$plain$x$password",
+							false,
+							"")),
+				),
+				expectFilter(
+					eventFromEventPusher(
+						org.NewPasswordComplexityPolicyAddedEvent(context.Background(),
+							&org.NewAggregate("org1").Aggregate,
+							1,
+							false,
+							false,
+							false,
+							false,
+						),
+					),
+				),
+				expectFilter(),
+				expectFilter(
+					eventFromEventPusher(
+						instance.NewPasswordAgePolicyAddedEvent(context.Background(),
+							&instance.NewAggregate("INSTANCE").Aggregate,
+							0,
+							0,
+							1,
+						),
+					),
+				),
+				expectPush(
+					user.NewHumanPasswordChangedEvent(context.Background(),
+						&user.NewAggregate("user1", "org1").Aggregate,
+						"$plain$x$password1",
+						false,
+						"",
+					),
+				),
+			},
+			res: res{
+				want: &domain.ObjectDetails{
+					ResourceOwner: "org1",
+				},
+			},
+		},
 		{
 			name: "change password, ok",
 			fields: fields{
@@ -935,6 +1095,8 @@ func TestCommandSide_ChangePassword(t *testing.T) {
 						),
 					),
 				),
+				expectFilter(),
+				expectFilter(),
 				expectPush(
 					user.NewHumanPasswordChangedEvent(context.Background(),
 						&user.NewAggregate("user1", "org1").Aggregate,
@@ -1003,6 +1165,8 @@ func TestCommandSide_ChangePassword(t *testing.T) {
 						),
 					),
 				),
+				expectFilter(),
+				expectFilter(),
 				expectPush(
 					user.NewHumanPasswordChangedEvent(context.Background(),
 						&user.NewAggregate("user1", "org1").Aggregate,
@@ -1072,6 +1236,8 @@ func TestCommandSide_ChangePassword(t *testing.T) {
 						),
 					),
 				),
+				expectFilter(),
+				expectFilter(),
 				expectPush(
 					user.NewHumanPasswordChangedEvent(context.Background(),
 						&user.NewAggregate("user1", "org1").Aggregate,
@@ -2286,3 +2452,23 @@ func Test_convertPasswapErr(t *testing.T) {
 		})
 	}
 }
+
+func TestCommands_SetPasswordHasher(t *testing.T) {
+	c := &Commands{userPasswordHasher: mockPasswordHasher("x")}
+	require.ErrorIs(t, c.SetPasswordHasher(nil), zerrors.ThrowInvalidArgument(nil, "COMMAND-Ph3sw", "Errors.Internal"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NotNil(t, c.passwordHasher())
+		}()
+	}
+	require.NoError(t, c.SetPasswordHasher(mockPasswordHasher("y")))
+	wg.Wait()
+
+	updated, err := c.passwordHasher().Verify("$plain$x$password", "password")
+	require.NoError(t, err)
+	assert.Equal(t, "$plain$y$password", updated, "password of the previous hasher must be rehashed")
+}
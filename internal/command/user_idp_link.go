@@ -48,6 +48,57 @@ func (c *Commands) AddUserIDPLink(ctx context.Context, userID, resourceOwner str
 	}, nil
 }
 
+// EnsureUserIDPLink links the user to the external user of the IDP if the link doesn't exist yet.
+// If the user is already linked, the existing link is returned and created is false,
+// which allows retries of bulk linking without errors or duplicate links.
+func (c *Commands) EnsureUserIDPLink(ctx context.Context, userID, resourceOwner string, link *AddLink) (_ *domain.ObjectDetails, created bool, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if userID == "" {
+		return nil, false, zerrors.ThrowInvalidArgument(nil, "COMMAND-Lq4ns", "Errors.IDMissing")
+	}
+	if link == nil || link.IDPID == "" || link.IDPExternalID == "" {
+		return nil, false, zerrors.ThrowInvalidArgument(nil, "COMMAND-Lq7vd", "Errors.User.ExternalIDP.Invalid")
+	}
+	existingUser, err := c.userWriteModelByID(ctx, userID, resourceOwner)
+	if err != nil {
+		return nil, false, err
+	}
+	if !isUserStateExists(existingUser.UserState) {
+		return nil, false, zerrors.ThrowPreconditionFailed(nil, "COMMAND-Lq2hx", "Errors.User.NotFound")
+	}
+	if userID != authz.GetCtxData(ctx).UserID {
+		if err := c.checkPermission(ctx, domain.PermissionUserWrite, existingUser.ResourceOwner, existingUser.AggregateID); err != nil {
+			return nil, false, err
+		}
+	}
+	existingLink, err := c.userIDPLinkWriteModelByID(ctx, userID, link.IDPID, link.IDPExternalID, existingUser.ResourceOwner)
+	if err != nil {
+		return nil, false, err
+	}
+	if existingLink.State == domain.UserIDPLinkStateActive {
+		return writeModelToObjectDetails(&existingLink.WriteModel), false, nil
+	}
+	//nolint:staticcheck
+	event, err := addLink(ctx, c.eventstore.Filter, user.NewAggregate(existingUser.AggregateID, existingUser.ResourceOwner), link)
+	if err != nil {
+		return nil, false, err
+	}
+	err = c.pushAppendAndReduce(ctx, existingLink, event)
+	if zerrors.IsErrorAlreadyExists(err) {
+		// a concurrent retry might have linked the user in the meantime
+		existingLink, readErr := c.userIDPLinkWriteModelByID(ctx, userID, link.IDPID, link.IDPExternalID, existingUser.ResourceOwner)
+		if readErr == nil && existingLink.State == domain.UserIDPLinkStateActive {
+			return writeModelToObjectDetails(&existingLink.WriteModel), false, nil
+		}
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return writeModelToObjectDetails(&existingLink.WriteModel), true, nil
+}
+
 func (c *Commands) BulkAddedUserIDPLinks(ctx context.Context, userID, resourceOwner string, links []*domain.UserIDPLink) (err error) {
 	if userID == "" {
 		return zerrors.ThrowInvalidArgument(nil, "COMMAND-03j8f", "Errors.IDMissing")
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/text/language"
 
+	"github.com/zitadel/zitadel/internal/api/authz"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/eventstore/v1/models"
@@ -517,6 +518,187 @@ func TestCommandSide_BulkAddUserIDPLinks(t *testing.T) {
 	}
 }
 
+func TestCommandSide_EnsureUserIDPLink(t *testing.T) {
+	ctx := authz.NewMockContext("instance1", "org1", "user1")
+	userAgg := user.NewAggregate("user1", "org1")
+	humanAdded := eventFromEventPusher(
+		user.NewHumanAddedEvent(ctx,
+			&userAgg.Aggregate,
+			"userName",
+			"firstName",
+			"lastName",
+			"nickName",
+			"displayName",
+			language.German,
+			domain.GenderFemale,
+			"email@Address.ch",
+			false,
+		),
+	)
+	linkAdded := func() *user.UserIDPLinkAddedEvent {
+		return user.NewUserIDPLinkAddedEvent(ctx, &userAgg.Aggregate, "idp1", "name", "externaluser1")
+	}
+	link := &AddLink{
+		IDPID:         "idp1",
+		DisplayName:   "name",
+		IDPExternalID: "externaluser1",
+	}
+	type fields struct {
+		eventstore      func(*testing.T) *eventstore.Eventstore
+		checkPermission domain.PermissionCheck
+	}
+	type args struct {
+		ctx    context.Context
+		userID string
+		link   *AddLink
+	}
+	type res struct {
+		want    *domain.ObjectDetails
+		created bool
+		err     error
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "missing user id, error",
+			fields: fields{
+				eventstore: expectEventstore(),
+			},
+			args: args{
+				ctx:  ctx,
+				link: link,
+			},
+			res: res{
+				err: zerrors.ThrowInvalidArgument(nil, "COMMAND-Lq4ns", "Errors.IDMissing"),
+			},
+		},
+		{
+			name: "invalid link, error",
+			fields: fields{
+				eventstore: expectEventstore(),
+			},
+			args: args{
+				ctx:    ctx,
+				userID: "user1",
+				link:   &AddLink{IDPID: "idp1"},
+			},
+			res: res{
+				err: zerrors.ThrowInvalidArgument(nil, "COMMAND-Lq7vd", "Errors.User.ExternalIDP.Invalid"),
+			},
+		},
+		{
+			name: "user not existing, error",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+				),
+			},
+			args: args{
+				ctx:    ctx,
+				userID: "user1",
+				link:   link,
+			},
+			res: res{
+				err: zerrors.ThrowPreconditionFailed(nil, "COMMAND-Lq2hx", "Errors.User.NotFound"),
+			},
+		},
+		{
+			name: "no permission, error",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(humanAdded),
+				),
+				checkPermission: newMockPermissionCheckNotAllowed(),
+			},
+			args: args{
+				ctx:    authz.NewMockContext("instance1", "org1", "admin"),
+				userID: "user1",
+				link:   link,
+			},
+			res: res{
+				err: zerrors.ThrowPermissionDenied(nil, "AUTHZ-HKJD33", "Errors.PermissionDenied"),
+			},
+		},
+		{
+			name: "link already existing, ok",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(humanAdded),
+					expectFilter(
+						eventFromEventPusher(linkAdded()),
+					),
+				),
+			},
+			args: args{
+				ctx:    ctx,
+				userID: "user1",
+				link:   link,
+			},
+			res: res{
+				want: &domain.ObjectDetails{
+					ResourceOwner: "org1",
+				},
+				created: false,
+			},
+		},
+		{
+			name: "link created, ok",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(humanAdded),
+					expectFilter(
+						eventFromEventPusher(linkAdded()),
+						eventFromEventPusher(
+							user.NewUserIDPLinkRemovedEvent(ctx, &userAgg.Aggregate, "idp1", "externaluser1"),
+						),
+					),
+					expectFilter(
+						eventFromEventPusher(
+							org.NewGoogleIDPAddedEvent(ctx,
+								&org.NewAggregate("org1").Aggregate,
+								"idp1",
+								"google",
+								"clientID",
+								nil,
+								[]string{"openid"},
+								idp.Options{},
+							),
+						),
+					),
+					expectPush(linkAdded()),
+				),
+			},
+			args: args{
+				ctx:    ctx,
+				userID: "user1",
+				link:   link,
+			},
+			res: res{
+				want: &domain.ObjectDetails{
+					ResourceOwner: "org1",
+				},
+				created: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Commands{
+				eventstore:      tt.fields.eventstore(t),
+				checkPermission: tt.fields.checkPermission,
+			}
+			got, created, err := r.EnsureUserIDPLink(tt.args.ctx, tt.args.userID, "org1", tt.args.link)
+			assert.ErrorIs(t, err, tt.res.err)
+			assert.Equal(t, tt.res.want, got)
+			assert.Equal(t, tt.res.created, created)
+		})
+	}
+}
+
 func TestCommandSide_RemoveUserIDPLink(t *testing.T) {
 	type fields struct {
 		eventstore *eventstore.Eventstore
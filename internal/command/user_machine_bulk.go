@@ -0,0 +1,81 @@
+package command
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/command/preparation"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/repository/user"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// AddMachineWithKeyResult is the result of a single machine of [Commands.AddMachinesWithKeys].
+// Either Err or the details and the key are set.
+type AddMachineWithKeyResult struct {
+	Username string
+	Details  *domain.ObjectDetails
+	// MachineKey contains the generated private key, it's only returned once
+	MachineKey *MachineKey
+	Err        error
+}
+
+// AddMachinesWithKeys creates the machines in the organization, each with a generated machine key of the passed type and expiration.
+// Every machine is pushed separately and a failing machine doesn't prevent the others from being created,
+// the results are in the same order as the machines.
+// Usernames must be unique within the request, the uniqueness within the organization is ensured by the eventstore.
+func (c *Commands) AddMachinesWithKeys(ctx context.Context, resourceOwner string, machines []*Machine, key *AddMachineKey) (_ []*AddMachineWithKeyResult, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if resourceOwner == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Bk2wq", "Errors.ResourceOwnerMissing")
+	}
+	if len(machines) == 0 || key == nil {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Bk7ds", "Errors.User.Invalid")
+	}
+	results := make([]*AddMachineWithKeyResult, len(machines))
+	usernames := make(map[string]struct{}, len(machines))
+	for i, machine := range machines {
+		results[i] = &AddMachineWithKeyResult{Username: machine.Username}
+		if _, ok := usernames[machine.Username]; ok {
+			results[i].Err = zerrors.ThrowAlreadyExists(nil, "COMMAND-Bk4nf", "Errors.User.AlreadyExisting")
+			continue
+		}
+		usernames[machine.Username] = struct{}{}
+		results[i].Details, results[i].MachineKey, results[i].Err = c.addMachineWithKey(ctx, resourceOwner, machine, key)
+	}
+	return results, nil
+}
+
+func (c *Commands) addMachineWithKey(ctx context.Context, resourceOwner string, machine *Machine, key *AddMachineKey) (_ *domain.ObjectDetails, _ *MachineKey, err error) {
+	machine.ResourceOwner = resourceOwner
+	if machine.AggregateID == "" {
+		machine.AggregateID, err = c.idGenerator.Next()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	machineKey := NewMachineKey(resourceOwner, machine.AggregateID, key.ExpirationDate, key.Type)
+	machineKey.KeyID, err = c.idGenerator.Next()
+	if err != nil {
+		return nil, nil, err
+	}
+	validations := []preparation.Validation{
+		AddMachineCommand(user.NewAggregate(machine.AggregateID, resourceOwner), machine),
+		prepareAddUserMachineKey(machineKey, c.machineKeySize),
+	}
+	cmds, err := preparation.PrepareCommands(ctx, c.eventstore.Filter, validations...) //nolint:staticcheck
+	if err != nil {
+		return nil, nil, err
+	}
+	events, err := c.eventstore.Push(ctx, cmds...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &domain.ObjectDetails{
+		Sequence:      events[len(events)-1].Sequence(),
+		EventDate:     events[len(events)-1].CreatedAt(),
+		ResourceOwner: events[len(events)-1].Aggregate().ResourceOwner,
+	}, machineKey, nil
+}
@@ -0,0 +1,108 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	id_mock "github.com/zitadel/zitadel/internal/id/mock"
+	"github.com/zitadel/zitadel/internal/repository/org"
+	"github.com/zitadel/zitadel/internal/repository/user"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestCommands_AddMachinesWithKeys(t *testing.T) {
+	domainPolicy := func() expect {
+		return expectFilter(
+			eventFromEventPusher(
+				org.NewDomainPolicyAddedEvent(context.Background(),
+					&org.NewAggregate("org1").Aggregate,
+					true,
+					true,
+					true,
+				),
+			),
+		)
+	}
+	key := &AddMachineKey{
+		Type:           domain.AuthNKeyTypeJSON,
+		ExpirationDate: time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC),
+	}
+
+	t.Run("missing resource owner, error", func(t *testing.T) {
+		c := &Commands{eventstore: expectEventstore()(t)}
+		_, err := c.AddMachinesWithKeys(context.Background(), "", []*Machine{{Username: "ci", Name: "ci"}}, key)
+		require.ErrorIs(t, err, zerrors.ThrowInvalidArgument(nil, "COMMAND-Bk2wq", "Errors.ResourceOwnerMissing"))
+	})
+
+	t.Run("no machines, error", func(t *testing.T) {
+		c := &Commands{eventstore: expectEventstore()(t)}
+		_, err := c.AddMachinesWithKeys(context.Background(), "org1", nil, key)
+		require.ErrorIs(t, err, zerrors.ThrowInvalidArgument(nil, "COMMAND-Bk7ds", "Errors.User.Invalid"))
+	})
+
+	t.Run("partial failure, others created", func(t *testing.T) {
+		pushErr := zerrors.ThrowAlreadyExists(nil, "V3-DKcAT", "Errors.User.AlreadyExists")
+		c := &Commands{
+			eventstore: expectEventstore(
+				// ci-1 is created
+				expectFilter(),
+				domainPolicy(),
+				expectFilter(),
+				expectFilter(),
+				expectRandomPush([]eventstore.Command{
+					user.NewMachineAddedEvent(context.Background(), &user.NewAggregate("user1", "org1").Aggregate, "ci-1", "ci-1", "", true, domain.OIDCTokenTypeBearer),
+					user.NewMachineKeyAddedEvent(context.Background(), &user.NewAggregate("user1", "org1").Aggregate, "key1", domain.AuthNKeyTypeJSON, key.ExpirationDate, nil),
+				}),
+				// ci-2 already exists in the organization
+				expectFilter(),
+				domainPolicy(),
+				expectFilter(),
+				expectFilter(),
+				expectRandomPushFailed(pushErr, []eventstore.Command{
+					user.NewMachineAddedEvent(context.Background(), &user.NewAggregate("user2", "org1").Aggregate, "ci-2", "ci-2", "", true, domain.OIDCTokenTypeBearer),
+					user.NewMachineKeyAddedEvent(context.Background(), &user.NewAggregate("user2", "org1").Aggregate, "key2", domain.AuthNKeyTypeJSON, key.ExpirationDate, nil),
+				}),
+				// ci-3 is created
+				expectFilter(),
+				domainPolicy(),
+				expectFilter(),
+				expectFilter(),
+				expectRandomPush([]eventstore.Command{
+					user.NewMachineAddedEvent(context.Background(), &user.NewAggregate("user3", "org1").Aggregate, "ci-3", "ci-3", "", true, domain.OIDCTokenTypeBearer),
+					user.NewMachineKeyAddedEvent(context.Background(), &user.NewAggregate("user3", "org1").Aggregate, "key3", domain.AuthNKeyTypeJSON, key.ExpirationDate, nil),
+				}),
+			)(t),
+			idGenerator:    id_mock.NewIDGeneratorExpectIDs(t, "user1", "key1", "user2", "key2", "user3", "key3"),
+			machineKeySize: 2048,
+		}
+		results, err := c.AddMachinesWithKeys(context.Background(), "org1", []*Machine{
+			{Username: "ci-1", Name: "ci-1"},
+			{Username: "ci-2", Name: "ci-2"},
+			{Username: "ci-1", Name: "ci-1"},
+			{Username: "ci-3", Name: "ci-3"},
+		}, key)
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, "user1", results[0].MachineKey.AggregateID)
+		assert.Equal(t, "key1", results[0].MachineKey.KeyID)
+		assert.NotEmpty(t, results[0].MachineKey.PrivateKey)
+
+		require.ErrorIs(t, results[1].Err, pushErr)
+		assert.Nil(t, results[1].MachineKey)
+
+		require.ErrorIs(t, results[2].Err, zerrors.ThrowAlreadyExists(nil, "COMMAND-Bk4nf", "Errors.User.AlreadyExisting"))
+		assert.Equal(t, "ci-1", results[2].Username)
+
+		assert.NoError(t, results[3].Err)
+		assert.Equal(t, "key3", results[3].MachineKey.KeyID)
+		assert.NotEmpty(t, results[3].MachineKey.PrivateKey)
+	})
+}
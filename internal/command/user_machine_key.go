@@ -204,6 +204,17 @@ func prepareRemoveUserMachineKey(machineKey *MachineKey) preparation.Validation
 	}
 }
 
+// MachineKeyUsed records the successful authentication of a machine user using the key.
+// The usage is only recorded if the last recorded usage is older than the configured interval,
+// lastUsed is nil if the key was never used.
+func (c *Commands) MachineKeyUsed(ctx context.Context, userID, resourceOwner, keyID string, lastUsed *time.Time) {
+	if lastUsed != nil && time.Since(*lastUsed) < c.machineKeyUsageInterval {
+		return
+	}
+	agg := user.NewAggregate(userID, resourceOwner)
+	c.asyncPush(ctx, user.NewMachineKeyUsedEvent(ctx, &agg.Aggregate, keyID))
+}
+
 func getMachineKeyWriteModelByID(ctx context.Context, filter preparation.FilterToQueryReducer, userID, keyID, resourceOwner string) (_ *MachineKeyWriteModel, err error) {
 	writeModel := NewMachineKeyWriteModel(userID, keyID, resourceOwner)
 	events, err := filter(ctx, writeModel.Query())
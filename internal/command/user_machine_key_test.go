@@ -274,3 +274,47 @@ func TestCommands_AddMachineKey(t *testing.T) {
 		})
 	}
 }
+
+func TestCommands_MachineKeyUsed(t *testing.T) {
+	agg := &user.NewAggregate("user1", "org1").Aggregate
+	recent := time.Now().Add(-time.Minute)
+	old := time.Now().Add(-2 * time.Hour)
+	tests := []struct {
+		name       string
+		eventstore func(t *testing.T) *eventstore.Eventstore
+		lastUsed   *time.Time
+	}{
+		{
+			name: "never used, recorded",
+			eventstore: expectEventstore(
+				expectPush(
+					user.NewMachineKeyUsedEvent(context.Background(), agg, "key1"),
+				),
+			),
+		},
+		{
+			name: "used before interval, recorded",
+			eventstore: expectEventstore(
+				expectPush(
+					user.NewMachineKeyUsedEvent(context.Background(), agg, "key1"),
+				),
+			),
+			lastUsed: &old,
+		},
+		{
+			name:       "used within interval, not recorded",
+			eventstore: expectEventstore(),
+			lastUsed:   &recent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore:              tt.eventstore(t),
+				machineKeyUsageInterval: time.Hour,
+			}
+			c.MachineKeyUsed(context.Background(), "user1", "org1", "key1", tt.lastUsed)
+			c.jobs.Wait()
+		})
+	}
+}
@@ -44,8 +44,12 @@ func (c *Commands) removeUserMemberships(ctx context.Context, memberships []*Cas
 			removeEvent := c.removeInstanceMember(ctx, &iamAgg.Aggregate, membership.UserID, true)
 			events = append(events, removeEvent)
 		} else if membership.Org != nil {
+			member := NewOrgMemberWriteModel(membership.Org.OrgID, membership.UserID)
+			if err = c.eventstore.FilterToQueryReducer(ctx, member); err != nil {
+				return nil, err
+			}
 			orgAgg := org.NewAggregate(membership.Org.OrgID)
-			removeEvent := c.removeOrgMember(ctx, &orgAgg.Aggregate, membership.UserID, true)
+			removeEvent := c.removeOrgMember(ctx, &orgAgg.Aggregate, membership.UserID, member.UniqueEmail, true)
 			events = append(events, removeEvent)
 		} else if membership.Project != nil {
 			projectAgg := project.NewAggregate(membership.Project.ProjectID, membership.ResourceOwner)
@@ -3,6 +3,7 @@ package command
 import (
 	"context"
 	"encoding/base64"
+	"slices"
 	"time"
 
 	"github.com/zitadel/zitadel/internal/command/preparation"
@@ -132,6 +133,35 @@ func prepareAddPersonalAccessToken(pat *PersonalAccessToken, algorithm crypto.En
 	}
 }
 
+// AddScopedAccessToken adds a personal access token which is restricted to the passed ZITADEL roles.
+// The caller must hold all permissions granted by the roles.
+// The token is only returned once in pat.Token.
+func (c *Commands) AddScopedAccessToken(ctx context.Context, pat *PersonalAccessToken, roles []string) (_ *domain.ObjectDetails, err error) {
+	if err := c.checkScopedAccessTokenRoles(ctx, pat.ResourceOwner, roles); err != nil {
+		return nil, err
+	}
+	pat.Scopes = append(pat.Scopes, domain.RoleScopes(roles)...)
+	return c.AddPersonalAccessToken(ctx, pat)
+}
+
+// checkScopedAccessTokenRoles ensures the roles are known and the caller holds all their permissions
+func (c *Commands) checkScopedAccessTokenRoles(ctx context.Context, resourceOwner string, roles []string) error {
+	if len(roles) == 0 || len(domain.CheckForInvalidRoles(roles, "", c.zitadelRoles)) > 0 {
+		return zerrors.ThrowInvalidArgument(nil, "COMMAND-Rk3ow", "Errors.User.PAT.RolesInvalid")
+	}
+	for _, mapping := range c.zitadelRoles {
+		if !slices.Contains(roles, mapping.Role) {
+			continue
+		}
+		for _, permission := range mapping.Permissions {
+			if err := c.checkPermission(ctx, permission, resourceOwner, ""); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (c *Commands) RemovePersonalAccessToken(ctx context.Context, pat *PersonalAccessToken) (*domain.ObjectDetails, error) {
 	validation := prepareRemovePersonalAccessToken(pat)
 	cmds, err := preparation.PrepareCommands(ctx, c.eventstore.Filter, validation)
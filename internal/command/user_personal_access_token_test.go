@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"github.com/zitadel/zitadel/internal/api/authz"
 	"github.com/zitadel/zitadel/internal/crypto"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
@@ -277,6 +279,143 @@ func TestCommands_AddPersonalAccessToken(t *testing.T) {
 	}
 }
 
+func TestCommands_AddScopedAccessToken(t *testing.T) {
+	zitadelRoles := []authz.RoleMapping{
+		{Role: "ORG_OWNER", Permissions: []string{"org.read", "org.write"}},
+		{Role: "ORG_MEMBER_VIEWER", Permissions: []string{"org.member.read"}},
+	}
+	newPat := func() *PersonalAccessToken {
+		return &PersonalAccessToken{
+			ObjectRoot: models.ObjectRoot{
+				AggregateID:   "user1",
+				ResourceOwner: "org1",
+			},
+			Scopes:          []string{"openid"},
+			AllowedUserType: domain.UserTypeMachine,
+		}
+	}
+	type fields struct {
+		eventstore      func(*testing.T) *eventstore.Eventstore
+		idGenerator     id.Generator
+		checkPermission domain.PermissionCheck
+	}
+	type args struct {
+		pat   *PersonalAccessToken
+		roles []string
+	}
+	type res struct {
+		want   *domain.ObjectDetails
+		scopes []string
+		token  string
+		err    error
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "no roles, error",
+			fields: fields{
+				eventstore: expectEventstore(),
+			},
+			args: args{
+				pat: newPat(),
+			},
+			res: res{
+				err: zerrors.ThrowInvalidArgument(nil, "COMMAND-Rk3ow", "Errors.User.PAT.RolesInvalid"),
+			},
+		},
+		{
+			name: "unknown role, error",
+			fields: fields{
+				eventstore: expectEventstore(),
+			},
+			args: args{
+				pat:   newPat(),
+				roles: []string{"ORG_MEMBER_VIEWER", "UNKNOWN"},
+			},
+			res: res{
+				err: zerrors.ThrowInvalidArgument(nil, "COMMAND-Rk3ow", "Errors.User.PAT.RolesInvalid"),
+			},
+		},
+		{
+			name: "role not held by caller, error",
+			fields: fields{
+				eventstore:      expectEventstore(),
+				checkPermission: newMockPermissionCheckNotAllowed(),
+			},
+			args: args{
+				pat:   newPat(),
+				roles: []string{"ORG_MEMBER_VIEWER"},
+			},
+			res: res{
+				err: zerrors.ThrowPermissionDenied(nil, "AUTHZ-HKJD33", "Errors.PermissionDenied"),
+			},
+		},
+		{
+			name: "token added",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							user.NewMachineAddedEvent(context.Background(),
+								&user.NewAggregate("user1", "org1").Aggregate,
+								"machine",
+								"Machine",
+								"",
+								true,
+								domain.OIDCTokenTypeBearer,
+							),
+						),
+					),
+					expectFilter(),
+					expectPush(
+						user.NewPersonalAccessTokenAddedEvent(context.Background(),
+							&user.NewAggregate("user1", "org1").Aggregate,
+							"token1",
+							time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC),
+							[]string{"openid", "urn:zitadel:iam:role:ORG_MEMBER_VIEWER"},
+						),
+					),
+				),
+				idGenerator:     id_mock.NewIDGeneratorExpectIDs(t, "token1"),
+				checkPermission: newMockPermissionCheckAllowed(),
+			},
+			args: args{
+				pat:   newPat(),
+				roles: []string{"ORG_MEMBER_VIEWER"},
+			},
+			res: res{
+				want: &domain.ObjectDetails{
+					ResourceOwner: "org1",
+				},
+				scopes: []string{"openid", "urn:zitadel:iam:role:ORG_MEMBER_VIEWER"},
+				token:  base64.RawURLEncoding.EncodeToString([]byte("token1:user1")),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore:      tt.fields.eventstore(t),
+				idGenerator:     tt.fields.idGenerator,
+				keyAlgorithm:    crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+				checkPermission: tt.fields.checkPermission,
+				zitadelRoles:    zitadelRoles,
+			}
+			got, err := c.AddScopedAccessToken(context.Background(), tt.args.pat, tt.args.roles)
+			require.ErrorIs(t, err, tt.res.err)
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.want, got)
+				assert.Equal(t, tt.res.scopes, tt.args.pat.Scopes)
+				assert.Equal(t, tt.res.token, tt.args.pat.Token)
+			}
+		})
+	}
+}
+
 func TestCommands_RemovePersonalAccessToken(t *testing.T) {
 	type fields struct {
 		eventstore *eventstore.Eventstore
@@ -1344,6 +1344,19 @@ func TestCommandSide_RemoveUser(t *testing.T) {
 							),
 						),
 					),
+					expectFilter(
+						eventFromEventPusher(
+							func() eventstore.Command {
+								event := org.NewMemberAddedEvent(context.Background(),
+									&org.NewAggregate("org1").Aggregate,
+									"user1",
+									"ORG_OWNER",
+								)
+								event.UniqueEmail = "email@test.ch"
+								return event
+							}(),
+						),
+					),
 					expectPush(
 						user.NewUserRemovedEvent(context.Background(),
 							&user.NewAggregate("user1", "org1").Aggregate,
@@ -1355,10 +1368,14 @@ func TestCommandSide_RemoveUser(t *testing.T) {
 							&instance.NewAggregate("INSTANCE").Aggregate,
 							"user1",
 						),
-						org.NewMemberCascadeRemovedEvent(context.Background(),
-							&org.NewAggregate("org1").Aggregate,
-							"user1",
-						),
+						func() eventstore.Command {
+							event := org.NewMemberCascadeRemovedEvent(context.Background(),
+								&org.NewAggregate("org1").Aggregate,
+								"user1",
+							)
+							event.UniqueEmail = "email@test.ch"
+							return event
+						}(),
 						project.NewProjectMemberCascadeRemovedEvent(context.Background(),
 							&project.NewAggregate("project1", "org1").Aggregate,
 							"user1",
@@ -184,6 +184,8 @@ type UserEmailEvents struct {
 	model      *HumanEmailWriteModel
 
 	plainCode *string
+	// orgMemberEmailChanges returns the events moving the emails the user reserved as org member
+	orgMemberEmailChanges func(ctx context.Context, userID string, email domain.EmailAddress) ([]eventstore.Command, error)
 }
 
 // NewUserEmailEvents constructs a UserEmailEvents with a Human Email Write Model,
@@ -205,9 +207,10 @@ func (c *Commands) NewUserEmailEvents(ctx context.Context, userID string) (*User
 		return nil, zerrors.ThrowPreconditionFailed(nil, "COMMAND-uz0Uu", "Errors.User.NotInitialised")
 	}
 	return &UserEmailEvents{
-		eventstore: c.eventstore,
-		aggregate:  UserAggregateFromWriteModel(&model.WriteModel),
-		model:      model,
+		eventstore:            c.eventstore,
+		aggregate:             UserAggregateFromWriteModel(&model.WriteModel),
+		model:                 model,
+		orgMemberEmailChanges: c.orgMemberEmailChangedEvents,
 	}, nil
 }
 
@@ -221,7 +224,12 @@ func (c *UserEmailEvents) Change(ctx context.Context, email domain.EmailAddress)
 	if !hasChanged {
 		return zerrors.ThrowPreconditionFailed(nil, "COMMAND-Uch5e", "Errors.User.Email.NotChanged")
 	}
+	memberEmailEvents, err := c.orgMemberEmailChanges(ctx, c.aggregate.ID, email)
+	if err != nil {
+		return err
+	}
 	c.events = append(c.events, event)
+	c.events = append(c.events, memberEmailEvents...)
 	return nil
 }
 
@@ -0,0 +1,98 @@
+package command
+
+import (
+	"context"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/repository/user"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// StartUserEmailSwap sets a pending email address for the user and generates a code to verify it.
+// The primary email address stays unchanged until the code is verified with [Commands.VerifyUserEmailSwap].
+// No notification e-mail is sent, the generated plain text code will be set in the returned Email object.
+func (c *Commands) StartUserEmailSwap(ctx context.Context, userID, email string, alg crypto.EncryptionAlgorithm) (*domain.Email, error) {
+	config, err := cryptoGeneratorConfig(ctx, c.eventstore.Filter, domain.SecretGeneratorTypeVerifyEmailCode) //nolint:staticcheck
+	if err != nil {
+		return nil, err
+	}
+	gen := crypto.NewEncryptionGenerator(*config, alg)
+	return c.startUserEmailSwapWithGenerator(ctx, userID, email, gen)
+}
+
+// VerifyUserEmailSwap verifies the code of the pending email address
+// and replaces the primary email address by the verified pending one in a single push.
+// If the verification fails, the swap is cancelled and the prior primary email address is kept.
+func (c *Commands) VerifyUserEmailSwap(ctx context.Context, userID, code string, alg crypto.EncryptionAlgorithm) (*domain.Email, error) {
+	config, err := cryptoGeneratorConfig(ctx, c.eventstore.Filter, domain.SecretGeneratorTypeVerifyEmailCode) //nolint:staticcheck
+	if err != nil {
+		return nil, err
+	}
+	gen := crypto.NewEncryptionGenerator(*config, alg)
+	return c.verifyUserEmailSwapWithGenerator(ctx, userID, code, gen)
+}
+
+func (c *Commands) startUserEmailSwapWithGenerator(ctx context.Context, userID, email string, gen crypto.Generator) (*domain.Email, error) {
+	cmd, err := c.NewUserEmailEvents(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if authz.GetCtxData(ctx).UserID != userID {
+		if err = c.checkPermission(ctx, domain.PermissionUserWrite, cmd.aggregate.ResourceOwner, userID); err != nil {
+			return nil, err
+		}
+	}
+	address := domain.EmailAddress(email)
+	if err = address.Validate(); err != nil {
+		return nil, err
+	}
+	if address == cmd.model.Email {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "COMMAND-Ra7sw", "Errors.User.Email.NotChanged")
+	}
+	value, plain, err := crypto.NewCode(gen)
+	if err != nil {
+		return nil, err
+	}
+	cmd.events = append(cmd.events, user.NewHumanEmailSwapRequestedEvent(ctx, cmd.aggregate, address, value, gen.Expiry()))
+	if _, err = cmd.Push(ctx); err != nil {
+		return nil, err
+	}
+	return &domain.Email{
+		ObjectRoot:   writeModelToObjectRoot(cmd.model.WriteModel),
+		EmailAddress: cmd.model.PendingEmail,
+		PlainCode:    &plain,
+	}, nil
+}
+
+func (c *Commands) verifyUserEmailSwapWithGenerator(ctx context.Context, userID, code string, gen crypto.Generator) (*domain.Email, error) {
+	cmd, err := c.NewUserEmailEvents(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if code == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Pw4xf", "Errors.User.Code.Empty")
+	}
+	if cmd.model.PendingCode == nil {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "COMMAND-Lk2ub", "Errors.User.Code.NotFound")
+	}
+	err = crypto.VerifyCode(cmd.model.PendingCodeCreationDate, cmd.model.PendingCodeExpiry, cmd.model.PendingCode, code, gen.Alg())
+	if err != nil {
+		_, pushErr := c.eventstore.Push(ctx, user.NewHumanEmailSwapCancelledEvent(ctx, cmd.aggregate))
+		logging.WithFields("id", "COMMAND-Zc8ew", "userID", userID).OnError(pushErr).Error("NewHumanEmailSwapCancelledEvent push failed")
+		return nil, zerrors.ThrowInvalidArgument(err, "COMMAND-Tq6ah", "Errors.User.Code.Invalid")
+	}
+	memberEmailEvents, err := c.orgMemberEmailChangedEvents(ctx, userID, cmd.model.PendingEmail)
+	if err != nil {
+		return nil, err
+	}
+	cmd.events = append(cmd.events,
+		user.NewHumanEmailChangedEvent(ctx, cmd.aggregate, cmd.model.PendingEmail),
+		user.NewHumanEmailVerifiedEvent(ctx, cmd.aggregate),
+	)
+	cmd.events = append(cmd.events, memberEmailEvents...)
+	return cmd.Push(ctx)
+}
@@ -0,0 +1,288 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/muhlemmer/gu"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/v1/models"
+	"github.com/zitadel/zitadel/internal/repository/user"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func userEmailSwapAddedEvent() eventstore.Event {
+	return eventFromEventPusher(
+		user.NewHumanAddedEvent(context.Background(),
+			&user.NewAggregate("user1", "org1").Aggregate,
+			"username",
+			"firstname",
+			"lastname",
+			"nickname",
+			"displayname",
+			language.German,
+			domain.GenderUnspecified,
+			"email@test.ch",
+			true,
+		),
+	)
+}
+
+func TestCommands_startUserEmailSwapWithGenerator(t *testing.T) {
+	type fields struct {
+		eventstore      func(*testing.T) *eventstore.Eventstore
+		checkPermission domain.PermissionCheck
+	}
+	type args struct {
+		userID string
+		email  string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *domain.Email
+		wantErr error
+	}{
+		{
+			name: "missing user",
+			fields: fields{
+				eventstore: expectEventstore(),
+			},
+			args: args{
+				userID: "",
+				email:  "email-changed@test.ch",
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-0Gzs3", "Errors.User.Email.IDMissing"),
+		},
+		{
+			name: "missing permission",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(userEmailSwapAddedEvent()),
+				),
+				checkPermission: newMockPermissionCheckNotAllowed(),
+			},
+			args: args{
+				userID: "user1",
+				email:  "email-changed@test.ch",
+			},
+			wantErr: zerrors.ThrowPermissionDenied(nil, "AUTHZ-HKJD33", "Errors.PermissionDenied"),
+		},
+		{
+			name: "invalid email",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(userEmailSwapAddedEvent()),
+				),
+				checkPermission: newMockPermissionCheckAllowed(),
+			},
+			args: args{
+				userID: "user1",
+				email:  "",
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "EMAIL-spblu", "Errors.User.Email.Empty"),
+		},
+		{
+			name: "email not changed",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(userEmailSwapAddedEvent()),
+				),
+				checkPermission: newMockPermissionCheckAllowed(),
+			},
+			args: args{
+				userID: "user1",
+				email:  "email@test.ch",
+			},
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "COMMAND-Ra7sw", "Errors.User.Email.NotChanged"),
+		},
+		{
+			name: "swap started",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(userEmailSwapAddedEvent()),
+					expectPush(
+						user.NewHumanEmailSwapRequestedEvent(context.Background(),
+							&user.NewAggregate("user1", "org1").Aggregate,
+							"email-changed@test.ch",
+							&crypto.CryptoValue{
+								CryptoType: crypto.TypeEncryption,
+								Algorithm:  "enc",
+								KeyID:      "id",
+								Crypted:    []byte("a"),
+							},
+							time.Hour*1,
+						),
+					),
+				),
+				checkPermission: newMockPermissionCheckAllowed(),
+			},
+			args: args{
+				userID: "user1",
+				email:  "email-changed@test.ch",
+			},
+			want: &domain.Email{
+				ObjectRoot: models.ObjectRoot{
+					AggregateID:   "user1",
+					ResourceOwner: "org1",
+				},
+				EmailAddress: "email-changed@test.ch",
+				PlainCode:    gu.Ptr("a"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore:      tt.fields.eventstore(t),
+				checkPermission: tt.fields.checkPermission,
+			}
+			got, err := c.startUserEmailSwapWithGenerator(context.Background(), tt.args.userID, tt.args.email, GetMockSecretGenerator(t))
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCommands_verifyUserEmailSwapWithGenerator(t *testing.T) {
+	swapRequested := func(email domain.EmailAddress) eventstore.Event {
+		return eventFromEventPusherWithCreationDateNow(
+			user.NewHumanEmailSwapRequestedEvent(context.Background(),
+				&user.NewAggregate("user1", "org1").Aggregate,
+				email,
+				&crypto.CryptoValue{
+					CryptoType: crypto.TypeEncryption,
+					Algorithm:  "enc",
+					KeyID:      "id",
+					Crypted:    []byte("a"),
+				},
+				time.Hour*1,
+			),
+		)
+	}
+	type args struct {
+		userID string
+		code   string
+	}
+	tests := []struct {
+		name       string
+		eventstore func(*testing.T) *eventstore.Eventstore
+		args       args
+		want       *domain.Email
+		wantErr    error
+	}{
+		{
+			name:       "missing user",
+			eventstore: expectEventstore(),
+			args: args{
+				userID: "",
+				code:   "a",
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-0Gzs3", "Errors.User.Email.IDMissing"),
+		},
+		{
+			name: "missing code",
+			eventstore: expectEventstore(
+				expectFilter(userEmailSwapAddedEvent(), swapRequested("email-changed@test.ch")),
+			),
+			args: args{
+				userID: "user1",
+				code:   "",
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-Pw4xf", "Errors.User.Code.Empty"),
+		},
+		{
+			name: "no pending email, error",
+			eventstore: expectEventstore(
+				expectFilter(userEmailSwapAddedEvent()),
+			),
+			args: args{
+				userID: "user1",
+				code:   "a",
+			},
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "COMMAND-Lk2ub", "Errors.User.Code.NotFound"),
+		},
+		{
+			name: "swap cancelled by primary email change, error",
+			eventstore: expectEventstore(
+				expectFilter(
+					userEmailSwapAddedEvent(),
+					swapRequested("email-changed@test.ch"),
+					eventFromEventPusher(
+						user.NewHumanEmailChangedEvent(context.Background(),
+							&user.NewAggregate("user1", "org1").Aggregate,
+							"other@test.ch",
+						),
+					),
+				),
+			),
+			args: args{
+				userID: "user1",
+				code:   "a",
+			},
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "COMMAND-Lk2ub", "Errors.User.Code.NotFound"),
+		},
+		{
+			name: "wrong code, rolled back",
+			eventstore: expectEventstore(
+				expectFilter(userEmailSwapAddedEvent(), swapRequested("email-changed@test.ch")),
+				expectPush(
+					user.NewHumanEmailSwapCancelledEvent(context.Background(),
+						&user.NewAggregate("user1", "org1").Aggregate,
+					),
+				),
+			),
+			args: args{
+				userID: "user1",
+				code:   "wrong",
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "COMMAND-Tq6ah", "Errors.User.Code.Invalid"),
+		},
+		{
+			name: "good code, swapped",
+			eventstore: expectEventstore(
+				expectFilter(userEmailSwapAddedEvent(), swapRequested("email-changed@test.ch")),
+				expectFilter(), // no org member emails reserved
+				expectPush(
+					user.NewHumanEmailChangedEvent(context.Background(),
+						&user.NewAggregate("user1", "org1").Aggregate,
+						"email-changed@test.ch",
+					),
+					user.NewHumanEmailVerifiedEvent(context.Background(),
+						&user.NewAggregate("user1", "org1").Aggregate,
+					),
+				),
+			),
+			args: args{
+				userID: "user1",
+				code:   "a",
+			},
+			want: &domain.Email{
+				ObjectRoot: models.ObjectRoot{
+					AggregateID:   "user1",
+					ResourceOwner: "org1",
+				},
+				EmailAddress:    "email-changed@test.ch",
+				IsEmailVerified: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.eventstore(t),
+			}
+			got, err := c.verifyUserEmailSwapWithGenerator(context.Background(), tt.args.userID, tt.args.code, GetMockSecretGenerator(t))
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
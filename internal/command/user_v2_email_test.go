@@ -869,6 +869,7 @@ func TestCommands_ChangeUserEmailVerified(t *testing.T) {
 							),
 						),
 					),
+					expectFilter(), // no org member emails reserved
 					expectPush(
 						user.NewHumanEmailChangedEvent(context.Background(),
 							&user.NewAggregate("user1", "org1").Aggregate,
@@ -1056,6 +1057,7 @@ func TestCommands_changeUserEmailWithGenerator(t *testing.T) {
 							),
 						),
 					),
+					expectFilter(), // no org member emails reserved
 					expectPush(
 						user.NewHumanEmailChangedEvent(context.Background(),
 							&user.NewAggregate("user1", "org1").Aggregate,
@@ -1112,6 +1114,7 @@ func TestCommands_changeUserEmailWithGenerator(t *testing.T) {
 							),
 						),
 					),
+					expectFilter(), // no org member emails reserved
 					expectPush(
 						user.NewHumanEmailChangedEvent(context.Background(),
 							&user.NewAggregate("user1", "org1").Aggregate,
@@ -1169,6 +1172,7 @@ func TestCommands_changeUserEmailWithGenerator(t *testing.T) {
 							),
 						),
 					),
+					expectFilter(), // no org member emails reserved
 					expectPush(
 						user.NewHumanEmailChangedEvent(context.Background(),
 							&user.NewAggregate("user1", "org1").Aggregate,
@@ -108,7 +108,7 @@ func (c *Commands) AddUserHuman(ctx context.Context, resourceOwner string, human
 		return zerrors.ThrowInvalidArgument(nil, "COMMA-095xh8fll1", "Errors.Internal")
 	}
 
-	if err := human.Validate(c.userPasswordHasher); err != nil {
+	if err := human.Validate(c.passwordHasher()); err != nil {
 		return err
 	}
 
@@ -185,7 +185,7 @@ func (c *Commands) AddUserHuman(ctx context.Context, resourceOwner string, human
 
 	// separated to change when old user logic is not used anymore
 	filter := c.eventstore.Filter //nolint:staticcheck
-	if err := addHumanCommandPassword(ctx, filter, createCmd, human, c.userPasswordHasher); err != nil {
+	if err := addHumanCommandPassword(ctx, filter, createCmd, human, c.passwordHasher()); err != nil {
 		return err
 	}
 
@@ -243,7 +243,7 @@ func (c *Commands) AddUserHuman(ctx context.Context, resourceOwner string, human
 }
 
 func (c *Commands) ChangeUserHuman(ctx context.Context, human *ChangeHuman, alg crypto.EncryptionAlgorithm) (err error) {
-	if err := human.Validate(c.userPasswordHasher); err != nil {
+	if err := human.Validate(c.passwordHasher()); err != nil {
 		return err
 	}
 
@@ -319,7 +319,12 @@ func (c *Commands) changeUserEmail(ctx context.Context, cmds []eventstore.Comman
 	defer func() { span.End() }()
 
 	if email.Address != "" && email.Address != wm.Email {
+		memberEmailCmds, err := c.orgMemberEmailChangedEvents(ctx, wm.AggregateID, email.Address)
+		if err != nil {
+			return cmds, code, err
+		}
 		cmds = append(cmds, user.NewHumanEmailChangedEvent(ctx, &wm.Aggregate().Aggregate, email.Address))
+		cmds = append(cmds, memberEmailCmds...)
 
 		if email.Verified {
 			return append(cmds, user.NewHumanEmailVerifiedEvent(ctx, &wm.Aggregate().Aggregate)), code, nil
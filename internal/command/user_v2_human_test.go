@@ -1815,6 +1815,7 @@ func TestCommandSide_ChangeUserHuman(t *testing.T) {
 							newAddHumanEvent("$plain$x$password", true, true, "", language.English),
 						),
 					),
+					expectFilter(), // no org member emails reserved
 					expectPush(
 						user.NewHumanEmailChangedEvent(context.Background(),
 							&userAgg.Aggregate,
@@ -1922,6 +1923,7 @@ func TestCommandSide_ChangeUserHuman(t *testing.T) {
 							newAddHumanEvent("$plain$x$password", true, true, "", language.English),
 						),
 					),
+					expectFilter(), // no org member emails reserved
 					expectPush(
 						user.NewHumanEmailChangedEvent(context.Background(),
 							&userAgg.Aggregate,
@@ -1995,6 +1997,7 @@ func TestCommandSide_ChangeUserHuman(t *testing.T) {
 							newAddHumanEvent("$plain$x$password", true, true, "", language.English),
 						),
 					),
+					expectFilter(), // no org member emails reserved
 					expectPush(
 						user.NewHumanEmailChangedEvent(context.Background(),
 							&userAgg.Aggregate,
@@ -0,0 +1,162 @@
+package command
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/zitadel/logging"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/zitadel/zitadel/internal/command/preparation"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/telemetry/metrics"
+)
+
+const (
+	WriteModelCacheHitCounter             = "zitadel.write_model_cache.hit"
+	WriteModelCacheHitCounterDescription  = "Write models read from the cache"
+	WriteModelCacheMissCounter            = "zitadel.write_model_cache.miss"
+	WriteModelCacheMissCounterDescription = "Write models reduced because they were not cached or outdated"
+)
+
+// WriteModelCacheKey identifies the aggregate a cached write model is reduced from
+type WriteModelCacheKey struct {
+	InstanceID    string
+	AggregateType eventstore.AggregateType
+	AggregateID   string
+}
+
+// WriteModelCacheMetrics is notified about the lookups of a [WriteModelCache]
+type WriteModelCacheMetrics interface {
+	CacheHit(ctx context.Context, key WriteModelCacheKey)
+	CacheMiss(ctx context.Context, key WriteModelCacheKey)
+}
+
+// cachedWriteModel is implemented by write models which are kept in a [WriteModelCache] by [queryAndReduce].
+// The write model is copied shallowly into and out of the cache,
+// so its reduce must replace slices and maps instead of changing them in place.
+// The cache must only be returned if the filter of [queryAndReduce] reads the eventstore,
+// the cache doesn't know the commands of a filter of [preparation.PrepareCommands] which are not pushed yet.
+type cachedWriteModel interface {
+	eventstore.QueryReducer
+	// WriteModelCache returns the cache of the write model type and the key of the write model
+	WriteModelCache() (*WriteModelCache, WriteModelCacheKey)
+}
+
+// WriteModelCache keeps reduced write models of frequently read aggregates.
+// A cached write model is only used as long as no newer events match its query.
+type WriteModelCache struct {
+	eventstore *eventstore.Eventstore
+	mu         sync.RWMutex
+	entries    map[WriteModelCacheKey]writeModelCacheEntry
+	maxEntries int
+	metrics    WriteModelCacheMetrics
+}
+
+type writeModelCacheEntry struct {
+	writeModel eventstore.QueryReducer
+	// position is the position of the latest event matching the query of the write model when it was reduced
+	position float64
+}
+
+// NewWriteModelCache creates a cache for at most maxEntries write models, metrics is optional.
+// The position of the latest event of a write model is read from es.
+func NewWriteModelCache(es *eventstore.Eventstore, maxEntries int, metrics WriteModelCacheMetrics) *WriteModelCache {
+	return &WriteModelCache{
+		eventstore: es,
+		entries:    make(map[WriteModelCacheKey]writeModelCacheEntry, maxEntries),
+		maxEntries: maxEntries,
+		metrics:    metrics,
+	}
+}
+
+// queryAndReduce sets the state of the cached write model if it's up to date and reduces the write model otherwise.
+func (cache *WriteModelCache) queryAndReduce(ctx context.Context, filter preparation.FilterToQueryReducer, key WriteModelCacheKey, wm eventstore.QueryReducer) error {
+	position, err := cache.eventstore.LatestPosition(ctx, wm.Query())
+	if err != nil {
+		return err
+	}
+	if cache.load(key, position, wm) {
+		cache.hit(ctx, key)
+		return nil
+	}
+	cache.miss(ctx, key)
+
+	if err = reduceEvents(ctx, filter, wm); err != nil {
+		return err
+	}
+	if position > 0 {
+		cache.store(key, position, wm)
+	}
+	return nil
+}
+
+// load copies the cached write model into wm if it was reduced up to position
+func (cache *WriteModelCache) load(key WriteModelCacheKey, position float64, wm eventstore.QueryReducer) bool {
+	cache.mu.RLock()
+	entry, ok := cache.entries[key]
+	cache.mu.RUnlock()
+	if !ok || position == 0 || entry.position < position || reflect.TypeOf(entry.writeModel) != reflect.TypeOf(wm) {
+		return false
+	}
+	reflect.ValueOf(wm).Elem().Set(reflect.ValueOf(entry.writeModel).Elem())
+	return true
+}
+
+func (cache *WriteModelCache) store(key WriteModelCacheKey, position float64, wm eventstore.QueryReducer) {
+	cached := reflect.New(reflect.TypeOf(wm).Elem())
+	cached.Elem().Set(reflect.ValueOf(wm).Elem())
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if _, ok := cache.entries[key]; !ok && len(cache.entries) >= cache.maxEntries {
+		// evict any entry, the map iteration order is random
+		for evict := range cache.entries {
+			delete(cache.entries, evict)
+			break
+		}
+	}
+	cache.entries[key] = writeModelCacheEntry{
+		writeModel: cached.Interface().(eventstore.QueryReducer),
+		position:   position,
+	}
+}
+
+func (cache *WriteModelCache) hit(ctx context.Context, key WriteModelCacheKey) {
+	if cache.metrics != nil {
+		cache.metrics.CacheHit(ctx, key)
+	}
+}
+
+func (cache *WriteModelCache) miss(ctx context.Context, key WriteModelCacheKey) {
+	if cache.metrics != nil {
+		cache.metrics.CacheMiss(ctx, key)
+	}
+}
+
+// writeModelCacheCounters counts the lookups of the cache per aggregate type in the telemetry metrics
+type writeModelCacheCounters struct{}
+
+func newWriteModelCacheCounters() writeModelCacheCounters {
+	err := metrics.RegisterCounter(WriteModelCacheHitCounter, WriteModelCacheHitCounterDescription)
+	logging.WithFields("metric", WriteModelCacheHitCounter).OnError(err).Warn("unable to register counter")
+	err = metrics.RegisterCounter(WriteModelCacheMissCounter, WriteModelCacheMissCounterDescription)
+	logging.WithFields("metric", WriteModelCacheMissCounter).OnError(err).Warn("unable to register counter")
+	return writeModelCacheCounters{}
+}
+
+func (writeModelCacheCounters) CacheHit(ctx context.Context, key WriteModelCacheKey) {
+	addWriteModelCacheCount(ctx, WriteModelCacheHitCounter, key)
+}
+
+func (writeModelCacheCounters) CacheMiss(ctx context.Context, key WriteModelCacheKey) {
+	addWriteModelCacheCount(ctx, WriteModelCacheMissCounter, key)
+}
+
+func addWriteModelCacheCount(ctx context.Context, counter string, key WriteModelCacheKey) {
+	err := metrics.AddCount(ctx, counter, 1, map[string]attribute.Value{
+		"aggregate_type": attribute.StringValue(string(key.AggregateType)),
+	})
+	logging.WithFields("metric", counter).OnError(err).Info("unable to count write model cache lookup")
+}
@@ -0,0 +1,135 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository/mock"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+)
+
+type cacheTestWriteModel struct {
+	eventstore.WriteModel
+	cache   *WriteModelCache
+	Reduced int
+}
+
+func (wm *cacheTestWriteModel) Reduce() error {
+	wm.Reduced += len(wm.Events)
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *cacheTestWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		AggregateTypes("test").
+		AggregateIDs("agg").
+		Builder()
+}
+
+func (wm *cacheTestWriteModel) WriteModelCache() (*WriteModelCache, WriteModelCacheKey) {
+	return wm.cache, WriteModelCacheKey{InstanceID: "instance", AggregateType: "test", AggregateID: "agg"}
+}
+
+type cacheTestMetrics struct {
+	hits, misses int
+}
+
+func (m *cacheTestMetrics) CacheHit(context.Context, WriteModelCacheKey)  { m.hits++ }
+func (m *cacheTestMetrics) CacheMiss(context.Context, WriteModelCacheKey) { m.misses++ }
+
+// expectMaxPosition expects the position of the latest event to be read by a [eventstore.ColumnsMaxPosition] query
+func expectMaxPosition(position float64) expect {
+	return func(m *mock.MockRepository) {
+		m.MockQuerier.EXPECT().
+			LatestSequence(gomock.Any(), gomock.Cond(func(query any) bool {
+				return query.(*eventstore.SearchQueryBuilder).GetColumns() == eventstore.ColumnsMaxPosition
+			})).
+			Return(position, nil)
+	}
+}
+
+func Test_queryAndReduce_writeModelCache(t *testing.T) {
+	event := func(position float64) eventstore.Event {
+		return &eventstore.BaseEvent{Pos: position, Agg: &eventstore.Aggregate{ID: "agg", Type: "test"}}
+	}
+	es := expectEventstore(
+		// not cached
+		expectMaxPosition(2),
+		expectFilter(event(1), event(2)),
+		// cached
+		expectMaxPosition(2),
+		expectMaxPosition(2),
+		// outdated
+		expectMaxPosition(3),
+		expectFilter(event(1), event(2), event(3)),
+	)(t)
+	metrics := new(cacheTestMetrics)
+	cache := NewWriteModelCache(es, 10, metrics)
+
+	first := &cacheTestWriteModel{cache: cache}
+	require.NoError(t, queryAndReduce(context.Background(), es.Filter, first)) //nolint:staticcheck
+	assert.Equal(t, 2, first.Reduced)
+
+	cached := &cacheTestWriteModel{cache: cache}
+	require.NoError(t, queryAndReduce(context.Background(), es.Filter, cached)) //nolint:staticcheck
+	assert.Equal(t, 2, cached.Reduced)
+
+	// the write model returned by the cache is a copy
+	cached.Reduced = 10
+	copied := &cacheTestWriteModel{cache: cache}
+	require.NoError(t, queryAndReduce(context.Background(), es.Filter, copied)) //nolint:staticcheck
+	assert.Equal(t, 2, copied.Reduced)
+
+	outdated := &cacheTestWriteModel{cache: cache}
+	require.NoError(t, queryAndReduce(context.Background(), es.Filter, outdated)) //nolint:staticcheck
+	assert.Equal(t, 3, outdated.Reduced)
+
+	assert.Equal(t, &cacheTestMetrics{hits: 2, misses: 2}, metrics)
+}
+
+func TestWriteModelCache_maxEntries(t *testing.T) {
+	cache := NewWriteModelCache(nil, 1, nil)
+	cache.store(WriteModelCacheKey{AggregateID: "1"}, 1, new(cacheTestWriteModel))
+	cache.store(WriteModelCacheKey{AggregateID: "2"}, 1, new(cacheTestWriteModel))
+	assert.Len(t, cache.entries, 1)
+	assert.True(t, cache.load(WriteModelCacheKey{AggregateID: "2"}, 1, new(cacheTestWriteModel)))
+}
+
+func TestCommands_instanceDomainPolicyWriteModel_cached(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "INSTANCE")
+	es := expectEventstore(
+		expectMaxPosition(1),
+		expectFilter(
+			eventFromEventPusher(
+				instance.NewDomainPolicyAddedEvent(ctx,
+					&instance.NewAggregate("INSTANCE").Aggregate,
+					true,
+					false,
+					false,
+				),
+			),
+		),
+		expectMaxPosition(1),
+	)(t)
+	metrics := new(cacheTestMetrics)
+	c := &Commands{
+		eventstore:      es,
+		writeModelCache: NewWriteModelCache(es, 10, metrics),
+	}
+
+	policy, err := c.instanceDomainPolicyWriteModel(ctx)
+	require.NoError(t, err)
+	assert.True(t, policy.UserLoginMustBeDomain)
+
+	cached, err := c.instanceDomainPolicyWriteModel(ctx)
+	require.NoError(t, err)
+	assert.True(t, cached.UserLoginMustBeDomain)
+	assert.Equal(t, &cacheTestMetrics{hits: 1, misses: 1}, metrics)
+}
@@ -14,6 +14,8 @@ type SystemDefaults struct {
 	DomainVerification DomainVerification
 	Notifications      Notifications
 	KeyConfig          KeyConfig
+	Impersonation      Impersonation
+	MachineKeyUsage    MachineKeyUsage
 }
 
 type SecretGenerators struct {
@@ -26,7 +28,8 @@ type MultifactorConfig struct {
 }
 
 type OTPConfig struct {
-	Issuer string
+	Issuer    string
+	SetupSkew uint
 }
 
 type DomainVerification struct {
@@ -37,10 +40,31 @@ type Notifications struct {
 	FileSystemPath string
 }
 
+type Impersonation struct {
+	MaxSessionLifetime time.Duration
+}
+
+type MachineKeyUsage struct {
+	// RecordInterval is the minimal time between two recorded usages of the same machine key
+	RecordInterval time.Duration
+}
+
 type KeyConfig struct {
 	Size                int
 	PrivateKeyLifetime  time.Duration
 	PublicKeyLifetime   time.Duration
 	CertificateSize     int
 	CertificateLifetime time.Duration
+	// SAMLCertificate customizes the certificates of SAML identity providers, if nil the defaults are used
+	SAMLCertificate *SAMLCertificateConfig
+}
+
+// SAMLCertificateConfig defines the subject, the subject alternative names and the validity of SAML certificates
+type SAMLCertificateConfig struct {
+	// Organization of the subject, defaults to ZITADEL
+	Organization string
+	CommonName   string
+	DNSNames     []string
+	// Lifetime defaults to the [KeyConfig.CertificateLifetime]
+	Lifetime time.Duration
 }
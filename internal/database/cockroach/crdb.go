@@ -108,6 +108,11 @@ func (c *Config) Timetravel(d time.Duration) string {
 	return ""
 }
 
+// TimetravelTo returns the clause to query the state as of the given time
+func (c *Config) TimetravelTo(t time.Time) string {
+	return " AS OF SYSTEM TIME '" + t.UTC().Format("2006-01-02 15:04:05.999999") + "' "
+}
+
 type User struct {
 	Username string
 	Password string
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/pquerna/otp"
 	"github.com/pquerna/otp/totp"
 
@@ -23,15 +25,29 @@ func NewTOTPKey(issuer, accountName string) (*otp.Key, error) {
 	return key, nil
 }
 
+// DefaultTOTPSkew is the amount of periods before and after the current one in which a TOTP code is valid
+const DefaultTOTPSkew = 1
+
 func VerifyTOTP(code string, secret *crypto.CryptoValue, cryptoAlg crypto.EncryptionAlgorithm) error {
+	return VerifyTOTPWithSkew(code, secret, cryptoAlg, DefaultTOTPSkew)
+}
+
+// VerifyTOTPWithSkew verifies the code like [VerifyTOTP],
+// but accepts the codes of skew periods before and after the current one.
+func VerifyTOTPWithSkew(code string, secret *crypto.CryptoValue, cryptoAlg crypto.EncryptionAlgorithm, skew uint) error {
 	decrypt, err := crypto.DecryptString(secret, cryptoAlg)
 	if err != nil {
 		return err
 	}
 
-	valid := totp.Validate(code, decrypt)
-	if !valid {
-		return zerrors.ThrowInvalidArgument(nil, "EVENT-8isk2", "Errors.User.MFA.OTP.InvalidCode")
+	valid, err := totp.ValidateCustom(code, decrypt, time.Now().UTC(), totp.ValidateOpts{
+		Period:    30,
+		Skew:      skew,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	})
+	if err != nil || !valid {
+		return zerrors.ThrowInvalidArgument(err, "EVENT-8isk2", "Errors.User.MFA.OTP.InvalidCode")
 	}
 	return nil
 }
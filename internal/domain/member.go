@@ -35,6 +35,7 @@ const (
 	MemberStateUnspecified MemberState = iota
 	MemberStateActive
 	MemberStateRemoved
+	MemberStateInactive
 
 	memberStateCount
 )
@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/zitadel/zitadel/internal/crypto"
+import (
+	"time"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+)
 
 type MFAState int32
 
@@ -20,4 +24,37 @@ type MultifactorConfigs struct {
 type OTPConfig struct {
 	Issuer    string
 	CryptoMFA crypto.EncryptionAlgorithm
+	// SetupSkew is the amount of periods before and after the current one in which a code is accepted
+	// while verifying the setup of a TOTP, 0 uses the [DefaultTOTPSkew]
+	SetupSkew uint
+}
+
+// TOTPSetupSkew returns the skew to verify the setup of a TOTP
+func (c OTPConfig) TOTPSetupSkew() uint {
+	if c.SetupSkew == 0 {
+		return DefaultTOTPSkew
+	}
+	return c.SetupSkew
+}
+
+// MFAGracePeriodState describes if a user still has to enroll a multi factor
+type MFAGracePeriodState int32
+
+const (
+	MFAGracePeriodStateUnspecified MFAGracePeriodState = iota
+	// MFAGracePeriodStateDisabled is returned if the organization doesn't require an enrollment
+	MFAGracePeriodStateDisabled
+	// MFAGracePeriodStateEnrolled is returned if the user has enrolled a multi factor
+	MFAGracePeriodStateEnrolled
+	// MFAGracePeriodStateActive is returned if the user has to enroll a multi factor until the expiration date
+	MFAGracePeriodStateActive
+	// MFAGracePeriodStateExpired is returned if the user didn't enroll a multi factor in time and has to be forced to
+	MFAGracePeriodStateExpired
+)
+
+// MFAGracePeriodStatus is the result of the grace period check,
+// the expiration date is only set for an active or expired grace period.
+type MFAGracePeriodStatus struct {
+	State          MFAGracePeriodState
+	ExpirationDate time.Time
 }
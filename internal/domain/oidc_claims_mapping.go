@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// OIDCClaimMapping maps user attributes into a custom claim of the tokens of an OIDC application.
+// The template is a text with attribute placeholders, e.g. "{{ email }}" or "{{ given_name }} {{ family_name }}".
+type OIDCClaimMapping struct {
+	Claim    string `json:"claim"`
+	Template string `json:"template"`
+}
+
+// OIDCClaimAttributes are the attributes of human users which can be referenced by claim templates.
+// Attributes of a user schema are referenced by their property path, e.g. "{{ address.city }}".
+var OIDCClaimAttributes = []string{
+	"username",
+	"preferred_login_name",
+	"email",
+	"email_verified",
+	"given_name",
+	"family_name",
+	"nick_name",
+	"display_name",
+	"preferred_language",
+	"gender",
+	"phone",
+	"phone_verified",
+}
+
+// reservedOIDCClaims are set by ZITADEL or the OIDC and JWT specifications and can't be mapped
+var reservedOIDCClaims = []string{
+	"iss", "sub", "aud", "exp", "nbf", "iat", "jti",
+	"azp", "nonce", "auth_time", "amr", "acr", "at_hash", "c_hash", "sid",
+	"client_id", "scope", "act", "may_act", "cnf",
+}
+
+const (
+	reservedOIDCClaimPrefix = "urn:zitadel:iam:"
+	maxOIDCClaimLength      = 200
+)
+
+var (
+	oidcClaimNameRegex          = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_.\-]*$`)
+	oidcClaimPlaceholderRegex   = regexp.MustCompile(`{{\s*([^{}]*?)\s*}}`)
+	oidcClaimAttributePathRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)*$`)
+)
+
+// ValidOIDCClaimName checks if the claim name is a RFC 7519 compliant name (a simple name or an URI)
+// which is not reserved.
+func ValidOIDCClaimName(claim string) bool {
+	if claim == "" || len(claim) > maxOIDCClaimLength {
+		return false
+	}
+	if slices.Contains(reservedOIDCClaims, claim) || strings.HasPrefix(claim, reservedOIDCClaimPrefix) {
+		return false
+	}
+	if oidcClaimNameRegex.MatchString(claim) {
+		return true
+	}
+	// names containing a colon must be URIs to be collision resistant
+	uri, err := url.Parse(claim)
+	return err == nil && uri.Scheme != "" && uri.Opaque+uri.Host+uri.Path != ""
+}
+
+// OIDCClaimTemplateAttributes returns the attributes referenced by the placeholders of the template.
+// It returns false if the template is empty, contains unbalanced braces or malformed placeholders.
+func OIDCClaimTemplateAttributes(template string) ([]string, bool) {
+	if strings.TrimSpace(template) == "" {
+		return nil, false
+	}
+	matches := oidcClaimPlaceholderRegex.FindAllStringSubmatch(template, -1)
+	// all braces must be part of a placeholder
+	rest := oidcClaimPlaceholderRegex.ReplaceAllString(template, "")
+	if strings.ContainsAny(rest, "{}") {
+		return nil, false
+	}
+	attributes := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if !oidcClaimAttributePathRegex.MatchString(match[1]) {
+			return nil, false
+		}
+		if !slices.Contains(attributes, match[1]) {
+			attributes = append(attributes, match[1])
+		}
+	}
+	return attributes, true
+}
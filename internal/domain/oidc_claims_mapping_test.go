@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidOIDCClaimName(t *testing.T) {
+	tests := []struct {
+		claim string
+		want  bool
+	}{
+		{claim: "", want: false},
+		{claim: "department", want: true},
+		{claim: "org.department-name", want: true},
+		{claim: "1department", want: false},
+		{claim: "depart ment", want: false},
+		{claim: "sub", want: false},
+		{claim: "urn:zitadel:iam:org:id", want: false},
+		{claim: "https://example.com/claims/department", want: true},
+		{claim: "urn:example:department", want: true},
+		{claim: "example:", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.claim, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidOIDCClaimName(tt.claim))
+		})
+	}
+}
+
+func TestOIDCClaimTemplateAttributes(t *testing.T) {
+	tests := []struct {
+		name           string
+		template       string
+		wantAttributes []string
+		wantOK         bool
+	}{
+		{
+			name:     "empty",
+			template: " ",
+		},
+		{
+			name:           "static text",
+			template:       "employee",
+			wantAttributes: []string{},
+			wantOK:         true,
+		},
+		{
+			name:           "placeholders",
+			template:       "{{ given_name }} {{family_name}} ({{ given_name }})",
+			wantAttributes: []string{"given_name", "family_name"},
+			wantOK:         true,
+		},
+		{
+			name:           "nested attribute",
+			template:       "{{ address.city }}",
+			wantAttributes: []string{"address.city"},
+			wantOK:         true,
+		},
+		{
+			name:     "unbalanced braces",
+			template: "{{ email }",
+		},
+		{
+			name:     "empty placeholder",
+			template: "{{ }}",
+		},
+		{
+			name:     "invalid attribute path",
+			template: "{{ address..city }}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attributes, ok := OIDCClaimTemplateAttributes(tt.template)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantAttributes, attributes)
+		})
+	}
+}
@@ -2,10 +2,25 @@ package domain
 
 import (
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/zitadel/zitadel/internal/eventstore/v1/models"
 )
 
+// OrgNameMaxLength is the maximum count of characters of an organization name
+const OrgNameMaxLength = 200
+
+// IsValidOrgName checks if the trimmed name is not empty,
+// doesn't exceed [OrgNameMaxLength] and doesn't contain control characters.
+func IsValidOrgName(name string) bool {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > OrgNameMaxLength {
+		return false
+	}
+	return strings.IndexFunc(name, unicode.IsControl) < 0
+}
+
 type Org struct {
 	models.ObjectRoot
 
@@ -21,7 +36,7 @@ func (o *Org) IsValid() bool {
 		return false
 	}
 	o.Name = strings.TrimSpace(o.Name)
-	return o.Name != ""
+	return IsValidOrgName(o.Name)
 }
 
 func (o *Org) AddIAMDomain(iamDomain string) {
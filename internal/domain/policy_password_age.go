@@ -9,4 +9,6 @@ type PasswordAgePolicy struct {
 
 	MaxAgeDays     uint64
 	ExpireWarnDays uint64
+	// HistoryCount is the number of previous passwords which must not be reused, 0 disables the check
+	HistoryCount uint64
 }
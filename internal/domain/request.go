@@ -10,6 +10,7 @@ const (
 	ProjectIDScopeZITADEL = "zitadel"
 	AudSuffix             = ":aud"
 	SelectIDPScope        = "urn:zitadel:iam:org:idp:id:"
+	ZitadelRoleScope      = "urn:zitadel:iam:role:"
 )
 
 // TODO: Change AuthRequest to interface and let oidcauthreqesut implement it
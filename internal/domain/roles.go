@@ -37,3 +37,24 @@ func containsRole(role, rolePrefix string, validRoles []authz.RoleMapping) bool
 	}
 	return false
 }
+
+// RoleScopes returns the scopes which restrict a token to the passed ZITADEL roles
+func RoleScopes(roles []string) []string {
+	scopes := make([]string, len(roles))
+	for i, role := range roles {
+		scopes[i] = ZitadelRoleScope + role
+	}
+	return scopes
+}
+
+// RolesFromScopes returns the ZITADEL roles a token is restricted to by its scopes.
+// It returns nil if the token is not restricted.
+func RolesFromScopes(scopes []string) []string {
+	var roles []string
+	for _, scope := range scopes {
+		if role, ok := strings.CutPrefix(scope, ZitadelRoleScope); ok {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
@@ -0,0 +1,19 @@
+package domain
+
+type ScheduledActionState int32
+
+const (
+	ScheduledActionStateUnspecified ScheduledActionState = iota
+	ScheduledActionStatePending
+	ScheduledActionStateExecuted
+	ScheduledActionStateCancelled
+)
+
+func (s ScheduledActionState) Exists() bool {
+	return s != ScheduledActionStateUnspecified
+}
+
+// IsCompleted returns true if the scheduled action was executed or cancelled
+func (s ScheduledActionState) IsCompleted() bool {
+	return s == ScheduledActionStateExecuted || s == ScheduledActionStateCancelled
+}
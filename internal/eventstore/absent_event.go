@@ -0,0 +1,54 @@
+package eventstore
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// AbsentEventQuery searches the aggregates of an instance which have a start event
+// which isn't followed by a completion event, e.g. users who started but never completed the MFA setup.
+type AbsentEventQuery struct {
+	// InstanceID is taken from the context if empty
+	InstanceID          string
+	AggregateType       AggregateType
+	StartEventType      EventType
+	CompletionEventType EventType
+	// AfterAggregateID is the cursor of the page,
+	// only aggregate ids greater than the cursor are returned.
+	// Pass the last aggregate id of the previous page to get the next one.
+	AfterAggregateID string
+	// Limit is the maximum amount of aggregate ids of the page, 0 returns all
+	Limit uint64
+}
+
+func (q *AbsentEventQuery) validate() error {
+	if q.InstanceID == "" || q.AggregateType == "" || q.StartEventType == "" || q.CompletionEventType == "" {
+		return zerrors.ThrowInvalidArgument(nil, "EVENT-Ab4rn", "instance, aggregate type, start and completion event type are required")
+	}
+	return nil
+}
+
+// AggregateIDsWithoutSubsequentEvent returns the ids of the aggregates which have an event of the start event type
+// without an event of the completion event type after it, ordered by the aggregate id.
+// An aggregate which started again after a completion is returned as well.
+// Events of the archive are not included.
+func (es *Eventstore) AggregateIDsWithoutSubsequentEvent(ctx context.Context, query *AbsentEventQuery) ([]string, error) {
+	if query.InstanceID == "" {
+		query.InstanceID = authz.GetInstance(ctx).InstanceID()
+	}
+	if err := query.validate(); err != nil {
+		return nil, err
+	}
+	querier, ok := es.querier.(absentEventQuerier)
+	if !ok {
+		return nil, zerrors.ThrowUnimplemented(nil, "EVENT-Ab7mc", "querier doesn't support absent event queries")
+	}
+	return querier.AggregateIDsWithoutSubsequentEvent(ctx, query)
+}
+
+// absentEventQuerier is implemented by queriers which are able to search for absent events
+type absentEventQuerier interface {
+	AggregateIDsWithoutSubsequentEvent(ctx context.Context, query *AbsentEventQuery) ([]string, error)
+}
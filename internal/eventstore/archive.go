@@ -0,0 +1,96 @@
+package eventstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// filterStoresToReducer calls r for every event of the hot store
+// and continues into the archive if the search query includes it and reaches beyond the retention window.
+func (es *Eventstore) filterStoresToReducer(ctx context.Context, searchQuery *SearchQueryBuilder, r Reducer) error {
+	if !es.queriesArchive(searchQuery) {
+		return es.querier.FilterToReducer(ctx, searchQuery, r)
+	}
+	if searchQuery.compiled != nil || searchQuery.offset > 0 {
+		return zerrors.ThrowInvalidArgument(nil, "EVENT-Xa4nf", "archive cannot be queried with compiled queries or offsets")
+	}
+	// the archive contains the older events, it is read first in ascending order and last in descending order
+	stores := []Querier{es.archive, es.querier}
+	if searchQuery.desc {
+		stores = []Querier{es.querier, es.archive}
+	}
+	var reduced uint64
+	for _, store := range stores {
+		query := *searchQuery
+		if store == es.archive {
+			query = searchQuery.archiveQuery()
+		}
+		if query.limit > 0 {
+			query.limit -= reduced
+		}
+		err := store.FilterToReducer(ctx, &query, func(event Event) error {
+			reduced++
+			return r(event)
+		})
+		if err != nil {
+			return err
+		}
+		if searchQuery.limit > 0 && reduced >= searchQuery.limit {
+			return nil
+		}
+	}
+	return nil
+}
+
+// count returns the amount of events of the hot store and, if the search query includes it, of the archive
+func (es *Eventstore) count(ctx context.Context, searchQuery *SearchQueryBuilder) (uint64, error) {
+	if err := searchQuery.Validate(); err != nil {
+		return 0, err
+	}
+	if searchQuery.queryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, searchQuery.queryTimeout)
+		defer cancel()
+	}
+	count, err := es.querier.Count(ctx, searchQuery)
+	if err != nil || !es.queriesArchive(searchQuery) {
+		return count, err
+	}
+	query := searchQuery.archiveQuery()
+	archived, err := es.archive.Count(ctx, &query)
+	if err != nil {
+		return 0, err
+	}
+	return count + archived, nil
+}
+
+// queriesArchive returns true if the archive is configured and queried by the search query.
+// Queries starting inside the retention window of the hot store skip the archive.
+func (es *Eventstore) queriesArchive(searchQuery *SearchQueryBuilder) bool {
+	if es.archive == nil || !searchQuery.includeArchive {
+		return false
+	}
+	if es.archiveRetention <= 0 {
+		return true
+	}
+	retentionStart := time.Now().Add(-es.archiveRetention)
+	if !searchQuery.creationDateAfter.IsZero() && searchQuery.creationDateAfter.After(retentionStart) {
+		return false
+	}
+	// the position is the unix timestamp of the transaction which pushed the event
+	position := max(searchQuery.positionAfter, searchQuery.positionAtOrAfter)
+	return position == 0 || position < float64(retentionStart.Unix())
+}
+
+// archiveQuery returns a copy of the builder without the options which only apply to the hot store
+func (builder *SearchQueryBuilder) archiveQuery() SearchQueryBuilder {
+	query := *builder
+	query.tx = nil
+	query.forUpdate = false
+	query.allowTimeTravel = false
+	query.timeTravelTime = time.Time{}
+	query.awaitOpenTransactions = false
+	return query
+}
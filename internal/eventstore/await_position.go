@@ -0,0 +1,39 @@
+package eventstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// awaitPositionInterval is the time between the checks of [Eventstore.awaitPosition]
+var awaitPositionInterval = 50 * time.Millisecond
+
+// awaitPosition polls the store until an event at or after the [SearchQueryBuilder.AwaitPosition] is visible
+func (es *Eventstore) awaitPosition(ctx context.Context, searchQuery *SearchQueryBuilder) error {
+	if searchQuery.awaitPosition <= 0 {
+		return nil
+	}
+	query := NewSearchQueryBuilder(ColumnsMaxPosition).
+		PositionAtOrAfter(searchQuery.awaitPosition)
+	query.instanceID = searchQuery.instanceID
+	query.instanceIDs = searchQuery.instanceIDs
+
+	ticker := time.NewTicker(awaitPositionInterval)
+	defer ticker.Stop()
+	for {
+		position, err := es.querier.LatestSequence(ctx, query)
+		if err != nil {
+			return err
+		}
+		if position >= searchQuery.awaitPosition {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return zerrors.ThrowDeadlineExceeded(ctx.Err(), "EVENT-Aw5pq", "awaited position not reached")
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package eventstore
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// queryParamPrefix marks a string value of a [SearchQueryBuilder] as parameter of a [CompiledQuery].
+// The null byte ensures that the placeholder never collides with a stored value.
+const queryParamPrefix = "\x00param:"
+
+// InstanceIDParam is the name of the parameter used for the instance id of a [CompiledQuery].
+// If the parameter is not bound, the instance id of the context is used.
+const InstanceIDParam = "instanceID"
+
+// QueryParam returns a placeholder for a string value of a [SearchQueryBuilder]
+// (e.g. aggregate id, resource owner or instance id), also as element of a list like [SearchQuery.AggregateIDs].
+// The value is bound on execution using [CompiledQuery.Bind].
+func QueryParam(name string) string {
+	return queryParamPrefix + name
+}
+
+// QueryParamName returns the name of the parameter if value is a placeholder created by [QueryParam].
+func QueryParamName(value any) (name string, ok bool) {
+	s, ok := value.(string)
+	if !ok {
+		return "", false
+	}
+	return strings.CutPrefix(s, queryParamPrefix)
+}
+
+// CompiledQuery is a [SearchQueryBuilder] with parameter placeholders.
+// The storage generates the statement on the first execution and reuses it for all further executions,
+// only the bound values change.
+// A CompiledQuery is safe for concurrent use.
+type CompiledQuery struct {
+	builder  *SearchQueryBuilder
+	template atomic.Pointer[QueryTemplate]
+}
+
+// QueryTemplate is the statement of a [CompiledQuery] generated by the storage.
+type QueryTemplate struct {
+	// Select is the part of the statement before the conditions
+	Select string
+	// Conditions contains the conditions, ordering and pagination of the statement
+	Conditions string
+	// Args are the arguments of the statement including the placeholders
+	Args []any
+	// Params maps the index of an argument to the name of its parameter
+	Params map[int]string
+	// ElementParams maps the index of a slice argument (e.g. the aggregate ids of an IN condition)
+	// to the index and parameter name of its placeholder elements
+	ElementParams map[int]map[int]string
+	// UseV1 is set if the statement was generated for the v1 events table
+	UseV1 bool
+}
+
+// Compile returns a [CompiledQuery] of the builder.
+// Values set using [QueryParam] are replaced by the values passed to [CompiledQuery.Bind].
+// If no instance is set, the instance id is bound from the [InstanceIDParam] or the context.
+// The builder must not be changed after compilation.
+func (builder *SearchQueryBuilder) Compile() *CompiledQuery {
+	if builder.instanceID == nil && len(builder.instanceIDs) == 0 {
+		builder.InstanceID(QueryParam(InstanceIDParam))
+	}
+	return &CompiledQuery{
+		builder: builder,
+	}
+}
+
+// Bind returns a builder which executes the compiled query with the given parameters.
+func (q *CompiledQuery) Bind(params map[string]any) *SearchQueryBuilder {
+	builder := *q.builder
+	builder.compiled = q
+	builder.params = params
+	return &builder
+}
+
+// Template returns the statement generated by the storage, nil if the query wasn't executed yet.
+func (q *CompiledQuery) Template() *QueryTemplate {
+	return q.template.Load()
+}
+
+// SetTemplate is called by the storage to store the generated statement.
+func (q *CompiledQuery) SetTemplate(template *QueryTemplate) {
+	q.template.Store(template)
+}
@@ -11,4 +11,22 @@ type Config struct {
 	Pusher   Pusher
 	Querier  Querier
 	Searcher Searcher
+
+	// Archive is queried for events which were moved out of the hot store, see [SearchQueryBuilder.IncludeArchive]
+	Archive Querier
+	// ArchiveRetention is the duration events are kept in the hot store, 0 queries the archive for every query including it
+	ArchiveRetention time.Duration
+
+	// InstanceResultCap is the maximum amount of events a query of a single instance returns to API callers regardless of its limit,
+	// larger results are truncated, see [Eventstore.FilterToReducerCapped]. 0 disables the cap
+	InstanceResultCap uint64
+
+	// RejectSealedInstances rejects the pushes to instances which are sealed by an [InstanceSealedType] event.
+	// The seal of the instances is queried within each push transaction, so it's disabled by default.
+	// Instances can only be sealed if it's enabled
+	RejectSealedInstances bool
+
+	// EventDataSchemas validate the data of the events of the aggregate types before they are mapped and reduced,
+	// the data of aggregate types without a schema isn't validated
+	EventDataSchemas map[AggregateType]EventDataSchema
 }
@@ -1,8 +1,10 @@
 package eventstore
 
 import (
+	"cmp"
 	"encoding/json"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/zitadel/zitadel/internal/zerrors"
@@ -57,6 +59,33 @@ type Event interface {
 
 type EventType string
 
+type relevancer interface {
+	Relevance() float64
+}
+
+// EventRelevance returns the relevance of an event queried using [SearchQueryBuilder.OrderByRelevance].
+// It returns 0 for events queried without relevance.
+func EventRelevance(event Event) float64 {
+	if r, ok := event.(relevancer); ok {
+		return r.Relevance()
+	}
+	return 0
+}
+
+type origin interface {
+	ProducedByVersion() string
+	PushedInMaintenanceWindow() string
+}
+
+// EventOrigin returns the version of ZITADEL which pushed the event and the label of its maintenance window.
+// Both are only set for events queried using [SearchQueryBuilder.IncludeOrigin] and empty if they weren't stored.
+func EventOrigin(event Event) (producerVersion, maintenanceWindow string) {
+	if o, ok := event.(origin); ok {
+		return o.ProducedByVersion(), o.PushedInMaintenanceWindow()
+	}
+	return "", ""
+}
+
 func EventData(event Command) ([]byte, error) {
 	switch data := event.Payload().(type) {
 	case nil:
@@ -107,3 +136,189 @@ func isEventTypes(command Command, types ...EventType) bool {
 	}
 	return false
 }
+
+// isEventDataText checks if the payload of the command contains all words of the text ignoring the case
+func isEventDataText(command Command, text string) bool {
+	data, err := EventData(command)
+	if err != nil {
+		return false
+	}
+	payload := strings.ToLower(string(data))
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		if !strings.Contains(payload, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// isEventDataMissingKeys checks if all keys are missing or null in the payload of the command
+// EventDataPathSeparator separates the segments of the keys of [SearchQuery.EventData]
+const EventDataPathSeparator = "."
+
+// isEventData checks if the payload of the command has the values at the paths of the data
+func isEventData(command Command, data map[string]interface{}) bool {
+	payload, err := EventData(command)
+	if err != nil || len(payload) == 0 {
+		return false
+	}
+	var decoded map[string]any
+	if err = json.Unmarshal(payload, &decoded); err != nil {
+		return false
+	}
+	for key, want := range data {
+		value, ok := eventDataValue(decoded, strings.Split(key, EventDataPathSeparator))
+		if !ok || !isJSONEqual(value, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// eventDataValue walks the payload along the path, it returns false if a segment of the path is missing
+func eventDataValue(payload map[string]any, path []string) (any, bool) {
+	var value any = payload
+	for _, segment := range path {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[segment]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// isJSONEqual compares the unmarshaled value with the value to marshal
+func isJSONEqual(value, want any) bool {
+	data, err := json.Marshal(want)
+	if err != nil {
+		return false
+	}
+	var normalized any
+	if err = json.Unmarshal(data, &normalized); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(value, normalized)
+}
+
+// isEventDataConditions checks if the payload of the command fulfills all conditions
+func isEventDataConditions(command Command, conditions []EventDataCondition) bool {
+	payload, err := EventData(command)
+	if err != nil || len(payload) == 0 {
+		return false
+	}
+	var decoded map[string]any
+	if err = json.Unmarshal(payload, &decoded); err != nil {
+		return false
+	}
+	for _, condition := range conditions {
+		value, ok := eventDataValue(decoded, strings.Split(condition.Field, EventDataPathSeparator))
+		if !ok {
+			return false
+		}
+		want, ok := jsonScalar(condition.Value)
+		if !ok || !isJSONComparison(compareJSON(value, want), condition.Operator) {
+			return false
+		}
+	}
+	return true
+}
+
+// jsonScalar returns the value as it is unmarshaled from json,
+// it returns false if the value isn't a string, a number, a boolean or null
+func jsonScalar(value any) (any, bool) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+	var normalized any
+	if err = json.Unmarshal(data, &normalized); err != nil {
+		return nil, false
+	}
+	switch normalized.(type) {
+	case nil, string, float64, bool:
+		return normalized, true
+	default:
+		return nil, false
+	}
+}
+
+// jsonTypeRank orders the json types like jsonb of the database
+func jsonTypeRank(value any) int {
+	switch value.(type) {
+	case nil:
+		return 0
+	case string:
+		return 1
+	case float64:
+		return 2
+	case bool:
+		return 3
+	case []any:
+		return 4
+	default:
+		return 5
+	}
+}
+
+// compareJSON compares two unmarshaled json values, the result is like [cmp.Compare].
+// Arrays and objects are only compared by their type.
+func compareJSON(a, b any) int {
+	if rankA, rankB := jsonTypeRank(a), jsonTypeRank(b); rankA != rankB {
+		return cmp.Compare(rankA, rankB)
+	}
+	switch a := a.(type) {
+	case string:
+		return cmp.Compare(a, b.(string))
+	case float64:
+		return cmp.Compare(a, b.(float64))
+	case bool:
+		if a == b.(bool) {
+			return 0
+		}
+		if a {
+			return 1
+		}
+		return -1
+	}
+	return 0
+}
+
+func isJSONComparison(result int, operator EventDataOperator) bool {
+	switch operator {
+	case EventDataEquals:
+		return result == 0
+	case EventDataNotEquals:
+		return result != 0
+	case EventDataGreater:
+		return result > 0
+	case EventDataGreaterOrEquals:
+		return result >= 0
+	case EventDataLess:
+		return result < 0
+	case EventDataLessOrEquals:
+		return result <= 0
+	}
+	return false
+}
+
+func isEventDataMissingKeys(command Command, keys ...string) bool {
+	data, err := EventData(command)
+	if err != nil {
+		return false
+	}
+	payload := make(map[string]json.RawMessage)
+	if len(data) > 0 {
+		if err = json.Unmarshal(data, &payload); err != nil {
+			return false
+		}
+	}
+	for _, key := range keys {
+		if value, ok := payload[key]; ok && string(value) != "null" {
+			return false
+		}
+	}
+	return true
+}
@@ -31,6 +31,10 @@ type BaseEvent struct {
 	//Service which created the event
 	Service string `json:"-"`
 	Data    []byte `json:"-"`
+
+	relevance         float64
+	producerVersion   string
+	maintenanceWindow string
 }
 
 // Position implements Event.
@@ -38,6 +42,21 @@ func (e *BaseEvent) Position() float64 {
 	return e.Pos
 }
 
+// Relevance is set if the event was queried using [SearchQueryBuilder.OrderByRelevance]
+func (e *BaseEvent) Relevance() float64 {
+	return e.relevance
+}
+
+// ProducedByVersion is set if the event was queried using [SearchQueryBuilder.IncludeOrigin]
+func (e *BaseEvent) ProducedByVersion() string {
+	return e.producerVersion
+}
+
+// PushedInMaintenanceWindow is set if the event was queried using [SearchQueryBuilder.IncludeOrigin]
+func (e *BaseEvent) PushedInMaintenanceWindow() string {
+	return e.maintenanceWindow
+}
+
 // EditorService implements Command
 func (e *BaseEvent) EditorService() string {
 	return e.Service
@@ -100,15 +119,19 @@ const defaultService = "zitadel"
 
 // BaseEventFromRepo maps a stored event to a BaseEvent
 func BaseEventFromRepo(event Event) *BaseEvent {
+	producerVersion, maintenanceWindow := EventOrigin(event)
 	return &BaseEvent{
-		Agg:       event.Aggregate(),
-		EventType: event.Type(),
-		Creation:  event.CreatedAt(),
-		Seq:       event.Sequence(),
-		Service:   defaultService,
-		User:      event.Creator(),
-		Data:      event.DataAsBytes(),
-		Pos:       event.Position(),
+		Agg:               event.Aggregate(),
+		EventType:         event.Type(),
+		Creation:          event.CreatedAt(),
+		Seq:               event.Sequence(),
+		Service:           defaultService,
+		User:              event.Creator(),
+		Data:              event.DataAsBytes(),
+		Pos:               event.Position(),
+		relevance:         EventRelevance(event),
+		producerVersion:   producerVersion,
+		maintenanceWindow: maintenanceWindow,
 	}
 }
 
@@ -0,0 +1,35 @@
+package eventstore
+
+import (
+	"encoding/json"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// EventDataSchema validates the unmarshalled data of an event,
+// it's implemented by [github.com/santhosh-tekuri/jsonschema/v5.Schema]
+type EventDataSchema interface {
+	Validate(v interface{}) error
+}
+
+// validateEventData validates the data of the event against the schema registered for its aggregate type.
+// Events of aggregate types without a schema are not unmarshalled.
+func (es *Eventstore) validateEventData(event Event) error {
+	if len(es.eventDataSchemas) == 0 {
+		return nil
+	}
+	schema, ok := es.eventDataSchemas[event.Aggregate().Type]
+	if !ok {
+		return nil
+	}
+	var data interface{}
+	if raw := event.DataAsBytes(); len(raw) > 0 {
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return zerrors.ThrowInvalidArgumentf(err, "EVENT-Sd3kq", "data of event %s at position %v is no valid json", event.Type(), event.Position())
+		}
+	}
+	if err := schema.Validate(data); err != nil {
+		return zerrors.ThrowInvalidArgumentf(err, "EVENT-Sd4lr", "data of event %s at position %v doesn't match the schema", event.Type(), event.Position())
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package eventstore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestEventstore_validateEventData(t *testing.T) {
+	schema := jsonschema.MustCompileString("test.json", `{
+		"type": "object",
+		"properties": {"name": {"type": "string"}},
+		"required": ["name"]
+	}`)
+	tests := []struct {
+		name    string
+		schemas map[AggregateType]EventDataSchema
+		event   Event
+		wantErr bool
+	}{
+		{
+			name: "no schema registered",
+			event: &BaseEvent{
+				Agg:  &Aggregate{Type: "test"},
+				Data: []byte(`{"name": 1}`),
+			},
+		},
+		{
+			name:    "other aggregate type",
+			schemas: map[AggregateType]EventDataSchema{"other": schema},
+			event: &BaseEvent{
+				Agg:  &Aggregate{Type: "test"},
+				Data: []byte(`{"name": 1}`),
+			},
+		},
+		{
+			name:    "valid data",
+			schemas: map[AggregateType]EventDataSchema{"test": schema},
+			event: &BaseEvent{
+				Agg:  &Aggregate{Type: "test"},
+				Data: []byte(`{"name": "hodor"}`),
+			},
+		},
+		{
+			name:    "invalid data",
+			schemas: map[AggregateType]EventDataSchema{"test": schema},
+			event: &BaseEvent{
+				Agg:  &Aggregate{Type: "test"},
+				Pos:  42.1,
+				Data: []byte(`{"name": 1}`),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "no data",
+			schemas: map[AggregateType]EventDataSchema{"test": schema},
+			event: &BaseEvent{
+				Agg: &Aggregate{Type: "test"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			schemas: map[AggregateType]EventDataSchema{"test": schema},
+			event: &BaseEvent{
+				Agg:  &Aggregate{Type: "test"},
+				Data: []byte(`{`),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := &Eventstore{eventDataSchemas: tt.schemas}
+			err := es.validateEventData(tt.event)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, zerrors.IsErrorInvalidArgument(err))
+			assert.Contains(t, err.Error(), fmt.Sprint(tt.event.Position()))
+		})
+	}
+}
@@ -3,6 +3,7 @@ package eventstore
 import (
 	"context"
 	"errors"
+	"slices"
 	"sort"
 	"sync"
 	"time"
@@ -24,6 +25,13 @@ type Eventstore struct {
 	querier  Querier
 	searcher Searcher
 
+	archive          Querier
+	archiveRetention time.Duration
+
+	instanceResultCap     uint64
+	rejectSealedInstances bool
+	eventDataSchemas      map[AggregateType]EventDataSchema
+
 	instances         []string
 	lastInstanceQuery time.Time
 	instancesMu       sync.Mutex
@@ -68,6 +76,13 @@ func NewEventstore(config *Config) *Eventstore {
 		querier:  config.Querier,
 		searcher: config.Searcher,
 
+		archive:          config.Archive,
+		archiveRetention: config.ArchiveRetention,
+
+		instanceResultCap:     config.InstanceResultCap,
+		rejectSealedInstances: config.RejectSealedInstances,
+		eventDataSchemas:      config.EventDataSchemas,
+
 		instancesMu: sync.Mutex{},
 	}
 }
@@ -94,12 +109,22 @@ func (es *Eventstore) Push(ctx context.Context, cmds ...Command) ([]Event, error
 		err    error
 	)
 
+	push := es.pusher.Push
+	if sealing, ok := es.pusher.(SealingPusher); ok && es.rejectSealedInstances {
+		// the seal is checked within the push transaction
+		push = sealing.PushUnsealed
+	} else if es.rejectSealedInstances {
+		if err = es.checkSealed(ctx, cmds); err != nil {
+			return nil, err
+		}
+	}
+
 	// Retry when there is a collision of the sequence as part of the primary key.
 	// "duplicate key value violates unique constraint \"events2_pkey\" (SQLSTATE 23505)"
 	// https://github.com/zitadel/zitadel/issues/7202
 retry:
 	for i := 0; i <= es.maxRetries; i++ {
-		events, err = es.pusher.Push(ctx, cmds...)
+		events, err = push(ctx, cmds...)
 		var pgErr *pgconn.PgError
 		if !errors.As(err, &pgErr) || pgErr.ConstraintName != "events2_pkey" || pgErr.SQLState() != "23505" {
 			break retry
@@ -118,6 +143,23 @@ retry:
 	return mappedEvents, nil
 }
 
+// PushWithMapping pushes the events in a single transaction like [Eventstore.Push]
+// and returns the pushed events per command in the order of the commands
+func (es *Eventstore) PushWithMapping(ctx context.Context, cmds ...Command) ([][]Event, error) {
+	events, err := es.Push(ctx, cmds...)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) != len(cmds) {
+		return nil, zerrors.ThrowInternalf(nil, "V2-Pq3xN", "pushed %d events for %d commands", len(events), len(cmds))
+	}
+	mapped := make([][]Event, len(cmds))
+	for i, event := range events {
+		mapped[i] = []Event{event}
+	}
+	return mapped, nil
+}
+
 func AggregateTypeFromEventType(typ EventType) AggregateType {
 	return eventTypeMapping[typ]
 }
@@ -145,13 +187,14 @@ func (es *Eventstore) Search(ctx context.Context, conditions ...map[FieldType]an
 }
 
 // Filter filters the stored events based on the searchQuery
-// and maps the events to the defined event structs
+// and maps the events to the defined event structs.
+// The events read before are returned together with [ErrByteBudgetExceeded] if the byte budget of the search query was exceeded.
 //
 // Deprecated: Use [FilterToQueryReducer] instead to avoid allocations.
 func (es *Eventstore) Filter(ctx context.Context, searchQuery *SearchQueryBuilder) ([]Event, error) {
 	events := make([]Event, 0, searchQuery.GetLimit())
 	searchQuery.ensureInstanceID(ctx)
-	err := es.querier.FilterToReducer(ctx, searchQuery, func(event Event) error {
+	err := es.filterToReducer(ctx, searchQuery, func(event Event) error {
 		event, err := es.mapEvent(event)
 		if err != nil {
 			return err
@@ -159,6 +202,9 @@ func (es *Eventstore) Filter(ctx context.Context, searchQuery *SearchQueryBuilde
 		events = append(events, event)
 		return nil
 	})
+	if errors.Is(err, ErrByteBudgetExceeded) {
+		return events, err
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -181,6 +227,9 @@ func (es *Eventstore) mapEvent(event Event) (Event, error) {
 }
 
 func (es *Eventstore) mapEventLocked(event Event) (Event, error) {
+	if err := es.validateEventData(event); err != nil {
+		return nil, err
+	}
 	interceptors, ok := eventInterceptors[event.Type()]
 	if !ok || interceptors.eventMapper == nil {
 		return BaseEventFromRepo(event), nil
@@ -207,7 +256,7 @@ type reducer interface {
 // FilterToReducer filters the events based on the search query, appends all events to the reducer and calls it's reduce function
 func (es *Eventstore) FilterToReducer(ctx context.Context, searchQuery *SearchQueryBuilder, r reducer) error {
 	searchQuery.ensureInstanceID(ctx)
-	return es.querier.FilterToReducer(ctx, searchQuery, func(event Event) error {
+	return es.filterToReducer(ctx, searchQuery, func(event Event) error {
 		event, err := es.mapEvent(event)
 		if err != nil {
 			return err
@@ -224,6 +273,59 @@ func (es *Eventstore) LatestSequence(ctx context.Context, queryFactory *SearchQu
 	return es.querier.LatestSequence(ctx, queryFactory)
 }
 
+// LatestPosition returns the highest position of the events found by the search query, 0 if no event is found
+func (es *Eventstore) LatestPosition(ctx context.Context, queryFactory *SearchQueryBuilder) (float64, error) {
+	queryFactory.ensureInstanceID(ctx)
+	return es.querier.LatestSequence(ctx, queryFactory.Columns(ColumnsMaxPosition))
+}
+
+// AggregateSequence returns the sequence of the latest event of the aggregate, 0 if the aggregate doesn't exist.
+// Commands can use it to check the existence of an aggregate or to get the current sequence before a push
+// without reducing a write model.
+// Other than [Eventstore.LatestSequence] the sequence of the aggregate is returned instead of the position of the event,
+// only the latest event is read from the storage.
+func (es *Eventstore) AggregateSequence(ctx context.Context, aggregateType AggregateType, aggregateID string) (sequence uint64, err error) {
+	query := NewSearchQueryBuilder(ColumnsEvent).
+		OrderDesc().
+		Limit(1).
+		AddQuery().
+		AggregateTypes(aggregateType).
+		AggregateIDs(aggregateID).
+		Builder()
+	query.ensureInstanceID(ctx)
+	err = es.filterToReducer(ctx, query, func(event Event) error {
+		sequence = event.Sequence()
+		return nil
+	})
+	return sequence, err
+}
+
+// Count returns the amount of events found by the search query.
+// The limit and offset of the search query are ignored.
+func (es *Eventstore) Count(ctx context.Context, queryFactory *SearchQueryBuilder) (uint64, error) {
+	queryFactory.ensureInstanceID(ctx)
+	return es.count(ctx, queryFactory)
+}
+
+// EstimateCount returns the approximate amount of events found by the search query.
+// The estimate is derived from the table statistics of the storage and can differ considerably from [Eventstore.Count],
+// it must only be used where imprecision is acceptable, e.g. dashboards.
+// Events of the archive are not included.
+// If the storage can't estimate, the exact amount is returned.
+func (es *Eventstore) EstimateCount(ctx context.Context, queryFactory *SearchQueryBuilder) (uint64, error) {
+	queryFactory.ensureInstanceID(ctx)
+	estimator, ok := es.querier.(countEstimator)
+	if !ok {
+		return es.querier.Count(ctx, queryFactory)
+	}
+	return estimator.EstimateCount(ctx, queryFactory)
+}
+
+// countEstimator is implemented by queriers which are able to estimate the amount of events
+type countEstimator interface {
+	EstimateCount(ctx context.Context, queryFactory *SearchQueryBuilder) (uint64, error)
+}
+
 // InstanceIDs returns the instance ids found by the search query
 // forceDBCall forces to query the database, the instance ids are not cached
 func (es *Eventstore) InstanceIDs(ctx context.Context, maxAge time.Duration, forceDBCall bool, queryFactory *SearchQueryBuilder) ([]string, error) {
@@ -247,6 +349,19 @@ func (es *Eventstore) InstanceIDs(ctx context.Context, maxAge time.Duration, for
 	return instances, nil
 }
 
+// DistinctInstanceIDs returns the sorted and de-duplicated ids of the instances with events found by the search query,
+// e.g. to enumerate the active instances in system-level maintenance jobs.
+// The columns of the search query are set to [ColumnsInstanceIDs].
+// In contrast to [Eventstore.InstanceIDs] the result is never cached.
+func (es *Eventstore) DistinctInstanceIDs(ctx context.Context, queryFactory *SearchQueryBuilder) ([]string, error) {
+	instances, err := es.querier.InstanceIDs(ctx, queryFactory.Columns(ColumnsInstanceIDs))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(instances)
+	return slices.Compact(instances), nil
+}
+
 type QueryReducer interface {
 	reducer
 	//Query returns the SearchQueryFactory for the events needed in reducer
@@ -270,6 +385,8 @@ type Querier interface {
 	LatestSequence(ctx context.Context, queryFactory *SearchQueryBuilder) (float64, error)
 	// InstanceIDs returns the instance ids found by the search query
 	InstanceIDs(ctx context.Context, queryFactory *SearchQueryBuilder) ([]string, error)
+	// Count returns the amount of events found by the search query
+	Count(ctx context.Context, queryFactory *SearchQueryBuilder) (uint64, error)
 }
 
 type Pusher interface {
@@ -160,3 +160,71 @@ func Benchmark_Push_MultipleAggregate_Parallel(b *testing.B) {
 		}
 	}
 }
+
+// Benchmark_Filter_OptimizeForTenant compares the queries of a single instance and owner
+// with and without [eventstore.SearchQueryBuilder.OptimizeForTenant].
+// The events are spread over several instances and owners so the index on instance id and owner is selective.
+//
+//	go test -run '^$' -bench Benchmark_Filter_OptimizeForTenant ./internal/eventstore/
+func Benchmark_Filter_OptimizeForTenant(b *testing.B) {
+	const (
+		instances         = 10
+		ownersPerInstance = 20
+		eventsPerOwner    = 50
+	)
+	ctx := context.Background()
+	client := clients["v3(inmemory)"]
+	cleanupEventstore(client)()
+	_, err := client.Exec(`CREATE INDEX IF NOT EXISTS es_instance_owner ON eventstore.events2 (instance_id, "owner", "position")`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	aggregateType := eventstore.AggregateType(b.Name())
+	for instance := 0; instance < instances; instance++ {
+		for owner := 0; owner < ownersPerInstance; owner++ {
+			cmds := make([]eventstore.Command, eventsPerOwner)
+			for i := range cmds {
+				cmds[i] = generateCommand(aggregateType, fmt.Sprintf("%d-%d-%d", instance, owner, i),
+					withInstanceAndOwner(strconv.Itoa(instance), strconv.Itoa(owner)),
+				)
+			}
+			if _, err = pushers["v3(inmemory)"].Push(ctx, cmds...); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	for name, optimize := range map[string]bool{"default": false, "optimized": true} {
+		b.Run(name, func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				query := eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+					InstanceID(strconv.Itoa(n % instances)).
+					ResourceOwner(strconv.Itoa(n % ownersPerInstance)).
+					AddQuery().
+					AggregateTypes(aggregateType).
+					Builder()
+				if optimize {
+					query = query.OptimizeForTenant()
+				}
+				var count int
+				err := queriers["v2(inmemory)"].FilterToReducer(ctx, query, func(eventstore.Event) error {
+					count++
+					return nil
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+				if count != eventsPerOwner {
+					b.Fatalf("got %d events, want %d", count, eventsPerOwner)
+				}
+			}
+		})
+	}
+}
+
+func withInstanceAndOwner(instanceID, owner string) func(e *testEvent) {
+	return func(e *testEvent) {
+		e.Agg.InstanceID = instanceID
+		e.Agg.ResourceOwner = owner
+	}
+}
@@ -180,3 +180,85 @@ func TestCRDB_LatestSequence(t *testing.T) {
 		}
 	}
 }
+
+func TestCRDB_AggregateSequence(t *testing.T) {
+	type args struct {
+		aggregateType eventstore.AggregateType
+		aggregateID   string
+	}
+	type fields struct {
+		existingEvents []eventstore.Command
+	}
+	type res struct {
+		sequence uint64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		res     res
+		wantErr bool
+	}{
+		{
+			name: "aggregate doesn't exist",
+			args: args{
+				aggregateType: eventstore.AggregateType(t.Name()),
+				aggregateID:   "not found",
+			},
+			fields: fields{
+				existingEvents: []eventstore.Command{
+					generateCommand(eventstore.AggregateType(t.Name()), "500"),
+				},
+			},
+			res: res{
+				sequence: 0,
+			},
+		},
+		{
+			name: "created aggregate",
+			args: args{
+				aggregateType: eventstore.AggregateType(t.Name()),
+				aggregateID:   "501",
+			},
+			fields: fields{
+				existingEvents: []eventstore.Command{
+					generateCommand(eventstore.AggregateType(t.Name()), "501"),
+					generateCommand(eventstore.AggregateType(t.Name()), "501"),
+					generateCommand(eventstore.AggregateType(t.Name()), "501"),
+					generateCommand(eventstore.AggregateType(t.Name()), "502"),
+				},
+			},
+			res: res{
+				sequence: 3,
+			},
+		},
+	}
+	for _, tt := range tests {
+		for querierName, querier := range queriers {
+			t.Run(querierName+"/"+tt.name, func(t *testing.T) {
+				t.Cleanup(cleanupEventstore(clients[querierName]))
+
+				db := eventstore.NewEventstore(
+					&eventstore.Config{
+						Querier: querier,
+						Pusher:  pushers["v3(inmemory)"],
+					},
+				)
+
+				// setup initial data for query
+				if _, err := db.Push(context.Background(), tt.fields.existingEvents...); err != nil {
+					t.Errorf("error in setup = %v", err)
+					return
+				}
+
+				sequence, err := db.AggregateSequence(context.Background(), tt.args.aggregateType, tt.args.aggregateID)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("CRDB.query() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if sequence != tt.res.sequence {
+					t.Errorf("CRDB.query() expected sequence: %v got %v", tt.res.sequence, sequence)
+				}
+			})
+		}
+	}
+}
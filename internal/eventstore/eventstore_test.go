@@ -3,8 +3,11 @@ package eventstore
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"reflect"
+	"slices"
 	"testing"
 	"time"
 
@@ -430,6 +433,13 @@ func (repo *testQuerier) LatestSequence(ctx context.Context, queryFactory *Searc
 	return repo.sequence, nil
 }
 
+func (repo *testQuerier) Count(ctx context.Context, queryFactory *SearchQueryBuilder) (uint64, error) {
+	if repo.err != nil {
+		return 0, repo.err
+	}
+	return uint64(len(repo.events)), nil
+}
+
 func (repo *testQuerier) InstanceIDs(ctx context.Context, queryFactory *SearchQueryBuilder) ([]string, error) {
 	if repo.err != nil {
 		return nil, repo.err
@@ -923,6 +933,72 @@ func TestEventstore_Push(t *testing.T) {
 	}
 }
 
+func TestEventstore_PushWithMapping(t *testing.T) {
+	pushedEvent := func(aggID string) *BaseEvent {
+		return &BaseEvent{
+			Agg: &Aggregate{
+				ID:            aggID,
+				Type:          "test.aggregate",
+				ResourceOwner: "caos",
+				InstanceID:    "zitadel",
+			},
+			Data:      []byte(nil),
+			User:      "editorUser",
+			EventType: "test.event",
+		}
+	}
+	command := func(aggID string) Command {
+		return newTestEvent(aggID, "", func() interface{} { return []byte(nil) }, false)
+	}
+	tests := []struct {
+		name    string
+		cmds    []Command
+		pusher  *testPusher
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "push failed",
+			cmds: []Command{command("1")},
+			pusher: &testPusher{
+				t:      t,
+				events: []Event{pushedEvent("1")},
+				errs:   []error{zerrors.ThrowInternal(nil, "V2-Fg4Kd", "test err")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "events per command",
+			cmds: []Command{command("1"), command("2"), command("1")},
+			pusher: &testPusher{
+				t:      t,
+				events: []Event{pushedEvent("1"), pushedEvent("2"), pushedEvent("1")},
+			},
+			want: []string{"1", "2", "1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventInterceptors = map[EventType]eventTypeInterceptors{}
+			es := &Eventstore{
+				pusher: tt.pusher,
+			}
+			got, err := es.PushWithMapping(context.Background(), tt.cmds...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Eventstore.PushWithMapping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Eventstore.PushWithMapping() got %d results, want %d", len(got), len(tt.want))
+			}
+			for i, events := range got {
+				if len(events) != 1 || events[0].Aggregate().ID != tt.want[i] {
+					t.Errorf("Eventstore.PushWithMapping() result %d = %v, want aggregate %s", i, events, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
 func TestEventstore_FilterEvents(t *testing.T) {
 	type args struct {
 		query *SearchQueryBuilder
@@ -1177,6 +1253,51 @@ func (r *testReducer) AppendEvents(e ...Event) {
 	r.events = append(r.events, e...)
 }
 
+func TestEventstore_AggregateSequence(t *testing.T) {
+	tests := []struct {
+		name    string
+		querier *testQuerier
+		want    uint64
+		wantErr func(error) bool
+	}{
+		{
+			name: "existing aggregate",
+			querier: &testQuerier{events: []Event{
+				&BaseEvent{Seq: 3, EventType: "test", Agg: &Aggregate{ID: "a", Type: "test.aggregate"}},
+			}},
+			want: 3,
+		},
+		{
+			name:    "aggregate doesn't exist",
+			querier: &testQuerier{},
+			want:    0,
+		},
+		{
+			name:    "query failed",
+			querier: &testQuerier{err: zerrors.ThrowInternal(nil, "V2-As4qe", "test err")},
+			wantErr: zerrors.IsInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := &Eventstore{querier: tt.querier}
+			got, err := es.AggregateSequence(authz.WithInstanceID(context.Background(), "instance"), "test.aggregate", "a")
+			if tt.wantErr != nil {
+				if !tt.wantErr(err) {
+					t.Errorf("Eventstore.AggregateSequence() error = %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eventstore.AggregateSequence() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Eventstore.AggregateSequence() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestEventstore_FilterToReducer(t *testing.T) {
 	type args struct {
 		query     *SearchQueryBuilder
@@ -1474,3 +1595,770 @@ func TestEventstore_mapEvents(t *testing.T) {
 		})
 	}
 }
+
+// archiveTestQuerier returns its events up to the limit of the search query and records the queries
+type archiveTestQuerier struct {
+	testQuerier
+	queries []*SearchQueryBuilder
+}
+
+func (repo *archiveTestQuerier) FilterToReducer(ctx context.Context, searchQuery *SearchQueryBuilder, reduce Reducer) error {
+	repo.queries = append(repo.queries, searchQuery)
+	for i, event := range repo.events {
+		if searchQuery.GetLimit() > 0 && uint64(i) >= searchQuery.GetLimit() {
+			return nil
+		}
+		if err := reduce(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestEventstore_Filter_archive(t *testing.T) {
+	archived := func(seq uint64) Event {
+		return &BaseEvent{Seq: seq, EventType: "archived", Agg: &Aggregate{ID: "a"}}
+	}
+	hot := func(seq uint64) Event {
+		return &BaseEvent{Seq: seq, EventType: "hot", Agg: &Aggregate{ID: "a"}}
+	}
+	tests := []struct {
+		name             string
+		query            *SearchQueryBuilder
+		archiveRetention time.Duration
+		wantSequences    []uint64
+		wantArchiveQuery bool
+		wantErr          bool
+	}{
+		{
+			name:          "archive not included",
+			query:         NewSearchQueryBuilder(ColumnsEvent),
+			wantSequences: []uint64{3, 4},
+		},
+		{
+			name:             "ascending, archive first",
+			query:            NewSearchQueryBuilder(ColumnsEvent).IncludeArchive().ForUpdate().AwaitOpenTransactions(),
+			wantSequences:    []uint64{1, 2, 3, 4},
+			wantArchiveQuery: true,
+		},
+		{
+			name:             "descending, hot store first",
+			query:            NewSearchQueryBuilder(ColumnsEvent).IncludeArchive().OrderDesc(),
+			wantSequences:    []uint64{3, 4, 1, 2},
+			wantArchiveQuery: true,
+		},
+		{
+			name:             "limit reached in archive",
+			query:            NewSearchQueryBuilder(ColumnsEvent).IncludeArchive().Limit(2),
+			wantSequences:    []uint64{1, 2},
+			wantArchiveQuery: true,
+		},
+		{
+			name:             "limit continues into hot store",
+			query:            NewSearchQueryBuilder(ColumnsEvent).IncludeArchive().Limit(3),
+			wantSequences:    []uint64{1, 2, 3},
+			wantArchiveQuery: true,
+		},
+		{
+			name:             "inside retention window",
+			query:            NewSearchQueryBuilder(ColumnsEvent).IncludeArchive().CreationDateAfter(time.Now().Add(-time.Hour)),
+			archiveRetention: 24 * time.Hour,
+			wantSequences:    []uint64{3, 4},
+		},
+		{
+			name:             "beyond retention window",
+			query:            NewSearchQueryBuilder(ColumnsEvent).IncludeArchive().CreationDateAfter(time.Now().Add(-48 * time.Hour)),
+			archiveRetention: 24 * time.Hour,
+			wantSequences:    []uint64{1, 2, 3, 4},
+			wantArchiveQuery: true,
+		},
+		{
+			name:    "offset, error",
+			query:   NewSearchQueryBuilder(ColumnsEvent).IncludeArchive().Offset(1),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := &archiveTestQuerier{testQuerier: testQuerier{events: []Event{archived(1), archived(2)}}}
+			es := &Eventstore{
+				querier:          &archiveTestQuerier{testQuerier: testQuerier{events: []Event{hot(3), hot(4)}}},
+				archive:          archive,
+				archiveRetention: tt.archiveRetention,
+			}
+			events, err := es.Filter(context.Background(), tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Eventstore.Filter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			sequences := make([]uint64, 0, len(events))
+			for _, event := range events {
+				sequences = append(sequences, event.Sequence())
+			}
+			if !tt.wantErr && !reflect.DeepEqual(sequences, tt.wantSequences) {
+				t.Errorf("Eventstore.Filter() = %v, want %v", sequences, tt.wantSequences)
+			}
+			if got := len(archive.queries) > 0; got != tt.wantArchiveQuery {
+				t.Fatalf("archive queried = %v, want %v", got, tt.wantArchiveQuery)
+			}
+			if tt.wantArchiveQuery && (archive.queries[0].GetForUpdate() || archive.queries[0].GetAwaitOpenTransactions()) {
+				t.Error("hot store options must not be passed to the archive")
+			}
+		})
+	}
+}
+
+func TestEventstore_FilterToReducerCapped(t *testing.T) {
+	event := func(seq uint64) Event {
+		return &BaseEvent{Seq: seq, EventType: "test", Agg: &Aggregate{ID: "a"}}
+	}
+	tests := []struct {
+		name          string
+		query         *SearchQueryBuilder
+		cap           uint64
+		wantSequences []uint64
+		wantCapped    bool
+	}{
+		{
+			name:          "no cap",
+			query:         NewSearchQueryBuilder(ColumnsEvent).InstanceID("instance"),
+			wantSequences: []uint64{1, 2, 3},
+		},
+		{
+			name:          "below cap",
+			query:         NewSearchQueryBuilder(ColumnsEvent).InstanceID("instance"),
+			cap:           3,
+			wantSequences: []uint64{1, 2, 3},
+		},
+		{
+			name:          "exceeds cap",
+			query:         NewSearchQueryBuilder(ColumnsEvent).InstanceID("instance"),
+			cap:           2,
+			wantSequences: []uint64{1, 2},
+			wantCapped:    true,
+		},
+		{
+			name:          "limit above cap",
+			query:         NewSearchQueryBuilder(ColumnsEvent).InstanceID("instance").Limit(10),
+			cap:           2,
+			wantSequences: []uint64{1, 2},
+			wantCapped:    true,
+		},
+		{
+			name:          "limit below cap",
+			query:         NewSearchQueryBuilder(ColumnsEvent).InstanceID("instance").Limit(1),
+			cap:           2,
+			wantSequences: []uint64{1},
+		},
+		{
+			name:          "compiled query exceeds cap",
+			query:         NewSearchQueryBuilder(ColumnsEvent).Compile().Bind(map[string]any{InstanceIDParam: "instance"}),
+			cap:           2,
+			wantSequences: []uint64{1, 2},
+			wantCapped:    true,
+		},
+		{
+			name:          "last events exceed cap",
+			query:         NewSearchQueryBuilder(ColumnsEvent).InstanceID("instance").LastEvents(10),
+			cap:           2,
+			wantSequences: []uint64{2, 1},
+			wantCapped:    true,
+		},
+		{
+			name:          "multiple instances not capped",
+			query:         NewSearchQueryBuilder(ColumnsEvent).InstanceIDs("instance", "other"),
+			cap:           2,
+			wantSequences: []uint64{1, 2, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := &archiveTestQuerier{testQuerier: testQuerier{events: []Event{event(1), event(2), event(3)}}}
+			es := &Eventstore{
+				querier:           querier,
+				instanceResultCap: tt.cap,
+			}
+			limit := tt.query.GetLimit()
+			reducer := new(appendReducer)
+			capped, err := es.FilterToReducerCapped(context.Background(), tt.query, reducer)
+			if err != nil {
+				t.Fatalf("Eventstore.FilterToReducerCapped() unexpected error = %v", err)
+			}
+			if got := sequencesOf(reducer.events); !reflect.DeepEqual(got, tt.wantSequences) {
+				t.Errorf("Eventstore.FilterToReducerCapped() = %v, want %v", got, tt.wantSequences)
+			}
+			if capped != tt.wantCapped {
+				t.Errorf("Eventstore.FilterToReducerCapped() capped = %v, want %v", capped, tt.wantCapped)
+			}
+			if got := tt.query.GetLimit(); got != limit {
+				t.Errorf("limit of the search query must not be changed, got %d, want %d", got, limit)
+			}
+		})
+	}
+}
+
+func TestEventstore_Filter_notCapped(t *testing.T) {
+	event := func(seq uint64) Event {
+		return &BaseEvent{Seq: seq, EventType: "test", Agg: &Aggregate{ID: "a"}}
+	}
+	es := &Eventstore{
+		querier:           &archiveTestQuerier{testQuerier: testQuerier{events: []Event{event(1), event(2), event(3)}}},
+		instanceResultCap: 2,
+	}
+	events, err := es.Filter(context.Background(), NewSearchQueryBuilder(ColumnsEvent).InstanceID("instance"))
+	if err != nil {
+		t.Fatalf("Eventstore.Filter() unexpected error = %v", err)
+	}
+	if got := sequencesOf(events); !reflect.DeepEqual(got, []uint64{1, 2, 3}) {
+		t.Errorf("Eventstore.Filter() = %v, write models must read all events", got)
+	}
+	reducer := new(appendReducer)
+	if err = es.FilterToReducer(context.Background(), NewSearchQueryBuilder(ColumnsEvent).InstanceID("instance"), reducer); err != nil {
+		t.Fatalf("Eventstore.FilterToReducer() unexpected error = %v", err)
+	}
+	if got := sequencesOf(reducer.events); !reflect.DeepEqual(got, []uint64{1, 2, 3}) {
+		t.Errorf("Eventstore.FilterToReducer() = %v, write models must read all events", got)
+	}
+}
+
+// byteBudgetTestQuerier stops reading the events after the budget like the sql storage
+type byteBudgetTestQuerier struct {
+	testQuerier
+	budget int
+}
+
+func (repo *byteBudgetTestQuerier) FilterToReducer(ctx context.Context, searchQuery *SearchQueryBuilder, reduce Reducer) error {
+	for i, event := range repo.events {
+		if i >= repo.budget {
+			return ErrByteBudgetExceeded
+		}
+		if err := reduce(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestEventstore_Filter_byteBudgetExceeded(t *testing.T) {
+	event := func(seq uint64) Event {
+		return &BaseEvent{Seq: seq, EventType: "test", Agg: &Aggregate{ID: "a"}}
+	}
+	es := &Eventstore{
+		querier: &byteBudgetTestQuerier{testQuerier: testQuerier{events: []Event{event(1), event(2), event(3)}}, budget: 2},
+	}
+	events, err := es.Filter(context.Background(), NewSearchQueryBuilder(ColumnsEvent).InstanceID("instance").ByteBudget(10))
+	if !errors.Is(err, ErrByteBudgetExceeded) {
+		t.Errorf("Eventstore.Filter() error = %v, want %v", err, ErrByteBudgetExceeded)
+	}
+	if got := sequencesOf(events); !reflect.DeepEqual(got, []uint64{1, 2}) {
+		t.Errorf("Eventstore.Filter() = %v, want the events within the budget", got)
+	}
+
+	reducer := new(appendReducer)
+	_, err = es.FilterToReducerCapped(context.Background(), NewSearchQueryBuilder(ColumnsEvent).InstanceID("instance").ByteBudget(10).LastEvents(3), reducer)
+	if !errors.Is(err, ErrByteBudgetExceeded) {
+		t.Errorf("Eventstore.FilterToReducerCapped() error = %v, want %v", err, ErrByteBudgetExceeded)
+	}
+	if got := sequencesOf(reducer.events); !reflect.DeepEqual(got, []uint64{2, 1}) {
+		t.Errorf("Eventstore.FilterToReducerCapped() = %v, want the buffered events within the budget", got)
+	}
+}
+
+// appendReducer collects the reduced events
+type appendReducer struct {
+	events []Event
+}
+
+func (r *appendReducer) AppendEvents(events ...Event) {
+	r.events = append(r.events, events...)
+}
+
+func (r *appendReducer) Reduce() error {
+	return nil
+}
+
+func sequencesOf(events []Event) []uint64 {
+	sequences := make([]uint64, 0, len(events))
+	for _, event := range events {
+		sequences = append(sequences, event.Sequence())
+	}
+	return sequences
+}
+
+func TestEventstore_FilterIterator(t *testing.T) {
+	event := func(seq uint64) Event {
+		return &BaseEvent{Seq: seq, EventType: "test", Agg: &Aggregate{ID: "a"}}
+	}
+	eventInterceptors = map[EventType]eventTypeInterceptors{}
+
+	t.Run("all events", func(t *testing.T) {
+		querier := &archiveTestQuerier{testQuerier: testQuerier{events: []Event{event(1), event(2), event(3)}}}
+		es := &Eventstore{querier: querier}
+		iterator, err := es.FilterIterator(context.Background(), NewSearchQueryBuilder(ColumnsEvent).InstanceID("instance").Limit(2))
+		if err != nil {
+			t.Fatalf("Eventstore.FilterIterator() unexpected error = %v", err)
+		}
+		defer iterator.Close()
+
+		sequences := make([]uint64, 0, 2)
+		for {
+			event, err := iterator.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Fatalf("EventIterator.Next() unexpected error = %v", err)
+			}
+			sequences = append(sequences, event.Sequence())
+		}
+		if want := []uint64{1, 2}; !reflect.DeepEqual(sequences, want) {
+			t.Errorf("EventIterator.Next() = %v, want %v", sequences, want)
+		}
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		es := &Eventstore{querier: &testQuerier{}}
+		_, err := es.FilterIterator(context.Background(), NewSearchQueryBuilder(ColumnsEvent).Offset(1))
+		if !zerrors.IsPreconditionFailed(err) {
+			t.Errorf("Eventstore.FilterIterator() error = %v, want precondition failed", err)
+		}
+	})
+
+	t.Run("query failed", func(t *testing.T) {
+		es := &Eventstore{querier: &testQuerier{err: zerrors.ThrowInternal(nil, "V2-It3qe", "test err")}}
+		iterator, err := es.FilterIterator(context.Background(), NewSearchQueryBuilder(ColumnsEvent).InstanceID("instance"))
+		if err != nil {
+			t.Fatalf("Eventstore.FilterIterator() unexpected error = %v", err)
+		}
+		defer iterator.Close()
+		if _, err = iterator.Next(); !zerrors.IsInternal(err) {
+			t.Errorf("EventIterator.Next() error = %v, want internal", err)
+		}
+	})
+
+	t.Run("closed before end", func(t *testing.T) {
+		querier := &archiveTestQuerier{testQuerier: testQuerier{events: []Event{event(1), event(2), event(3)}}}
+		es := &Eventstore{querier: querier}
+		iterator, err := es.FilterIterator(context.Background(), NewSearchQueryBuilder(ColumnsEvent).InstanceID("instance"))
+		if err != nil {
+			t.Fatalf("Eventstore.FilterIterator() unexpected error = %v", err)
+		}
+		if _, err = iterator.Next(); err != nil {
+			t.Fatalf("EventIterator.Next() unexpected error = %v", err)
+		}
+		iterator.Close()
+		iterator.Close()
+		if _, err = iterator.Next(); !errors.Is(err, context.Canceled) {
+			t.Errorf("EventIterator.Next() after close error = %v, want %v", err, context.Canceled)
+		}
+	})
+
+	t.Run("context canceled without close", func(t *testing.T) {
+		querier := &archiveTestQuerier{testQuerier: testQuerier{events: []Event{event(1), event(2), event(3)}}}
+		es := &Eventstore{querier: querier}
+		ctx, cancel := context.WithCancel(context.Background())
+		iterator, err := es.FilterIterator(ctx, NewSearchQueryBuilder(ColumnsEvent).InstanceID("instance"))
+		if err != nil {
+			t.Fatalf("Eventstore.FilterIterator() unexpected error = %v", err)
+		}
+		if _, err = iterator.Next(); err != nil {
+			t.Fatalf("EventIterator.Next() unexpected error = %v", err)
+		}
+		cancel()
+		select {
+		case <-iterator.(*eventIterator).done:
+		case <-time.After(time.Second):
+			t.Fatal("query must stop if the context is canceled")
+		}
+		if _, err = iterator.Next(); !errors.Is(err, context.Canceled) {
+			t.Errorf("EventIterator.Next() after cancel error = %v, want %v", err, context.Canceled)
+		}
+	})
+}
+
+type instanceIDsTestQuerier struct {
+	testQuerier
+	columns Columns
+}
+
+func (repo *instanceIDsTestQuerier) InstanceIDs(ctx context.Context, queryFactory *SearchQueryBuilder) ([]string, error) {
+	repo.columns = queryFactory.GetColumns()
+	return repo.testQuerier.InstanceIDs(ctx, queryFactory)
+}
+
+func TestEventstore_DistinctInstanceIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		querier *instanceIDsTestQuerier
+		want    []string
+		wantErr func(error) bool
+	}{
+		{
+			name: "distinct and sorted",
+			querier: &instanceIDsTestQuerier{testQuerier: testQuerier{
+				instances: []string{"instance3", "instance1", "instance2", "instance1", "instance3"},
+			}},
+			want: []string{"instance1", "instance2", "instance3"},
+		},
+		{
+			name:    "no instances",
+			querier: &instanceIDsTestQuerier{},
+			want:    []string{},
+		},
+		{
+			name: "query failed",
+			querier: &instanceIDsTestQuerier{testQuerier: testQuerier{
+				err: zerrors.ThrowInternal(nil, "V2-Di5qe", "test err"),
+			}},
+			wantErr: zerrors.IsInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := &Eventstore{querier: tt.querier}
+			got, err := es.DistinctInstanceIDs(context.Background(), NewSearchQueryBuilder(ColumnsEvent).PositionAfter(42))
+			if tt.wantErr != nil {
+				if !tt.wantErr(err) {
+					t.Errorf("Eventstore.DistinctInstanceIDs() error = %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eventstore.DistinctInstanceIDs() unexpected error = %v", err)
+			}
+			if tt.querier.columns != ColumnsInstanceIDs {
+				t.Errorf("Eventstore.DistinctInstanceIDs() queried columns %v, want %v", tt.querier.columns, ColumnsInstanceIDs)
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+				t.Errorf("Eventstore.DistinctInstanceIDs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventstore_Filter_lastEvents(t *testing.T) {
+	event := func(seq uint64) Event {
+		return &BaseEvent{Seq: seq, Agg: &Aggregate{ID: "a"}}
+	}
+	// the storage returns the events in descending order
+	querier := &archiveTestQuerier{testQuerier: testQuerier{events: []Event{event(3), event(2), event(1)}}}
+	es := &Eventstore{querier: querier}
+	query := NewSearchQueryBuilder(ColumnsEvent).LastEvents(2)
+	events, err := es.Filter(context.Background(), query)
+	if err != nil {
+		t.Fatalf("Eventstore.Filter() unexpected error = %v", err)
+	}
+	sequences := make([]uint64, 0, len(events))
+	for _, event := range events {
+		sequences = append(sequences, event.Sequence())
+	}
+	if want := []uint64{2, 3}; !reflect.DeepEqual(sequences, want) {
+		t.Errorf("Eventstore.Filter() = %v, want %v", sequences, want)
+	}
+	if len(querier.queries) != 1 || !querier.queries[0].GetDesc() || querier.queries[0].GetLimit() != 2 {
+		t.Errorf("expected one descending query limited to 2, got %v", querier.queries)
+	}
+	if !query.GetLastEvents() || query.OrderAsc().GetLastEvents() {
+		t.Error("last events must be disabled by a later order")
+	}
+}
+
+// timeoutTestQuerier reduces its events and blocks until the context is done
+type timeoutTestQuerier struct {
+	testQuerier
+}
+
+func (repo *timeoutTestQuerier) FilterToReducer(ctx context.Context, searchQuery *SearchQueryBuilder, reduce Reducer) error {
+	for _, event := range repo.events {
+		if err := reduce(event); err != nil {
+			return err
+		}
+	}
+	<-ctx.Done()
+	return zerrors.ThrowInternal(ctx.Err(), "SQL-KyeAx", "unable to filter events")
+}
+
+func TestEventstore_Filter_queryTimeout(t *testing.T) {
+	es := &Eventstore{
+		querier: &timeoutTestQuerier{testQuerier: testQuerier{events: []Event{&BaseEvent{Seq: 1, Agg: &Aggregate{ID: "a"}}}}},
+	}
+	events, err := es.Filter(context.Background(), NewSearchQueryBuilder(ColumnsEvent).QueryTimeout(time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Eventstore.Filter() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if events != nil {
+		t.Errorf("Eventstore.Filter() = %v, want no events", events)
+	}
+}
+
+// awaitTestQuerier returns the next of its positions on every call of LatestSequence
+type awaitTestQuerier struct {
+	testQuerier
+	positions []float64
+	calls     int
+}
+
+func (repo *awaitTestQuerier) LatestSequence(ctx context.Context, queryFactory *SearchQueryBuilder) (float64, error) {
+	position := repo.positions[min(repo.calls, len(repo.positions)-1)]
+	repo.calls++
+	return position, nil
+}
+
+func TestEventstore_Filter_awaitPosition(t *testing.T) {
+	interval := awaitPositionInterval
+	awaitPositionInterval = time.Millisecond
+	t.Cleanup(func() { awaitPositionInterval = interval })
+	tests := []struct {
+		name      string
+		query     *SearchQueryBuilder
+		positions []float64
+		wantCalls int
+		wantErr   func(error) bool
+	}{
+		{
+			name:      "position not awaited",
+			query:     NewSearchQueryBuilder(ColumnsEvent),
+			positions: []float64{0},
+		},
+		{
+			name:      "position reached",
+			query:     NewSearchQueryBuilder(ColumnsEvent).AwaitPosition(5),
+			positions: []float64{0, 0, 5.5},
+			wantCalls: 3,
+		},
+		{
+			name:      "position not reached",
+			query:     NewSearchQueryBuilder(ColumnsEvent).AwaitPosition(5).QueryTimeout(10 * time.Millisecond),
+			positions: []float64{0},
+			wantErr:   zerrors.IsDeadlineExceeded,
+		},
+		{
+			name:      "time travel",
+			query:     NewSearchQueryBuilder(ColumnsEvent).AwaitPosition(5).AllowTimeTravel(),
+			positions: []float64{5},
+			wantErr:   zerrors.IsPreconditionFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := &awaitTestQuerier{positions: tt.positions}
+			es := &Eventstore{querier: querier}
+			_, err := es.Filter(context.Background(), tt.query)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Eventstore.Filter() unexpected error = %v", err)
+			}
+			if tt.wantErr != nil {
+				if !tt.wantErr(err) {
+					t.Errorf("Eventstore.Filter() unexpected error = %v", err)
+				}
+				return
+			}
+			if querier.calls != tt.wantCalls {
+				t.Errorf("expected %d position checks, got %d", tt.wantCalls, querier.calls)
+			}
+		})
+	}
+}
+
+func TestInstancesToCheckSeal(t *testing.T) {
+	command := func(instanceID string, typ EventType) Command {
+		event := newTestEvent(instanceID, "", func() interface{} { return nil }, false)
+		event.BaseEvent.Agg.InstanceID = instanceID
+		event.BaseEvent.EventType = typ
+		return event
+	}
+	tests := []struct {
+		name string
+		cmds []Command
+		want []string
+	}{
+		{
+			name: "no instance",
+			cmds: []Command{command("", "test.event")},
+			want: []string{},
+		},
+		{
+			name: "unseal",
+			cmds: []Command{command("instance", InstanceUnsealedType)},
+			want: []string{},
+		},
+		{
+			name: "unseal with other commands",
+			cmds: []Command{command("instance", InstanceUnsealedType), command("instance", "test.event")},
+			want: []string{"instance"},
+		},
+		{
+			name: "multiple instances",
+			cmds: []Command{command("instance1", "test.event"), command("instance2", InstanceUnsealedType), command("instance3", "test.event"), command("instance1", "test.event")},
+			want: []string{"instance1", "instance3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InstancesToCheckSeal(tt.cmds); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InstancesToCheckSeal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventstore_checkSealed(t *testing.T) {
+	command := func(typ EventType) Command {
+		event := newTestEvent("instance", "", func() interface{} { return nil }, false)
+		event.BaseEvent.Agg.InstanceID = "instance"
+		event.BaseEvent.EventType = typ
+		return event
+	}
+	sealEvent := func(typ EventType) Event {
+		return &BaseEvent{EventType: typ, Agg: &Aggregate{ID: "instance", InstanceID: "instance", Type: instanceAggregateType}}
+	}
+	tests := []struct {
+		name    string
+		events  []Event
+		cmds    []Command
+		wantErr bool
+		queried bool
+	}{
+		{
+			name:    "never sealed",
+			cmds:    []Command{command("test.event")},
+			queried: true,
+		},
+		{
+			name:    "sealed",
+			events:  []Event{sealEvent(InstanceSealedType)},
+			cmds:    []Command{command("test.event")},
+			wantErr: true,
+			queried: true,
+		},
+		{
+			name:    "unsealed",
+			events:  []Event{sealEvent(InstanceUnsealedType)},
+			cmds:    []Command{command("test.event")},
+			queried: true,
+		},
+		{
+			name:   "unseal of sealed instance",
+			events: []Event{sealEvent(InstanceSealedType)},
+			cmds:   []Command{command(InstanceUnsealedType)},
+		},
+		{
+			name:    "unseal with other commands",
+			events:  []Event{sealEvent(InstanceSealedType)},
+			cmds:    []Command{command(InstanceUnsealedType), command("test.event")},
+			wantErr: true,
+			queried: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := &archiveTestQuerier{testQuerier: testQuerier{events: tt.events}}
+			es := &Eventstore{querier: querier}
+			err := es.checkSealed(context.Background(), tt.cmds)
+			if !tt.wantErr && err != nil {
+				t.Fatalf("Eventstore.checkSealed() unexpected error = %v", err)
+			}
+			if tt.wantErr && !zerrors.IsPreconditionFailed(err) {
+				t.Errorf("Eventstore.checkSealed() error = %v, want precondition failed", err)
+			}
+			if queried := len(querier.queries) > 0; queried != tt.queried {
+				t.Errorf("Eventstore.checkSealed() queried = %v, want %v", queried, tt.queried)
+			}
+			for _, query := range querier.queries {
+				if !query.GetDesc() || query.GetLimit() != 1 || query.GetInstanceID() == nil || *query.GetInstanceID() != "instance" {
+					t.Errorf("unexpected seal query %v", query)
+				}
+			}
+		})
+	}
+}
+
+// lagTestQuerier applies the positions, order and limit of the search query to its events
+type lagTestQuerier struct {
+	testQuerier
+}
+
+func (repo *lagTestQuerier) query(searchQuery *SearchQueryBuilder) []Event {
+	events := make([]Event, 0, len(repo.events))
+	for _, event := range repo.events {
+		if event.Position() <= searchQuery.GetPositionAfter() || event.Position() < searchQuery.GetPositionAtOrAfter() {
+			continue
+		}
+		events = append(events, event)
+	}
+	if searchQuery.GetDesc() {
+		slices.Reverse(events)
+	}
+	if searchQuery.GetLimit() > 0 && uint64(len(events)) > searchQuery.GetLimit() {
+		events = events[:searchQuery.GetLimit()]
+	}
+	return events
+}
+
+func (repo *lagTestQuerier) FilterToReducer(ctx context.Context, searchQuery *SearchQueryBuilder, reduce Reducer) error {
+	for _, event := range repo.query(searchQuery) {
+		if err := reduce(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *lagTestQuerier) LatestSequence(ctx context.Context, searchQuery *SearchQueryBuilder) (float64, error) {
+	if len(repo.events) == 0 {
+		return 0, nil
+	}
+	return repo.events[len(repo.events)-1].Position(), nil
+}
+
+func (repo *lagTestQuerier) Count(ctx context.Context, searchQuery *SearchQueryBuilder) (uint64, error) {
+	return uint64(len(repo.query(searchQuery))), nil
+}
+
+func TestEventstore_ProjectionLag(t *testing.T) {
+	now := time.Now()
+	event := func(position float64, createdAt time.Time) Event {
+		return &BaseEvent{Pos: position, Creation: createdAt, EventType: "test", Agg: &Aggregate{ID: "a"}}
+	}
+	events := []Event{
+		event(1, now.Add(-time.Hour)),
+		event(2, now.Add(-time.Minute)),
+		event(3, now),
+	}
+	tests := []struct {
+		name     string
+		position float64
+		want     *ProjectionLag
+	}{
+		{
+			name:     "up to date",
+			position: 3,
+			want:     &ProjectionLag{},
+		},
+		{
+			name:     "behind",
+			position: 1,
+			want:     &ProjectionLag{Events: 2, Delay: time.Hour},
+		},
+		{
+			name:     "never processed",
+			position: 0,
+			want:     &ProjectionLag{Events: 3, Delay: time.Hour},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := &Eventstore{querier: &lagTestQuerier{testQuerier: testQuerier{events: events}}}
+			query := NewSearchQueryBuilder(ColumnsEvent).InstanceID("instance").Limit(10)
+			got, err := es.ProjectionLag(authz.WithInstanceID(context.Background(), "instance"), tt.position, query)
+			if err != nil {
+				t.Fatalf("Eventstore.ProjectionLag() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Eventstore.ProjectionLag() = %+v, want %+v", got, tt.want)
+			}
+			if query.GetColumns() != ColumnsEvent || query.GetLimit() != 10 || query.GetDesc() || query.GetPositionAfter() != 0 {
+				t.Errorf("search query must be restored, got %v", query)
+			}
+		})
+	}
+}
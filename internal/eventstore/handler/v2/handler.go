@@ -651,7 +651,7 @@ func (h *Handler) eventQuery(currentState *state) *eventstore.SearchQueryBuilder
 		// decrease position by 10 because builder.PositionAfter filters for position > and we need position >=
 		builder = builder.PositionAfter(math.Float64frombits(math.Float64bits(currentState.position) - 10))
 		if currentState.offset > 0 {
-			builder = builder.Offset(currentState.offset)
+			builder = builder.Offset(uint64(currentState.offset))
 		}
 	}
 
@@ -0,0 +1,84 @@
+package eventstore
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// EventIterator streams the events of a search query, see [Eventstore.FilterIterator]
+type EventIterator interface {
+	// Next returns the next event of the query, [io.EOF] is returned after the last event
+	Next() (Event, error)
+	// Close stops the query and releases its database connection,
+	// it must be called if the iterator is not read until [io.EOF] and the context is not canceled
+	Close()
+}
+
+// FilterIterator queries the events of the search query and returns them one by one,
+// the events are read from the database cursor when [EventIterator.Next] is called
+// instead of loading all events into memory, e.g. to replay millions of events during a projection rebuild.
+// Filters, limit and ordering of the search query are honored,
+// [SearchQueryBuilder.LastEvents] buffers the limited amount of events to reverse their order.
+//
+// The query runs in the background and holds its database connection
+// until all events are read, [EventIterator.Close] is called or ctx is canceled.
+// If a transaction is set on the search query, it must not be committed before the iterator is done.
+func (es *Eventstore) FilterIterator(ctx context.Context, searchQuery *SearchQueryBuilder) (EventIterator, error) {
+	searchQuery.ensureInstanceID(ctx)
+	if err := searchQuery.Validate(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	iterator := &eventIterator{
+		events: make(chan Event),
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+	go func() {
+		defer close(iterator.done)
+		iterator.err = es.filterToReducer(ctx, searchQuery, func(event Event) error {
+			event, err := es.mapEvent(event)
+			if err != nil {
+				return err
+			}
+			select {
+			case iterator.events <- event:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+	return iterator, nil
+}
+
+type eventIterator struct {
+	events chan Event
+	// done is closed as soon as the query finished, err is set before
+	done      chan struct{}
+	err       error
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+}
+
+// Next implements [EventIterator]
+func (i *eventIterator) Next() (Event, error) {
+	select {
+	case event := <-i.events:
+		return event, nil
+	case <-i.done:
+		if i.err != nil {
+			return nil, i.err
+		}
+		return nil, io.EOF
+	}
+}
+
+// Close implements [EventIterator]
+func (i *eventIterator) Close() {
+	i.closeOnce.Do(func() {
+		i.cancel()
+		<-i.done
+	})
+}
@@ -51,14 +51,14 @@ func TestMain(m *testing.M) {
 	queriers["v2(inmemory)"] = v2
 	clients["v2(inmemory)"] = testCRDBClient
 
-	pushers["v3(inmemory)"] = new_es.NewEventstore(testCRDBClient)
+	pushers["v3(inmemory)"] = new_es.NewEventstore(testCRDBClient, "")
 	clients["v3(inmemory)"] = testCRDBClient
 
 	if localDB, err := connectLocalhost(); err == nil {
 		if err = initDB(localDB); err != nil {
 			logging.WithFields("error", err).Fatal("migrations failed")
 		}
-		pushers["v3(singlenode)"] = new_es.NewEventstore(localDB)
+		pushers["v3(singlenode)"] = new_es.NewEventstore(localDB, "")
 		clients["v3(singlenode)"] = localDB
 	}
 
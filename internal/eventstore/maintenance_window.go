@@ -0,0 +1,18 @@
+package eventstore
+
+import "context"
+
+type maintenanceWindowKey struct{}
+
+// WithMaintenanceWindow tags all events pushed with the returned context with the label of the maintenance window,
+// e.g. to report the changes of a release window using [SearchQueryBuilder.MaintenanceWindow].
+func WithMaintenanceWindow(ctx context.Context, label string) context.Context {
+	return context.WithValue(ctx, maintenanceWindowKey{}, label)
+}
+
+// MaintenanceWindowFromContext returns the label of the maintenance window of the context,
+// it's empty if no maintenance window was set.
+func MaintenanceWindowFromContext(ctx context.Context) string {
+	label, _ := ctx.Value(maintenanceWindowKey{}).(string)
+	return label
+}
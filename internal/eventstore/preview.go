@@ -0,0 +1,24 @@
+package eventstore
+
+import "time"
+
+// Preview maps the commands to the events they would create if they were pushed.
+// The events are never written to the eventstore, their sequence and position are not set.
+func (es *Eventstore) Preview(cmds ...Command) ([]Event, error) {
+	events := make([]Event, len(cmds))
+	for i, cmd := range cmds {
+		data, err := EventData(cmd)
+		if err != nil {
+			return nil, err
+		}
+		events[i] = &BaseEvent{
+			EventType: cmd.Type(),
+			Agg:       cmd.Aggregate(),
+			Creation:  time.Now(),
+			User:      cmd.Creator(),
+			Service:   defaultService,
+			Data:      data,
+		}
+	}
+	return es.mapEvents(events)
+}
@@ -0,0 +1,50 @@
+package eventstore
+
+import (
+	"context"
+	"time"
+)
+
+// ProjectionLag describes how far a projection is behind the events it handles
+type ProjectionLag struct {
+	// Events is the amount of events after the position of the projection
+	Events uint64
+	// Delay is the time between the creation of the event at the position of the projection and the latest event
+	Delay time.Duration
+}
+
+// ProjectionLag returns the lag of a projection at the position for the events of the search query,
+// e.g. to report it on a monitoring endpoint.
+// The columns, order, limit and positions of the search query are set by the lag queries and restored afterwards.
+func (es *Eventstore) ProjectionLag(ctx context.Context, position float64, searchQuery *SearchQueryBuilder) (_ *ProjectionLag, err error) {
+	columns, desc, limit := searchQuery.columns, searchQuery.desc, searchQuery.limit
+	positionAfter, positionAtOrAfter := searchQuery.positionAfter, searchQuery.positionAtOrAfter
+	defer func() {
+		searchQuery.columns, searchQuery.desc, searchQuery.limit = columns, desc, limit
+		searchQuery.positionAfter, searchQuery.positionAtOrAfter = positionAfter, positionAtOrAfter
+	}()
+
+	lag := new(ProjectionLag)
+	latest, err := es.LatestSequence(ctx, searchQuery.Columns(ColumnsMaxSequence))
+	if err != nil || latest <= position {
+		return lag, err
+	}
+	lag.Events, err = es.Count(ctx, searchQuery.Columns(ColumnsCount).PositionAfter(position))
+	if err != nil {
+		return nil, err
+	}
+
+	// the event at the position might not match the search query anymore, the next one is used instead
+	processed, err := es.Filter(ctx, searchQuery.Columns(ColumnsEvent).PositionAfter(0).PositionAtOrAfter(position).OrderAsc().Limit(1))
+	if err != nil {
+		return nil, err
+	}
+	newest, err := es.Filter(ctx, searchQuery.PositionAtOrAfter(0).OrderDesc().Limit(1))
+	if err != nil {
+		return nil, err
+	}
+	if len(processed) == 1 && len(newest) == 1 {
+		lag.Delay = newest[0].CreatedAt().Sub(processed[0].CreatedAt())
+	}
+	return lag, nil
+}
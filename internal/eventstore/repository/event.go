@@ -19,6 +19,9 @@ type Event struct {
 	Seq uint64
 	// Pos is the global sequence of the event multiple events can have the same sequence
 	Pos float64
+	// RelevanceScore is the rank of the event for the texts of the query
+	// it's only set if the events are ordered by relevance
+	RelevanceScore float64
 
 	//CreationDate is the time the event is created
 	// it's used for human readability.
@@ -55,6 +58,12 @@ type Event struct {
 	//InstanceID is the instance where this event belongs to
 	// use the ID of the instance
 	InstanceID string
+	// ProducerVersion is the version of ZITADEL which pushed the event
+	// it's only set if the origin of the events was queried
+	ProducerVersion sql.NullString
+	// MaintenanceWindow is the label of the maintenance window the event was pushed in
+	// it's only set if the origin of the events was queried
+	MaintenanceWindow sql.NullString
 
 	Constraints []*eventstore.UniqueConstraint
 }
@@ -95,11 +104,26 @@ func (e *Event) Position() float64 {
 	return e.Pos
 }
 
+// Relevance returns the rank of the event, it's read by [eventstore.EventRelevance]
+func (e *Event) Relevance() float64 {
+	return e.RelevanceScore
+}
+
 // CreatedAt implements [eventstore.Event]
 func (e *Event) CreatedAt() time.Time {
 	return e.CreationDate
 }
 
+// ProducedByVersion implements the origin of [eventstore.EventOrigin]
+func (e *Event) ProducedByVersion() string {
+	return e.ProducerVersion.String
+}
+
+// PushedInMaintenanceWindow implements the origin of [eventstore.EventOrigin]
+func (e *Event) PushedInMaintenanceWindow() string {
+	return e.MaintenanceWindow.String
+}
+
 // Unmarshal implements [eventstore.Event]
 func (e *Event) Unmarshal(ptr any) error {
 	if len(e.Data) == 0 {
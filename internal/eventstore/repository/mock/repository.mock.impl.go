@@ -75,6 +75,27 @@ func (m *MockRepository) ExpectInstanceIDsError(err error) *MockRepository {
 	return m
 }
 
+func (m *MockRepository) ExpectCount(count uint64) *MockRepository {
+	m.MockQuerier.ctrl.T.Helper()
+
+	m.MockQuerier.EXPECT().Count(gomock.Any(), gomock.Any()).Return(count, nil)
+	return m
+}
+
+func (m *MockRepository) ExpectCountError(err error) *MockRepository {
+	m.MockQuerier.ctrl.T.Helper()
+
+	m.MockQuerier.EXPECT().Count(gomock.Any(), gomock.Any()).Return(uint64(0), err)
+	return m
+}
+
+func (m *MockRepository) ExpectLatestSequence(position float64) *MockRepository {
+	m.MockQuerier.ctrl.T.Helper()
+
+	m.MockQuerier.EXPECT().LatestSequence(gomock.Any(), gomock.Any()).Return(position, nil)
+	return m
+}
+
 // ExpectPush checks if the expectedCommands are send to the Push method.
 // The call will sleep at least the amount of passed duration.
 func (m *MockRepository) ExpectPush(expectedCommands []eventstore.Command, sleep time.Duration) *MockRepository {
@@ -2,6 +2,11 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/eventstore"
@@ -14,21 +19,39 @@ type SearchQuery struct {
 
 	SubQueries            [][]*Filter
 	Tx                    *sql.Tx
+	ForUpdate             bool
 	AllowTimeTravel       bool
 	AwaitOpenTransactions bool
 	Limit                 uint64
-	Offset                uint32
+	Offset                uint64
 	Desc                  bool
+	AggregateIDsOrder     []string
+	OrderByEventType      bool
+	NullsOrder            eventstore.NullsOrder
+	// OptimizeForTenant is only set if the query is restricted to a single instance and an owner
+	OptimizeForTenant bool
+	// RelevanceText contains the texts of all sub queries the events are ranked by
+	RelevanceText string
+	// SubQueryLimits contains the limit of each sub query, it's nil if no sub query is limited
+	SubQueryLimits []uint64
+	// IncludeOrigin additionally selects the producer version and maintenance window of the events
+	IncludeOrigin bool
 
 	InstanceID        *Filter
 	InstanceIDs       *Filter
 	ExcludedInstances *Filter
 	Creator           *Filter
+	Creators          *Filter
 	Owner             *Filter
 	Position          *Filter
+	PositionAtOrAfter *Filter
 	Sequence          *Filter
 	CreatedAfter      *Filter
 	CreatedBefore     *Filter
+	ProducerVersion   *Filter
+	// ProducerVersionBefore contains the major, minor and patch version of the semantic version
+	ProducerVersionBefore *Filter
+	MaintenanceWindow     *Filter
 }
 
 // Filter represents all fields needed to compare a field of an event with a value
@@ -54,6 +77,23 @@ const (
 	OperationJSONContains
 	//OperationNotIn checks if a stored value does not match one of the passed value list
 	OperationNotIn
+	//OperationJSONKeyMissing checks if the passed key of a stored json is missing or null
+	OperationJSONKeyMissing
+	//OperationVersionLess checks if the stored semantic version is less than the given major, minor and patch version
+	OperationVersionLess
+	// OperationGreaterOrEquals compares if the stored value is greater than or equal to the given one
+	OperationGreaterOrEquals
+	// OperationTextSearch checks if the stored value matches all words of the given text using the full-text search
+	OperationTextSearch
+	// OperationInSet checks if a stored value is part of the set built from the passed value list,
+	// it's intended for large lists
+	OperationInSet
+	// OperationJSONPathEquals checks if the text of the stored json at the path of the passed [JSONPath] equals its value
+	OperationJSONPathEquals
+	// OperationLessOrEquals compares if the stored value is less than or equal to the given one
+	OperationLessOrEquals
+	// OperationNotEquals compares two values for inequality
+	OperationNotEquals
 
 	operationCount
 )
@@ -84,6 +124,12 @@ const (
 	FieldCreationDate
 	// FieldPosition represents the field of the global sequence
 	FieldPosition
+	// FieldProducerVersion represents the version of ZITADEL which pushed the event
+	FieldProducerVersion
+	// FieldMaintenanceWindow represents the label of the maintenance window the event was pushed in
+	FieldMaintenanceWindow
+	// FieldAggregateVersion represents the version of the aggregate, e.g. "v1"
+	FieldAggregateVersion
 
 	fieldCount
 )
@@ -119,6 +165,24 @@ func QueryFromBuilder(builder *eventstore.SearchQueryBuilder) (*SearchQuery, err
 		builder.GetColumns().Validate() != nil {
 		return nil, zerrors.ThrowPreconditionFailed(nil, "MODEL-4m9gs", "builder invalid")
 	}
+	if builder.GetForUpdate() && builder.GetTx() == nil {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "MODEL-Lx3kd", "for update requires a transaction")
+	}
+	if builder.GetForUpdate() && builder.GetColumns() != eventstore.ColumnsEvent {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "MODEL-Ow7fj", "for update is only allowed for events")
+	}
+	if builder.GetByteBudget() < 0 {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "MODEL-Qe5jn", "byte budget must not be negative")
+	}
+	if builder.GetByteBudget() > 0 && builder.GetColumns() != eventstore.ColumnsEvent {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "MODEL-Vt2wa", "byte budget is only allowed for events")
+	}
+	if builder.GetOrderByRelevance() && builder.GetColumns() != eventstore.ColumnsEvent {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "MODEL-Jn6rb", "order by relevance is only allowed for events")
+	}
+	if builder.GetIncludeOrigin() && builder.GetColumns() != eventstore.ColumnsEvent {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "MODEL-Or3gn", "origin is only allowed for events")
+	}
 
 	query := &SearchQuery{
 		Columns:               builder.GetColumns(),
@@ -126,8 +190,13 @@ func QueryFromBuilder(builder *eventstore.SearchQueryBuilder) (*SearchQuery, err
 		Offset:                builder.GetOffset(),
 		Desc:                  builder.GetDesc(),
 		Tx:                    builder.GetTx(),
+		ForUpdate:             builder.GetForUpdate(),
 		AllowTimeTravel:       builder.GetAllowTimeTravel(),
 		AwaitOpenTransactions: builder.GetAwaitOpenTransactions(),
+		AggregateIDsOrder:     builder.GetAggregateIDsOrder(),
+		OrderByEventType:      builder.GetOrderByEventTypeThenDate(),
+		NullsOrder:            builder.GetNullsOrder(),
+		IncludeOrigin:         builder.GetIncludeOrigin(),
 		SubQueries:            make([][]*Filter, len(builder.GetQueries())),
 	}
 
@@ -135,11 +204,16 @@ func QueryFromBuilder(builder *eventstore.SearchQueryBuilder) (*SearchQuery, err
 		instanceIDFilter,
 		instanceIDsFilter,
 		editorUserFilter,
+		editorUsersFilter,
 		resourceOwnerFilter,
 		positionAfterFilter,
+		positionAtOrAfterFilter,
 		eventSequenceGreaterFilter,
 		creationDateAfterFilter,
 		creationDateBeforeFilter,
+		producerVersionFilter,
+		producerVersionBeforeFilter,
+		maintenanceWindowFilter,
 	} {
 		filter := f(builder, query)
 		if filter == nil {
@@ -151,11 +225,24 @@ func QueryFromBuilder(builder *eventstore.SearchQueryBuilder) (*SearchQuery, err
 	}
 
 	for i, q := range builder.GetQueries() {
+		if q.GetLimit() > 0 {
+			if query.SubQueryLimits == nil {
+				query.SubQueryLimits = make([]uint64, len(builder.GetQueries()))
+			}
+			query.SubQueryLimits[i] = q.GetLimit()
+		}
 		for _, f := range []func(query *eventstore.SearchQuery) *Filter{
 			aggregateTypeFilter,
 			aggregateIDFilter,
+			excludedAggregateIDFilter,
+			aggregateVersionFilter,
 			eventTypeFilter,
+			excludedEventTypeFilter,
+			creatorsFilter,
 			eventDataFilter,
+			eventDataTextFilter,
+			sequenceGreaterFilter,
+			sequenceLessFilter,
 		} {
 			filter := f(q)
 			if filter == nil {
@@ -166,11 +253,51 @@ func QueryFromBuilder(builder *eventstore.SearchQueryBuilder) (*SearchQuery, err
 			}
 			query.SubQueries[i] = append(query.SubQueries[i], filter)
 		}
+		for _, filter := range eventDataPathFilters(q) {
+			if err := filter.Validate(); err != nil {
+				return nil, err
+			}
+			query.SubQueries[i] = append(query.SubQueries[i], filter)
+		}
+		for _, filter := range eventDataConditionFilters(q) {
+			if err := filter.Validate(); err != nil {
+				return nil, err
+			}
+			query.SubQueries[i] = append(query.SubQueries[i], filter)
+		}
+		for _, filter := range eventDataMissingKeysFilter(q) {
+			if err := filter.Validate(); err != nil {
+				return nil, err
+			}
+			query.SubQueries[i] = append(query.SubQueries[i], filter)
+		}
+	}
+	// the count covers all events matching the filters, not only the requested page
+	if query.Columns == eventstore.ColumnsCount {
+		query.Limit, query.Offset = 0, 0
+	}
+	query.OptimizeForTenant = builder.GetOptimizeForTenant() && query.InstanceID != nil && query.Owner != nil
+	if builder.GetOrderByRelevance() {
+		query.RelevanceText = relevanceText(builder)
+		if query.RelevanceText == "" {
+			return nil, zerrors.ThrowPreconditionFailed(nil, "MODEL-Ud8sz", "order by relevance requires a text")
+		}
 	}
 
 	return query, nil
 }
 
+// relevanceText joins the texts of all sub queries
+func relevanceText(builder *eventstore.SearchQueryBuilder) string {
+	texts := make([]string, 0, len(builder.GetQueries()))
+	for _, q := range builder.GetQueries() {
+		if q.GetEventDataText() != "" {
+			texts = append(texts, q.GetEventDataText())
+		}
+	}
+	return strings.Join(texts, " ")
+}
+
 func eventSequenceGreaterFilter(builder *eventstore.SearchQueryBuilder, query *SearchQuery) *Filter {
 	if builder.GetEventSequenceGreater() == 0 {
 		return nil
@@ -200,10 +327,14 @@ func creationDateBeforeFilter(builder *eventstore.SearchQueryBuilder, query *Sea
 }
 
 func resourceOwnerFilter(builder *eventstore.SearchQueryBuilder, query *SearchQuery) *Filter {
-	if builder.GetResourceOwner() == "" {
+	switch len(builder.GetResourceOwners()) {
+	case 0:
 		return nil
+	case 1:
+		query.Owner = NewFilter(FieldResourceOwner, builder.GetResourceOwners()[0], OperationEquals)
+	default:
+		query.Owner = NewFilter(FieldResourceOwner, database.TextArray[string](builder.GetResourceOwners()), OperationIn)
 	}
-	query.Owner = NewFilter(FieldResourceOwner, builder.GetResourceOwner(), OperationEquals)
 	return query.Owner
 }
 
@@ -215,6 +346,64 @@ func editorUserFilter(builder *eventstore.SearchQueryBuilder, query *SearchQuery
 	return query.Creator
 }
 
+func editorUsersFilter(builder *eventstore.SearchQueryBuilder, query *SearchQuery) *Filter {
+	if len(builder.GetEditorUsers()) == 0 {
+		return nil
+	}
+	query.Creators = NewFilter(FieldEditorUser, database.TextArray[string](builder.GetEditorUsers()), OperationIn)
+	return query.Creators
+}
+
+func producerVersionFilter(builder *eventstore.SearchQueryBuilder, query *SearchQuery) *Filter {
+	if builder.GetProducedByVersion() == "" {
+		return nil
+	}
+	query.ProducerVersion = NewFilter(FieldProducerVersion, builder.GetProducedByVersion(), OperationEquals)
+	return query.ProducerVersion
+}
+
+func maintenanceWindowFilter(builder *eventstore.SearchQueryBuilder, query *SearchQuery) *Filter {
+	if builder.GetMaintenanceWindow() == "" {
+		return nil
+	}
+	query.MaintenanceWindow = NewFilter(FieldMaintenanceWindow, builder.GetMaintenanceWindow(), OperationEquals)
+	return query.MaintenanceWindow
+}
+
+func producerVersionBeforeFilter(builder *eventstore.SearchQueryBuilder, query *SearchQuery) *Filter {
+	if builder.GetProducedByVersionBefore() == "" {
+		return nil
+	}
+	query.ProducerVersionBefore = NewFilter(FieldProducerVersion, nil, OperationVersionLess)
+	// an invalid version leaves the value empty which fails the validation of the filter
+	if version := semanticVersion(builder.GetProducedByVersionBefore()); version != nil {
+		query.ProducerVersionBefore.Value = database.NumberArray[uint64](version)
+	}
+	return query.ProducerVersionBefore
+}
+
+// semanticVersion returns the major, minor and patch version of a semantic version with an optional v prefix.
+// Pre-release and build metadata are ignored.
+func semanticVersion(version string) []uint64 {
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+	numbers := make([]uint64, len(parts))
+	for i, part := range parts {
+		number, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil
+		}
+		numbers[i] = number
+	}
+	return numbers
+}
+
 func instanceIDFilter(builder *eventstore.SearchQueryBuilder, query *SearchQuery) *Filter {
 	if builder.GetInstanceID() == nil {
 		return nil
@@ -239,6 +428,14 @@ func positionAfterFilter(builder *eventstore.SearchQueryBuilder, query *SearchQu
 	return query.Position
 }
 
+func positionAtOrAfterFilter(builder *eventstore.SearchQueryBuilder, query *SearchQuery) *Filter {
+	if builder.GetPositionAtOrAfter() == 0 {
+		return nil
+	}
+	query.PositionAtOrAfter = NewFilter(FieldPosition, builder.GetPositionAtOrAfter(), OperationGreaterOrEquals)
+	return query.PositionAtOrAfter
+}
+
 func aggregateIDFilter(query *eventstore.SearchQuery) *Filter {
 	if len(query.GetAggregateIDs()) < 1 {
 		return nil
@@ -246,9 +443,29 @@ func aggregateIDFilter(query *eventstore.SearchQuery) *Filter {
 	if len(query.GetAggregateIDs()) == 1 {
 		return NewFilter(FieldAggregateID, query.GetAggregateIDs()[0], OperationEquals)
 	}
+	if query.GetLargeAggregateIDs() && len(query.GetAggregateIDs()) > eventstore.LargeAggregateIDsThreshold {
+		return NewFilter(FieldAggregateID, database.TextArray[string](query.GetAggregateIDs()), OperationInSet)
+	}
 	return NewFilter(FieldAggregateID, database.TextArray[string](query.GetAggregateIDs()), OperationIn)
 }
 
+func aggregateVersionFilter(query *eventstore.SearchQuery) *Filter {
+	if len(query.GetAggregateVersions()) < 1 {
+		return nil
+	}
+	if len(query.GetAggregateVersions()) == 1 {
+		return NewFilter(FieldAggregateVersion, query.GetAggregateVersions()[0], OperationEquals)
+	}
+	return NewFilter(FieldAggregateVersion, database.TextArray[eventstore.Version](query.GetAggregateVersions()), OperationIn)
+}
+
+func excludedAggregateIDFilter(query *eventstore.SearchQuery) *Filter {
+	if len(query.GetExcludedAggregateIDs()) < 1 {
+		return nil
+	}
+	return NewFilter(FieldAggregateID, database.TextArray[string](query.GetExcludedAggregateIDs()), OperationNotIn)
+}
+
 func eventTypeFilter(query *eventstore.SearchQuery) *Filter {
 	if len(query.GetEventTypes()) < 1 {
 		return nil
@@ -259,6 +476,23 @@ func eventTypeFilter(query *eventstore.SearchQuery) *Filter {
 	return NewFilter(FieldEventType, database.TextArray[eventstore.EventType](query.GetEventTypes()), OperationIn)
 }
 
+func excludedEventTypeFilter(query *eventstore.SearchQuery) *Filter {
+	if len(query.GetExcludeEventTypes()) < 1 {
+		return nil
+	}
+	return NewFilter(FieldEventType, database.TextArray[eventstore.EventType](query.GetExcludeEventTypes()), OperationNotIn)
+}
+
+func creatorsFilter(query *eventstore.SearchQuery) *Filter {
+	if len(query.GetCreators()) < 1 {
+		return nil
+	}
+	if len(query.GetCreators()) == 1 {
+		return NewFilter(FieldEditorUser, query.GetCreators()[0], OperationEquals)
+	}
+	return NewFilter(FieldEditorUser, database.TextArray[string](query.GetCreators()), OperationIn)
+}
+
 func aggregateTypeFilter(query *eventstore.SearchQuery) *Filter {
 	if len(query.GetAggregateTypes()) < 1 {
 		return nil
@@ -269,9 +503,115 @@ func aggregateTypeFilter(query *eventstore.SearchQuery) *Filter {
 	return NewFilter(FieldAggregateType, database.TextArray[eventstore.AggregateType](query.GetAggregateTypes()), OperationIn)
 }
 
+// JSONPath is the value of an [OperationJSONPathEquals] filter
+type JSONPath struct {
+	Path database.TextArray[string]
+	// Value is compared to the text of the json at the path
+	Value string
+}
+
+// eventDataFilter filters the top-level keys of the event data, nested paths are filtered by [eventDataPathFilters]
 func eventDataFilter(query *eventstore.SearchQuery) *Filter {
-	if len(query.GetEventData()) == 0 {
+	data := make(map[string]interface{}, len(query.GetEventData()))
+	for key, value := range query.GetEventData() {
+		if !strings.Contains(key, eventstore.EventDataPathSeparator) {
+			data[key] = value
+		}
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return NewFilter(FieldEventData, data, OperationJSONContains)
+}
+
+// eventDataPathFilters filters the keys of the event data which are paths to nested fields
+func eventDataPathFilters(query *eventstore.SearchQuery) []*Filter {
+	paths := make([]string, 0, len(query.GetEventData()))
+	for key := range query.GetEventData() {
+		if strings.Contains(key, eventstore.EventDataPathSeparator) {
+			paths = append(paths, key)
+		}
+	}
+	// sorted to build the same statement for the same query
+	slices.Sort(paths)
+	filters := make([]*Filter, len(paths))
+	for i, path := range paths {
+		filters[i] = NewFilter(FieldEventData, JSONPath{
+			Path:  strings.Split(path, eventstore.EventDataPathSeparator),
+			Value: jsonPathValue(query.GetEventData()[path]),
+		}, OperationJSONPathEquals)
+	}
+	return filters
+}
+
+// jsonPathValue returns the value as the text returned by the #>> operator
+func jsonPathValue(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// JSONPathComparison is the value of a filter comparing the json of the event data at the path with the json of the value,
+// the operation of the filter defines the comparison
+type JSONPathComparison struct {
+	Path database.TextArray[string]
+	// Value is the marshaled json compared to the json at the path
+	Value string
+}
+
+var eventDataOperations = map[eventstore.EventDataOperator]Operation{
+	eventstore.EventDataEquals:          OperationEquals,
+	eventstore.EventDataNotEquals:       OperationNotEquals,
+	eventstore.EventDataGreater:         OperationGreater,
+	eventstore.EventDataGreaterOrEquals: OperationGreaterOrEquals,
+	eventstore.EventDataLess:            OperationLess,
+	eventstore.EventDataLessOrEquals:    OperationLessOrEquals,
+}
+
+// eventDataConditionFilters compares the event data with the conditions of [eventstore.SearchQuery.EventDataWhere]
+func eventDataConditionFilters(query *eventstore.SearchQuery) []*Filter {
+	filters := make([]*Filter, len(query.GetEventDataConditions()))
+	for i, condition := range query.GetEventDataConditions() {
+		// invalid values are rejected by the validation of the builder
+		value, _ := json.Marshal(condition.Value)
+		filters[i] = NewFilter(FieldEventData, JSONPathComparison{
+			Path:  strings.Split(condition.Field, eventstore.EventDataPathSeparator),
+			Value: string(value),
+		}, eventDataOperations[condition.Operator])
+	}
+	return filters
+}
+
+func eventDataTextFilter(query *eventstore.SearchQuery) *Filter {
+	if query.GetEventDataText() == "" {
+		return nil
+	}
+	return NewFilter(FieldEventData, query.GetEventDataText(), OperationTextSearch)
+}
+
+func sequenceGreaterFilter(query *eventstore.SearchQuery) *Filter {
+	if query.GetSequenceGreater() == 0 {
 		return nil
 	}
-	return NewFilter(FieldEventData, query.GetEventData(), OperationJSONContains)
+	return NewFilter(FieldSequence, query.GetSequenceGreater(), OperationGreater)
+}
+
+func sequenceLessFilter(query *eventstore.SearchQuery) *Filter {
+	if query.GetSequenceLess() == 0 {
+		return nil
+	}
+	return NewFilter(FieldSequence, query.GetSequenceLess(), OperationLess)
+}
+
+func eventDataMissingKeysFilter(query *eventstore.SearchQuery) []*Filter {
+	filters := make([]*Filter, len(query.GetEventDataMissingKeys()))
+	for i, key := range query.GetEventDataMissingKeys() {
+		filters[i] = NewFilter(FieldEventData, key, OperationJSONKeyMissing)
+	}
+	return filters
 }
@@ -2,9 +2,12 @@ package repository
 
 import (
 	"reflect"
+	"strconv"
 	"testing"
 
+	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
 func TestNewFilter(t *testing.T) {
@@ -144,3 +147,423 @@ func TestColumns_Validate(t *testing.T) {
 		})
 	}
 }
+
+func Test_semanticVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    []uint64
+	}{
+		{version: "v2.54.3", want: []uint64{2, 54, 3}},
+		{version: "2.54.3", want: []uint64{2, 54, 3}},
+		{version: "v2.54.3-rc.1", want: []uint64{2, 54, 3}},
+		{version: "v2.54.3+build.5", want: []uint64{2, 54, 3}},
+		{version: "v2.54", want: nil},
+		{version: "v2.x.3", want: nil},
+		{version: "2024-06-01T10:00:00Z", want: nil},
+		{version: "", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := semanticVersion(tt.version); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("semanticVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryFromBuilder_producerVersion(t *testing.T) {
+	query, err := QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		ProducedByVersion("v2.54.3").
+		ProducedByVersionBefore("v2.55.0"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := NewFilter(FieldProducerVersion, "v2.54.3", OperationEquals); !reflect.DeepEqual(query.ProducerVersion, want) {
+		t.Errorf("wrong producer version filter: got: %v want: %v", query.ProducerVersion, want)
+	}
+	if want := NewFilter(FieldProducerVersion, database.NumberArray[uint64]{2, 55, 0}, OperationVersionLess); !reflect.DeepEqual(query.ProducerVersionBefore, want) {
+		t.Errorf("wrong producer version before filter: got: %v want: %v", query.ProducerVersionBefore, want)
+	}
+
+	_, err = QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		ProducedByVersionBefore("latest"),
+	)
+	if err == nil {
+		t.Error("expected error for invalid version")
+	}
+}
+
+func TestQueryFromBuilder_maintenanceWindow(t *testing.T) {
+	query, err := QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		MaintenanceWindow("upgrade-2024-06"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := NewFilter(FieldMaintenanceWindow, "upgrade-2024-06", OperationEquals); !reflect.DeepEqual(query.MaintenanceWindow, want) {
+		t.Errorf("wrong maintenance window filter: got: %v want: %v", query.MaintenanceWindow, want)
+	}
+
+	query, err = QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query.MaintenanceWindow != nil {
+		t.Errorf("unexpected maintenance window filter: %v", query.MaintenanceWindow)
+	}
+}
+
+func TestQueryFromBuilder_optimizeForTenant(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *eventstore.SearchQueryBuilder
+		want    bool
+	}{
+		{
+			name:    "not requested",
+			builder: eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).InstanceID("instance").ResourceOwner("org"),
+			want:    false,
+		},
+		{
+			name:    "without owner",
+			builder: eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).InstanceID("instance").OptimizeForTenant(),
+			want:    false,
+		},
+		{
+			name:    "multiple instances",
+			builder: eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).InstanceIDs("instance1", "instance2").ResourceOwner("org").OptimizeForTenant(),
+			want:    false,
+		},
+		{
+			name:    "instance and owner",
+			builder: eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).InstanceID("instance").ResourceOwner("org").OptimizeForTenant(),
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := QueryFromBuilder(tt.builder)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if query.OptimizeForTenant != tt.want {
+				t.Errorf("wrong optimize for tenant: got: %v want: %v", query.OptimizeForTenant, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryFromBuilder_editorUsers(t *testing.T) {
+	query, err := QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		EditorUser("user1").
+		EditorUsers("user1", "user2"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := NewFilter(FieldEditorUser, "user1", OperationEquals); !reflect.DeepEqual(query.Creator, want) {
+		t.Errorf("wrong editor user filter: got: %v want: %v", query.Creator, want)
+	}
+	if want := NewFilter(FieldEditorUser, database.TextArray[string]{"user1", "user2"}, OperationIn); !reflect.DeepEqual(query.Creators, want) {
+		t.Errorf("wrong editor users filter: got: %v want: %v", query.Creators, want)
+	}
+}
+
+func TestQueryFromBuilder_position(t *testing.T) {
+	query, err := QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		PositionAfter(1.5).
+		PositionAtOrAfter(2.5),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := NewFilter(FieldPosition, 1.5, OperationGreater); !reflect.DeepEqual(query.Position, want) {
+		t.Errorf("wrong position after filter: got: %v want: %v", query.Position, want)
+	}
+	if want := NewFilter(FieldPosition, 2.5, OperationGreaterOrEquals); !reflect.DeepEqual(query.PositionAtOrAfter, want) {
+		t.Errorf("wrong position at or after filter: got: %v want: %v", query.PositionAtOrAfter, want)
+	}
+}
+
+func TestQueryFromBuilder_countIgnoresPage(t *testing.T) {
+	query, err := QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsCount).
+		Limit(10).
+		Offset(20),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query.Limit != 0 || query.Offset != 0 {
+		t.Errorf("page must be ignored for count: got limit %d offset %d", query.Limit, query.Offset)
+	}
+
+	query, err = QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		Limit(10).
+		Offset(20),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query.Limit != 10 || query.Offset != 20 {
+		t.Errorf("page must be kept for events: got limit %d offset %d", query.Limit, query.Offset)
+	}
+}
+
+func TestQueryFromBuilder_relevance(t *testing.T) {
+	query, err := QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		OrderByRelevance().
+		AddQuery().
+		EventDataText("alice").
+		Or().
+		AggregateTypes("user").
+		Or().
+		EventDataText("admin").
+		Builder(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []*Filter{NewFilter(FieldEventData, "alice", OperationTextSearch)}; !reflect.DeepEqual(query.SubQueries[0], want) {
+		t.Errorf("wrong text filter: got: %v want: %v", query.SubQueries[0], want)
+	}
+	if want := "alice admin"; query.RelevanceText != want {
+		t.Errorf("wrong relevance text: got: %q want: %q", query.RelevanceText, want)
+	}
+
+	_, err = QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		OrderByRelevance().
+		AddQuery().
+		AggregateTypes("user").
+		Builder(),
+	)
+	if !zerrors.IsPreconditionFailed(err) {
+		t.Errorf("expected precondition failed without text, got: %v", err)
+	}
+}
+
+func TestQueryFromBuilder_subQueries(t *testing.T) {
+	query, err := QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		OrderDesc().
+		AddQuery().
+		AggregateTypes("user").
+		AggregateVersions("v1").
+		SequenceGreater(5).
+		Or().
+		AggregateTypes("org").
+		SequenceGreater(2).
+		SequenceLess(10).
+		Builder(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := [][]*Filter{
+		{
+			NewFilter(FieldAggregateType, eventstore.AggregateType("user"), OperationEquals),
+			NewFilter(FieldAggregateVersion, eventstore.Version("v1"), OperationEquals),
+			NewFilter(FieldSequence, uint64(5), OperationGreater),
+		},
+		{
+			NewFilter(FieldAggregateType, eventstore.AggregateType("org"), OperationEquals),
+			NewFilter(FieldSequence, uint64(2), OperationGreater),
+			NewFilter(FieldSequence, uint64(10), OperationLess),
+		},
+	}
+	if !reflect.DeepEqual(query.SubQueries, want) {
+		t.Errorf("wrong sub queries: got: %v want: %v", query.SubQueries, want)
+	}
+}
+
+func TestQueryFromBuilder_eventDataPaths(t *testing.T) {
+	query, err := QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		EventData(map[string]interface{}{
+			"userId":                "user",
+			"user.profile.language": "de",
+			"user.verified":         true,
+		}).
+		Builder(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []*Filter{
+		NewFilter(FieldEventData, map[string]interface{}{"userId": "user"}, OperationJSONContains),
+		NewFilter(FieldEventData, JSONPath{Path: []string{"user", "profile", "language"}, Value: "de"}, OperationJSONPathEquals),
+		NewFilter(FieldEventData, JSONPath{Path: []string{"user", "verified"}, Value: "true"}, OperationJSONPathEquals),
+	}
+	if !reflect.DeepEqual(query.SubQueries[0], want) {
+		t.Errorf("wrong event data filters: got: %v want: %v", query.SubQueries[0], want)
+	}
+}
+
+func TestQueryFromBuilder_largeAggregateIDs(t *testing.T) {
+	ids := make([]string, eventstore.LargeAggregateIDsThreshold+1)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i)
+	}
+	tests := []struct {
+		name  string
+		query func(*eventstore.SearchQuery) *eventstore.SearchQuery
+		want  *Filter
+	}{
+		{
+			name:  "below threshold",
+			query: func(q *eventstore.SearchQuery) *eventstore.SearchQuery { return q.AggregateIDsLarge(ids[:2]) },
+			want:  NewFilter(FieldAggregateID, database.TextArray[string](ids[:2]), OperationIn),
+		},
+		{
+			name:  "above threshold",
+			query: func(q *eventstore.SearchQuery) *eventstore.SearchQuery { return q.AggregateIDsLarge(ids) },
+			want:  NewFilter(FieldAggregateID, database.TextArray[string](ids), OperationInSet),
+		},
+		{
+			name:  "above threshold without large option",
+			query: func(q *eventstore.SearchQuery) *eventstore.SearchQuery { return q.AggregateIDs(ids...) },
+			want:  NewFilter(FieldAggregateID, database.TextArray[string](ids), OperationIn),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := QueryFromBuilder(tt.query(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).AddQuery()).Builder())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(query.SubQueries[0][0], tt.want) {
+				t.Errorf("wrong aggregate id filter: got: %v want: %v", query.SubQueries[0][0].Operation, tt.want.Operation)
+			}
+		})
+	}
+}
+
+func TestQueryFromBuilder_excludedAggregateIDs(t *testing.T) {
+	query, err := QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		AggregateIDs("1", "2").
+		ExcludeAggregateIDs("2").
+		Or().
+		ExcludeAggregateIDs().
+		AggregateTypes("user").
+		Builder(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []*Filter{
+		NewFilter(FieldAggregateID, database.TextArray[string]{"1", "2"}, OperationIn),
+		NewFilter(FieldAggregateID, database.TextArray[string]{"2"}, OperationNotIn),
+	}
+	if !reflect.DeepEqual(query.SubQueries[0], want) {
+		t.Errorf("wrong aggregate id filters: got: %v want: %v", query.SubQueries[0], want)
+	}
+	if want := []*Filter{NewFilter(FieldAggregateType, eventstore.AggregateType("user"), OperationEquals)}; !reflect.DeepEqual(query.SubQueries[1], want) {
+		t.Errorf("empty exclusion must not add a filter: got: %v want: %v", query.SubQueries[1], want)
+	}
+}
+
+func TestQueryFromBuilder_excludedEventTypes(t *testing.T) {
+	query, err := QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		EventTypes("user.added", "user.token.added").
+		ExcludeEventTypes("user.token.added").
+		Builder(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []*Filter{
+		NewFilter(FieldEventType, database.TextArray[eventstore.EventType]{"user.added", "user.token.added"}, OperationIn),
+		NewFilter(FieldEventType, database.TextArray[eventstore.EventType]{"user.token.added"}, OperationNotIn),
+	}
+	if !reflect.DeepEqual(query.SubQueries[0], want) {
+		t.Errorf("wrong event type filters: got: %v want: %v", query.SubQueries[0], want)
+	}
+}
+
+func TestQueryFromBuilder_creators(t *testing.T) {
+	query, err := QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		AggregateTypes("user").
+		Creators("system").
+		Or().
+		AggregateTypes("user").
+		Creators("user1", "user2").
+		Builder(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := NewFilter(FieldEditorUser, "system", OperationEquals); !reflect.DeepEqual(query.SubQueries[0][1], want) {
+		t.Errorf("wrong creator filter: got: %v want: %v", query.SubQueries[0][1], want)
+	}
+	if want := NewFilter(FieldEditorUser, database.TextArray[string]{"user1", "user2"}, OperationIn); !reflect.DeepEqual(query.SubQueries[1][1], want) {
+		t.Errorf("wrong creators filter: got: %v want: %v", query.SubQueries[1][1], want)
+	}
+}
+
+func TestQueryFromBuilder_eventDataConditions(t *testing.T) {
+	query, err := QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		AggregateTypes("order").
+		EventDataWhere(
+			eventstore.EventDataCondition{Field: "amount", Operator: eventstore.EventDataGreater, Value: 100},
+			eventstore.EventDataCondition{Field: "customer.country", Operator: eventstore.EventDataNotEquals, Value: "CH"},
+		).
+		Builder(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []*Filter{
+		NewFilter(FieldAggregateType, eventstore.AggregateType("order"), OperationEquals),
+		NewFilter(FieldEventData, JSONPathComparison{Path: []string{"amount"}, Value: "100"}, OperationGreater),
+		NewFilter(FieldEventData, JSONPathComparison{Path: []string{"customer", "country"}, Value: `"CH"`}, OperationNotEquals),
+	}
+	if !reflect.DeepEqual(query.SubQueries[0], want) {
+		t.Errorf("wrong event data condition filters: got: %v want: %v", query.SubQueries[0], want)
+	}
+}
+
+func TestQueryFromBuilder_subQueryLimits(t *testing.T) {
+	query, err := QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AddQuery().
+		AggregateTypes("user").
+		Or().
+		AggregateTypes("org").
+		Limit(5).
+		Builder(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []uint64{0, 5}; !reflect.DeepEqual(query.SubQueryLimits, want) {
+		t.Errorf("wrong sub query limits: got: %v want: %v", query.SubQueryLimits, want)
+	}
+
+	query, err = QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).AggregateTypes("user", "org"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query.SubQueryLimits != nil {
+		t.Errorf("unexpected sub query limits: %v", query.SubQueryLimits)
+	}
+}
+
+func TestQueryFromBuilder_resourceOwners(t *testing.T) {
+	query, err := QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).ResourceOwners("org1", "org2"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := NewFilter(FieldResourceOwner, database.TextArray[string]{"org1", "org2"}, OperationIn); !reflect.DeepEqual(query.Owner, want) {
+		t.Errorf("wrong owner filter: got: %v want: %v", query.Owner, want)
+	}
+
+	query, err = QueryFromBuilder(eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).ResourceOwner("org1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := NewFilter(FieldResourceOwner, "org1", OperationEquals); !reflect.DeepEqual(query.Owner, want) {
+		t.Errorf("wrong owner filter: got: %v want: %v", query.Owner, want)
+	}
+}
@@ -0,0 +1,50 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// aggregateIDsWithoutSubsequentEventStmt is an anti join of the start events against the completion events of the same aggregate,
+// both sides are covered by the es_wm index
+const aggregateIDsWithoutSubsequentEventStmt = "SELECT DISTINCT s.aggregate_id FROM eventstore.events2 s" +
+	" WHERE s.instance_id = $1 AND s.aggregate_type = $2 AND s.event_type = $3 AND s.aggregate_id > $4" +
+	" AND NOT EXISTS (SELECT 1 FROM eventstore.events2 c" +
+	" WHERE c.instance_id = s.instance_id" +
+	" AND c.aggregate_type = s.aggregate_type" +
+	" AND c.aggregate_id = s.aggregate_id" +
+	" AND c.event_type = $5" +
+	` AND c."sequence" > s."sequence")` +
+	" ORDER BY s.aggregate_id"
+
+// AggregateIDsWithoutSubsequentEvent implements [eventstore.Eventstore.AggregateIDsWithoutSubsequentEvent]
+func (db *CRDB) AggregateIDsWithoutSubsequentEvent(ctx context.Context, query *eventstore.AbsentEventQuery) (ids []string, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	stmt := aggregateIDsWithoutSubsequentEventStmt
+	args := []any{query.InstanceID, query.AggregateType, query.StartEventType, query.AfterAggregateID, query.CompletionEventType}
+	if query.Limit > 0 {
+		stmt += " LIMIT $6"
+		args = append(args, query.Limit)
+	}
+	err = db.DB.QueryContext(ctx,
+		func(rows *sql.Rows) error {
+			for rows.Next() {
+				var id string
+				if err := rows.Scan(&id); err != nil {
+					return err
+				}
+				ids = append(ids, id)
+			}
+			return nil
+		}, stmt, args...)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "SQL-Ab2ke", "unable to query aggregates without subsequent event")
+	}
+	return ids, nil
+}
@@ -0,0 +1,35 @@
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+func TestCRDB_AggregateIDsWithoutSubsequentEvent(t *testing.T) {
+	mock := newMockClient(t)
+	mock.mock.ExpectBegin()
+	mock.mock.ExpectQuery(`SELECT DISTINCT s.aggregate_id FROM eventstore.events2 s WHERE s.instance_id = \$1 AND s.aggregate_type = \$2 AND s.event_type = \$3 AND s.aggregate_id > \$4 AND NOT EXISTS \(SELECT 1 FROM eventstore.events2 c WHERE c.instance_id = s.instance_id AND c.aggregate_type = s.aggregate_type AND c.aggregate_id = s.aggregate_id AND c.event_type = \$5 AND c."sequence" > s."sequence"\) ORDER BY s.aggregate_id LIMIT \$6`).
+		WithArgs("instance", eventstore.AggregateType("user"), eventstore.EventType("user.human.mfa.otp.added"), "user1", eventstore.EventType("user.human.mfa.otp.verified"), uint64(2)).
+		WillReturnRows(mock.mock.NewRows([]string{"aggregate_id"}).AddRow("user2").AddRow("user5"))
+	mock.mock.ExpectCommit()
+	crdb := NewCRDB(&database.DB{Database: new(testDB)})
+	crdb.DB.DB = mock.client
+
+	ids, err := crdb.AggregateIDsWithoutSubsequentEvent(context.Background(), &eventstore.AbsentEventQuery{
+		InstanceID:          "instance",
+		AggregateType:       "user",
+		StartEventType:      "user.human.mfa.otp.added",
+		CompletionEventType: "user.human.mfa.otp.verified",
+		AfterAggregateID:    "user1",
+		Limit:               2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user2", "user5"}, ids)
+	assert.NoError(t, mock.mock.ExpectationsWereMet())
+}
@@ -272,6 +272,12 @@ func (db *CRDB) LatestSequence(ctx context.Context, searchQuery *eventstore.Sear
 	return position.Float64, err
 }
 
+// Count returns the amount of events found by the search query
+func (db *CRDB) Count(ctx context.Context, searchQuery *eventstore.SearchQueryBuilder) (count uint64, err error) {
+	err = query(ctx, db, searchQuery, &count, false)
+	return count, err
+}
+
 // InstanceIDs returns the instance ids found by the search query
 func (db *CRDB) InstanceIDs(ctx context.Context, searchQuery *eventstore.SearchQueryBuilder) ([]string, error) {
 	var ids []string
@@ -300,10 +306,18 @@ func (db *CRDB) orderByEventSequence(desc, shouldOrderBySequence, useV1 bool) st
 		return ` ORDER BY "sequence"`
 	}
 
+	return " ORDER BY " + EventOrder(desc)
+}
+
+// EventOrder returns the columns used to order events if the query does not define a more specific order.
+// Besides the position, the order contains tiebreakers (in_tx_order, aggregate_id and sequence)
+// so that events with the same position are returned in the same order
+// regardless of which node or replica served the query.
+func EventOrder(desc bool) string {
 	if desc {
-		return ` ORDER BY "position" DESC, in_tx_order DESC`
+		return `"position" DESC, in_tx_order DESC, aggregate_id DESC, "sequence" DESC`
 	}
-	return ` ORDER BY "position", in_tx_order`
+	return `"position", in_tx_order, aggregate_id, "sequence"`
 }
 
 func (db *CRDB) eventQuery(useV1 bool) string {
@@ -343,6 +357,21 @@ func (db *CRDB) maxSequenceQuery(useV1 bool) string {
 	return `SELECT "position" FROM eventstore.events2`
 }
 
+// maxPositionQuery is not supported by the events of v1, they don't have a position
+func (db *CRDB) maxPositionQuery(useV1 bool) string {
+	if useV1 {
+		return ""
+	}
+	return `SELECT "position" FROM eventstore.events2`
+}
+
+func (db *CRDB) countQuery(useV1 bool) string {
+	if useV1 {
+		return "SELECT COUNT(*) FROM eventstore.events"
+	}
+	return "SELECT COUNT(*) FROM eventstore.events2"
+}
+
 func (db *CRDB) instanceIDsQuery(useV1 bool) string {
 	table := "eventstore.events2"
 	if useV1 {
@@ -393,6 +422,22 @@ func (db *CRDB) columnName(col repository.Field, useV1 bool) string {
 		return "created_at"
 	case repository.FieldPosition:
 		return `"position"`
+	case repository.FieldProducerVersion:
+		if useV1 {
+			return ""
+		}
+		return "producer_version"
+	case repository.FieldMaintenanceWindow:
+		if useV1 {
+			return ""
+		}
+		return "maintenance_window"
+	case repository.FieldAggregateVersion:
+		if useV1 {
+			return "aggregate_version"
+		}
+		// the revision is stored as number, the version is mapped the same way as in [eventsScanner]
+		return "('v' || revision::TEXT)"
 	default:
 		return ""
 	}
@@ -404,22 +449,43 @@ func (db *CRDB) conditionFormat(operation repository.Operation) string {
 		return "%s %s ANY(?)"
 	case repository.OperationNotIn:
 		return "%s %s ALL(?)"
+	case repository.OperationInSet:
+		return "%s %s (SELECT unnest(?::TEXT[]))"
+	case repository.OperationJSONKeyMissing:
+		return "%s %s ? IS NULL"
+	case repository.OperationVersionLess:
+		// versions which are no semantic versions result in NULL and therefore never match
+		return `string_to_array(substring(%s FROM '^v*([0-9]+\.[0-9]+\.[0-9]+)'), '.')::INT[] %s ?::INT[]`
+	case repository.OperationJSONPathEquals:
+		return "%s #>> ?::TEXT[] %s ?"
+	case repository.OperationTextSearch:
+		return "to_tsvector('simple', %s::TEXT) %s plainto_tsquery('simple', ?)"
 	}
 	return "%s %s ?"
 }
 
 func (db *CRDB) operation(operation repository.Operation) string {
 	switch operation {
-	case repository.OperationEquals, repository.OperationIn:
+	case repository.OperationEquals, repository.OperationIn, repository.OperationJSONPathEquals:
 		return "="
 	case repository.OperationGreater:
 		return ">"
-	case repository.OperationLess:
+	case repository.OperationGreaterOrEquals:
+		return ">="
+	case repository.OperationLess, repository.OperationVersionLess:
 		return "<"
+	case repository.OperationLessOrEquals:
+		return "<="
 	case repository.OperationJSONContains:
 		return "@>"
-	case repository.OperationNotIn:
+	case repository.OperationNotIn, repository.OperationNotEquals:
 		return "<>"
+	case repository.OperationJSONKeyMissing:
+		return "->>"
+	case repository.OperationTextSearch:
+		return "@@"
+	case repository.OperationInSet:
+		return "IN"
 	}
 	return ""
 }
@@ -156,6 +156,15 @@ func TestCRDB_conditionFormat(t *testing.T) {
 				format: "%s %s ANY(?)",
 			},
 		},
+		{
+			name: "in set",
+			args: args{
+				operation: repository.OperationInSet,
+			},
+			res: res{
+				format: "%s %s (SELECT unnest(?::TEXT[]))",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -321,3 +330,59 @@ func generateEvent(t *testing.T, aggregateID string, opts ...func(*repository.Ev
 
 	return e
 }
+
+func TestCRDB_orderByEventSequence(t *testing.T) {
+	type args struct {
+		desc                  bool
+		shouldOrderBySequence bool
+		useV1                 bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "v1",
+			args: args{
+				useV1: true,
+			},
+			want: " ORDER BY event_sequence",
+		},
+		{
+			name: "v1 desc",
+			args: args{
+				desc:  true,
+				useV1: true,
+			},
+			want: " ORDER BY event_sequence DESC",
+		},
+		{
+			name: "order by sequence",
+			args: args{
+				shouldOrderBySequence: true,
+			},
+			want: ` ORDER BY "sequence"`,
+		},
+		{
+			name: "deterministic order for equal positions",
+			args: args{},
+			want: ` ORDER BY "position", in_tx_order, aggregate_id, "sequence"`,
+		},
+		{
+			name: "deterministic order for equal positions desc",
+			args: args{
+				desc: true,
+			},
+			want: ` ORDER BY "position" DESC, in_tx_order DESC, aggregate_id DESC, "sequence" DESC`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &CRDB{}
+			if got := db.orderByEventSequence(tt.args.desc, tt.args.shouldOrderBySequence, tt.args.useV1); got != tt.want {
+				t.Errorf("CRDB.orderByEventSequence() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+var crdbEstimatedRowsRegex = regexp.MustCompile(`estimated row count: ([0-9,]+)`)
+
+// EstimateCount returns the approximate amount of events found by the search query.
+// The amount is the row estimate of the query planner which is derived from the table statistics,
+// the events are not scanned. Ordering, limit and offset of the search query are ignored.
+func (db *CRDB) EstimateCount(ctx context.Context, searchQuery *eventstore.SearchQueryBuilder) (count uint64, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if searchQuery.GetCompiledQuery() != nil {
+		return 0, zerrors.ThrowInvalidArgument(nil, "SQL-Ek4wb", "compiled queries cannot be estimated")
+	}
+	q, err := repository.QueryFromBuilder(searchQuery)
+	if err != nil {
+		return 0, err
+	}
+	where, values := prepareConditions(db, q, false)
+	if where == "" {
+		return 0, zerrors.ThrowInvalidArgument(nil, "SQL-Ek7zq", "invalid query factory")
+	}
+
+	explain := "EXPLAIN "
+	parse := parseCRDBEstimate
+	if db.Type() == "postgres" {
+		explain = "EXPLAIN (FORMAT JSON) "
+		parse = parsePostgresEstimate
+	}
+	var plan strings.Builder
+	err = db.DB.QueryContext(ctx,
+		func(rows *sql.Rows) error {
+			for rows.Next() {
+				var line string
+				if err := rows.Scan(&line); err != nil {
+					return err
+				}
+				plan.WriteString(line)
+				plan.WriteByte('\n')
+			}
+			return nil
+		}, explain+"SELECT 1 FROM eventstore.events2"+db.placeholder(where), values...)
+	if err != nil {
+		return 0, zerrors.ThrowInternal(err, "SQL-Ek2nf", "unable to estimate events")
+	}
+	return parse(plan.String())
+}
+
+// parsePostgresEstimate returns the planned rows of the root node of a JSON formatted plan
+func parsePostgresEstimate(plan string) (uint64, error) {
+	var nodes []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(plan), &nodes); err != nil || len(nodes) == 0 {
+		return 0, zerrors.ThrowInternal(err, "SQL-Ek5ho", "unable to parse query plan")
+	}
+	return uint64(nodes[0].Plan.Rows), nil
+}
+
+// parseCRDBEstimate returns the estimated row count of the root node of a plan,
+// which is the first estimate of the plan
+func parseCRDBEstimate(plan string) (uint64, error) {
+	match := crdbEstimatedRowsRegex.FindStringSubmatch(plan)
+	if match == nil {
+		return 0, zerrors.ThrowInternal(nil, "SQL-Ek9ud", "query plan contains no estimate")
+	}
+	count, err := strconv.ParseUint(strings.ReplaceAll(match[1], ",", ""), 10, 64)
+	if err != nil {
+		return 0, zerrors.ThrowInternal(err, "SQL-Ek3ma", "unable to parse query plan")
+	}
+	return count, nil
+}
@@ -0,0 +1,59 @@
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+func TestCRDB_EstimateCount(t *testing.T) {
+	mock := newMockClient(t)
+	mock.mock.ExpectBegin()
+	mock.mock.ExpectQuery(`EXPLAIN SELECT 1 FROM eventstore.events2 WHERE instance_id = \$1 AND aggregate_type = \$2`).
+		WithArgs("instance", eventstore.AggregateType("user")).
+		WillReturnRows(mock.mock.NewRows([]string{"info"}).
+			AddRow("distribution: full").
+			AddRow("• scan").
+			AddRow("  estimated row count: 12,345 (0.50% of the table; stats collected 2 hours ago)").
+			AddRow("  table: events2@primary"),
+		)
+	mock.mock.ExpectCommit()
+	crdb := NewCRDB(&database.DB{Database: new(testDB)})
+	crdb.DB.DB = mock.client
+
+	count, err := crdb.EstimateCount(context.Background(),
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsCount).
+			InstanceID("instance").
+			OrderDesc().
+			Limit(10).
+			AddQuery().
+			AggregateTypes("user").
+			Builder(),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(12345), count)
+	assert.NoError(t, mock.mock.ExpectationsWereMet())
+}
+
+func Test_parsePostgresEstimate(t *testing.T) {
+	count, err := parsePostgresEstimate(`[{"Plan": {"Node Type": "Index Only Scan", "Plan Rows": 4211, "Plan Width": 4}}]`)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4211), count)
+
+	_, err = parsePostgresEstimate(`[]`)
+	assert.Error(t, err)
+}
+
+func Test_parseCRDBEstimate(t *testing.T) {
+	count, err := parseCRDBEstimate("• filter\n  estimated row count: 10 (missing stats)\n\n  └── • scan\n        estimated row count: 1,000 (100% of the table)\n")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), count)
+
+	_, err = parseCRDBEstimate("• scan\n  table: events2@primary\n")
+	assert.Error(t, err)
+}
@@ -80,6 +80,10 @@ type testDB struct{}
 
 func (_ *testDB) Timetravel(time.Duration) string { return " AS OF SYSTEM TIME '-1 ms' " }
 
+func (_ *testDB) TimetravelTo(t time.Time) string {
+	return " AS OF SYSTEM TIME '" + t.UTC().Format("2006-01-02 15:04:05.999999") + "' "
+}
+
 func (*testDB) DatabaseName() string { return "db" }
 
 func (*testDB) Username() string { return "user" }
@@ -6,8 +6,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/zitadel/logging"
 
@@ -26,7 +28,9 @@ type querier interface {
 	placeholder(query string) string
 	eventQuery(useV1 bool) string
 	maxSequenceQuery(useV1 bool) string
+	maxPositionQuery(useV1 bool) string
 	instanceIDsQuery(useV1 bool) string
+	countQuery(useV1 bool) string
 	db() *database.DB
 	orderByEventSequence(desc, shouldOrderBySequence, useV1 bool) string
 	dialect.Database
@@ -55,26 +59,127 @@ func (t *tx) QueryContext(ctx context.Context, scan func(rows *sql.Rows) error,
 }
 
 func query(ctx context.Context, criteria querier, searchQuery *eventstore.SearchQueryBuilder, dest interface{}, useV1 bool) error {
-	q, err := repository.QueryFromBuilder(searchQuery)
+	template, rowScanner, err := prepareTemplate(criteria, searchQuery, useV1)
+	if err != nil {
+		return err
+	}
+	values, err := bindParams(template, searchQuery.GetQueryParams())
 	if err != nil {
 		return err
 	}
 
+	query := template.Select
+	var travel string
+	if searchQuery.GetTx() == nil {
+		travel, err = prepareTimeTravel(ctx, criteria, searchQuery)
+		if err != nil {
+			return err
+		}
+		query += travel
+	}
+	query += template.Conditions
+
+	var contextQuerier interface {
+		QueryContext(context.Context, func(rows *sql.Rows) error, string, ...interface{}) error
+	}
+	contextQuerier = criteria.db()
+	// cockroach requires a time travel query to be the first statement of the transaction
+	if timeout := statementTimeout(ctx, searchQuery.GetQueryTimeout()); timeout > 0 && travel == "" {
+		contextQuerier = &statementTimeoutQuerier{client: criteria.db(), timeout: timeout}
+	}
+	if searchQuery.GetTx() != nil {
+		contextQuerier = &tx{Tx: searchQuery.GetTx()}
+	}
+
+	if budget := searchQuery.GetByteBudget(); budget > 0 {
+		dest = byteBudgetReducer(dest, budget)
+	}
+
+	err = contextQuerier.QueryContext(ctx,
+		func(rows *sql.Rows) error {
+			for rows.Next() {
+				err := rowScanner(rows.Scan, dest)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}, query, values...)
+	if errors.Is(err, eventstore.ErrByteBudgetExceeded) {
+		return err
+	}
+	if err != nil {
+		logging.New().WithError(err).Info("query failed")
+		return zerrors.ThrowInternal(err, "SQL-KyeAx", "unable to filter events")
+	}
+
+	return nil
+}
+
+// byteBudgetReducer passes the events to the reducer of dest as long as the cumulative size of the payloads is within the budget.
+// [eventstore.ErrByteBudgetExceeded] is returned as soon as an event exceeds the budget to stop reading the rows.
+func byteBudgetReducer(dest interface{}, budget int) interface{} {
+	reduce, ok := dest.(eventstore.Reducer)
+	if !ok {
+		return dest
+	}
+	var size int
+	return eventstore.Reducer(func(event eventstore.Event) error {
+		size += len(event.DataAsBytes())
+		if size > budget {
+			return eventstore.ErrByteBudgetExceeded
+		}
+		return reduce(event)
+	})
+}
+
+// prepareTemplate returns the statement of the search query.
+// The statement of a compiled query is only generated on the first execution.
+func prepareTemplate(criteria querier, searchQuery *eventstore.SearchQueryBuilder, useV1 bool) (*eventstore.QueryTemplate, func(s scan, dest interface{}) error, error) {
+	compiled := searchQuery.GetCompiledQuery()
+	if compiled != nil {
+		if template := compiled.Template(); template != nil && template.UseV1 == useV1 {
+			_, rowScanner := prepareColumns(criteria, searchQuery.GetColumns(), useV1)
+			if searchQuery.GetOrderByRelevance() || searchQuery.GetIncludeOrigin() {
+				rowScanner = eventsScanner(useV1, searchQuery.GetOrderByRelevance(), searchQuery.GetIncludeOrigin())
+			}
+			return template, rowScanner, nil
+		}
+	}
+
+	template, rowScanner, err := prepareStatement(criteria, searchQuery, useV1)
+	if err != nil {
+		return nil, nil, err
+	}
+	if compiled != nil {
+		template.Params, template.ElementParams = queryParams(template.Args)
+		compiled.SetTemplate(template)
+	}
+	return template, rowScanner, nil
+}
+
+func prepareStatement(criteria querier, searchQuery *eventstore.SearchQueryBuilder, useV1 bool) (*eventstore.QueryTemplate, func(s scan, dest interface{}) error, error) {
+	q, err := repository.QueryFromBuilder(searchQuery)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	query, rowScanner := prepareColumns(criteria, q.Columns, useV1)
+	if q.IncludeOrigin {
+		if useV1 {
+			return nil, nil, zerrors.ThrowUnimplemented(nil, "SQL-Or5vq", "the events of v1 have no origin")
+		}
+		query = originColumns(criteria, query)
+		rowScanner = eventsScanner(useV1, false, true)
+	}
 	where, values := prepareConditions(criteria, q, useV1)
 	if where == "" || query == "" {
-		return zerrors.ThrowInvalidArgument(nil, "SQL-rWeBw", "invalid query factory")
-	}
-	if q.Tx == nil {
-		if travel := prepareTimeTravel(ctx, criteria, q.AllowTimeTravel); travel != "" {
-			query += travel
-		}
+		return nil, nil, zerrors.ThrowInvalidArgument(nil, "SQL-rWeBw", "invalid query factory")
 	}
-	query += where
 
 	// instead of using the max function of the database (which doesn't work for postgres)
 	// we select the most recent row
-	if q.Columns == eventstore.ColumnsMaxSequence {
+	if q.Columns == eventstore.ColumnsMaxSequence || q.Columns == eventstore.ColumnsMaxPosition {
 		q.Limit = 1
 		q.Desc = true
 	}
@@ -91,67 +196,222 @@ func query(ctx context.Context, criteria querier, searchQuery *eventstore.Search
 
 	switch q.Columns {
 	case eventstore.ColumnsEvent,
-		eventstore.ColumnsMaxSequence:
-		query += criteria.orderByEventSequence(q.Desc, shouldOrderBySequence, useV1)
+		eventstore.ColumnsMaxSequence,
+		eventstore.ColumnsMaxPosition:
+		var order string
+		switch {
+		case len(q.AggregateIDsOrder) > 0 && q.Columns == eventstore.ColumnsEvent:
+			values = append(values, database.TextArray[string](q.AggregateIDsOrder))
+			order = orderByAggregateIDs(criteria, q.Desc, useV1)
+		case q.OrderByEventType && q.Columns == eventstore.ColumnsEvent:
+			order = orderByEventTypeThenDate(criteria, q.Desc, useV1)
+		case q.RelevanceText != "":
+			order = orderByRelevance(criteria, q.Desc, useV1)
+		default:
+			order = criteria.orderByEventSequence(q.Desc, shouldOrderBySequence, useV1)
+		}
+		where += orderNulls(order, q.NullsOrder)
 	}
 
 	if q.Limit > 0 {
 		values = append(values, q.Limit)
-		query += " LIMIT ?"
+		where += " LIMIT ?"
 	}
 
 	if q.Offset > 0 {
 		values = append(values, q.Offset)
-		query += " OFFSET ?"
+		where += " OFFSET ?"
 	}
 
-	query = criteria.placeholder(query)
-
-	var contextQuerier interface {
-		QueryContext(context.Context, func(rows *sql.Rows) error, string, ...interface{}) error
+	if q.ForUpdate {
+		where += " FOR UPDATE"
 	}
-	contextQuerier = criteria.db()
-	if q.Tx != nil {
-		contextQuerier = &tx{Tx: q.Tx}
+
+	if q.RelevanceText != "" {
+		// the rank is selected, so its argument is appended after the arguments of the conditions
+		values = append(values, q.RelevanceText)
+		query = relevanceColumn(criteria, query, len(values), useV1)
+		rowScanner = eventsScanner(useV1, true, q.IncludeOrigin)
 	}
 
-	err = contextQuerier.QueryContext(ctx,
-		func(rows *sql.Rows) error {
-			for rows.Next() {
-				err := rowScanner(rows.Scan, dest)
-				if err != nil {
-					return err
+	return &eventstore.QueryTemplate{
+		Select:     query,
+		Conditions: criteria.placeholder(where),
+		Args:       values,
+		UseV1:      useV1,
+	}, rowScanner, nil
+}
+
+// queryParams returns the index of all arguments which are placeholders of a compiled query
+// and the index of the placeholder elements of slice arguments
+func queryParams(args []any) (params map[int]string, elementParams map[int]map[int]string) {
+	params = make(map[int]string)
+	elementParams = make(map[int]map[int]string)
+	for i, arg := range args {
+		if name, ok := eventstore.QueryParamName(arg); ok {
+			params[i] = name
+			continue
+		}
+		slice := reflect.ValueOf(arg)
+		if slice.Kind() != reflect.Slice || slice.Type().Elem().Kind() != reflect.String {
+			continue
+		}
+		for j := 0; j < slice.Len(); j++ {
+			// typed strings like aggregate types are placeholders as well
+			if name, ok := eventstore.QueryParamName(slice.Index(j).String()); ok {
+				if elementParams[i] == nil {
+					elementParams[i] = make(map[int]string)
 				}
+				elementParams[i][j] = name
 			}
-			return nil
-		}, query, values...)
-	if err != nil {
-		logging.New().WithError(err).Info("query failed")
-		return zerrors.ThrowInternal(err, "SQL-KyeAx", "unable to filter events")
+		}
 	}
+	return params, elementParams
+}
 
-	return nil
+// bindParams replaces the placeholders of the template with the bound values,
+// the slices containing placeholders are copied before their elements are replaced
+func bindParams(template *eventstore.QueryTemplate, params map[string]any) ([]any, error) {
+	if len(template.Params) == 0 && len(template.ElementParams) == 0 {
+		return template.Args, nil
+	}
+	values := make([]any, len(template.Args))
+	copy(values, template.Args)
+	for i, name := range template.Params {
+		value, ok := params[name]
+		if !ok {
+			return nil, zerrors.ThrowInvalidArgumentf(nil, "SQL-Pq7mL", "parameter %s not bound", name)
+		}
+		values[i] = value
+	}
+	for i, elements := range template.ElementParams {
+		arg := reflect.ValueOf(template.Args[i])
+		slice := reflect.MakeSlice(arg.Type(), arg.Len(), arg.Len())
+		reflect.Copy(slice, arg)
+		for j, name := range elements {
+			value, ok := params[name].(string)
+			if !ok {
+				return nil, zerrors.ThrowInvalidArgumentf(nil, "SQL-Pq8nM", "parameter %s of a list must be bound to a string", name)
+			}
+			slice.Index(j).SetString(value)
+		}
+		values[i] = slice.Interface()
+	}
+	return values, nil
+}
+
+// orderByAggregateIDs orders the events by the position of the aggregate id in the passed array
+// events of the same aggregate are ordered by the default order
+func orderByAggregateIDs(criteria querier, desc, useV1 bool) string {
+	order := " ORDER BY array_position(?::TEXT[], aggregate_id)"
+	if desc {
+		order += " DESC"
+	}
+	return order + ", " + strings.TrimPrefix(criteria.orderByEventSequence(desc, false, useV1), " ORDER BY ")
+}
+
+// relevanceColumn adds the rank of the payload for the text passed as argument at position arg to the selected columns
+func relevanceColumn(criteria querier, query string, arg int, useV1 bool) string {
+	column := fmt.Sprintf(", ts_rank(to_tsvector('simple', %s::TEXT), plainto_tsquery('simple', $%d)) AS relevance",
+		criteria.columnName(repository.FieldEventData, useV1),
+		arg,
+	)
+	return strings.Replace(query, " FROM ", column+" FROM ", 1)
+}
+
+// originColumns adds the producer version and the maintenance window to the selected columns of the events
+func originColumns(criteria querier, query string) string {
+	columns := ", " + criteria.columnName(repository.FieldProducerVersion, false) +
+		", " + criteria.columnName(repository.FieldMaintenanceWindow, false)
+	return strings.Replace(query, " FROM ", columns+" FROM ", 1)
+}
+
+// orderNulls appends the nulls ordering to every column of the order by clause
+func orderNulls(order string, nulls eventstore.NullsOrder) string {
+	var suffix string
+	switch nulls {
+	case eventstore.NullsOrderFirst:
+		suffix = " NULLS FIRST"
+	case eventstore.NullsOrderLast:
+		suffix = " NULLS LAST"
+	default:
+		return order
+	}
+	var (
+		ordered strings.Builder
+		depth   int
+	)
+	for _, r := range order {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				ordered.WriteString(suffix)
+			}
+		}
+		ordered.WriteRune(r)
+	}
+	return ordered.String() + suffix
+}
+
+// orderByRelevance orders the events by the rank selected by [relevanceColumn], the most relevant first
+// events with the same relevance are ordered by the default order
+func orderByRelevance(criteria querier, desc, useV1 bool) string {
+	return " ORDER BY relevance DESC, " + strings.TrimPrefix(criteria.orderByEventSequence(desc, false, useV1), " ORDER BY ")
+}
+
+// orderByEventTypeThenDate orders the events by event type and creation date
+// events with the same creation date are ordered by the default order
+func orderByEventTypeThenDate(criteria querier, desc, useV1 bool) string {
+	order := " ORDER BY event_type, " + criteria.columnName(repository.FieldCreationDate, useV1)
+	if desc {
+		order = " ORDER BY event_type DESC, " + criteria.columnName(repository.FieldCreationDate, useV1) + " DESC"
+	}
+	return order + ", " + strings.TrimPrefix(criteria.orderByEventSequence(desc, false, useV1), " ORDER BY ")
 }
 
 func prepareColumns(criteria querier, columns eventstore.Columns, useV1 bool) (string, func(s scan, dest interface{}) error) {
 	switch columns {
 	case eventstore.ColumnsMaxSequence:
 		return criteria.maxSequenceQuery(useV1), maxSequenceScanner
+	case eventstore.ColumnsMaxPosition:
+		query := criteria.maxPositionQuery(useV1)
+		if query == "" {
+			return "", nil
+		}
+		return query, maxSequenceScanner
 	case eventstore.ColumnsInstanceIDs:
 		return criteria.instanceIDsQuery(useV1), instanceIDsScanner
+	case eventstore.ColumnsCount:
+		return criteria.countQuery(useV1), countScanner
 	case eventstore.ColumnsEvent:
-		return criteria.eventQuery(useV1), eventsScanner(useV1)
+		return criteria.eventQuery(useV1), eventsScanner(useV1, false, false)
 	default:
 		return "", nil
 	}
 }
 
-func prepareTimeTravel(ctx context.Context, criteria querier, allow bool) string {
-	if !allow {
-		return ""
+// timeTraveler is implemented by databases which can query the state as of a fixed time
+type timeTraveler interface {
+	TimetravelTo(time.Time) string
+}
+
+func prepareTimeTravel(ctx context.Context, criteria querier, searchQuery *eventstore.SearchQueryBuilder) (string, error) {
+	if travelTime := searchQuery.GetTimeTravelTime(); !travelTime.IsZero() {
+		traveler, ok := criteria.db().Database.(timeTraveler)
+		if !ok {
+			return "", zerrors.ThrowUnimplemented(nil, "SQL-Tt7nq", "time travel to a timestamp is not supported by the database")
+		}
+		return traveler.TimetravelTo(travelTime), nil
+	}
+	if !searchQuery.GetAllowTimeTravel() {
+		return "", nil
 	}
 	took := call.Took(ctx)
-	return criteria.Timetravel(took)
+	return criteria.Timetravel(took), nil
 }
 
 func maxSequenceScanner(row scan, dest interface{}) (err error) {
@@ -166,6 +426,18 @@ func maxSequenceScanner(row scan, dest interface{}) (err error) {
 	return zerrors.ThrowInternal(err, "SQL-bN5xg", "something went wrong")
 }
 
+func countScanner(row scan, dest interface{}) (err error) {
+	count, ok := dest.(*uint64)
+	if !ok {
+		return zerrors.ThrowInvalidArgumentf(nil, "SQL-Ux9vc", "type must be *uint64 got: %T", dest)
+	}
+	err = row(count)
+	if err == nil || errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	return zerrors.ThrowInternal(err, "SQL-Kc5ne", "something went wrong")
+}
+
 func instanceIDsScanner(scanner scan, dest interface{}) (err error) {
 	ids, ok := dest.(*[]string)
 	if !ok {
@@ -182,7 +454,10 @@ func instanceIDsScanner(scanner scan, dest interface{}) (err error) {
 	return nil
 }
 
-func eventsScanner(useV1 bool) func(scanner scan, dest interface{}) (err error) {
+// eventsScanner scans the columns of [querier.eventQuery],
+// withOrigin additionally scans the columns added by [originColumns] which are only available in v2,
+// withRelevance additionally scans the relevance column added by [relevanceColumn]
+func eventsScanner(useV1, withRelevance, withOrigin bool) func(scanner scan, dest interface{}) (err error) {
 	return func(scanner scan, dest interface{}) (err error) {
 		reduce, ok := dest.(eventstore.Reducer)
 		if !ok {
@@ -192,7 +467,7 @@ func eventsScanner(useV1 bool) func(scanner scan, dest interface{}) (err error)
 		position := new(sql.NullFloat64)
 
 		if useV1 {
-			err = scanner(
+			dests := []any{
 				&event.CreationDate,
 				&event.Typ,
 				&event.Seq,
@@ -203,10 +478,14 @@ func eventsScanner(useV1 bool) func(scanner scan, dest interface{}) (err error)
 				&event.AggregateType,
 				&event.AggregateID,
 				&event.Version,
-			)
+			}
+			if withRelevance {
+				dests = append(dests, &event.RelevanceScore)
+			}
+			err = scanner(dests...)
 		} else {
 			var revision uint8
-			err = scanner(
+			dests := []any{
 				&event.CreationDate,
 				&event.Typ,
 				&event.Seq,
@@ -218,7 +497,14 @@ func eventsScanner(useV1 bool) func(scanner scan, dest interface{}) (err error)
 				&event.AggregateType,
 				&event.AggregateID,
 				&revision,
-			)
+			}
+			if withOrigin {
+				dests = append(dests, &event.ProducerVersion, &event.MaintenanceWindow)
+			}
+			if withRelevance {
+				dests = append(dests, &event.RelevanceScore)
+			}
+			err = scanner(dests...)
 			event.Version = eventstore.Version("v" + strconv.Itoa(int(revision)))
 		}
 
@@ -232,36 +518,43 @@ func eventsScanner(useV1 bool) func(scanner scan, dest interface{}) (err error)
 }
 
 func prepareConditions(criteria querier, query *repository.SearchQuery, useV1 bool) (_ string, args []any) {
-	clauses, args := prepareQuery(criteria, useV1, query.InstanceID, query.InstanceIDs, query.ExcludedInstances)
+	scope := []*repository.Filter{query.InstanceID, query.InstanceIDs, query.ExcludedInstances}
+	owner := query.Owner
+	// the owner is compared directly after the instance to match the prefix of the index
+	if query.OptimizeForTenant {
+		scope = append(scope, owner)
+		owner = nil
+	}
+	clauses, args := prepareQuery(criteria, useV1, scope...)
 	if clauses != "" && len(query.SubQueries) > 0 {
 		clauses += " AND "
 	}
-	subClauses := make([]string, len(query.SubQueries))
-	for i, filters := range query.SubQueries {
-		var subArgs []any
-		subClauses[i], subArgs = prepareQuery(criteria, useV1, filters...)
-		// an error is thrown in [query]
-		if subClauses[i] == "" {
-			return "", nil
-		}
-		if len(query.SubQueries) > 1 && len(subArgs) > 1 {
-			subClauses[i] = "(" + subClauses[i] + ")"
-		}
-		args = append(args, subArgs...)
+	var subQueries string
+	var subArgs []any
+	if query.SubQueryLimits != nil {
+		subQueries, subArgs = limitedSubQueries(criteria, query, useV1)
+	} else {
+		subQueries, subArgs = prepareSubQueries(criteria, query, useV1)
 	}
-	if len(subClauses) == 1 {
-		clauses += subClauses[0]
-	} else if len(subClauses) > 1 {
-		clauses += "(" + strings.Join(subClauses, " OR ") + ")"
+	// an error is thrown in [query]
+	if subQueries == "" && len(query.SubQueries) > 0 {
+		return "", nil
 	}
+	clauses += subQueries
+	args = append(args, subArgs...)
 
 	additionalClauses, additionalArgs := prepareQuery(criteria, useV1,
 		query.Position,
-		query.Owner,
+		query.PositionAtOrAfter,
+		owner,
 		query.Sequence,
 		query.CreatedAfter,
 		query.CreatedBefore,
 		query.Creator,
+		query.Creators,
+		query.ProducerVersion,
+		query.ProducerVersionBefore,
+		query.MaintenanceWindow,
 	)
 	if additionalClauses != "" {
 		if clauses != "" {
@@ -282,6 +575,62 @@ func prepareConditions(criteria querier, query *repository.SearchQuery, useV1 bo
 	return " WHERE " + clauses, args
 }
 
+// prepareSubQueries combines the conditions of the sub queries using OR
+func prepareSubQueries(criteria querier, query *repository.SearchQuery, useV1 bool) (clauses string, args []any) {
+	subClauses := make([]string, len(query.SubQueries))
+	for i, filters := range query.SubQueries {
+		var subArgs []any
+		subClauses[i], subArgs = prepareQuery(criteria, useV1, filters...)
+		// an error is thrown in [query]
+		if subClauses[i] == "" {
+			return "", nil
+		}
+		if len(query.SubQueries) > 1 && len(subArgs) > 1 {
+			subClauses[i] = "(" + subClauses[i] + ")"
+		}
+		args = append(args, subArgs...)
+	}
+	if len(subClauses) == 1 {
+		clauses = subClauses[0]
+	} else if len(subClauses) > 1 {
+		clauses = "(" + strings.Join(subClauses, " OR ") + ")"
+	}
+	return clauses, args
+}
+
+// limitedSubQueries combines the sub queries using UNION to apply the limit of each sub query,
+// every sub query selects the primary keys of its events including the other conditions of the query.
+func limitedSubQueries(criteria querier, query *repository.SearchQuery, useV1 bool) (_ string, args []any) {
+	table := "eventstore.events2"
+	if useV1 {
+		table = "eventstore.events"
+	}
+	key := strings.Join([]string{
+		criteria.columnName(repository.FieldInstanceID, useV1),
+		criteria.columnName(repository.FieldAggregateType, useV1),
+		criteria.columnName(repository.FieldAggregateID, useV1),
+		criteria.columnName(repository.FieldSequence, useV1),
+	}, ", ")
+	branches := make([]string, len(query.SubQueries))
+	for i, filters := range query.SubQueries {
+		branch := *query
+		branch.SubQueries = [][]*repository.Filter{filters}
+		branch.SubQueryLimits = nil
+		where, branchArgs := prepareConditions(criteria, &branch, useV1)
+		if where == "" {
+			return "", nil
+		}
+		args = append(args, branchArgs...)
+		branches[i] = "(SELECT " + key + " FROM " + table + where
+		if limit := query.SubQueryLimits[i]; limit > 0 {
+			branches[i] += criteria.orderByEventSequence(query.Desc, false, useV1) + " LIMIT ?"
+			args = append(args, limit)
+		}
+		branches[i] += ")"
+	}
+	return "(" + key + ") IN (" + strings.Join(branches, " UNION ") + ")", args
+}
+
 func prepareQuery(criteria querier, useV1 bool, filters ...*repository.Filter) (_ string, args []any) {
 	clauses := make([]string, 0, len(filters))
 	args = make([]any, 0, len(filters))
@@ -292,7 +641,7 @@ func prepareQuery(criteria querier, useV1 bool, filters ...*repository.Filter) (
 		arg := filter.Value
 
 		// marshal if payload filter
-		if filter.Field == repository.FieldEventData {
+		if filter.Field == repository.FieldEventData && filter.Operation == repository.OperationJSONContains {
 			var err error
 			arg, err = json.Marshal(arg)
 			if err != nil {
@@ -307,6 +656,15 @@ func prepareQuery(criteria querier, useV1 bool, filters ...*repository.Filter) (
 		if clauses[len(clauses)-1] == "" {
 			return "", nil
 		}
+		// the path and the value of a json path are separate arguments
+		if path, ok := arg.(repository.JSONPath); ok {
+			args = append(args, path.Path, path.Value)
+			continue
+		}
+		if comparison, ok := arg.(repository.JSONPathComparison); ok {
+			args = append(args, comparison.Path, comparison.Value)
+			continue
+		}
 		args = append(args, arg)
 	}
 
@@ -320,6 +678,10 @@ func getCondition(cond querier, filter *repository.Filter, useV1 bool) (conditio
 		return ""
 	}
 	format := cond.conditionFormat(filter.Operation)
+	// the json at the path is compared, the type of the value is inferred from it
+	if _, ok := filter.Value.(repository.JSONPathComparison); ok {
+		field += " #> ?::TEXT[]"
+	}
 
 	return fmt.Sprintf(format, field, operation)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"reflect"
 	"strconv"
 	"testing"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/database/cockroach"
@@ -39,6 +41,11 @@ func Test_getCondition(t *testing.T) {
 			args: args{filter: repository.NewFilter(repository.FieldSequence, 0, repository.OperationGreater)},
 			want: `"sequence" > ?`,
 		},
+		{
+			name: "greater or equals",
+			args: args{filter: repository.NewFilter(repository.FieldPosition, 1.5, repository.OperationGreaterOrEquals)},
+			want: `"position" >= ?`,
+		},
 		{
 			name: "less",
 			args: args{filter: repository.NewFilter(repository.FieldSequence, 5000, repository.OperationLess)},
@@ -49,6 +56,36 @@ func Test_getCondition(t *testing.T) {
 			args: args{filter: repository.NewFilter(repository.FieldAggregateType, []eventstore.AggregateType{"movies", "actors"}, repository.OperationIn)},
 			want: "aggregate_type = ANY(?)",
 		},
+		{
+			name: "in set",
+			args: args{filter: repository.NewFilter(repository.FieldAggregateID, []string{"1", "2"}, repository.OperationInSet)},
+			want: "aggregate_id IN (SELECT unnest(?::TEXT[]))",
+		},
+		{
+			name: "text search",
+			args: args{filter: repository.NewFilter(repository.FieldEventData, "alice", repository.OperationTextSearch)},
+			want: "to_tsvector('simple', payload::TEXT) @@ plainto_tsquery('simple', ?)",
+		},
+		{
+			name: "aggregate versions",
+			args: args{filter: repository.NewFilter(repository.FieldAggregateVersion, []eventstore.Version{"v1", "v2"}, repository.OperationIn)},
+			want: "('v' || revision::TEXT) = ANY(?)",
+		},
+		{
+			name: "json path",
+			args: args{filter: repository.NewFilter(repository.FieldEventData, repository.JSONPath{Path: []string{"user", "language"}, Value: "de"}, repository.OperationJSONPathEquals)},
+			want: "payload #>> ?::TEXT[] = ?",
+		},
+		{
+			name: "json path comparison",
+			args: args{filter: repository.NewFilter(repository.FieldEventData, repository.JSONPathComparison{Path: []string{"order", "amount"}, Value: "100"}, repository.OperationGreater)},
+			want: "payload #> ?::TEXT[] > ?",
+		},
+		{
+			name: "json path comparison less or equals",
+			args: args{filter: repository.NewFilter(repository.FieldEventData, repository.JSONPathComparison{Path: []string{"amount"}, Value: "100"}, repository.OperationLessOrEquals)},
+			want: "payload #> ?::TEXT[] <= ?",
+		},
 		{
 			name: "invalid operation",
 			args: args{filter: repository.NewFilter(repository.FieldAggregateType, []eventstore.AggregateType{"movies", "actors"}, repository.Operation(-1))},
@@ -135,6 +172,30 @@ func Test_prepareColumns(t *testing.T) {
 				dbRow: []interface{}{sql.NullFloat64{Float64: 43, Valid: true}},
 			},
 		},
+		{
+			name: "max position v2",
+			args: args{
+				columns: eventstore.ColumnsMaxPosition,
+				dest:    new(sql.NullFloat64),
+			},
+			res: res{
+				query:    `SELECT "position" FROM eventstore.events2`,
+				expected: sql.NullFloat64{Float64: 43.5, Valid: true},
+			},
+			fields: fields{
+				dbRow: []interface{}{sql.NullFloat64{Float64: 43.5, Valid: true}},
+			},
+		},
+		{
+			name: "max position not supported v1",
+			args: args{
+				columns: eventstore.ColumnsMaxPosition,
+				useV1:   true,
+			},
+			res: res{
+				query: "",
+			},
+		},
 		{
 			name: "max sequence wrong dest type",
 			args: args{
@@ -146,6 +207,31 @@ func Test_prepareColumns(t *testing.T) {
 				dbErr: zerrors.IsErrorInvalidArgument,
 			},
 		},
+		{
+			name: "count column",
+			args: args{
+				columns: eventstore.ColumnsCount,
+				dest:    new(uint64),
+			},
+			res: res{
+				query:    `SELECT COUNT(*) FROM eventstore.events2`,
+				expected: uint64(12),
+			},
+			fields: fields{
+				dbRow: []interface{}{uint64(12)},
+			},
+		},
+		{
+			name: "count wrong dest type",
+			args: args{
+				columns: eventstore.ColumnsCount,
+				dest:    new(sql.NullFloat64),
+			},
+			res: res{
+				query: `SELECT COUNT(*) FROM eventstore.events2`,
+				dbErr: zerrors.IsErrorInvalidArgument,
+			},
+		},
 		{
 			name: "events",
 			args: args{
@@ -462,6 +548,169 @@ func Test_prepareCondition(t *testing.T) {
 				values: []interface{}{[]eventstore.AggregateType{"user", "org"}, "1234", []eventstore.EventType{"user.created", "org.created"}},
 			},
 		},
+		{
+			name: "json path v2",
+			args: args{
+				query: &repository.SearchQuery{
+					SubQueries: [][]*repository.Filter{
+						{
+							repository.NewFilter(repository.FieldEventData, repository.JSONPath{Path: []string{"user", "language"}, Value: "de"}, repository.OperationJSONPathEquals),
+						},
+					},
+				},
+			},
+			res: res{
+				clause: ` WHERE payload #>> ?::TEXT[] = ?`,
+				values: []interface{}{database.TextArray[string]{"user", "language"}, "de"},
+			},
+		},
+		{
+			name: "sub query limits v2",
+			args: args{
+				query: &repository.SearchQuery{
+					InstanceID: repository.NewFilter(repository.FieldInstanceID, "instance", repository.OperationEquals),
+					SubQueries: [][]*repository.Filter{
+						{
+							repository.NewFilter(repository.FieldAggregateType, eventstore.AggregateType("user"), repository.OperationEquals),
+						},
+						{
+							repository.NewFilter(repository.FieldAggregateType, eventstore.AggregateType("org"), repository.OperationEquals),
+						},
+					},
+					SubQueryLimits: []uint64{5, 0},
+					Position:       repository.NewFilter(repository.FieldPosition, 42.1, repository.OperationGreater),
+				},
+			},
+			res: res{
+				clause: ` WHERE instance_id = ? AND (instance_id, aggregate_type, aggregate_id, "sequence") IN (` +
+					`(SELECT instance_id, aggregate_type, aggregate_id, "sequence" FROM eventstore.events2 WHERE instance_id = ? AND aggregate_type = ? AND "position" > ? ORDER BY "position", in_tx_order, aggregate_id, "sequence" LIMIT ?)` +
+					` UNION (SELECT instance_id, aggregate_type, aggregate_id, "sequence" FROM eventstore.events2 WHERE instance_id = ? AND aggregate_type = ? AND "position" > ?)` +
+					`) AND "position" > ?`,
+				values: []interface{}{"instance", "instance", eventstore.AggregateType("user"), 42.1, uint64(5), "instance", eventstore.AggregateType("org"), 42.1, 42.1},
+			},
+		},
+		{
+			name: "json path comparison v2",
+			args: args{
+				query: &repository.SearchQuery{
+					SubQueries: [][]*repository.Filter{
+						{
+							repository.NewFilter(repository.FieldEventData, repository.JSONPathComparison{Path: []string{"amount"}, Value: "100"}, repository.OperationGreater),
+							repository.NewFilter(repository.FieldEventData, repository.JSONPathComparison{Path: []string{"currency"}, Value: `"CHF"`}, repository.OperationNotEquals),
+						},
+					},
+				},
+			},
+			res: res{
+				clause: ` WHERE payload #> ?::TEXT[] > ? AND payload #> ?::TEXT[] <> ?`,
+				values: []interface{}{database.TextArray[string]{"amount"}, "100", database.TextArray[string]{"currency"}, `"CHF"`},
+			},
+		},
+		{
+			name: "producer version v2",
+			args: args{
+				query: &repository.SearchQuery{
+					ProducerVersion: repository.NewFilter(repository.FieldProducerVersion, "v2.54.3", repository.OperationEquals),
+				},
+			},
+			res: res{
+				clause: ` WHERE producer_version = ?`,
+				values: []interface{}{"v2.54.3"},
+			},
+		},
+		{
+			name: "producer version before v2",
+			args: args{
+				query: &repository.SearchQuery{
+					SubQueries: [][]*repository.Filter{
+						{
+							repository.NewFilter(repository.FieldAggregateType, "user", repository.OperationEquals),
+						},
+					},
+					ProducerVersionBefore: repository.NewFilter(repository.FieldProducerVersion, database.NumberArray[uint64]{2, 54, 3}, repository.OperationVersionLess),
+				},
+			},
+			res: res{
+				clause: ` WHERE aggregate_type = ? AND string_to_array(substring(producer_version FROM '^v*([0-9]+\.[0-9]+\.[0-9]+)'), '.')::INT[] < ?::INT[]`,
+				values: []interface{}{"user", database.NumberArray[uint64]{2, 54, 3}},
+			},
+		},
+		{
+			name: "producer version not supported v1",
+			args: args{
+				query: &repository.SearchQuery{
+					ProducerVersion: repository.NewFilter(repository.FieldProducerVersion, "v2.54.3", repository.OperationEquals),
+				},
+				useV1: true,
+			},
+			res: res{
+				clause: "",
+				values: nil,
+			},
+		},
+		{
+			name: "maintenance window v2",
+			args: args{
+				query: &repository.SearchQuery{
+					MaintenanceWindow: repository.NewFilter(repository.FieldMaintenanceWindow, "upgrade-2024-06", repository.OperationEquals),
+				},
+			},
+			res: res{
+				clause: ` WHERE maintenance_window = ?`,
+				values: []interface{}{"upgrade-2024-06"},
+			},
+		},
+		{
+			name: "owner after sub queries",
+			args: args{
+				query: &repository.SearchQuery{
+					InstanceID: repository.NewFilter(repository.FieldInstanceID, "instance", repository.OperationEquals),
+					SubQueries: [][]*repository.Filter{
+						{
+							repository.NewFilter(repository.FieldAggregateType, "user", repository.OperationEquals),
+						},
+					},
+					Owner: repository.NewFilter(repository.FieldResourceOwner, "org", repository.OperationEquals),
+				},
+			},
+			res: res{
+				clause: ` WHERE instance_id = ? AND aggregate_type = ? AND "owner" = ?`,
+				values: []interface{}{"instance", "user", "org"},
+			},
+		},
+		{
+			name: "optimize for tenant, owner after instance",
+			args: args{
+				query: &repository.SearchQuery{
+					InstanceID: repository.NewFilter(repository.FieldInstanceID, "instance", repository.OperationEquals),
+					SubQueries: [][]*repository.Filter{
+						{
+							repository.NewFilter(repository.FieldAggregateType, "user", repository.OperationEquals),
+						},
+					},
+					Owner:             repository.NewFilter(repository.FieldResourceOwner, "org", repository.OperationEquals),
+					Position:          repository.NewFilter(repository.FieldPosition, 123.4, repository.OperationGreater),
+					OptimizeForTenant: true,
+				},
+			},
+			res: res{
+				clause: ` WHERE instance_id = ? AND "owner" = ? AND aggregate_type = ? AND "position" > ?`,
+				values: []interface{}{"instance", "org", "user", 123.4},
+			},
+		},
+		{
+			name: "maintenance window not supported v1",
+			args: args{
+				query: &repository.SearchQuery{
+					MaintenanceWindow: repository.NewFilter(repository.FieldMaintenanceWindow, "upgrade-2024-06", repository.OperationEquals),
+				},
+				useV1: true,
+			},
+			res: res{
+				clause: "",
+				values: nil,
+			},
+		},
 	}
 	crdb := NewCRDB(&database.DB{Database: new(cockroach.Config)})
 	for _, tt := range tests {
@@ -657,6 +906,115 @@ func Test_query_events_with_crdb(t *testing.T) {
 	}
 }
 
+func Test_query_events_tieBreak_with_crdb(t *testing.T) {
+	db := &CRDB{
+		DB: &database.DB{
+			DB:       testCRDBClient,
+			Database: new(testDB),
+		},
+	}
+	aggregateType := t.Name()
+	// the events share position and in tx order, they are inserted out of order
+	for _, event := range []struct {
+		aggregateID string
+		sequence    uint64
+	}{
+		{aggregateID: "b", sequence: 1},
+		{aggregateID: "a", sequence: 2},
+		{aggregateID: "c", sequence: 1},
+		{aggregateID: "a", sequence: 1},
+	} {
+		_, err := testCRDBClient.Exec(`INSERT INTO eventstore.events2 (instance_id, aggregate_type, aggregate_id, event_type, "sequence", revision, created_at, creator, "owner", "position", in_tx_order) VALUES ('instance', $1, $2, 'test.added', $3, 1, now(), 'creator', 'owner', 42, 0)`,
+			aggregateType, event.aggregateID, event.sequence,
+		)
+		require.NoError(t, err)
+	}
+	type event struct {
+		AggregateID string
+		Sequence    uint64
+	}
+	tests := []struct {
+		name string
+		desc bool
+		want []event
+	}{
+		{
+			name: "asc",
+			want: []event{{"a", 1}, {"a", 2}, {"b", 1}, {"c", 1}},
+		},
+		{
+			name: "desc",
+			desc: true,
+			want: []event{{"c", 1}, {"b", 1}, {"a", 2}, {"a", 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searchQuery := eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+				InstanceID("instance").
+				AddQuery().
+				AggregateTypes(eventstore.AggregateType(aggregateType)).
+				Builder()
+			if tt.desc {
+				searchQuery = searchQuery.OrderDesc()
+			}
+			got := make([]event, 0, len(tt.want))
+			err := query(context.Background(), db, searchQuery, eventstore.Reducer(func(e eventstore.Event) error {
+				got = append(got, event{AggregateID: e.Aggregate().ID, Sequence: e.Sequence()})
+				return nil
+			}), false)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_count_with_crdb(t *testing.T) {
+	db := &CRDB{
+		DB: &database.DB{
+			DB:       testCRDBClient,
+			Database: new(testDB),
+		},
+	}
+	if _, err := db.Push(context.Background(),
+		generateEvent(t, "320"),
+		generateEvent(t, "320"),
+		generateEvent(t, "321"),
+		generateEvent(t, "322", func(e *repository.Event) { e.AggregateType = "not in list" }),
+	); err != nil {
+		t.Fatalf("error in setup = %v", err)
+	}
+
+	events := []eventstore.Event{}
+	err := query(context.Background(), db,
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			AddQuery().
+			AggregateTypes(eventstore.AggregateType(t.Name())).
+			Builder(),
+		eventstore.Reducer(func(event eventstore.Event) error {
+			events = append(events, event)
+			return nil
+		}), true)
+	if err != nil {
+		t.Fatalf("CRDB.query() error = %v", err)
+	}
+
+	count, err := db.Count(context.Background(),
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsCount).
+			Limit(1).
+			Offset(1).
+			AddQuery().
+			AggregateTypes(eventstore.AggregateType(t.Name())).
+			Builder(),
+	)
+	if err != nil {
+		t.Fatalf("CRDB.Count() error = %v", err)
+	}
+	if count != uint64(len(events)) {
+		t.Errorf("CRDB.Count() = %d, want %d", count, len(events))
+	}
+}
+
 func Test_query_events_mocked(t *testing.T) {
 	type args struct {
 		query *eventstore.SearchQueryBuilder
@@ -762,6 +1120,114 @@ func Test_query_events_mocked(t *testing.T) {
 				wantErr: false,
 			},
 		},
+		{
+			name: "with aggregate ids ordered",
+			args: args{
+				dest: &[]*repository.Event{},
+				query: eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+					AwaitOpenTransactions().
+					AddQuery().
+					AggregateTypes("user").
+					AggregateIDsOrdered("old", "new").
+					Builder(),
+			},
+			fields: fields{
+				mock: newMockClient(t).expectQuery(t,
+					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = \$1 AND aggregate_id = ANY\(\$2\) AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY array_position\(\$3::TEXT\[\], aggregate_id\), event_sequence`,
+					[]driver.Value{eventstore.AggregateType("user"), []string{"old", "new"}, []string{"old", "new"}},
+				),
+			},
+			res: res{
+				wantErr: false,
+			},
+		},
+		{
+			name: "with aggregate ids ordered nulls last",
+			args: args{
+				dest: &[]*repository.Event{},
+				query: eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+					AwaitOpenTransactions().
+					OrderDesc().
+					NullsLast().
+					AddQuery().
+					AggregateTypes("user").
+					AggregateIDsOrdered("old", "new").
+					Builder(),
+			},
+			fields: fields{
+				mock: newMockClient(t).expectQuery(t,
+					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = \$1 AND aggregate_id = ANY\(\$2\) AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY array_position\(\$3::TEXT\[\], aggregate_id\) DESC NULLS LAST, event_sequence DESC NULLS LAST`,
+					[]driver.Value{eventstore.AggregateType("user"), []string{"old", "new"}, []string{"old", "new"}},
+				),
+			},
+			res: res{
+				wantErr: false,
+			},
+		},
+		{
+			name: "with order by event type then date",
+			args: args{
+				dest: &[]*repository.Event{},
+				query: eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+					AwaitOpenTransactions().
+					OrderByEventTypeThenDate().
+					AddQuery().
+					AggregateTypes("user").
+					Builder(),
+			},
+			fields: fields{
+				mock: newMockClient(t).expectQuery(t,
+					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = \$1 AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_type, creation_date, event_sequence`,
+					[]driver.Value{eventstore.AggregateType("user")},
+				),
+			},
+			res: res{
+				wantErr: false,
+			},
+		},
+		{
+			name: "with event data missing key",
+			args: args{
+				dest: &[]*repository.Event{},
+				query: eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+					AwaitOpenTransactions().
+					AddQuery().
+					AggregateTypes("user").
+					EventDataMissingKey("actorType").
+					Builder(),
+			},
+			fields: fields{
+				mock: newMockClient(t).expectQuery(t,
+					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE aggregate_type = \$1 AND event_data ->> \$2 IS NULL AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY event_sequence`,
+					[]driver.Value{eventstore.AggregateType("user"), "actorType"},
+				),
+			},
+			res: res{
+				wantErr: false,
+			},
+		},
+		{
+			name: "with event data text ordered by relevance",
+			args: args{
+				dest: &[]*repository.Event{},
+				query: eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+					AwaitOpenTransactions().
+					OrderByRelevance().
+					AddQuery().
+					AggregateTypes("user").
+					EventDataText("alice admin").
+					Builder(),
+			},
+			fields: fields{
+				mock: newMockClient(t).expectQuery(t,
+					`SELECT creation_date, event_type, event_sequence, event_data, editor_user, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version, ts_rank\(to_tsvector\('simple', event_data::TEXT\), plainto_tsquery\('simple', \$3\)\) AS relevance FROM eventstore.events WHERE aggregate_type = \$1 AND to_tsvector\('simple', event_data::TEXT\) @@ plainto_tsquery\('simple', \$2\) AND creation_date::TIMESTAMP < \(SELECT COALESCE\(MIN\(start\), NOW\(\)\)::TIMESTAMP FROM crdb_internal\.cluster_transactions where application_name = 'zitadel_es_pusher'\) ORDER BY relevance DESC, event_sequence`,
+					[]driver.Value{eventstore.AggregateType("user"), "alice admin", "alice admin"},
+				),
+			},
+			res: res{
+				wantErr: false,
+			},
+		},
 		{
 			name: "error sql conn closed",
 			args: args{
@@ -864,6 +1330,306 @@ func Test_query_events_mocked(t *testing.T) {
 	}
 }
 
+func Test_orderNulls(t *testing.T) {
+	tests := []struct {
+		name  string
+		order string
+		nulls eventstore.NullsOrder
+		want  string
+	}{
+		{
+			name:  "default",
+			order: ` ORDER BY "position" DESC, in_tx_order DESC`,
+			nulls: eventstore.NullsOrderDefault,
+			want:  ` ORDER BY "position" DESC, in_tx_order DESC`,
+		},
+		{
+			name:  "nulls first",
+			order: ` ORDER BY event_type, created_at`,
+			nulls: eventstore.NullsOrderFirst,
+			want:  ` ORDER BY event_type NULLS FIRST, created_at NULLS FIRST`,
+		},
+		{
+			name:  "function arguments not split",
+			order: ` ORDER BY array_position(?::TEXT[], aggregate_id), "sequence"`,
+			nulls: eventstore.NullsOrderLast,
+			want:  ` ORDER BY array_position(?::TEXT[], aggregate_id) NULLS LAST, "sequence" NULLS LAST`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := orderNulls(tt.order, tt.nulls); got != tt.want {
+				t.Errorf("orderNulls() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_query_forUpdate(t *testing.T) {
+	mock := newMockClient(t)
+	mock.mock.ExpectBegin()
+	mock.mock.ExpectQuery(`SELECT created_at, event_type, "sequence", "position", payload, creator, "owner", instance_id, aggregate_type, aggregate_id, revision FROM eventstore.events2 WHERE instance_id = \$1 AND aggregate_type = \$2 AND aggregate_id = \$3 ORDER BY "sequence" FOR UPDATE`).
+		WithArgs("instance", eventstore.AggregateType("user"), "id").
+		WillReturnRows(mock.mock.NewRows([]string{"sequence"}))
+	crdb := NewCRDB(&database.DB{Database: new(testDB)})
+	crdb.DB.DB = mock.client
+
+	tx, err := mock.client.Begin()
+	if !assert.NoError(t, err) {
+		return
+	}
+	builder := func() *eventstore.SearchQueryBuilder {
+		return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			InstanceID("instance").
+			ForUpdate().
+			AddQuery().
+			AggregateTypes("user").
+			AggregateIDs("id").
+			Builder()
+	}
+
+	err = query(context.Background(), crdb, builder().SetTx(tx), &[]*repository.Event{}, false)
+	assert.NoError(t, err)
+
+	err = query(context.Background(), crdb, builder(), &[]*repository.Event{}, false)
+	assert.True(t, zerrors.IsPreconditionFailed(err), "without transaction: %v", err)
+
+	err = query(context.Background(), crdb, builder().SetTx(tx).Columns(eventstore.ColumnsMaxSequence), new(sql.NullFloat64), false)
+	assert.True(t, zerrors.IsPreconditionFailed(err), "max sequence: %v", err)
+
+	if err := mock.mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("not all expectaions met: %v", err)
+	}
+}
+
+func Test_query_timeTravelTo(t *testing.T) {
+	travelTime := time.Date(2024, 3, 1, 12, 30, 15, 123456000, time.UTC)
+	mock := newMockClient(t).
+		expectQuery(t,
+			`SELECT created_at, event_type, "sequence", "position", payload, creator, "owner", instance_id, aggregate_type, aggregate_id, revision FROM eventstore.events2 AS OF SYSTEM TIME '2024-03-01 12:30:15.123456' WHERE instance_id = \$1 AND aggregate_type = \$2 ORDER BY "position", in_tx_order`,
+			[]driver.Value{"instance", eventstore.AggregateType("user")},
+		)
+	crdb := NewCRDB(&database.DB{Database: new(testDB)})
+	crdb.DB.DB = mock.client
+
+	err := query(context.Background(), crdb,
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			InstanceID("instance").
+			TimeTravelTo(travelTime.In(time.FixedZone("CET", 3600))).
+			AllowTimeTravel().
+			AddQuery().
+			AggregateTypes("user").
+			Builder(),
+		&[]*repository.Event{}, false)
+	assert.NoError(t, err)
+
+	if err := mock.mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("not all expectaions met: %v", err)
+	}
+}
+
+func Test_query_includeOrigin(t *testing.T) {
+	mock := newMockClient(t)
+	mock.mock.ExpectBegin()
+	mock.mock.ExpectQuery(`SELECT created_at, event_type, "sequence", "position", payload, creator, "owner", instance_id, aggregate_type, aggregate_id, revision, producer_version, maintenance_window FROM eventstore.events2 WHERE instance_id = \$1 AND aggregate_type = \$2 ORDER BY "position", in_tx_order`).
+		WithArgs("instance", eventstore.AggregateType("user")).
+		WillReturnRows(mock.mock.NewRows([]string{"created_at", "event_type", "sequence", "position", "payload", "creator", "owner", "instance_id", "aggregate_type", "aggregate_id", "revision", "producer_version", "maintenance_window"}).
+			AddRow(time.Now(), "user.added", 1, 1.5, nil, "creator", "org", "instance", "user", "id", 1, "v2.54.3", nil))
+	mock.mock.ExpectCommit()
+	crdb := NewCRDB(&database.DB{Database: new(testDB)})
+	crdb.DB.DB = mock.client
+
+	builder := func() *eventstore.SearchQueryBuilder {
+		return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			InstanceID("instance").
+			IncludeOrigin().
+			AddQuery().
+			AggregateTypes("user").
+			Builder()
+	}
+	var events []eventstore.Event
+	err := query(context.Background(), crdb, builder(), eventstore.Reducer(func(event eventstore.Event) error {
+		events = append(events, event)
+		return nil
+	}), false)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		producerVersion, maintenanceWindow := eventstore.EventOrigin(events[0])
+		assert.Equal(t, "v2.54.3", producerVersion)
+		assert.Empty(t, maintenanceWindow)
+	}
+
+	err = query(context.Background(), crdb, builder(), &[]*repository.Event{}, true)
+	assert.True(t, zerrors.IsUnimplemented(err), "v1: %v", err)
+
+	if err := mock.mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("not all expectaions met: %v", err)
+	}
+}
+
+func Test_query_optimizeForTenant(t *testing.T) {
+	mock := newMockClient(t).
+		expectQuery(t,
+			`SELECT created_at, event_type, "sequence", "position", payload, creator, "owner", instance_id, aggregate_type, aggregate_id, revision FROM eventstore.events2 WHERE instance_id = \$1 AND "owner" = \$2 AND aggregate_type = \$3 ORDER BY "position", in_tx_order`,
+			[]driver.Value{"instance", "org", eventstore.AggregateType("user")},
+		).
+		expectQuery(t,
+			`SELECT created_at, event_type, "sequence", "position", payload, creator, "owner", instance_id, aggregate_type, aggregate_id, revision FROM eventstore.events2 WHERE aggregate_type = \$1 AND "owner" = \$2 ORDER BY "position", in_tx_order`,
+			[]driver.Value{eventstore.AggregateType("user"), "org"},
+		)
+	crdb := NewCRDB(&database.DB{Database: new(testDB)})
+	crdb.DB.DB = mock.client
+
+	err := query(context.Background(), crdb,
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			InstanceID("instance").
+			ResourceOwner("org").
+			OptimizeForTenant().
+			AddQuery().
+			AggregateTypes("user").
+			Builder(),
+		&[]*repository.Event{}, false)
+	assert.NoError(t, err)
+
+	// without instance the hint is not applied
+	err = query(context.Background(), crdb,
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			ResourceOwner("org").
+			OptimizeForTenant().
+			AddQuery().
+			AggregateTypes("user").
+			Builder(),
+		&[]*repository.Event{}, false)
+	assert.NoError(t, err)
+
+	if err := mock.mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("not all expectaions met: %v", err)
+	}
+}
+
+func Test_byteBudgetReducer(t *testing.T) {
+	tests := []struct {
+		name         string
+		sizes        []int
+		budget       int
+		wantReduced  int
+		wantExceeded bool
+	}{
+		{
+			name:        "within budget",
+			sizes:       []int{2, 3, 5},
+			budget:      10,
+			wantReduced: 3,
+		},
+		{
+			name:         "exceeded",
+			sizes:        []int{2, 3, 6, 1},
+			budget:       10,
+			wantReduced:  2,
+			wantExceeded: true,
+		},
+		{
+			name:         "first event exceeds",
+			sizes:        []int{11},
+			budget:       10,
+			wantReduced:  0,
+			wantExceeded: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reduced int
+			reduce := byteBudgetReducer(eventstore.Reducer(func(eventstore.Event) error {
+				reduced++
+				return nil
+			}), tt.budget).(eventstore.Reducer)
+
+			var err error
+			for _, size := range tt.sizes {
+				if err = reduce(&repository.Event{Data: make([]byte, size)}); err != nil {
+					break
+				}
+			}
+			assert.Equal(t, tt.wantExceeded, errors.Is(err, eventstore.ErrByteBudgetExceeded))
+			assert.Equal(t, tt.wantReduced, reduced)
+		})
+	}
+}
+
+func Test_query_compiled(t *testing.T) {
+	compiled := eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		InstanceID("instance").
+		AddQuery().
+		AggregateTypes("user").
+		AggregateIDs(eventstore.QueryParam("id")).
+		Builder().
+		Compile()
+
+	stmt := `SELECT creation_date, event_type, event_sequence, event_data, editor_user, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE instance_id = \$1 AND aggregate_type = \$2 AND aggregate_id = \$3 ORDER BY event_sequence`
+	mock := newMockClient(t).
+		expectQuery(t, stmt, []driver.Value{"instance", eventstore.AggregateType("user"), "id1"}).
+		expectQuery(t, stmt, []driver.Value{"instance", eventstore.AggregateType("user"), "id2"})
+	crdb := NewCRDB(&database.DB{Database: new(testDB)})
+	crdb.DB.DB = mock.client
+
+	err := query(context.Background(), crdb, compiled.Bind(map[string]any{"id": "id1"}), &[]*repository.Event{}, true)
+	assert.NoError(t, err)
+	template := compiled.Template()
+	if !assert.NotNil(t, template) {
+		return
+	}
+	assert.Equal(t, map[int]string{2: "id"}, template.Params)
+
+	err = query(context.Background(), crdb, compiled.Bind(map[string]any{"id": "id2"}), &[]*repository.Event{}, true)
+	assert.NoError(t, err)
+	assert.Same(t, template, compiled.Template())
+
+	err = query(context.Background(), crdb, compiled.Bind(nil), &[]*repository.Event{}, true)
+	assert.True(t, zerrors.IsErrorInvalidArgument(err))
+
+	if err := mock.mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("not all expectaions met: %v", err)
+	}
+}
+
+func Test_query_compiled_list(t *testing.T) {
+	compiled := eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		InstanceID("instance").
+		AddQuery().
+		AggregateTypes("user", eventstore.AggregateType(eventstore.QueryParam("type"))).
+		AggregateIDs(eventstore.QueryParam("id"), "fixed").
+		Builder().
+		Compile()
+
+	stmt := `SELECT creation_date, event_type, event_sequence, event_data, editor_user, resource_owner, instance_id, aggregate_type, aggregate_id, aggregate_version FROM eventstore.events WHERE instance_id = \$1 AND aggregate_type = ANY\(\$2\) AND aggregate_id = ANY\(\$3\) ORDER BY event_sequence`
+	mock := newMockClient(t).
+		expectQuery(t, stmt, []driver.Value{"instance", []eventstore.AggregateType{"user", "org"}, []string{"id1", "fixed"}}).
+		expectQuery(t, stmt, []driver.Value{"instance", []eventstore.AggregateType{"user", "project"}, []string{"id2", "fixed"}})
+	crdb := NewCRDB(&database.DB{Database: new(testDB)})
+	crdb.DB.DB = mock.client
+
+	err := query(context.Background(), crdb, compiled.Bind(map[string]any{"type": "org", "id": "id1"}), &[]*repository.Event{}, true)
+	assert.NoError(t, err)
+	template := compiled.Template()
+	if !assert.NotNil(t, template) {
+		return
+	}
+	assert.Empty(t, template.Params)
+	assert.Equal(t, map[int]map[int]string{1: {1: "type"}, 2: {0: "id"}}, template.ElementParams)
+
+	err = query(context.Background(), crdb, compiled.Bind(map[string]any{"type": "project", "id": "id2"}), &[]*repository.Event{}, true)
+	assert.NoError(t, err)
+	// the bound values must not change the arguments of the template
+	assert.Equal(t, database.TextArray[string]{eventstore.QueryParam("id"), "fixed"}, template.Args[2])
+
+	err = query(context.Background(), crdb, compiled.Bind(map[string]any{"type": "org"}), &[]*repository.Event{}, true)
+	assert.True(t, zerrors.IsErrorInvalidArgument(err))
+
+	if err := mock.mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("not all expectaions met: %v", err)
+	}
+}
+
 type dbMock struct {
 	mock   sqlmock.Sqlmock
 	client *sql.DB
@@ -0,0 +1,56 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/database"
+)
+
+// statementTimeout returns the earlier of the explicit timeout and the deadline of the context,
+// 0 if neither is set.
+func statementTimeout(ctx context.Context, timeout time.Duration) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return timeout
+	}
+	untilDeadline := time.Until(deadline)
+	if timeout > 0 && timeout < untilDeadline {
+		return timeout
+	}
+	// a statement timeout of 0 disables the timeout on the database
+	return max(untilDeadline, time.Millisecond)
+}
+
+// statementTimeoutQuerier executes the query in a read only transaction
+// which is canceled by the database if the query runs longer than the timeout
+type statementTimeoutQuerier struct {
+	client  *database.DB
+	timeout time.Duration
+}
+
+func (q *statementTimeoutQuerier) QueryContext(ctx context.Context, scan func(rows *sql.Rows) error, query string, args ...any) (err error) {
+	readTx, err := q.client.BeginTx(ctx, &sql.TxOptions{ReadOnly: true, Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := readTx.Rollback()
+			logging.OnError(rollbackErr).Info("rollback of read only transaction failed")
+			return
+		}
+		err = readTx.Commit()
+	}()
+
+	// SET doesn't support placeholders, the value is an integer in milliseconds
+	timeout := max(q.timeout.Milliseconds(), 1)
+	if _, err = readTx.ExecContext(ctx, "SET LOCAL statement_timeout = "+strconv.FormatInt(timeout, 10)); err != nil {
+		return err
+	}
+	return (&tx{Tx: readTx}).QueryContext(ctx, scan, query, args...)
+}
@@ -0,0 +1,157 @@
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+)
+
+func Test_statementTimeout(t *testing.T) {
+	deadline := func(d time.Duration) context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), d)
+		t.Cleanup(cancel)
+		return ctx
+	}
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		timeout time.Duration
+		min     time.Duration
+		max     time.Duration
+	}{
+		{
+			name: "no timeout",
+			ctx:  context.Background(),
+		},
+		{
+			name:    "explicit timeout",
+			ctx:     context.Background(),
+			timeout: time.Second,
+			min:     time.Second,
+			max:     time.Second,
+		},
+		{
+			name: "deadline",
+			ctx:  deadline(time.Minute),
+			min:  time.Minute - 10*time.Second,
+			max:  time.Minute,
+		},
+		{
+			name:    "explicit timeout before deadline",
+			ctx:     deadline(time.Minute),
+			timeout: time.Second,
+			min:     time.Second,
+			max:     time.Second,
+		},
+		{
+			name:    "deadline before explicit timeout",
+			ctx:     deadline(time.Minute),
+			timeout: time.Hour,
+			min:     time.Minute - 10*time.Second,
+			max:     time.Minute,
+		},
+		{
+			name: "deadline exceeded",
+			ctx:  deadline(-time.Second),
+			min:  time.Millisecond,
+			max:  time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := statementTimeout(tt.ctx, tt.timeout)
+			assert.GreaterOrEqual(t, got, tt.min)
+			assert.LessOrEqual(t, got, tt.max)
+		})
+	}
+}
+
+func Test_query_statementTimeout(t *testing.T) {
+	tests := []struct {
+		name             string
+		deadline         time.Duration
+		timeout          time.Duration
+		statementTimeout string
+	}{
+		{
+			name:             "explicit timeout before deadline",
+			deadline:         time.Hour,
+			timeout:          1500 * time.Millisecond,
+			statementTimeout: `1500`,
+		},
+		{
+			name:             "deadline before explicit timeout",
+			deadline:         time.Minute,
+			timeout:          time.Hour,
+			statementTimeout: `(5\d{4}|60000)`,
+		},
+		{
+			name:             "deadline without explicit timeout",
+			deadline:         time.Minute,
+			statementTimeout: `(5\d{4}|60000)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newMockClient(t)
+			mock.mock.ExpectBegin()
+			mock.mock.ExpectExec(`SET LOCAL statement_timeout = ` + tt.statementTimeout + `$`).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.mock.ExpectQuery(`SELECT created_at, event_type, "sequence", "position", payload, creator, "owner", instance_id, aggregate_type, aggregate_id, revision FROM eventstore.events2 WHERE instance_id = \$1 AND aggregate_type = \$2`).
+				WithArgs([]driver.Value{"instance", eventstore.AggregateType("user")}...).
+				WillReturnRows(mock.mock.NewRows([]string{"sequence"}))
+			mock.mock.ExpectCommit()
+			crdb := NewCRDB(&database.DB{Database: new(testDB)})
+			crdb.DB.DB = mock.client
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.deadline)
+			defer cancel()
+			err := query(ctx, crdb,
+				eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+					InstanceID("instance").
+					QueryTimeout(tt.timeout).
+					AddQuery().
+					AggregateTypes("user").
+					Builder(),
+				&[]*repository.Event{}, false)
+			assert.NoError(t, err)
+
+			if err := mock.mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("not all expectaions met: %v", err)
+			}
+		})
+	}
+}
+
+func Test_query_withoutStatementTimeout(t *testing.T) {
+	mock := newMockClient(t)
+	// neither a deadline nor a timeout is set, no statement timeout is sent to the database
+	mock.mock.ExpectBegin()
+	mock.mock.ExpectQuery(`SELECT created_at, event_type, "sequence", "position", payload, creator, "owner", instance_id, aggregate_type, aggregate_id, revision FROM eventstore.events2 WHERE instance_id = \$1 AND aggregate_type = \$2`).
+		WithArgs([]driver.Value{"instance", eventstore.AggregateType("user")}...).
+		WillReturnRows(mock.mock.NewRows([]string{"sequence"}))
+	mock.mock.ExpectCommit()
+	crdb := NewCRDB(&database.DB{Database: new(testDB)})
+	crdb.DB.DB = mock.client
+
+	err := query(context.Background(), crdb,
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			InstanceID("instance").
+			AddQuery().
+			AggregateTypes("user").
+			Builder(),
+		&[]*repository.Event{}, false)
+	assert.NoError(t, err)
+
+	if err := mock.mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("not all expectaions met: %v", err)
+	}
+}
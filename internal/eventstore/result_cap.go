@@ -0,0 +1,115 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+
+	"github.com/zitadel/logging"
+)
+
+// FilterToReducerCapped calls r for every event found by the search query like [Eventstore.FilterToReducer].
+// If the search query reads the events of a single instance, the result is truncated to the [Config.InstanceResultCap]
+// regardless of the limit of the search query, capped is true if the result was truncated.
+// It's meant for events returned to API callers,
+// write models and projections must read the complete result using [Eventstore.FilterToReducer].
+func (es *Eventstore) FilterToReducerCapped(ctx context.Context, searchQuery *SearchQueryBuilder, r reducer) (capped bool, err error) {
+	searchQuery.ensureInstanceID(ctx)
+	var resultCap uint64
+	if es.capsResult(searchQuery) {
+		resultCap = es.instanceResultCap
+	}
+	capped, err = es.filterCappedToReducer(ctx, searchQuery, resultCap, func(event Event) error {
+		event, err := es.mapEvent(event)
+		if err != nil {
+			return err
+		}
+		r.AppendEvents(event)
+		return r.Reduce()
+	})
+	if err != nil && !errors.Is(err, ErrByteBudgetExceeded) {
+		return false, err
+	}
+	if capped {
+		logging.WithFields("instance", searchQuery.boundInstanceID(), "cap", resultCap).Warn("result of event query truncated")
+	}
+	return capped, err
+}
+
+// filterToReducer calls r for every event of the stores.
+func (es *Eventstore) filterToReducer(ctx context.Context, searchQuery *SearchQueryBuilder, r Reducer) error {
+	_, err := es.filterCappedToReducer(ctx, searchQuery, 0, r)
+	return err
+}
+
+// filterCappedToReducer calls r for every event of the stores, the result is truncated to resultCap if it's not 0.
+// The statement of a compiled query keeps the limit of its first execution,
+// the result is truncated regardless of the limit of the statement.
+// Events of a search query with [SearchQueryBuilder.LastEvents] are buffered and r is called in ascending order.
+// If the storage returns [ErrByteBudgetExceeded] the events read before are still passed to r and the error is returned.
+func (es *Eventstore) filterCappedToReducer(ctx context.Context, searchQuery *SearchQueryBuilder, resultCap uint64, r Reducer) (capped bool, err error) {
+	if err := searchQuery.Validate(); err != nil {
+		return false, err
+	}
+	if searchQuery.queryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, searchQuery.queryTimeout)
+		defer cancel()
+	}
+	if err := es.awaitPosition(ctx, searchQuery); err != nil {
+		return false, err
+	}
+	query := searchQuery
+	if resultCap > 0 && (query.limit == 0 || query.limit > resultCap) {
+		// one additional event is queried to know if the result exceeds the cap,
+		// the limit is set on a copy to keep the search query of the caller unchanged
+		limited := *searchQuery
+		limited.limit = resultCap + 1
+		query = &limited
+	}
+
+	reduce := r
+	var lastEvents []Event
+	if query.lastEvents {
+		lastEvents = make([]Event, 0, query.limit)
+		reduce = func(event Event) error {
+			lastEvents = append(lastEvents, event)
+			return nil
+		}
+	}
+	var reduced uint64
+	err = es.filterStoresToReducer(ctx, query, func(event Event) error {
+		if resultCap > 0 && reduced >= resultCap {
+			capped = true
+			return nil
+		}
+		reduced++
+		return reduce(event)
+	})
+	if err != nil && !errors.Is(err, ErrByteBudgetExceeded) {
+		return false, err
+	}
+	for i := len(lastEvents) - 1; i >= 0; i-- {
+		if err := r(lastEvents[i]); err != nil {
+			return false, err
+		}
+	}
+	return capped, err
+}
+
+// capsResult returns true if the result cap is configured and the search query reads the events of a single instance
+func (es *Eventstore) capsResult(searchQuery *SearchQueryBuilder) bool {
+	return es.instanceResultCap > 0 &&
+		searchQuery.columns == ColumnsEvent &&
+		searchQuery.instanceID != nil &&
+		*searchQuery.instanceID != "" &&
+		len(searchQuery.instanceIDs) == 0
+}
+
+// boundInstanceID returns the instance id of the search query,
+// the value bound to the parameter if the instance id is a placeholder of a compiled query
+func (builder *SearchQueryBuilder) boundInstanceID() any {
+	if name, ok := QueryParamName(*builder.instanceID); ok {
+		return builder.params[name]
+	}
+	return *builder.instanceID
+}
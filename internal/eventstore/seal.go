@@ -0,0 +1,125 @@
+package eventstore
+
+import (
+	"context"
+	"slices"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// The seal events are pushed on the instance aggregate by the instance repository.
+// They are declared here because the eventstore rejects the pushes to sealed instances, see [Config.RejectSealedInstances].
+const (
+	InstanceSealedType   EventType = "instance.sealed"
+	InstanceUnsealedType EventType = "instance.unsealed"
+
+	instanceAggregateType AggregateType = "instance"
+)
+
+// SealingPusher is implemented by pushers which check the seal of the instances within the push transaction,
+// so the events can't be stored after the instance was sealed.
+type SealingPusher interface {
+	// PushUnsealed pushes the commands like [Pusher.Push]
+	// but fails with a precondition failed error if one of the instances returned by [InstancesToCheckSeal] is sealed.
+	PushUnsealed(ctx context.Context, commands ...Command) (_ []Event, err error)
+}
+
+// RejectsSealedInstances returns true if pushes to sealed instances are rejected, see [Config.RejectSealedInstances]
+func (es *Eventstore) RejectsSealedInstances() bool {
+	return es.rejectSealedInstances
+}
+
+// InstancesToCheckSeal returns the instances of the commands which must not be sealed for the push.
+// The commands of an instance are allowed if all of them unseal it.
+func InstancesToCheckSeal(cmds []Command) []string {
+	unsealing := make(map[string]bool, 1)
+	instanceIDs := make([]string, 0, 1)
+	for _, cmd := range cmds {
+		instanceID := cmd.Aggregate().InstanceID
+		if instanceID == "" {
+			continue
+		}
+		isUnseal := cmd.Type() == InstanceUnsealedType
+		previous, ok := unsealing[instanceID]
+		if !ok {
+			instanceIDs = append(instanceIDs, instanceID)
+		}
+		unsealing[instanceID] = isUnseal && (!ok || previous)
+	}
+	return slices.DeleteFunc(instanceIDs, func(instanceID string) bool {
+		return unsealing[instanceID]
+	})
+}
+
+// checkSealed returns an error if one of the instances of the commands is sealed.
+// It's used for pushers which don't implement [SealingPusher].
+func (es *Eventstore) checkSealed(ctx context.Context, cmds []Command) error {
+	for _, instanceID := range InstancesToCheckSeal(cmds) {
+		sealed, err := es.isSealed(ctx, instanceID)
+		if err != nil {
+			return err
+		}
+		if sealed {
+			return zerrors.ThrowPreconditionFailed(nil, "EVENT-Sl4qe", "Errors.Instance.Sealed")
+		}
+	}
+	return nil
+}
+
+// isSealed returns true if the latest seal event of the instance sealed it
+func (es *Eventstore) isSealed(ctx context.Context, instanceID string) (sealed bool, err error) {
+	err = es.querier.FilterToReducer(ctx,
+		NewSearchQueryBuilder(ColumnsEvent).
+			InstanceID(instanceID).
+			OrderDesc().
+			Limit(1).
+			AddQuery().
+			AggregateTypes(instanceAggregateType).
+			AggregateIDs(instanceID).
+			EventTypes(InstanceSealedType, InstanceUnsealedType).
+			Builder(),
+		func(event Event) error {
+			sealed = event.Type() == InstanceSealedType
+			return nil
+		},
+	)
+	return sealed, err
+}
+
+// InstanceState contains the unique constraints and fields of an instance, which are stored alongside its events
+type InstanceState struct {
+	// UniqueConstraints are the constraints the instance holds, global constraints are flagged by [UniqueConstraint.IsGlobal]
+	UniqueConstraints []*UniqueConstraint
+	Fields            []*Field
+}
+
+// Importer is implemented by pushers which are able to store the events of another deployment
+type Importer interface {
+	// InstanceState returns the unique constraints and fields stored for the instance.
+	// Global unique constraints aren't returned because they can't be assigned to an instance.
+	InstanceState(ctx context.Context, instanceID string) (*InstanceState, error)
+	// Import stores the events as they are including their creation date and position
+	// together with the unique constraints and fields of the state.
+	Import(ctx context.Context, state *InstanceState, events ...Event) error
+}
+
+// InstanceState returns the unique constraints and fields stored for the instance, see [Importer.InstanceState]
+func (es *Eventstore) InstanceState(ctx context.Context, instanceID string) (*InstanceState, error) {
+	importer, ok := es.pusher.(Importer)
+	if !ok {
+		return nil, zerrors.ThrowUnimplemented(nil, "EVENT-Im5ce", "pusher doesn't support imports")
+	}
+	return importer.InstanceState(ctx, instanceID)
+}
+
+// Import stores the events exported from another deployment
+// and the unique constraints and fields of the state in a single transaction,
+// the creation date, sequence and position of the events are preserved.
+// The events aren't checked against the seal of their instance.
+func (es *Eventstore) Import(ctx context.Context, state *InstanceState, events ...Event) error {
+	importer, ok := es.pusher.(Importer)
+	if !ok {
+		return zerrors.ThrowUnimplemented(nil, "EVENT-Im3ka", "pusher doesn't support imports")
+	}
+	return importer.Import(ctx, state, events...)
+}
@@ -3,8 +3,15 @@ package eventstore
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
 	"time"
 
+	"github.com/zitadel/logging"
+
 	"github.com/zitadel/zitadel/internal/api/authz"
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
@@ -14,20 +21,40 @@ import (
 type SearchQueryBuilder struct {
 	columns               Columns
 	limit                 uint64
-	offset                uint32
+	offset                uint64
 	desc                  bool
-	resourceOwner         string
+	resourceOwners        []string
 	instanceID            *string
 	instanceIDs           []string
 	editorUser            string
+	editorUsers           []string
+	producerVersion       string
+	producerVersionBefore string
+	maintenanceWindow     string
 	queries               []*SearchQuery
 	tx                    *sql.Tx
+	forUpdate             bool
 	allowTimeTravel       bool
+	timeTravelTime        time.Time
 	positionAfter         float64
+	positionAtOrAfter     float64
 	awaitOpenTransactions bool
+	awaitPosition         float64
 	creationDateAfter     time.Time
 	creationDateBefore    time.Time
 	eventSequenceGreater  uint64
+	aggregateIDsOrder     []string
+	orderByEventType      bool
+	orderByRelevance      bool
+	nullsOrder            NullsOrder
+	includeArchive        bool
+	includeOrigin         bool
+	optimizeForTenant     bool
+	byteBudget            int
+	lastEvents            bool
+	queryTimeout          time.Duration
+	compiled              *CompiledQuery
+	params                map[string]any
 }
 
 func (b *SearchQueryBuilder) GetColumns() Columns {
@@ -38,7 +65,7 @@ func (b *SearchQueryBuilder) GetLimit() uint64 {
 	return b.limit
 }
 
-func (b *SearchQueryBuilder) GetOffset() uint32 {
+func (b *SearchQueryBuilder) GetOffset() uint64 {
 	return b.offset
 }
 
@@ -46,8 +73,13 @@ func (b *SearchQueryBuilder) GetDesc() bool {
 	return b.desc
 }
 
-func (b *SearchQueryBuilder) GetResourceOwner() string {
-	return b.resourceOwner
+// GetLastEvents returns true if the builder queries the last events, see [SearchQueryBuilder.LastEvents]
+func (b *SearchQueryBuilder) GetLastEvents() bool {
+	return b.lastEvents
+}
+
+func (b *SearchQueryBuilder) GetResourceOwners() []string {
+	return b.resourceOwners
 }
 
 func (b *SearchQueryBuilder) GetInstanceID() *string {
@@ -62,6 +94,22 @@ func (b *SearchQueryBuilder) GetEditorUser() string {
 	return b.editorUser
 }
 
+func (b *SearchQueryBuilder) GetEditorUsers() []string {
+	return b.editorUsers
+}
+
+func (b *SearchQueryBuilder) GetProducedByVersion() string {
+	return b.producerVersion
+}
+
+func (b *SearchQueryBuilder) GetProducedByVersionBefore() string {
+	return b.producerVersionBefore
+}
+
+func (b *SearchQueryBuilder) GetMaintenanceWindow() string {
+	return b.maintenanceWindow
+}
+
 func (b *SearchQueryBuilder) GetQueries() []*SearchQuery {
 	return b.queries
 }
@@ -70,6 +118,10 @@ func (b *SearchQueryBuilder) GetTx() *sql.Tx {
 	return b.tx
 }
 
+func (b *SearchQueryBuilder) GetForUpdate() bool {
+	return b.forUpdate
+}
+
 func (b *SearchQueryBuilder) GetAllowTimeTravel() bool {
 	return b.allowTimeTravel
 }
@@ -78,10 +130,22 @@ func (b SearchQueryBuilder) GetPositionAfter() float64 {
 	return b.positionAfter
 }
 
+func (b SearchQueryBuilder) GetPositionAtOrAfter() float64 {
+	return b.positionAtOrAfter
+}
+
 func (b SearchQueryBuilder) GetAwaitOpenTransactions() bool {
 	return b.awaitOpenTransactions
 }
 
+func (b *SearchQueryBuilder) GetTimeTravelTime() time.Time {
+	return b.timeTravelTime
+}
+
+func (b *SearchQueryBuilder) GetAwaitPosition() float64 {
+	return b.awaitPosition
+}
+
 func (q SearchQueryBuilder) GetEventSequenceGreater() uint64 {
 	return q.eventSequenceGreater
 }
@@ -94,19 +158,97 @@ func (q SearchQueryBuilder) GetCreationDateBefore() time.Time {
 	return q.creationDateBefore
 }
 
+func (q SearchQueryBuilder) GetAggregateIDsOrder() []string {
+	return q.aggregateIDsOrder
+}
+
+func (q SearchQueryBuilder) GetOrderByEventTypeThenDate() bool {
+	return q.orderByEventType
+}
+
+func (q SearchQueryBuilder) GetOrderByRelevance() bool {
+	return q.orderByRelevance
+}
+
+func (q SearchQueryBuilder) GetIncludeOrigin() bool {
+	return q.includeOrigin
+}
+
+func (q SearchQueryBuilder) GetNullsOrder() NullsOrder {
+	return q.nullsOrder
+}
+
+func (q SearchQueryBuilder) GetIncludeArchive() bool {
+	return q.includeArchive
+}
+
+func (q SearchQueryBuilder) GetOptimizeForTenant() bool {
+	return q.optimizeForTenant
+}
+
+func (q SearchQueryBuilder) GetByteBudget() int {
+	return q.byteBudget
+}
+
+func (q SearchQueryBuilder) GetQueryTimeout() time.Duration {
+	return q.queryTimeout
+}
+
+func (q SearchQueryBuilder) GetCompiledQuery() *CompiledQuery {
+	return q.compiled
+}
+
+func (q SearchQueryBuilder) GetQueryParams() map[string]any {
+	return q.params
+}
+
 // ensureInstanceID makes sure that the instance id is always set
 func (b *SearchQueryBuilder) ensureInstanceID(ctx context.Context) {
+	if b.compiled != nil {
+		b.ensureInstanceIDParam(ctx)
+		return
+	}
 	if b.instanceID == nil && len(b.instanceIDs) == 0 && authz.GetInstance(ctx).InstanceID() != "" {
 		b.InstanceID(authz.GetInstance(ctx).InstanceID())
 	}
 }
 
+// ensureInstanceIDParam binds the instance id of the context if the compiled query was compiled without instance
+func (b *SearchQueryBuilder) ensureInstanceIDParam(ctx context.Context) {
+	if b.instanceID == nil {
+		return
+	}
+	if name, ok := QueryParamName(*b.instanceID); !ok || name != InstanceIDParam {
+		return
+	}
+	if _, ok := b.params[InstanceIDParam]; ok {
+		return
+	}
+	params := make(map[string]any, len(b.params)+1)
+	for name, value := range b.params {
+		params[name] = value
+	}
+	params[InstanceIDParam] = authz.GetInstance(ctx).InstanceID()
+	b.params = params
+}
+
 type SearchQuery struct {
-	builder        *SearchQueryBuilder
-	aggregateTypes []AggregateType
-	aggregateIDs   []string
-	eventTypes     []EventType
-	eventData      map[string]interface{}
+	builder              *SearchQueryBuilder
+	aggregateTypes       []AggregateType
+	aggregateIDs         []string
+	largeAggregateIDs    bool
+	excludedAggregateIDs []string
+	aggregateVersions    []Version
+	eventTypes           []EventType
+	excludedEventTypes   []EventType
+	creators             []string
+	eventData            map[string]interface{}
+	eventDataMissingKeys []string
+	eventDataText        string
+	eventDataConditions  []EventDataCondition
+	sequenceGreater      uint64
+	sequenceLess         uint64
+	limit                uint64
 }
 
 func (q SearchQuery) GetAggregateTypes() []AggregateType {
@@ -117,28 +259,107 @@ func (q SearchQuery) GetAggregateIDs() []string {
 	return q.aggregateIDs
 }
 
+// GetLargeAggregateIDs returns true if the aggregate ids were set using [SearchQuery.AggregateIDsLarge]
+func (q SearchQuery) GetLargeAggregateIDs() bool {
+	return q.largeAggregateIDs
+}
+
+func (q SearchQuery) GetExcludedAggregateIDs() []string {
+	return q.excludedAggregateIDs
+}
+
 func (q SearchQuery) GetEventTypes() []EventType {
 	return q.eventTypes
 }
 
+func (q SearchQuery) GetExcludeEventTypes() []EventType {
+	return q.excludedEventTypes
+}
+
+func (q SearchQuery) GetCreators() []string {
+	return q.creators
+}
+
 func (q SearchQuery) GetEventData() map[string]interface{} {
 	return q.eventData
 }
 
+func (q SearchQuery) GetEventDataMissingKeys() []string {
+	return q.eventDataMissingKeys
+}
+
+func (q SearchQuery) GetAggregateVersions() []Version {
+	return q.aggregateVersions
+}
+
+func (q SearchQuery) GetEventDataText() string {
+	return q.eventDataText
+}
+
+func (q SearchQuery) GetEventDataConditions() []EventDataCondition {
+	return q.eventDataConditions
+}
+
+func (q SearchQuery) GetSequenceGreater() uint64 {
+	return q.sequenceGreater
+}
+
+func (q SearchQuery) GetSequenceLess() uint64 {
+	return q.sequenceLess
+}
+
+func (q SearchQuery) GetLimit() uint64 {
+	return q.limit
+}
+
 // Columns defines which fields of the event are needed for the query
 type Columns int8
 
 const (
 	//ColumnsEvent represents all fields of an event
-	ColumnsEvent = iota + 1
+	ColumnsEvent Columns = iota + 1
 	// ColumnsMaxSequence represents the latest sequence of the filtered events
 	ColumnsMaxSequence
 	// ColumnsInstanceIDs represents the instance ids of the filtered events
 	ColumnsInstanceIDs
+	// ColumnsCount represents the amount of the filtered events
+	ColumnsCount
+	// ColumnsMaxPosition represents the highest position of the filtered events
+	ColumnsMaxPosition
 
 	columnsCount
 )
 
+// NullsOrder defines where the storage orders null values of the sort columns
+type NullsOrder int8
+
+const (
+	// NullsOrderDefault keeps the ordering of the database,
+	// postgres orders nulls last in ascending and first in descending order
+	NullsOrderDefault NullsOrder = iota
+	// NullsOrderFirst orders null values before all other values
+	NullsOrderFirst
+	// NullsOrderLast orders null values after all other values
+	NullsOrderLast
+)
+
+// String returns the name of the columns for logging and error messages
+func (c Columns) String() string {
+	switch c {
+	case ColumnsEvent:
+		return "event"
+	case ColumnsMaxSequence:
+		return "max_sequence"
+	case ColumnsInstanceIDs:
+		return "instance_ids"
+	case ColumnsCount:
+		return "count"
+	case ColumnsMaxPosition:
+		return "max_position"
+	}
+	return "columns(" + strconv.Itoa(int(c)) + ")"
+}
+
 func (c Columns) Validate() error {
 	if c <= 0 || c >= columnsCount {
 		return zerrors.ThrowPreconditionFailed(nil, "REPOS-x8R35", "column out of range")
@@ -146,6 +367,72 @@ func (c Columns) Validate() error {
 	return nil
 }
 
+// Validate checks the builder for filters which can't return a meaningful result,
+// so the query fails instead of silently returning no events
+func (b *SearchQueryBuilder) Validate() error {
+	if err := b.columns.Validate(); err != nil {
+		return err
+	}
+	if b.offset > 0 && b.limit == 0 {
+		return zerrors.ThrowPreconditionFailed(nil, "EVENT-Vq2lo", "offset requires a limit")
+	}
+	if b.awaitPosition > 0 && b.allowTimeTravel {
+		return zerrors.ThrowPreconditionFailed(nil, "EVENT-Aw2tt", "await position and time travel are mutually exclusive")
+	}
+	if !b.timeTravelTime.IsZero() {
+		if b.positionAfter > 0 || b.awaitPosition > 0 {
+			return zerrors.ThrowPreconditionFailed(nil, "EVENT-Tt4pa", "time travel to a timestamp and positions are mutually exclusive")
+		}
+		if b.tx != nil {
+			return zerrors.ThrowPreconditionFailed(nil, "EVENT-Tt5tx", "time travel to a timestamp is not possible in a transaction")
+		}
+	}
+	for _, query := range b.queries {
+		if err := query.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate checks that the sub query has at least one filter and no contradicting filters
+func (q *SearchQuery) validate() error {
+	if len(q.aggregateTypes) == 0 &&
+		len(q.aggregateIDs) == 0 &&
+		len(q.excludedAggregateIDs) == 0 &&
+		len(q.aggregateVersions) == 0 &&
+		len(q.eventTypes) == 0 &&
+		len(q.excludedEventTypes) == 0 &&
+		len(q.creators) == 0 &&
+		len(q.eventData) == 0 &&
+		len(q.eventDataMissingKeys) == 0 &&
+		q.eventDataText == "" &&
+		len(q.eventDataConditions) == 0 &&
+		q.sequenceGreater == 0 &&
+		q.sequenceLess == 0 {
+		return zerrors.ThrowPreconditionFailed(nil, "EVENT-Vq5no", "sub query without filter")
+	}
+	for _, id := range q.aggregateIDs {
+		if slices.Contains(q.excludedAggregateIDs, id) {
+			return zerrors.ThrowPreconditionFailed(nil, "EVENT-Vq7xa", "aggregate id is included and excluded")
+		}
+	}
+	for _, eventType := range q.eventTypes {
+		if slices.Contains(q.excludedEventTypes, eventType) {
+			return zerrors.ThrowPreconditionFailed(nil, "EVENT-Vq9pe", "event type is included and excluded")
+		}
+	}
+	if q.sequenceLess > 0 && q.sequenceLess <= q.sequenceGreater+1 {
+		return zerrors.ThrowPreconditionFailed(nil, "EVENT-Vq3sq", "sequence range is empty")
+	}
+	for _, condition := range q.eventDataConditions {
+		if err := condition.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // NewSearchQueryBuilder creates a new builder for event filters
 // aggregateTypes must contain at least one aggregate type
 func NewSearchQueryBuilder(columns Columns) *SearchQueryBuilder {
@@ -154,35 +441,128 @@ func NewSearchQueryBuilder(columns Columns) *SearchQueryBuilder {
 	}
 }
 
+// Clone returns a copy of the builder which can be changed without changing the builder,
+// the slices and maps of the builder and its sub queries are copied.
+// The transaction and the compiled query are shared.
+func (builder *SearchQueryBuilder) Clone() *SearchQueryBuilder {
+	clone := *builder
+	if builder.instanceID != nil {
+		instanceID := *builder.instanceID
+		clone.instanceID = &instanceID
+	}
+	clone.resourceOwners = slices.Clone(builder.resourceOwners)
+	clone.instanceIDs = slices.Clone(builder.instanceIDs)
+	clone.editorUsers = slices.Clone(builder.editorUsers)
+	clone.aggregateIDsOrder = slices.Clone(builder.aggregateIDsOrder)
+	clone.params = maps.Clone(builder.params)
+
+	if builder.queries != nil {
+		clone.queries = make([]*SearchQuery, len(builder.queries))
+	}
+	for i, query := range builder.queries {
+		clone.queries[i] = query.clone(&clone)
+	}
+	return &clone
+}
+
+// clone returns a copy of the sub query for the builder
+func (query *SearchQuery) clone(builder *SearchQueryBuilder) *SearchQuery {
+	return &SearchQuery{
+		builder:              builder,
+		aggregateTypes:       slices.Clone(query.aggregateTypes),
+		aggregateIDs:         slices.Clone(query.aggregateIDs),
+		largeAggregateIDs:    query.largeAggregateIDs,
+		excludedAggregateIDs: slices.Clone(query.excludedAggregateIDs),
+		aggregateVersions:    slices.Clone(query.aggregateVersions),
+		eventTypes:           slices.Clone(query.eventTypes),
+		excludedEventTypes:   slices.Clone(query.excludedEventTypes),
+		creators:             slices.Clone(query.creators),
+		eventData:            maps.Clone(query.eventData),
+		eventDataMissingKeys: slices.Clone(query.eventDataMissingKeys),
+		eventDataText:        query.eventDataText,
+		eventDataConditions:  slices.Clone(query.eventDataConditions),
+		sequenceGreater:      query.sequenceGreater,
+		sequenceLess:         query.sequenceLess,
+		limit:                query.limit,
+	}
+}
+
 func (builder *SearchQueryBuilder) Matches(commands ...Command) []Command {
 	matches := make([]Command, 0, len(commands))
+	// the limits of the sub queries are applied before the offset of the builder
+	subQueryMatches := make([]uint64, len(builder.queries))
 	for i, command := range commands {
 		if builder.limit > 0 && builder.limit <= uint64(len(matches)) {
 			break
 		}
-		if builder.offset > 0 && uint32(i) < builder.offset {
+		matched := builder.matchCommand(command, subQueryMatches)
+		if builder.offset > 0 && uint64(i) < builder.offset {
 			continue
 		}
 
-		if builder.matchCommand(command) {
+		if matched {
 			matches = append(matches, command)
 		}
 	}
+	if len(builder.aggregateIDsOrder) > 0 {
+		builder.sortByAggregateIDsOrder(matches)
+	} else if builder.orderByEventType {
+		builder.sortByEventType(matches)
+	}
 
 	return matches
 }
 
+// sortByAggregateIDsOrder sorts the commands by the position of their aggregate id in [SearchQueryBuilder.aggregateIDsOrder].
+// The order of commands with the same aggregate id is preserved.
+func (builder *SearchQueryBuilder) sortByAggregateIDsOrder(commands []Command) {
+	positions := make(map[string]int, len(builder.aggregateIDsOrder))
+	for i, id := range builder.aggregateIDsOrder {
+		if _, ok := positions[id]; !ok {
+			positions[id] = i
+		}
+	}
+	sort.SliceStable(commands, func(i, j int) bool {
+		if builder.desc {
+			return positions[commands[i].Aggregate().ID] > positions[commands[j].Aggregate().ID]
+		}
+		return positions[commands[i].Aggregate().ID] < positions[commands[j].Aggregate().ID]
+	})
+}
+
+// sortByEventType sorts the commands by their event type.
+// The order of commands with the same event type is preserved.
+func (builder *SearchQueryBuilder) sortByEventType(commands []Command) {
+	sort.SliceStable(commands, func(i, j int) bool {
+		if builder.desc {
+			return commands[i].Type() > commands[j].Type()
+		}
+		return commands[i].Type() < commands[j].Type()
+	})
+}
+
 type sequencer interface {
 	Sequence() uint64
 }
 
-func (builder *SearchQueryBuilder) matchCommand(command Command) bool {
-	if builder.resourceOwner != "" && command.Aggregate().ResourceOwner != builder.resourceOwner {
+// matchCommand checks if the command matches the builder,
+// subQueryMatches counts the matches of each sub query to apply [SearchQuery.Limit]
+func (builder *SearchQueryBuilder) matchCommand(command Command, subQueryMatches []uint64) bool {
+	if len(builder.resourceOwners) > 0 && !slices.Contains(builder.resourceOwners, command.Aggregate().ResourceOwner) {
 		return false
 	}
 	if command.Aggregate().InstanceID != "" && builder.instanceID != nil && *builder.instanceID != "" && command.Aggregate().InstanceID != *builder.instanceID {
 		return false
 	}
+	if command.Aggregate().InstanceID != "" && len(builder.instanceIDs) > 0 && !slices.Contains(builder.instanceIDs, command.Aggregate().InstanceID) {
+		return false
+	}
+	if builder.editorUser != "" && command.Creator() != builder.editorUser {
+		return false
+	}
+	if len(builder.editorUsers) > 0 && !slices.Contains(builder.editorUsers, command.Creator()) {
+		return false
+	}
 	if seq, ok := command.(sequencer); ok {
 		if builder.eventSequenceGreater > 0 && seq.Sequence() <= builder.eventSequenceGreater {
 			return false
@@ -192,12 +572,17 @@ func (builder *SearchQueryBuilder) matchCommand(command Command) bool {
 	if len(builder.queries) == 0 {
 		return true
 	}
-	for _, query := range builder.queries {
-		if query.matches(command) {
-			return true
+	var matched bool
+	for i, query := range builder.queries {
+		if !query.matches(command) {
+			continue
+		}
+		subQueryMatches[i]++
+		if query.limit == 0 || subQueryMatches[i] <= query.limit {
+			matched = true
 		}
 	}
-	return false
+	return matched
 }
 
 // Columns defines which fields are set
@@ -212,15 +597,24 @@ func (builder *SearchQueryBuilder) Limit(limit uint64) *SearchQueryBuilder {
 	return builder
 }
 
-// Limit defines how many events are returned maximally.
-func (builder *SearchQueryBuilder) Offset(offset uint32) *SearchQueryBuilder {
+// Offset defines how many events are skipped.
+func (builder *SearchQueryBuilder) Offset(offset uint64) *SearchQueryBuilder {
 	builder.offset = offset
 	return builder
 }
 
-// ResourceOwner defines the resource owner (org or instance) of the events
+// ResourceOwner defines the resource owner (org or instance) of the events,
+// an empty resource owner removes the filter
 func (builder *SearchQueryBuilder) ResourceOwner(resourceOwner string) *SearchQueryBuilder {
-	builder.resourceOwner = resourceOwner
+	if resourceOwner == "" {
+		return builder.ResourceOwners()
+	}
+	return builder.ResourceOwners(resourceOwner)
+}
+
+// ResourceOwners filters for events of any of the given resource owners (orgs or instances)
+func (builder *SearchQueryBuilder) ResourceOwners(resourceOwners ...string) *SearchQueryBuilder {
+	builder.resourceOwners = resourceOwners
 	return builder
 }
 
@@ -230,8 +624,9 @@ func (builder *SearchQueryBuilder) InstanceID(instanceID string) *SearchQueryBui
 	return builder
 }
 
-// InstanceIDs defines the instanceIDs (system) of the events
-func (builder *SearchQueryBuilder) InstanceIDs(instanceIDs []string) *SearchQueryBuilder {
+// InstanceIDs defines the instanceIDs (system) of the events, e.g. to replay the events of a subset of the instances.
+// The instance of the context isn't added by the eventstore if the instance ids are set.
+func (builder *SearchQueryBuilder) InstanceIDs(instanceIDs ...string) *SearchQueryBuilder {
 	builder.instanceIDs = instanceIDs
 	return builder
 }
@@ -239,12 +634,93 @@ func (builder *SearchQueryBuilder) InstanceIDs(instanceIDs []string) *SearchQuer
 // OrderDesc changes the sorting order of the returned events to descending
 func (builder *SearchQueryBuilder) OrderDesc() *SearchQueryBuilder {
 	builder.desc = true
+	builder.lastEvents = false
 	return builder
 }
 
 // OrderAsc changes the sorting order of the returned events to ascending
 func (builder *SearchQueryBuilder) OrderAsc() *SearchQueryBuilder {
 	builder.desc = false
+	builder.lastEvents = false
+	return builder
+}
+
+// LastEvents queries the n most recent events, they are returned in ascending order.
+// The storage is queried in descending order limited to n, the eventstore reverses the result.
+// A later call of [SearchQueryBuilder.OrderAsc] or [SearchQueryBuilder.OrderDesc] disables the mode.
+func (builder *SearchQueryBuilder) LastEvents(n uint64) *SearchQueryBuilder {
+	builder.desc = true
+	builder.limit = n
+	builder.lastEvents = true
+	return builder
+}
+
+// OrderByEventTypeThenDate orders the returned events by event type
+// and the events of the same event type by their creation date.
+// The order is primarily useful for bounded result sets (e.g. using [SearchQueryBuilder.Limit] or a single aggregate),
+// because the storage has to sort all matching events before returning the first one.
+// [SearchQuery.AggregateIDsOrdered] takes precedence over this order.
+func (builder *SearchQueryBuilder) OrderByEventTypeThenDate() *SearchQueryBuilder {
+	builder.orderByEventType = true
+	return builder
+}
+
+// OrderByRelevance orders the events by their relevance for the texts of [SearchQuery.EventDataText], the most relevant first.
+// Events with the same relevance are ordered by the default order.
+// The relevance of each event is returned by [EventRelevance].
+// The relevance is calculated by the storage using ts_rank of the full-text search,
+// which must be supported by the database (PostgreSQL or CockroachDB 23.1 and later).
+// It's only allowed for [ColumnsEvent] and requires at least one sub query with a text.
+// [SearchQuery.AggregateIDsOrdered] and [SearchQueryBuilder.OrderByEventTypeThenDate] take precedence over this order.
+func (builder *SearchQueryBuilder) OrderByRelevance() *SearchQueryBuilder {
+	builder.orderByRelevance = true
+	return builder
+}
+
+// IncludeOrigin additionally reads the version of ZITADEL which pushed the events and their maintenance window,
+// both are returned by [EventOrigin]. It's only allowed for [ColumnsEvent],
+// e.g. to export the events including all their stored fields.
+func (builder *SearchQueryBuilder) IncludeOrigin() *SearchQueryBuilder {
+	builder.includeOrigin = true
+	return builder
+}
+
+// NullsFirst orders null values of the sort columns before all other values regardless of the sorting direction
+func (builder *SearchQueryBuilder) NullsFirst() *SearchQueryBuilder {
+	builder.nullsOrder = NullsOrderFirst
+	return builder
+}
+
+// NullsLast orders null values of the sort columns after all other values regardless of the sorting direction.
+// It keeps the pagination over optional columns (e.g. the creation date of [SearchQueryBuilder.OrderByEventTypeThenDate]) stable
+// if the sorting direction changes.
+func (builder *SearchQueryBuilder) NullsLast() *SearchQueryBuilder {
+	builder.nullsOrder = NullsOrderLast
+	return builder
+}
+
+// ErrByteBudgetExceeded is returned by the storage if it stopped reading events because of the [SearchQueryBuilder.ByteBudget],
+// the events within the budget were passed to the reducer.
+var ErrByteBudgetExceeded = errors.New("byte budget of the search query exceeded")
+
+// ByteBudget limits the cumulative size of the payloads of the returned events.
+// The storage stops reading events before the size of the payloads exceeds the budget
+// and returns [ErrByteBudgetExceeded] to signal the truncated result.
+// The budget is checked after the events were fetched from the database while streaming them,
+// so it protects the response and not the database.
+// It's only allowed for [ColumnsEvent], a budget of 0 disables the check.
+func (builder *SearchQueryBuilder) ByteBudget(bytes int) *SearchQueryBuilder {
+	builder.byteBudget = bytes
+	return builder
+}
+
+// QueryTimeout limits the execution time of the query.
+// The eventstore derives a context with the timeout for the query, without timeout the context of the caller is used.
+// If the context has a deadline, the statement timeout on the database is the earlier of the timeout and the deadline.
+// The statement timeout isn't applied to a transaction set by [SearchQueryBuilder.SetTx] because it would affect the whole transaction
+// and not to time travel queries, see [SearchQueryBuilder.AllowTimeTravel].
+func (builder *SearchQueryBuilder) QueryTimeout(timeout time.Duration) *SearchQueryBuilder {
+	builder.queryTimeout = timeout
 	return builder
 }
 
@@ -254,11 +730,52 @@ func (builder *SearchQueryBuilder) SetTx(tx *sql.Tx) *SearchQueryBuilder {
 	return builder
 }
 
+// ForUpdate locks the returned events until the transaction set by [SearchQueryBuilder.SetTx] ends.
+// Concurrent writers of the locked aggregates wait instead of failing with a concurrency error,
+// which is useful for aggregates written by many requests at the same time.
+// The query fails if no transaction is set or other columns than [ColumnsEvent] are queried.
+//
+// Locks are held until the end of the transaction so keep it short.
+// Transactions locking multiple aggregates must lock them in the same order to prevent deadlocks.
+func (builder *SearchQueryBuilder) ForUpdate() *SearchQueryBuilder {
+	builder.forUpdate = true
+	return builder
+}
+
 func (builder *SearchQueryBuilder) EditorUser(id string) *SearchQueryBuilder {
 	builder.editorUser = id
 	return builder
 }
 
+// EditorUsers filters for events created by any of the given users.
+// If [SearchQueryBuilder.EditorUser] is set as well, the events must match both filters.
+func (builder *SearchQueryBuilder) EditorUsers(ids ...string) *SearchQueryBuilder {
+	builder.editorUsers = ids
+	return builder
+}
+
+// ProducedByVersion filters for events pushed by the given version of ZITADEL.
+// Events pushed before the version was stored are never returned.
+func (builder *SearchQueryBuilder) ProducedByVersion(version string) *SearchQueryBuilder {
+	builder.producerVersion = version
+	return builder
+}
+
+// ProducedByVersionBefore filters for events pushed by versions of ZITADEL lower than the given semantic version (e.g. v2.54.3).
+// Only the major, minor and patch version are compared, pre-release and build metadata are ignored.
+// Events pushed by versions which aren't semantic versions (e.g. development builds) or before the version was stored are never returned.
+func (builder *SearchQueryBuilder) ProducedByVersionBefore(version string) *SearchQueryBuilder {
+	builder.producerVersionBefore = version
+	return builder
+}
+
+// MaintenanceWindow filters for events pushed during the maintenance window with the given label,
+// see [WithMaintenanceWindow]. Events pushed outside of a maintenance window are never returned.
+func (builder *SearchQueryBuilder) MaintenanceWindow(label string) *SearchQueryBuilder {
+	builder.maintenanceWindow = label
+	return builder
+}
+
 // AllowTimeTravel activates the time travel feature of the database if supported
 // The queries will be made based on the call time
 func (builder *SearchQueryBuilder) AllowTimeTravel() *SearchQueryBuilder {
@@ -266,12 +783,61 @@ func (builder *SearchQueryBuilder) AllowTimeTravel() *SearchQueryBuilder {
 	return builder
 }
 
+// TimeTravelTo queries the state of the store as of the given time if supported by the database,
+// e.g. for reproducible audit snapshots. It takes precedence over [SearchQueryBuilder.AllowTimeTravel].
+// The query fails if it's combined with [SearchQueryBuilder.PositionAfter], [SearchQueryBuilder.AwaitPosition] or a transaction.
+func (builder *SearchQueryBuilder) TimeTravelTo(t time.Time) *SearchQueryBuilder {
+	builder.timeTravelTime = t
+	return builder
+}
+
+// AwaitPosition blocks the query until an event at or after the position is visible in the store,
+// e.g. to read the events of a push in a read-your-writes manner.
+// The wait ends with the context or the [SearchQueryBuilder.QueryTimeout].
+// Time travel queries read a past state of the store, the query fails if [SearchQueryBuilder.AllowTimeTravel] is set as well.
+func (builder *SearchQueryBuilder) AwaitPosition(position float64) *SearchQueryBuilder {
+	builder.awaitPosition = position
+	return builder
+}
+
 // PositionAfter filters for events which happened after the specified time
+// The event at the position is excluded, use it to catch up from the position of the last processed event
+// to process each event exactly once.
 func (builder *SearchQueryBuilder) PositionAfter(position float64) *SearchQueryBuilder {
 	builder.positionAfter = position
 	return builder
 }
 
+// PositionAtOrAfter filters for events which happened at or after the specified position
+// The event at the position is included, use it to catch up at least once
+// if the event at the saved position might not have been processed completely.
+func (builder *SearchQueryBuilder) PositionAtOrAfter(position float64) *SearchQueryBuilder {
+	builder.positionAtOrAfter = position
+	return builder
+}
+
+// IncludeArchive continues the query into the archive of the eventstore if it reaches beyond the retention window of the hot store.
+// The events of the archive are merged in order with the events of the hot store, the stores must not contain the same events.
+// Queries of the archive are expected to be considerably slower, use it for audit queries and not for write models.
+// [SearchQueryBuilder.AwaitOpenTransactions], [SearchQueryBuilder.AllowTimeTravel], [SearchQueryBuilder.ForUpdate]
+// and the transaction don't apply to the archive, the byte budget applies to each store separately.
+// Offsets and compiled queries can't be combined with the archive.
+func (builder *SearchQueryBuilder) IncludeArchive() *SearchQueryBuilder {
+	builder.includeArchive = true
+	return builder
+}
+
+// OptimizeForTenant favors the index on instance id and owner (setup step 34) for the query
+// by comparing the owner directly after the instance.
+// No index hint is used, so the query still succeeds if the index doesn't exist.
+// It only takes effect if the query is restricted to a single instance and a resource owner,
+// other query shapes are executed unchanged.
+// Benchmark_Filter_OptimizeForTenant measures the query with and without the option.
+func (builder *SearchQueryBuilder) OptimizeForTenant() *SearchQueryBuilder {
+	builder.optimizeForTenant = true
+	return builder
+}
+
 // AwaitOpenTransactions filters for events which are older than the oldest transaction of the database
 func (builder *SearchQueryBuilder) AwaitOpenTransactions() *SearchQueryBuilder {
 	builder.awaitOpenTransactions = true
@@ -293,6 +859,25 @@ func (builder *SearchQueryBuilder) CreationDateAfter(creationDate time.Time) *Se
 	return builder
 }
 
+// CreationDateDay filters for events which happened on the calendar day of date in loc.
+// The day starts and ends at local midnight, so days with a daylight saving time transition last 23 or 25 hours.
+// If loc is nil, the location of date is used.
+func (builder *SearchQueryBuilder) CreationDateDay(date time.Time, loc *time.Location) *SearchQueryBuilder {
+	if date.IsZero() {
+		return builder
+	}
+	if loc == nil {
+		loc = date.Location()
+	}
+	year, month, day := date.In(loc).Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	end := time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+	// creation date after is exclusive, events at midnight belong to the day
+	builder.creationDateAfter = start.Add(-time.Nanosecond)
+	builder.creationDateBefore = end
+	return builder
+}
+
 // CreationDateBefore filters for events which happened before the specified time
 func (builder *SearchQueryBuilder) CreationDateBefore(creationDate time.Time) *SearchQueryBuilder {
 	if creationDate.IsZero() || creationDate.Unix() == 0 {
@@ -302,6 +887,214 @@ func (builder *SearchQueryBuilder) CreationDateBefore(creationDate time.Time) *S
 	return builder
 }
 
+// CreationDateBetween filters for events which happened after after and before before.
+// Zero bounds are ignored like in [SearchQueryBuilder.CreationDateAfter] and [SearchQueryBuilder.CreationDateBefore].
+// If after isn't before before, the builder is returned unchanged.
+func (builder *SearchQueryBuilder) CreationDateBetween(after, before time.Time) *SearchQueryBuilder {
+	if !after.IsZero() && !before.IsZero() && !after.Before(before) {
+		logging.WithFields("after", after, "before", before).Warn("creation date range is inverted and ignored")
+		return builder
+	}
+	return builder.CreationDateAfter(after).CreationDateBefore(before)
+}
+
+// Merge combines the filters of other into the builder, e.g. a base filter (instance, owner, date range)
+// with a feature specific filter built separately.
+//
+// The scope-level fields of both builders are AND-connected:
+//   - unset fields are taken from the builder which sets them
+//   - ranges are narrowed: the lower limit and byte budget, the later creation date after,
+//     the earlier creation date before, the higher position and sequence are used
+//   - flags (e.g. [SearchQueryBuilder.ForUpdate]) set on either builder are set,
+//     [SearchQueryBuilder.AllowTimeTravel] is only kept if both builders allow it
+//   - differing orders (see [SearchQueryBuilder.OrderDesc]) and times of [SearchQueryBuilder.TimeTravelTo] return an error
+//   - differing values which can't be narrowed (e.g. two resource owners) return an error
+//
+// The sub queries of other are copied and appended as additional OR-connected sub queries,
+// they don't restrict the sub queries of the builder.
+// The builder is only changed if the builders can be merged, other stays untouched. Compiled queries can't be merged.
+func (builder *SearchQueryBuilder) Merge(other *SearchQueryBuilder) (_ *SearchQueryBuilder, err error) {
+	if other == nil {
+		return builder, nil
+	}
+	if builder.compiled != nil || other.compiled != nil {
+		return nil, zerrors.ThrowInvalidArgument(nil, "EVENT-Ck8pw", "compiled queries cannot be merged")
+	}
+	columns, err := mergeScalar(builder.columns, other.columns, "EVENT-Hd2ms", "columns")
+	if err != nil {
+		return nil, err
+	}
+	offset, err := mergeScalar(builder.offset, other.offset, "EVENT-Zr5vn", "offset")
+	if err != nil {
+		return nil, err
+	}
+	resourceOwners, err := mergeIntersection(builder.resourceOwners, other.resourceOwners, "EVENT-Wq3kt", "resource owners")
+	if err != nil {
+		return nil, err
+	}
+	editorUser, err := mergeScalar(builder.editorUser, other.editorUser, "EVENT-Fj7xe", "editor user")
+	if err != nil {
+		return nil, err
+	}
+	producerVersion, err := mergeScalar(builder.producerVersion, other.producerVersion, "EVENT-Ny4ob", "producer version")
+	if err != nil {
+		return nil, err
+	}
+	producerVersionBefore, err := mergeScalar(builder.producerVersionBefore, other.producerVersionBefore, "EVENT-Gu9ri", "producer version before")
+	if err != nil {
+		return nil, err
+	}
+	maintenanceWindow, err := mergeScalar(builder.maintenanceWindow, other.maintenanceWindow, "EVENT-Mw3qd", "maintenance window")
+	if err != nil {
+		return nil, err
+	}
+	nullsOrder, err := mergeScalar(builder.nullsOrder, other.nullsOrder, "EVENT-Nl4xu", "nulls order")
+	if err != nil {
+		return nil, err
+	}
+	tx, err := mergeScalar(builder.tx, other.tx, "EVENT-Ls6hd", "transaction")
+	if err != nil {
+		return nil, err
+	}
+	instanceID, instanceIDs, err := builder.mergeInstanceIDs(other)
+	if err != nil {
+		return nil, err
+	}
+	editorUsers, err := mergeIntersection(builder.editorUsers, other.editorUsers, "EVENT-Xo8nd", "editor users")
+	if err != nil {
+		return nil, err
+	}
+	if !builder.timeTravelTime.IsZero() && !other.timeTravelTime.IsZero() && !builder.timeTravelTime.Equal(other.timeTravelTime) {
+		return nil, zerrors.ThrowInvalidArgument(nil, "EVENT-Tt6mg", "conflicting time travel times")
+	}
+	if len(builder.aggregateIDsOrder) > 0 && len(other.aggregateIDsOrder) > 0 && !slices.Equal(builder.aggregateIDsOrder, other.aggregateIDsOrder) {
+		return nil, zerrors.ThrowInvalidArgument(nil, "EVENT-Td1yq", "conflicting aggregate id orders")
+	}
+	if builder.desc != other.desc {
+		return nil, zerrors.ThrowInvalidArgument(nil, "EVENT-Od5rw", "conflicting order")
+	}
+
+	// all conflicts are checked, the builder is changed from here on
+	builder.columns = columns
+	builder.offset = offset
+	builder.resourceOwners = resourceOwners
+	builder.editorUser = editorUser
+	builder.producerVersion = producerVersion
+	builder.producerVersionBefore = producerVersionBefore
+	builder.maintenanceWindow = maintenanceWindow
+	builder.nullsOrder = nullsOrder
+	builder.tx = tx
+	builder.instanceID = instanceID
+	builder.instanceIDs = instanceIDs
+	builder.editorUsers = editorUsers
+	if builder.timeTravelTime.IsZero() {
+		builder.timeTravelTime = other.timeTravelTime
+	}
+	if len(builder.aggregateIDsOrder) == 0 {
+		builder.aggregateIDsOrder = slices.Clone(other.aggregateIDsOrder)
+	}
+	if other.limit > 0 && (builder.limit == 0 || other.limit < builder.limit) {
+		builder.limit = other.limit
+	}
+	if other.byteBudget > 0 && (builder.byteBudget == 0 || other.byteBudget < builder.byteBudget) {
+		builder.byteBudget = other.byteBudget
+	}
+	if other.queryTimeout > 0 && (builder.queryTimeout == 0 || other.queryTimeout < builder.queryTimeout) {
+		builder.queryTimeout = other.queryTimeout
+	}
+	if other.creationDateAfter.After(builder.creationDateAfter) {
+		builder.creationDateAfter = other.creationDateAfter
+	}
+	if !other.creationDateBefore.IsZero() && (builder.creationDateBefore.IsZero() || other.creationDateBefore.Before(builder.creationDateBefore)) {
+		builder.creationDateBefore = other.creationDateBefore
+	}
+	builder.positionAfter = max(builder.positionAfter, other.positionAfter)
+	builder.positionAtOrAfter = max(builder.positionAtOrAfter, other.positionAtOrAfter)
+	builder.awaitPosition = max(builder.awaitPosition, other.awaitPosition)
+	builder.eventSequenceGreater = max(builder.eventSequenceGreater, other.eventSequenceGreater)
+
+	builder.lastEvents = builder.lastEvents || other.lastEvents
+	builder.orderByEventType = builder.orderByEventType || other.orderByEventType
+	builder.orderByRelevance = builder.orderByRelevance || other.orderByRelevance
+	builder.includeArchive = builder.includeArchive || other.includeArchive
+	builder.includeOrigin = builder.includeOrigin || other.includeOrigin
+	builder.optimizeForTenant = builder.optimizeForTenant || other.optimizeForTenant
+	builder.forUpdate = builder.forUpdate || other.forUpdate
+	builder.awaitOpenTransactions = builder.awaitOpenTransactions || other.awaitOpenTransactions
+	builder.allowTimeTravel = builder.allowTimeTravel && other.allowTimeTravel
+
+	for _, query := range other.queries {
+		builder.queries = append(builder.queries, query.clone(builder))
+	}
+	return builder, nil
+}
+
+// mergeInstanceIDs returns the instances of both builders
+func (builder *SearchQueryBuilder) mergeInstanceIDs(other *SearchQueryBuilder) (instanceID *string, instanceIDs []string, err error) {
+	instanceID = builder.instanceID
+	if other.instanceID != nil {
+		if builder.instanceID != nil && *builder.instanceID != *other.instanceID {
+			return nil, nil, zerrors.ThrowInvalidArgument(nil, "EVENT-Pb3ua", "conflicting instance id")
+		}
+		id := *other.instanceID
+		instanceID = &id
+	}
+	switch {
+	case len(other.instanceIDs) == 0:
+		instanceIDs = builder.instanceIDs
+	case len(builder.instanceIDs) == 0:
+		instanceIDs = slices.Clone(other.instanceIDs)
+	default:
+		instanceIDs = make([]string, 0, len(builder.instanceIDs))
+		for _, id := range builder.instanceIDs {
+			if slices.Contains(other.instanceIDs, id) {
+				instanceIDs = append(instanceIDs, id)
+			}
+		}
+	}
+	if instanceID != nil && len(instanceIDs) > 0 {
+		if !slices.Contains(instanceIDs, *instanceID) {
+			return nil, nil, zerrors.ThrowInvalidArgument(nil, "EVENT-Ea7gk", "no common instance ids")
+		}
+		instanceIDs = nil
+	}
+	if len(instanceIDs) == 0 && (len(builder.instanceIDs) > 0 || len(other.instanceIDs) > 0) && instanceID == nil {
+		return nil, nil, zerrors.ThrowInvalidArgument(nil, "EVENT-Ri2vo", "no common instance ids")
+	}
+	return instanceID, instanceIDs, nil
+}
+
+// mergeIntersection returns the values of both builders, all values of the builder which sets them
+func mergeIntersection(values, other []string, id, field string) ([]string, error) {
+	if len(other) == 0 {
+		return values, nil
+	}
+	if len(values) == 0 {
+		return slices.Clone(other), nil
+	}
+	intersection := make([]string, 0, len(values))
+	for _, value := range values {
+		if slices.Contains(other, value) {
+			intersection = append(intersection, value)
+		}
+	}
+	if len(intersection) == 0 {
+		return nil, zerrors.ThrowInvalidArgumentf(nil, id, "no common %s", field)
+	}
+	return intersection, nil
+}
+
+func mergeScalar[T comparable](value, other T, id, field string) (T, error) {
+	var zero T
+	if other == zero || value == other {
+		return value, nil
+	}
+	if value == zero {
+		return other, nil
+	}
+	return zero, zerrors.ThrowInvalidArgumentf(nil, id, "conflicting %s", field)
+}
+
 // AddQuery creates a new sub query.
 // All fields in the sub query are AND-connected in the storage request.
 // Multiple sub queries are OR-connected in the storage request.
@@ -314,6 +1107,69 @@ func (builder *SearchQueryBuilder) AddQuery() *SearchQuery {
 	return query
 }
 
+// AddAggregate adds a sub query for the events of the aggregate with the given type and id.
+// Repeated calls add further aggregates, which can be of different types.
+// The events of all aggregates are returned merged in the order of their position,
+// which allows to query e.g. the timeline of a session together with its user.
+func (builder *SearchQueryBuilder) AddAggregate(typ AggregateType, id string) *SearchQueryBuilder {
+	return builder.AddQuery().
+		AggregateTypes(typ).
+		AggregateIDs(id).
+		Builder()
+}
+
+// AggregateTypes adds a sub query for the events of the given aggregate types.
+// It's equivalent to AddQuery().AggregateTypes(types...).Builder(),
+// so every call adds another sub query which is OR-ed with the others.
+func (builder *SearchQueryBuilder) AggregateTypes(types ...AggregateType) *SearchQueryBuilder {
+	return builder.AddQuery().
+		AggregateTypes(types...).
+		Builder()
+}
+
+// SearchQueryGroup is a group of sub queries which share their event types, see [SearchQueryBuilder.AddQueryGroup]
+type SearchQueryGroup struct {
+	builder    *SearchQueryBuilder
+	queries    []*SearchQuery
+	eventTypes []EventType
+}
+
+// AddQueryGroup creates a group of aggregate alternatives which share the same event types.
+// Every alternative added by [SearchQueryGroup.Aggregate] is a sub query of the builder,
+// so the storage request is (aggregate alternative 1 AND event types) OR (aggregate alternative 2 AND event types) ...
+// Alternatives without aggregate ids can also be expressed by a single sub query with multiple [SearchQuery.AggregateTypes].
+func (builder *SearchQueryBuilder) AddQueryGroup() *SearchQueryGroup {
+	return &SearchQueryGroup{
+		builder: builder,
+	}
+}
+
+// Aggregate adds an alternative for the events of the aggregate type, ids are optional
+func (group *SearchQueryGroup) Aggregate(typ AggregateType, ids ...string) *SearchQueryGroup {
+	query := group.builder.AddQuery().
+		AggregateTypes(typ).
+		EventTypes(group.eventTypes...)
+	if len(ids) > 0 {
+		query.AggregateIDs(ids...)
+	}
+	group.queries = append(group.queries, query)
+	return group
+}
+
+// EventTypes filters all alternatives of the group for events with the given event types
+func (group *SearchQueryGroup) EventTypes(types ...EventType) *SearchQueryGroup {
+	group.eventTypes = types
+	for _, query := range group.queries {
+		query.EventTypes(types...)
+	}
+	return group
+}
+
+// Builder returns the SearchQueryBuilder of the group
+func (group *SearchQueryGroup) Builder() *SearchQueryBuilder {
+	return group.builder
+}
+
 // Or creates a new sub query on the search query builder
 func (query SearchQuery) Or() *SearchQuery {
 	return query.builder.AddQuery()
@@ -328,6 +1184,48 @@ func (query *SearchQuery) AggregateTypes(types ...AggregateType) *SearchQuery {
 // AggregateIDs filters for events with the given aggregate id's
 func (query *SearchQuery) AggregateIDs(ids ...string) *SearchQuery {
 	query.aggregateIDs = ids
+	query.largeAggregateIDs = false
+	return query
+}
+
+// ExcludeAggregateIDs filters for events which don't belong to one of the given aggregate id's,
+// it's combined with [SearchQuery.AggregateIDs] using AND
+func (query *SearchQuery) ExcludeAggregateIDs(ids ...string) *SearchQuery {
+	query.excludedAggregateIDs = ids
+	return query
+}
+
+// LargeAggregateIDsThreshold is the count of aggregate ids above which [SearchQuery.AggregateIDsLarge]
+// compares the aggregate ids against a set built by the database instead of an array
+const LargeAggregateIDsThreshold = 1000
+
+// AggregateIDsLarge filters for events with the given aggregate id's like [SearchQuery.AggregateIDs]
+// but is intended for sets of hundreds of thousands ids.
+// Above [LargeAggregateIDsThreshold] ids the ids are still sent as a single array parameter,
+// but the database unnests them into a set, so the ids are joined instead of compared one by one.
+// No temporary table is created because the queries don't necessarily run in a transaction,
+// the set only exists during the statement.
+func (query *SearchQuery) AggregateIDsLarge(ids []string) *SearchQuery {
+	query.aggregateIDs = ids
+	query.largeAggregateIDs = true
+	return query
+}
+
+// AggregateIDsOrdered filters for events with the given aggregate id's
+// and orders the result by the position of the aggregate id in ids.
+// All events of the first id are returned before the events of the second id and so on.
+// Inside an aggregate id the events are ordered as usual.
+// This is useful to query a logical entity which was moved to a new aggregate id (e.g. old id then new id).
+func (query *SearchQuery) AggregateIDsOrdered(ids ...string) *SearchQuery {
+	query.aggregateIDs = ids
+	query.largeAggregateIDs = false
+	query.builder.aggregateIDsOrder = ids
+	return query
+}
+
+// AggregateVersions filters for events of aggregates with one of the given versions, e.g. "v1"
+func (query *SearchQuery) AggregateVersions(versions ...Version) *SearchQuery {
+	query.aggregateVersions = versions
 	return query
 }
 
@@ -337,13 +1235,120 @@ func (query *SearchQuery) EventTypes(types ...EventType) *SearchQuery {
 	return query
 }
 
+// ExcludeEventTypes filters for events which don't have one of the given event types,
+// it's combined with [SearchQuery.EventTypes] using AND
+func (query *SearchQuery) ExcludeEventTypes(types ...EventType) *SearchQuery {
+	query.excludedEventTypes = types
+	return query
+}
+
+// Creators filters for events created by one of the given creators,
+// e.g. to separate the events of a system user from the events of the api users.
+// The creator is the editor user of the event, see [Command.Creator].
+func (query *SearchQuery) Creators(creators ...string) *SearchQuery {
+	query.creators = creators
+	return query
+}
+
 // EventData filters for events with the given event data.
+// The keys can be paths to nested fields with segments separated by dots, e.g. "user.profile.language".
+// Events with a missing key on the path don't match.
 // Use this call with care as it will be slower than the other filters.
 func (query *SearchQuery) EventData(data map[string]interface{}) *SearchQuery {
 	query.eventData = data
 	return query
 }
 
+// EventDataMissingKey filters for events where the given key of the payload is missing or null.
+// All keys of multiple calls must be missing.
+// This is useful to find events stored without a field which is required by now.
+func (query *SearchQuery) EventDataMissingKey(key string) *SearchQuery {
+	query.eventDataMissingKeys = append(query.eventDataMissingKeys, key)
+	return query
+}
+
+// EventDataText filters for events whose payload matches all words of the text.
+// The storage uses the full-text search of the database with the simple configuration,
+// so words are matched case-insensitive but without stemming.
+// The payload isn't indexed for the full-text search, so the text should be combined with selective filters.
+// The events can be ranked by their relevance using [SearchQueryBuilder.OrderByRelevance].
+func (query *SearchQuery) EventDataText(text string) *SearchQuery {
+	query.eventDataText = text
+	return query
+}
+
+// EventDataOperator defines how an [EventDataCondition] compares the payload with its value
+type EventDataOperator string
+
+const (
+	EventDataEquals          EventDataOperator = "eq"
+	EventDataNotEquals       EventDataOperator = "neq"
+	EventDataGreater         EventDataOperator = "gt"
+	EventDataGreaterOrEquals EventDataOperator = "gte"
+	EventDataLess            EventDataOperator = "lt"
+	EventDataLessOrEquals    EventDataOperator = "lte"
+)
+
+// EventDataCondition compares the value of the payload at the field with the value using the operator,
+// e.g. {Field: "amount", Operator: EventDataGreater, Value: 100}
+type EventDataCondition struct {
+	// Field is the key of the payload, it can be a path to a nested field like the keys of [SearchQuery.EventData]
+	Field    string
+	Operator EventDataOperator
+	// Value must be a string, a number, a boolean or nil
+	Value interface{}
+}
+
+func (c EventDataCondition) validate() error {
+	if c.Field == "" {
+		return zerrors.ThrowPreconditionFailed(nil, "EVENT-Ed4fi", "event data condition without field")
+	}
+	switch c.Operator {
+	case EventDataEquals, EventDataNotEquals, EventDataGreater, EventDataGreaterOrEquals, EventDataLess, EventDataLessOrEquals:
+	default:
+		return zerrors.ThrowPreconditionFailedf(nil, "EVENT-Ed2op", "unknown event data operator %q", c.Operator)
+	}
+	if _, ok := jsonScalar(c.Value); !ok {
+		return zerrors.ThrowPreconditionFailedf(nil, "EVENT-Ed3va", "value of event data condition %q must be a json scalar", c.Field)
+	}
+	return nil
+}
+
+// EventDataWhere filters for events whose payload fulfills all conditions,
+// contrary to [SearchQuery.EventData] the values can be compared using other operators than equality.
+// Values are compared as json: numbers are compared by their value regardless of their go type,
+// so 100 and 100.0 are equal, and values of different json types are ordered like jsonb of the database
+// (null < string < number < boolean), they are never equal.
+// Events with a missing field don't match any condition.
+// Use this call with care as it will be slower than the other filters.
+func (query *SearchQuery) EventDataWhere(conditions ...EventDataCondition) *SearchQuery {
+	query.eventDataConditions = append(query.eventDataConditions, conditions...)
+	return query
+}
+
+// SequenceGreater filters for events of the sub query with a sequence greater than seq.
+// Other than [SearchQueryBuilder.SequenceGreater] it doesn't depend on the sort order,
+// so sub queries of different aggregates can be resumed from their own sequence.
+func (query *SearchQuery) SequenceGreater(seq uint64) *SearchQuery {
+	query.sequenceGreater = seq
+	return query
+}
+
+// SequenceLess filters for events of the sub query with a sequence less than seq
+func (query *SearchQuery) SequenceLess(seq uint64) *SearchQuery {
+	query.sequenceLess = seq
+	return query
+}
+
+// Limit restricts the amount of events returned by this sub query to the first events in the sort direction of the builder.
+// Other than [SearchQueryBuilder.Limit] it bounds the contribution of each sub query combined using [SearchQuery.Or],
+// e.g. to get at most 10 events of every aggregate type. The limit of the builder still restricts the total amount of events.
+// The events are selected before the offset of the builder is applied.
+func (query *SearchQuery) Limit(limit uint64) *SearchQuery {
+	query.limit = limit
+	return query
+}
+
 // Builder returns the SearchQueryBuilder of the sub query
 func (query *SearchQuery) Builder() *SearchQueryBuilder {
 	return query.builder
@@ -356,8 +1361,44 @@ func (query *SearchQuery) matches(command Command) bool {
 	if ok := isAggregateIDs(command.Aggregate(), query.aggregateIDs...); len(query.aggregateIDs) > 0 && !ok {
 		return false
 	}
+	if len(query.excludedAggregateIDs) > 0 && isAggregateIDs(command.Aggregate(), query.excludedAggregateIDs...) {
+		return false
+	}
+	if len(query.aggregateVersions) > 0 && !slices.Contains(query.aggregateVersions, command.Aggregate().Version) {
+		return false
+	}
 	if ok := isEventTypes(command, query.eventTypes...); len(query.eventTypes) > 0 && !ok {
 		return false
 	}
+	if len(query.excludedEventTypes) > 0 && isEventTypes(command, query.excludedEventTypes...) {
+		return false
+	}
+	if len(query.creators) > 0 && !slices.Contains(query.creators, command.Creator()) {
+		return false
+	}
+	if len(query.eventDataMissingKeys) > 0 && !isEventDataMissingKeys(command, query.eventDataMissingKeys...) {
+		return false
+	}
+	if query.eventDataText != "" && !isEventDataText(command, query.eventDataText) {
+		return false
+	}
+	if len(query.eventData) > 0 && !isEventData(command, query.eventData) {
+		return false
+	}
+	if len(query.eventDataConditions) > 0 && !isEventDataConditions(command, query.eventDataConditions) {
+		return false
+	}
+	if query.sequenceGreater > 0 || query.sequenceLess > 0 {
+		seq, ok := command.(sequencer)
+		if !ok {
+			return false
+		}
+		if query.sequenceGreater > 0 && seq.Sequence() <= query.sequenceGreater {
+			return false
+		}
+		if query.sequenceLess > 0 && seq.Sequence() >= query.sequenceLess {
+			return false
+		}
+	}
 	return true
 }
@@ -0,0 +1,192 @@
+package eventstore
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// String renders the fields of the builder which are set in a single line.
+// It is meant for logging and debugging, the format is not stable.
+// The transaction set by [SearchQueryBuilder.SetTx] is only rendered as set.
+func (builder *SearchQueryBuilder) String() string {
+	fields := builder.debugFields()
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field.isSet {
+			parts = append(parts, field.name+"="+field.value)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// DebugString renders all fields of the builder including the unset ones, one field per line.
+// It is meant for debugging, the format is not stable.
+// The transaction set by [SearchQueryBuilder.SetTx] is only rendered as set.
+func (builder *SearchQueryBuilder) DebugString() string {
+	var b strings.Builder
+	for _, field := range builder.debugFields() {
+		fmt.Fprintf(&b, "%s: %s\n", field.name, field.value)
+	}
+	return b.String()
+}
+
+// String renders the filters of the sub query which are set
+func (query *SearchQuery) String() string {
+	parts := make([]string, 0, 6)
+	if len(query.aggregateTypes) > 0 {
+		parts = append(parts, fmt.Sprintf("aggregateTypes=%v", query.aggregateTypes))
+	}
+	if len(query.aggregateIDs) > 0 {
+		parts = append(parts, fmt.Sprintf("aggregateIDs=%v", query.aggregateIDs))
+	}
+	if len(query.aggregateVersions) > 0 {
+		parts = append(parts, fmt.Sprintf("aggregateVersions=%v", query.aggregateVersions))
+	}
+	if len(query.eventTypes) > 0 {
+		parts = append(parts, fmt.Sprintf("eventTypes=%v", query.eventTypes))
+	}
+	if len(query.creators) > 0 {
+		parts = append(parts, fmt.Sprintf("creators=%v", query.creators))
+	}
+	if len(query.eventData) > 0 {
+		parts = append(parts, "eventData="+debugMap(query.eventData))
+	}
+	if len(query.eventDataMissingKeys) > 0 {
+		parts = append(parts, fmt.Sprintf("eventDataMissingKeys=%v", query.eventDataMissingKeys))
+	}
+	if query.eventDataText != "" {
+		parts = append(parts, fmt.Sprintf("eventDataText=%q", query.eventDataText))
+	}
+	if len(query.eventDataConditions) > 0 {
+		parts = append(parts, fmt.Sprintf("eventDataConditions=%v", query.eventDataConditions))
+	}
+	if query.sequenceGreater > 0 {
+		parts = append(parts, fmt.Sprintf("sequenceGreater=%d", query.sequenceGreater))
+	}
+	if query.sequenceLess > 0 {
+		parts = append(parts, fmt.Sprintf("sequenceLess=%d", query.sequenceLess))
+	}
+	if query.limit > 0 {
+		parts = append(parts, fmt.Sprintf("limit=%d", query.limit))
+	}
+	return "{" + strings.Join(parts, " ") + "}"
+}
+
+type debugField struct {
+	name  string
+	value string
+	isSet bool
+}
+
+func (builder *SearchQueryBuilder) debugFields() []debugField {
+	queries := make([]string, len(builder.queries))
+	for i, query := range builder.queries {
+		queries[i] = query.String()
+	}
+	instanceID := "<nil>"
+	if builder.instanceID != nil {
+		instanceID = *builder.instanceID
+	}
+	params := "{}"
+	if len(builder.params) > 0 {
+		params = debugMap(builder.params)
+	}
+	return []debugField{
+		{name: "columns", value: builder.columns.String(), isSet: builder.columns != 0},
+		{name: "instanceID", value: instanceID, isSet: builder.instanceID != nil},
+		{name: "instanceIDs", value: fmt.Sprint(builder.instanceIDs), isSet: len(builder.instanceIDs) > 0},
+		{name: "resourceOwner", value: strings.Join(builder.resourceOwners, ", "), isSet: len(builder.resourceOwners) > 0},
+		{name: "editorUser", value: builder.editorUser, isSet: builder.editorUser != ""},
+		{name: "editorUsers", value: fmt.Sprint(builder.editorUsers), isSet: len(builder.editorUsers) > 0},
+		{name: "producerVersion", value: builder.producerVersion, isSet: builder.producerVersion != ""},
+		{name: "producerVersionBefore", value: builder.producerVersionBefore, isSet: builder.producerVersionBefore != ""},
+		{name: "maintenanceWindow", value: builder.maintenanceWindow, isSet: builder.maintenanceWindow != ""},
+		{name: "positionAfter", value: fmt.Sprint(builder.positionAfter), isSet: builder.positionAfter != 0},
+		{name: "positionAtOrAfter", value: fmt.Sprint(builder.positionAtOrAfter), isSet: builder.positionAtOrAfter != 0},
+		{name: "awaitPosition", value: fmt.Sprint(builder.awaitPosition), isSet: builder.awaitPosition != 0},
+		{name: "creationDateAfter", value: debugTime(builder.creationDateAfter), isSet: !builder.creationDateAfter.IsZero()},
+		{name: "creationDateBefore", value: debugTime(builder.creationDateBefore), isSet: !builder.creationDateBefore.IsZero()},
+		{name: "sequenceGreater", value: fmt.Sprint(builder.eventSequenceGreater), isSet: builder.eventSequenceGreater != 0},
+		{name: "queries", value: "[" + strings.Join(queries, " ") + "]", isSet: len(queries) > 0},
+		{name: "order", value: builder.debugOrder(), isSet: true},
+		{name: "nulls", value: builder.nullsOrder.debugString(), isSet: builder.nullsOrder != NullsOrderDefault},
+		{name: "limit", value: fmt.Sprint(builder.limit), isSet: builder.limit != 0},
+		{name: "offset", value: fmt.Sprint(builder.offset), isSet: builder.offset != 0},
+		{name: "lastEvents", value: fmt.Sprint(builder.lastEvents), isSet: builder.lastEvents},
+		{name: "byteBudget", value: fmt.Sprint(builder.byteBudget), isSet: builder.byteBudget != 0},
+		{name: "queryTimeout", value: builder.queryTimeout.String(), isSet: builder.queryTimeout != 0},
+		{name: "tx", value: debugSet(builder.tx != nil), isSet: builder.tx != nil},
+		{name: "forUpdate", value: fmt.Sprint(builder.forUpdate), isSet: builder.forUpdate},
+		{name: "allowTimeTravel", value: fmt.Sprint(builder.allowTimeTravel), isSet: builder.allowTimeTravel},
+		{name: "timeTravelTo", value: debugTime(builder.timeTravelTime), isSet: !builder.timeTravelTime.IsZero()},
+		{name: "awaitOpenTransactions", value: fmt.Sprint(builder.awaitOpenTransactions), isSet: builder.awaitOpenTransactions},
+		{name: "includeArchive", value: fmt.Sprint(builder.includeArchive), isSet: builder.includeArchive},
+		{name: "includeOrigin", value: fmt.Sprint(builder.includeOrigin), isSet: builder.includeOrigin},
+		{name: "optimizeForTenant", value: fmt.Sprint(builder.optimizeForTenant), isSet: builder.optimizeForTenant},
+		{name: "compiled", value: fmt.Sprint(builder.compiled != nil), isSet: builder.compiled != nil},
+		{name: "params", value: params, isSet: len(builder.params) > 0},
+	}
+}
+
+// debugOrder renders the ordering the storage applies for the builder
+func (builder *SearchQueryBuilder) debugOrder() string {
+	if builder.columns == ColumnsMaxSequence || builder.columns == ColumnsMaxPosition {
+		return "position desc limit 1"
+	}
+	direction := "asc"
+	if builder.desc {
+		direction = "desc"
+	}
+	if builder.columns != ColumnsEvent {
+		return direction
+	}
+	switch {
+	case len(builder.aggregateIDsOrder) > 0:
+		return fmt.Sprintf("aggregateIDs%v, position %s", builder.aggregateIDsOrder, direction)
+	case builder.orderByEventType:
+		return "eventType, creationDate, position " + direction
+	case builder.orderByRelevance:
+		return "relevance, position " + direction
+	}
+	return "position " + direction
+}
+
+func (o NullsOrder) debugString() string {
+	switch o {
+	case NullsOrderFirst:
+		return "first"
+	case NullsOrderLast:
+		return "last"
+	}
+	return "default"
+}
+
+// debugMap renders the map sorted by its keys
+func debugMap(m map[string]any) string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = fmt.Sprintf("%s:%v", key, m[key])
+	}
+	return "{" + strings.Join(parts, " ") + "}"
+}
+
+func debugTime(t time.Time) string {
+	if t.IsZero() {
+		return "<nil>"
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+func debugSet(isSet bool) string {
+	if isSet {
+		return "<set>"
+	}
+	return "<nil>"
+}
@@ -0,0 +1,69 @@
+package eventstore
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+func TestSearchQueryBuilder_String(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *SearchQueryBuilder
+		want    string
+	}{
+		{
+			name:    "empty",
+			builder: NewSearchQueryBuilder(ColumnsEvent),
+			want:    "columns=event order=position asc",
+		},
+		{
+			name: "scope and sub queries",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				InstanceID("instance").
+				ResourceOwner("org").
+				OrderDesc().
+				Limit(10).
+				AddQuery().
+				AggregateTypes("user").
+				AggregateIDs("1", "2").
+				EventTypes("user.added").
+				EventData(map[string]interface{}{"b": 2, "a": "1"}).
+				Or().
+				AggregateTypes("org").
+				Builder(),
+			want: "columns=event instanceID=instance resourceOwner=org queries=[{aggregateTypes=[user] aggregateIDs=[1 2] eventTypes=[user.added] eventData={a:1 b:2}} {aggregateTypes=[org]}] order=position desc limit=10",
+		},
+		{
+			name:    "max sequence",
+			builder: NewSearchQueryBuilder(ColumnsMaxSequence).OrderAsc(),
+			want:    "columns=max_sequence order=position desc limit 1",
+		},
+		{
+			name:    "ordered by aggregate ids",
+			builder: NewSearchQueryBuilder(ColumnsEvent).AddQuery().AggregateIDsOrdered("2", "1").Builder(),
+			want:    "columns=event queries=[{aggregateIDs=[2 1]}] order=aggregateIDs[2 1], position asc",
+		},
+		{
+			name:    "transaction",
+			builder: NewSearchQueryBuilder(ColumnsEvent).SetTx(new(sql.Tx)),
+			want:    "columns=event order=position asc tx=<set>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.builder.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchQueryBuilder_DebugString(t *testing.T) {
+	got := NewSearchQueryBuilder(ColumnsEvent).SetTx(new(sql.Tx)).DebugString()
+	for _, want := range []string{"columns: event\n", "resourceOwner: \n", "order: position asc\n", "tx: <set>\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("DebugString() = %q, must contain %q", got, want)
+		}
+	}
+}
@@ -1,8 +1,16 @@
 package eventstore
 
 import (
+	"context"
+	"database/sql"
+	"math"
 	"reflect"
+	"strconv"
 	"testing"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
 func testSetQuery(queryFuncs ...func(*SearchQueryBuilder) *SearchQueryBuilder) func(*SearchQueryBuilder) *SearchQueryBuilder {
@@ -110,7 +118,7 @@ func TestSearchQuerybuilderSetters(t *testing.T) {
 				columns: ColumnsEvent,
 			},
 			res: &SearchQueryBuilder{
-				columns: Columns(ColumnsEvent),
+				columns: ColumnsEvent,
 			},
 		},
 		{
@@ -174,7 +182,7 @@ func TestSearchQuerybuilderSetters(t *testing.T) {
 				setters: []func(*SearchQueryBuilder) *SearchQueryBuilder{testSetResourceOwner("hodor")},
 			},
 			res: &SearchQueryBuilder{
-				resourceOwner: "hodor",
+				resourceOwners: []string{"hodor"},
 			},
 		},
 		{
@@ -217,8 +225,8 @@ func assertBuilder(t *testing.T, want, got *SearchQueryBuilder) {
 	if got.limit != want.limit {
 		t.Errorf("wrong limit: got: %v want: %v", got.limit, want.limit)
 	}
-	if got.resourceOwner != want.resourceOwner {
-		t.Errorf("wrong : got: %v want: %v", got.resourceOwner, want.resourceOwner)
+	if !reflect.DeepEqual(got.resourceOwners, want.resourceOwners) {
+		t.Errorf("wrong : got: %v want: %v", got.resourceOwners, want.resourceOwners)
 	}
 	if len(got.queries) != len(want.queries) {
 		t.Errorf("wrong length of queries: got: %v want: %v", len(got.queries), len(want.queries))
@@ -283,6 +291,68 @@ func TestSearchQuery_matches(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name:  "excluded aggregate id",
+			query: NewSearchQueryBuilder(ColumnsEvent).AddQuery().AggregateIDs("1", "2").ExcludeAggregateIDs("2"),
+			event: &matcherCommand{
+				BaseEvent{
+					Agg: &Aggregate{
+						ID: "2",
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name:  "not excluded aggregate id",
+			query: NewSearchQueryBuilder(ColumnsEvent).AddQuery().ExcludeAggregateIDs("2"),
+			event: &matcherCommand{
+				BaseEvent{
+					Agg: &Aggregate{
+						ID: "1",
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name:  "empty exclusion",
+			query: NewSearchQueryBuilder(ColumnsEvent).AddQuery().ExcludeAggregateIDs(),
+			event: &matcherCommand{
+				BaseEvent{
+					Agg: &Aggregate{
+						ID: "1",
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name:  "excluded event type",
+			query: NewSearchQueryBuilder(ColumnsEvent).AddQuery().AggregateTypes("user").ExcludeEventTypes("user.token.added"),
+			event: &matcherCommand{
+				BaseEvent{
+					EventType: "user.token.added",
+					Agg: &Aggregate{
+						Type: "user",
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name:  "not excluded event type",
+			query: NewSearchQueryBuilder(ColumnsEvent).AddQuery().AggregateTypes("user").ExcludeEventTypes("user.token.added"),
+			event: &matcherCommand{
+				BaseEvent{
+					EventType: "user.added",
+					Agg: &Aggregate{
+						Type: "user",
+					},
+				},
+			},
+			want: true,
+		},
 		{
 			name:  "wrong event type",
 			query: NewSearchQueryBuilder(ColumnsEvent).AddQuery().EventTypes("event.searched.type"),
@@ -328,14 +398,76 @@ func TestSearchQuery_matches(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name:  "wrong aggregate version",
+			query: NewSearchQueryBuilder(ColumnsEvent).AddQuery().AggregateVersions("v1", "v2"),
+			event: &matcherCommand{
+				BaseEvent{
+					Agg: &Aggregate{
+						Version: "v3",
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name:  "aggregate version",
+			query: NewSearchQueryBuilder(ColumnsEvent).AddQuery().AggregateVersions("v1", "v2"),
+			event: &matcherCommand{
+				BaseEvent{
+					Agg: &Aggregate{
+						Version: "v2",
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name:  "sequence not greater",
+			query: NewSearchQueryBuilder(ColumnsEvent).AddQuery().SequenceGreater(5),
+			event: &matcherCommand{
+				BaseEvent{
+					Seq: 5,
+					Agg: &Aggregate{},
+				},
+			},
+			want: false,
+		},
+		{
+			name:  "sequence not less",
+			query: NewSearchQueryBuilder(ColumnsEvent).AddQuery().SequenceLess(5),
+			event: &matcherCommand{
+				BaseEvent{
+					Seq: 5,
+					Agg: &Aggregate{},
+				},
+			},
+			want: false,
+		},
+		{
+			name:  "sequence in range",
+			query: NewSearchQueryBuilder(ColumnsEvent).AddQuery().SequenceGreater(4).SequenceLess(6),
+			event: &matcherCommand{
+				BaseEvent{
+					Seq: 5,
+					Agg: &Aggregate{},
+				},
+			},
+			want: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			query := &SearchQuery{
-				aggregateTypes: tt.query.aggregateTypes,
-				aggregateIDs:   tt.query.aggregateIDs,
-				eventTypes:     tt.query.eventTypes,
-				eventData:      tt.query.eventData,
+				aggregateTypes:       tt.query.aggregateTypes,
+				aggregateIDs:         tt.query.aggregateIDs,
+				excludedAggregateIDs: tt.query.excludedAggregateIDs,
+				aggregateVersions:    tt.query.aggregateVersions,
+				eventTypes:           tt.query.eventTypes,
+				excludedEventTypes:   tt.query.excludedEventTypes,
+				eventData:            tt.query.eventData,
+				sequenceGreater:      tt.query.sequenceGreater,
+				sequenceLess:         tt.query.sequenceLess,
 			}
 			if got := query.matches(tt.event); got != tt.want {
 				t.Errorf("SearchQuery.matches() = %v, want %v", got, tt.want)
@@ -588,6 +720,24 @@ func TestSearchQueryBuilder_Matches(t *testing.T) {
 			},
 			wantedLen: 1,
 		},
+		{
+			name: "offset above 32 bit",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				Offset(math.MaxUint32 + 1),
+			args: args{
+				commands: []Command{
+					&matcherCommand{
+						BaseEvent{
+							Agg: &Aggregate{
+								InstanceID: "instance",
+							},
+							Seq: 1001,
+						},
+					},
+				},
+			},
+			wantedLen: 0,
+		},
 		{
 			name: "offset too high",
 			builder: NewSearchQueryBuilder(ColumnsEvent).
@@ -715,3 +865,1184 @@ func TestSearchQueryBuilder_Matches(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchQueryBuilder_Matches_AggregateIDsOrdered(t *testing.T) {
+	commands := []Command{
+		&matcherCommand{BaseEvent{Seq: 1, Agg: &Aggregate{ID: "new"}}},
+		&matcherCommand{BaseEvent{Seq: 1, Agg: &Aggregate{ID: "old"}}},
+		&matcherCommand{BaseEvent{Seq: 2, Agg: &Aggregate{ID: "new"}}},
+		&matcherCommand{BaseEvent{Seq: 1, Agg: &Aggregate{ID: "other"}}},
+		&matcherCommand{BaseEvent{Seq: 2, Agg: &Aggregate{ID: "old"}}},
+	}
+	tests := []struct {
+		name    string
+		builder *SearchQueryBuilder
+		want    []string
+	}{
+		{
+			name: "ascending",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().
+				AggregateIDsOrdered("old", "new").
+				Builder(),
+			want: []string{"old/1", "old/2", "new/1", "new/2"},
+		},
+		{
+			name: "descending",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				OrderDesc().
+				AddQuery().
+				AggregateIDsOrdered("old", "new").
+				Builder(),
+			want: []string{"new/1", "new/2", "old/1", "old/2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.builder.Matches(commands...)
+			ids := make([]string, len(got))
+			for i, command := range got {
+				ids[i] = command.Aggregate().ID + "/" + strconv.FormatUint(command.(*matcherCommand).Seq, 10)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("SearchQueryBuilder.Matches() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchQueryBuilder_Matches_AddAggregate(t *testing.T) {
+	commands := []Command{
+		&matcherCommand{BaseEvent{Seq: 1, Agg: &Aggregate{ID: "session1", Type: "session"}}},
+		&matcherCommand{BaseEvent{Seq: 1, Agg: &Aggregate{ID: "user1", Type: "user"}}},
+		&matcherCommand{BaseEvent{Seq: 1, Agg: &Aggregate{ID: "session1", Type: "user"}}},
+		&matcherCommand{BaseEvent{Seq: 2, Agg: &Aggregate{ID: "session1", Type: "session"}}},
+		&matcherCommand{BaseEvent{Seq: 1, Agg: &Aggregate{ID: "user2", Type: "user"}}},
+		&matcherCommand{BaseEvent{Seq: 2, Agg: &Aggregate{ID: "user1", Type: "user"}}},
+	}
+	builder := NewSearchQueryBuilder(ColumnsEvent).
+		AddAggregate("session", "session1").
+		AddAggregate("user", "user1")
+
+	got := builder.Matches(commands...)
+	ids := make([]string, len(got))
+	for i, command := range got {
+		ids[i] = command.Aggregate().ID + "/" + strconv.FormatUint(command.(*matcherCommand).Seq, 10)
+	}
+	want := []string{"session1/1", "user1/1", "session1/2", "user1/2"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("SearchQueryBuilder.Matches() = %v, want %v", ids, want)
+	}
+	if len(builder.GetQueries()) != 2 {
+		t.Errorf("expected 2 sub queries, got %d", len(builder.GetQueries()))
+	}
+}
+
+func TestSearchQueryBuilder_AggregateTypes(t *testing.T) {
+	builder := NewSearchQueryBuilder(ColumnsEvent).
+		AggregateTypes("user", "org").
+		AggregateTypes("session")
+	want := NewSearchQueryBuilder(ColumnsEvent).
+		AddQuery().
+		AggregateTypes("user", "org").
+		Or().
+		AggregateTypes("session").
+		Builder()
+	if !reflect.DeepEqual(builder, want) {
+		t.Errorf("unexpected builder:\ngot: %#v\nwant: %#v", builder, want)
+	}
+
+	commands := []Command{
+		&matcherCommand{BaseEvent{Agg: &Aggregate{ID: "user1", Type: "user"}}},
+		&matcherCommand{BaseEvent{Agg: &Aggregate{ID: "project1", Type: "project"}}},
+		&matcherCommand{BaseEvent{Agg: &Aggregate{ID: "session1", Type: "session"}}},
+	}
+	got := builder.Matches(commands...)
+	if want := []Command{commands[0], commands[2]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchQueryBuilder.Matches() = %v, want %v", got, want)
+	}
+}
+
+func TestSearchQueryBuilder_AddQueryGroup(t *testing.T) {
+	builder := NewSearchQueryBuilder(ColumnsEvent).
+		AddQueryGroup().
+		Aggregate("user").
+		EventTypes("added").
+		Aggregate("org", "org1").
+		Builder()
+	want := NewSearchQueryBuilder(ColumnsEvent).
+		AddQuery().
+		AggregateTypes("user").
+		EventTypes("added").
+		Or().
+		AggregateTypes("org").
+		AggregateIDs("org1").
+		EventTypes("added").
+		Builder()
+	if !reflect.DeepEqual(builder, want) {
+		t.Errorf("unexpected builder:\ngot: %#v\nwant: %#v", builder, want)
+	}
+
+	commands := []Command{
+		&matcherCommand{BaseEvent{EventType: "added", Agg: &Aggregate{ID: "user1", Type: "user"}}},
+		&matcherCommand{BaseEvent{EventType: "changed", Agg: &Aggregate{ID: "user1", Type: "user"}}},
+		&matcherCommand{BaseEvent{EventType: "added", Agg: &Aggregate{ID: "org1", Type: "org"}}},
+		&matcherCommand{BaseEvent{EventType: "added", Agg: &Aggregate{ID: "org2", Type: "org"}}},
+	}
+	got := builder.Matches(commands...)
+	if want := []Command{commands[0], commands[2]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchQueryBuilder.Matches() = %v, want %v", got, want)
+	}
+}
+
+func TestSearchQueryBuilder_CreationDateDay(t *testing.T) {
+	zurich, err := time.LoadLocation("Europe/Zurich")
+	if err != nil {
+		t.Fatalf("unable to load location: %v", err)
+	}
+	tests := []struct {
+		name       string
+		date       time.Time
+		loc        *time.Location
+		wantStart  time.Time
+		wantLength time.Duration
+	}{
+		{
+			name:       "utc",
+			date:       time.Date(2024, 5, 10, 13, 30, 0, 0, time.UTC),
+			loc:        time.UTC,
+			wantStart:  time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC),
+			wantLength: 24 * time.Hour,
+		},
+		{
+			name:       "date in other location",
+			date:       time.Date(2024, 5, 10, 23, 30, 0, 0, time.UTC),
+			loc:        zurich,
+			wantStart:  time.Date(2024, 5, 10, 22, 0, 0, 0, time.UTC),
+			wantLength: 24 * time.Hour,
+		},
+		{
+			name:       "nil location uses location of date",
+			date:       time.Date(2024, 5, 10, 1, 0, 0, 0, zurich),
+			wantStart:  time.Date(2024, 5, 9, 22, 0, 0, 0, time.UTC),
+			wantLength: 24 * time.Hour,
+		},
+		{
+			name:       "start of daylight saving time",
+			date:       time.Date(2024, 3, 31, 12, 0, 0, 0, zurich),
+			loc:        zurich,
+			wantStart:  time.Date(2024, 3, 30, 23, 0, 0, 0, time.UTC),
+			wantLength: 23 * time.Hour,
+		},
+		{
+			name:       "end of daylight saving time",
+			date:       time.Date(2024, 10, 27, 12, 0, 0, 0, zurich),
+			loc:        zurich,
+			wantStart:  time.Date(2024, 10, 26, 22, 0, 0, 0, time.UTC),
+			wantLength: 25 * time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewSearchQueryBuilder(ColumnsEvent).CreationDateDay(tt.date, tt.loc)
+			if want := tt.wantStart.Add(-time.Nanosecond); !builder.GetCreationDateAfter().Equal(want) {
+				t.Errorf("wrong creation date after: got %v want %v", builder.GetCreationDateAfter(), want)
+			}
+			if want := tt.wantStart.Add(tt.wantLength); !builder.GetCreationDateBefore().Equal(want) {
+				t.Errorf("wrong creation date before: got %v want %v", builder.GetCreationDateBefore(), want)
+			}
+		})
+	}
+}
+
+func TestSearchQueryBuilder_CreationDateBetween(t *testing.T) {
+	after := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	before := after.Add(time.Hour)
+	tests := []struct {
+		name       string
+		after      time.Time
+		before     time.Time
+		wantAfter  time.Time
+		wantBefore time.Time
+	}{
+		{
+			name:       "both bounds",
+			after:      after,
+			before:     before,
+			wantAfter:  after,
+			wantBefore: before,
+		},
+		{
+			name:   "inverted bounds",
+			after:  before,
+			before: after,
+		},
+		{
+			name:   "equal bounds",
+			after:  after,
+			before: after,
+		},
+		{
+			name:      "zero before",
+			after:     after,
+			wantAfter: after,
+		},
+		{
+			name:       "zero after",
+			before:     before,
+			wantBefore: before,
+		},
+		{
+			name: "zero bounds",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewSearchQueryBuilder(ColumnsEvent).CreationDateBetween(tt.after, tt.before)
+			if !builder.GetCreationDateAfter().Equal(tt.wantAfter) {
+				t.Errorf("wrong creation date after: got %v want %v", builder.GetCreationDateAfter(), tt.wantAfter)
+			}
+			if !builder.GetCreationDateBefore().Equal(tt.wantBefore) {
+				t.Errorf("wrong creation date before: got %v want %v", builder.GetCreationDateBefore(), tt.wantBefore)
+			}
+		})
+	}
+}
+
+func TestSearchQueryBuilder_Matches_EditorUsers(t *testing.T) {
+	commands := []Command{
+		&matcherCommand{BaseEvent{Agg: &Aggregate{ID: "1"}, User: "user1"}},
+		&matcherCommand{BaseEvent{Agg: &Aggregate{ID: "2"}, User: "user2"}},
+		&matcherCommand{BaseEvent{Agg: &Aggregate{ID: "3"}, User: "user3"}},
+	}
+	tests := []struct {
+		name    string
+		builder *SearchQueryBuilder
+		want    []string
+	}{
+		{
+			name:    "empty editor user",
+			builder: NewSearchQueryBuilder(ColumnsEvent).EditorUser(""),
+			want:    []string{"1", "2", "3"},
+		},
+		{
+			name:    "editor user",
+			builder: NewSearchQueryBuilder(ColumnsEvent).EditorUser("user2"),
+			want:    []string{"2"},
+		},
+		{
+			name:    "editor users",
+			builder: NewSearchQueryBuilder(ColumnsEvent).EditorUsers("user1", "user3"),
+			want:    []string{"1", "3"},
+		},
+		{
+			name:    "editor user and editor users",
+			builder: NewSearchQueryBuilder(ColumnsEvent).EditorUser("user3").EditorUsers("user1", "user3"),
+			want:    []string{"3"},
+		},
+		{
+			name:    "editor user not in editor users",
+			builder: NewSearchQueryBuilder(ColumnsEvent).EditorUser("user2").EditorUsers("user1", "user3"),
+			want:    []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.builder.Matches(commands...)
+			ids := make([]string, len(got))
+			for i, command := range got {
+				ids[i] = command.Aggregate().ID
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("SearchQueryBuilder.Matches() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchQueryBuilder_Matches_Creators(t *testing.T) {
+	commands := []Command{
+		&matcherCommand{BaseEvent{Agg: &Aggregate{ID: "1", Type: "user"}, User: "system"}},
+		&matcherCommand{BaseEvent{Agg: &Aggregate{ID: "2", Type: "user"}, User: "user1"}},
+		&matcherCommand{BaseEvent{Agg: &Aggregate{ID: "3", Type: "user"}, User: "user2"}},
+	}
+	tests := []struct {
+		name    string
+		builder *SearchQueryBuilder
+		want    []string
+	}{
+		{
+			name: "no creators",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().
+				AggregateTypes("user").
+				Creators().
+				Builder(),
+			want: []string{"1", "2", "3"},
+		},
+		{
+			name: "creator",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().
+				AggregateTypes("user").
+				Creators("system").
+				Builder(),
+			want: []string{"1"},
+		},
+		{
+			name: "creators",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().
+				AggregateTypes("user").
+				Creators("user1", "user2").
+				Builder(),
+			want: []string{"2", "3"},
+		},
+		{
+			name: "creators per sub query",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().
+				AggregateIDs("1").
+				Creators("user1").
+				Or().
+				AggregateIDs("2").
+				Creators("user1").
+				Builder(),
+			want: []string{"2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.builder.Matches(commands...)
+			ids := make([]string, len(got))
+			for i, command := range got {
+				ids[i] = command.Aggregate().ID
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("SearchQueryBuilder.Matches() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchQueryBuilder_Matches_OrderByEventTypeThenDate(t *testing.T) {
+	commands := []Command{
+		&matcherCommand{BaseEvent{Seq: 1, EventType: "user.added", Agg: &Aggregate{ID: "user"}}},
+		&matcherCommand{BaseEvent{Seq: 1, EventType: "org.added", Agg: &Aggregate{ID: "org"}}},
+		&matcherCommand{BaseEvent{Seq: 2, EventType: "user.changed", Agg: &Aggregate{ID: "user"}}},
+		&matcherCommand{BaseEvent{Seq: 3, EventType: "user.added", Agg: &Aggregate{ID: "user"}}},
+	}
+	tests := []struct {
+		name    string
+		builder *SearchQueryBuilder
+		want    []string
+	}{
+		{
+			name:    "ascending",
+			builder: NewSearchQueryBuilder(ColumnsEvent).OrderByEventTypeThenDate(),
+			want:    []string{"org.added/1", "user.added/1", "user.added/3", "user.changed/2"},
+		},
+		{
+			name:    "descending",
+			builder: NewSearchQueryBuilder(ColumnsEvent).OrderByEventTypeThenDate().OrderDesc(),
+			want:    []string{"user.changed/2", "user.added/1", "user.added/3", "org.added/1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.builder.Matches(commands...)
+			types := make([]string, len(got))
+			for i, command := range got {
+				types[i] = string(command.Type()) + "/" + strconv.FormatUint(command.(*matcherCommand).Seq, 10)
+			}
+			if !reflect.DeepEqual(types, tt.want) {
+				t.Errorf("SearchQueryBuilder.Matches() = %v, want %v", types, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchQueryBuilder_Matches_EventDataText(t *testing.T) {
+	newCommand := func(id string, data interface{}) Command {
+		return newTestEvent(id, "", func() interface{} { return data }, false)
+	}
+	commands := []Command{
+		newCommand("both", []byte(`{"userName": "Alice", "role": "admin"}`)),
+		newCommand("one", []byte(`{"userName": "alice"}`)),
+		newCommand("none", []byte(`{"userName": "bob"}`)),
+		newCommand("empty", nil),
+	}
+	got := NewSearchQueryBuilder(ColumnsEvent).
+		AddQuery().
+		EventDataText("alice ADMIN").
+		Builder().
+		Matches(commands...)
+	ids := make([]string, len(got))
+	for i, command := range got {
+		ids[i] = command.Aggregate().ID
+	}
+	if want := []string{"both"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("SearchQueryBuilder.Matches() = %v, want %v", ids, want)
+	}
+}
+
+func TestSearchQueryBuilder_Matches_EventData(t *testing.T) {
+	newCommand := func(id string, data interface{}) Command {
+		return newTestEvent(id, "", func() interface{} { return data }, false)
+	}
+	commands := []Command{
+		newCommand("nested", []byte(`{"userId": "user", "user": {"profile": {"language": "de"}, "age": 42}}`)),
+		newCommand("other value", []byte(`{"userId": "user", "user": {"profile": {"language": "en"}, "age": 42}}`)),
+		newCommand("missing intermediate", []byte(`{"userId": "user", "user": {"age": 42}}`)),
+		newCommand("no object", []byte(`{"userId": "user", "user": "de"}`)),
+		newCommand("empty", nil),
+	}
+	got := NewSearchQueryBuilder(ColumnsEvent).
+		AddQuery().
+		EventData(map[string]interface{}{
+			"userId":                "user",
+			"user.profile.language": "de",
+			"user.age":              42,
+		}).
+		Builder().
+		Matches(commands...)
+	ids := make([]string, len(got))
+	for i, command := range got {
+		ids[i] = command.Aggregate().ID
+	}
+	if want := []string{"nested"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("SearchQueryBuilder.Matches() = %v, want %v", ids, want)
+	}
+}
+
+func TestSearchQueryBuilder_Matches_EventDataWhere(t *testing.T) {
+	newCommand := func(id string, data interface{}) Command {
+		return newTestEvent(id, "", func() interface{} { return data }, false)
+	}
+	commands := []Command{
+		newCommand("above", []byte(`{"amount": 150, "currency": "CHF", "order": {"express": true}}`)),
+		newCommand("float above", []byte(`{"amount": 100.5, "currency": "EUR", "order": {"express": true}}`)),
+		newCommand("equal", []byte(`{"amount": 100, "currency": "EUR", "order": {"express": true}}`)),
+		newCommand("below", []byte(`{"amount": 50, "currency": "EUR", "order": {"express": true}}`)),
+		newCommand("string amount", []byte(`{"amount": "150", "currency": "EUR", "order": {"express": true}}`)),
+		newCommand("missing", []byte(`{"currency": "EUR", "order": {"express": true}}`)),
+		newCommand("empty", nil),
+	}
+	tests := []struct {
+		name       string
+		conditions []EventDataCondition
+		want       []string
+	}{
+		{
+			name:       "greater",
+			conditions: []EventDataCondition{{Field: "amount", Operator: EventDataGreater, Value: 100}},
+			want:       []string{"above", "float above"},
+		},
+		{
+			name:       "greater or equals float",
+			conditions: []EventDataCondition{{Field: "amount", Operator: EventDataGreaterOrEquals, Value: 100.0}},
+			want:       []string{"above", "float above", "equal"},
+		},
+		{
+			name:       "less, strings are less than numbers",
+			conditions: []EventDataCondition{{Field: "amount", Operator: EventDataLess, Value: uint8(100)}},
+			want:       []string{"below", "string amount"},
+		},
+		{
+			name:       "less or equals",
+			conditions: []EventDataCondition{{Field: "amount", Operator: EventDataLessOrEquals, Value: 100}},
+			want:       []string{"equal", "below", "string amount"},
+		},
+		{
+			name:       "equals",
+			conditions: []EventDataCondition{{Field: "amount", Operator: EventDataEquals, Value: 100}},
+			want:       []string{"equal"},
+		},
+		{
+			name:       "not equals",
+			conditions: []EventDataCondition{{Field: "currency", Operator: EventDataNotEquals, Value: "EUR"}},
+			want:       []string{"above"},
+		},
+		{
+			name:       "string is not equal to number",
+			conditions: []EventDataCondition{{Field: "amount", Operator: EventDataEquals, Value: "150"}},
+			want:       []string{"string amount"},
+		},
+		{
+			name: "all conditions on nested fields",
+			conditions: []EventDataCondition{
+				{Field: "amount", Operator: EventDataGreater, Value: 60},
+				{Field: "amount", Operator: EventDataLess, Value: 120},
+				{Field: "order.express", Operator: EventDataEquals, Value: true},
+			},
+			want: []string{"float above", "equal"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().
+				EventDataWhere(tt.conditions...).
+				Builder().
+				Matches(commands...)
+			ids := make([]string, len(got))
+			for i, command := range got {
+				ids[i] = command.Aggregate().ID
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("SearchQueryBuilder.Matches() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchQueryBuilder_Matches_subQueryLimit(t *testing.T) {
+	commands := []Command{
+		&matcherCommand{BaseEvent{Agg: &Aggregate{ID: "u1", Type: "user"}, EventType: "user.added"}},
+		&matcherCommand{BaseEvent{Agg: &Aggregate{ID: "o1", Type: "org"}, EventType: "org.added"}},
+		&matcherCommand{BaseEvent{Agg: &Aggregate{ID: "u2", Type: "user"}, EventType: "user.changed"}},
+		&matcherCommand{BaseEvent{Agg: &Aggregate{ID: "u3", Type: "user"}, EventType: "user.changed"}},
+		&matcherCommand{BaseEvent{Agg: &Aggregate{ID: "o2", Type: "org"}, EventType: "org.changed"}},
+		&matcherCommand{BaseEvent{Agg: &Aggregate{ID: "o3", Type: "org"}, EventType: "org.changed"}},
+	}
+	tests := []struct {
+		name    string
+		builder *SearchQueryBuilder
+		want    []string
+	}{
+		{
+			name: "per sub query",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().AggregateTypes("user").Limit(2).
+				Or().AggregateTypes("org").Limit(1).
+				Builder(),
+			want: []string{"u1", "o1", "u2"},
+		},
+		{
+			name: "unlimited sub query",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().AggregateTypes("user").Limit(1).
+				Or().AggregateTypes("org").
+				Builder(),
+			want: []string{"u1", "o1", "o2", "o3"},
+		},
+		{
+			name: "builder limit caps total",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				Limit(3).
+				AddQuery().AggregateTypes("user").Limit(2).
+				Or().AggregateTypes("org").Limit(2).
+				Builder(),
+			want: []string{"u1", "o1", "u2"},
+		},
+		{
+			name: "overlapping sub queries",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().AggregateTypes("user").Limit(1).
+				Or().EventTypes("user.changed").Limit(1).
+				Builder(),
+			want: []string{"u1", "u2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.builder.Matches(commands...)
+			ids := make([]string, len(got))
+			for i, command := range got {
+				ids[i] = command.Aggregate().ID
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("SearchQueryBuilder.Matches() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchQueryBuilder_Matches_EventDataMissingKey(t *testing.T) {
+	newCommand := func(id string, data interface{}) Command {
+		return newTestEvent(id, "", func() interface{} { return data }, false)
+	}
+	commands := []Command{
+		newCommand("missing", []byte(`{"userId": "user"}`)),
+		newCommand("null", []byte(`{"actorType": null}`)),
+		newCommand("set", []byte(`{"actorType": "user"}`)),
+		newCommand("empty", nil),
+	}
+	got := NewSearchQueryBuilder(ColumnsEvent).
+		AddQuery().
+		EventDataMissingKey("actorType").
+		Builder().
+		Matches(commands...)
+	ids := make([]string, len(got))
+	for i, command := range got {
+		ids[i] = command.Aggregate().ID
+	}
+	if want := []string{"missing", "null", "empty"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("SearchQueryBuilder.Matches() = %v, want %v", ids, want)
+	}
+}
+
+func TestColumns_String(t *testing.T) {
+	tests := []struct {
+		columns Columns
+		want    string
+	}{
+		{columns: ColumnsEvent, want: "event"},
+		{columns: ColumnsMaxSequence, want: "max_sequence"},
+		{columns: ColumnsInstanceIDs, want: "instance_ids"},
+		{columns: ColumnsCount, want: "count"},
+		{columns: ColumnsMaxPosition, want: "max_position"},
+		{columns: columnsCount, want: "columns(6)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.columns.String(); got != tt.want {
+				t.Errorf("Columns.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchQueryBuilder_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *SearchQueryBuilder
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				Limit(10).
+				Offset(5).
+				AddQuery().
+				AggregateTypes("user").
+				AggregateIDs("1").
+				ExcludeAggregateIDs("2").
+				Builder(),
+		},
+		{
+			name:    "invalid columns",
+			builder: NewSearchQueryBuilder(0),
+			wantErr: true,
+		},
+		{
+			name: "offset without limit",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				Offset(5),
+			wantErr: true,
+		},
+		{
+			name: "sub query without filter",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().
+				AggregateTypes("user").
+				Or().
+				Builder(),
+			wantErr: true,
+		},
+		{
+			name: "aggregate id included and excluded",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().
+				AggregateIDs("1", "2").
+				ExcludeAggregateIDs("2").
+				Builder(),
+			wantErr: true,
+		},
+		{
+			name: "empty sequence range",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().
+				SequenceGreater(4).
+				SequenceLess(5).
+				Builder(),
+			wantErr: true,
+		},
+		{
+			name: "event type included and excluded",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().
+				EventTypes("user.added").
+				ExcludeEventTypes("user.added").
+				Builder(),
+			wantErr: true,
+		},
+		{
+			name: "event data condition",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().
+				EventDataWhere(EventDataCondition{Field: "amount", Operator: EventDataGreater, Value: 100}).
+				Builder(),
+		},
+		{
+			name: "event data condition unknown operator",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().
+				EventDataWhere(EventDataCondition{Field: "amount", Operator: "like", Value: 100}).
+				Builder(),
+			wantErr: true,
+		},
+		{
+			name: "event data condition without field",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().
+				EventDataWhere(EventDataCondition{Operator: EventDataEquals, Value: 100}).
+				Builder(),
+			wantErr: true,
+		},
+		{
+			name: "event data condition with object value",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().
+				EventDataWhere(EventDataCondition{Field: "amount", Operator: EventDataEquals, Value: map[string]int{"value": 100}}).
+				Builder(),
+			wantErr: true,
+		},
+		{
+			name: "time travel to timestamp",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				TimeTravelTo(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)).
+				PositionAtOrAfter(1.5),
+		},
+		{
+			name: "time travel to timestamp and position after",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				TimeTravelTo(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)).
+				PositionAfter(1.5),
+			wantErr: true,
+		},
+		{
+			name: "time travel to timestamp in transaction",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				TimeTravelTo(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)).
+				SetTx(new(sql.Tx)),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.builder.Validate()
+			if tt.wantErr != zerrors.IsPreconditionFailed(err) {
+				t.Errorf("SearchQueryBuilder.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("SearchQueryBuilder.Validate() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestCompiledQuery_ensureInstanceID(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance")
+	compiled := NewSearchQueryBuilder(ColumnsEvent).
+		AddQuery().
+		AggregateIDs(QueryParam("id")).
+		Builder().
+		Compile()
+
+	bound := compiled.Bind(map[string]any{"id": "id1"})
+	bound.ensureInstanceID(ctx)
+
+	if name, ok := QueryParamName(*bound.GetInstanceID()); !ok || name != InstanceIDParam {
+		t.Errorf("instance id must be a placeholder got %q", *bound.GetInstanceID())
+	}
+	if bound.GetQueryParams()[InstanceIDParam] != "instance" {
+		t.Errorf("instance id param must be bound from context got %v", bound.GetQueryParams()[InstanceIDParam])
+	}
+	if bound.GetCompiledQuery() != compiled {
+		t.Error("bound builder must reference the compiled query")
+	}
+
+	next := compiled.Bind(map[string]any{"id": "id2", InstanceIDParam: "other"})
+	next.ensureInstanceID(ctx)
+	if next.GetQueryParams()[InstanceIDParam] != "other" {
+		t.Errorf("bound instance id must not be overwritten got %v", next.GetQueryParams()[InstanceIDParam])
+	}
+}
+
+func TestSearchQueryBuilder_Merge(t *testing.T) {
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	after := before.Add(time.Hour)
+	tests := []struct {
+		name    string
+		builder *SearchQueryBuilder
+		other   *SearchQueryBuilder
+		want    *SearchQueryBuilder
+		wantErr bool
+	}{
+		{
+			name: "nil other",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				ResourceOwner("ro"),
+			want: NewSearchQueryBuilder(ColumnsEvent).
+				ResourceOwner("ro"),
+		},
+		{
+			name: "scope and sub queries",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				InstanceID("instance").
+				ResourceOwner("ro").
+				CreationDateAfter(before).
+				OrderDesc().
+				AllowTimeTravel(),
+			other: NewSearchQueryBuilder(0).
+				OrderDesc().
+				AddQuery().
+				AggregateTypes("user").
+				EventTypes("user.added").
+				Or().
+				AggregateTypes("org").
+				Builder(),
+			want: NewSearchQueryBuilder(ColumnsEvent).
+				InstanceID("instance").
+				ResourceOwner("ro").
+				CreationDateAfter(before).
+				OrderDesc().
+				AddQuery().
+				AggregateTypes("user").
+				EventTypes("user.added").
+				Or().
+				AggregateTypes("org").
+				Builder(),
+		},
+		{
+			name: "sub queries appended",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().
+				AggregateTypes("user").
+				Builder(),
+			other: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().
+				AggregateTypes("org").
+				Builder(),
+			want: NewSearchQueryBuilder(ColumnsEvent).
+				AddQuery().
+				AggregateTypes("user").
+				Or().
+				AggregateTypes("org").
+				Builder(),
+		},
+		{
+			name: "more restrictive ranges",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				Limit(10).
+				ByteBudget(100).
+				CreationDateAfter(before).
+				CreationDateBefore(after).
+				PositionAfter(2).
+				SequenceGreater(5),
+			other: NewSearchQueryBuilder(ColumnsEvent).
+				Limit(5).
+				ByteBudget(50).
+				CreationDateAfter(after).
+				CreationDateBefore(before).
+				PositionAfter(1).
+				SequenceGreater(6).
+				ForUpdate(),
+			want: NewSearchQueryBuilder(ColumnsEvent).
+				Limit(5).
+				ByteBudget(50).
+				CreationDateAfter(after).
+				CreationDateBefore(before).
+				PositionAfter(2).
+				SequenceGreater(6).
+				ForUpdate(),
+		},
+		{
+			name: "common instance ids",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				InstanceIDs("i1", "i2", "i3"),
+			other: NewSearchQueryBuilder(ColumnsEvent).
+				InstanceIDs("i2", "i3", "i4"),
+			want: NewSearchQueryBuilder(ColumnsEvent).
+				InstanceIDs("i2", "i3"),
+		},
+		{
+			name: "instance id in instance ids",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				InstanceIDs("i1", "i2"),
+			other: NewSearchQueryBuilder(ColumnsEvent).
+				InstanceID("i2"),
+			want: NewSearchQueryBuilder(ColumnsEvent).
+				InstanceID("i2"),
+		},
+		{
+			name: "no common instance ids, error",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				InstanceIDs("i1"),
+			other: NewSearchQueryBuilder(ColumnsEvent).
+				InstanceIDs("i2"),
+			wantErr: true,
+		},
+		{
+			name: "conflicting instance id, error",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				InstanceID("i1"),
+			other: NewSearchQueryBuilder(ColumnsEvent).
+				InstanceID("i2"),
+			wantErr: true,
+		},
+		{
+			name: "common editor users",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				EditorUsers("u1", "u2"),
+			other: NewSearchQueryBuilder(ColumnsEvent).
+				EditorUsers("u2", "u3"),
+			want: NewSearchQueryBuilder(ColumnsEvent).
+				EditorUsers("u2"),
+		},
+		{
+			name: "no common editor users, error",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				EditorUsers("u1"),
+			other: NewSearchQueryBuilder(ColumnsEvent).
+				EditorUsers("u2"),
+			wantErr: true,
+		},
+		{
+			name: "common resource owners",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				ResourceOwners("ro1", "ro2"),
+			other: NewSearchQueryBuilder(ColumnsEvent).
+				ResourceOwners("ro2", "ro3"),
+			want: NewSearchQueryBuilder(ColumnsEvent).
+				ResourceOwners("ro2"),
+		},
+		{
+			name: "conflicting resource owner, error",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				ResourceOwner("ro1"),
+			other: NewSearchQueryBuilder(ColumnsEvent).
+				ResourceOwner("ro2"),
+			wantErr: true,
+		},
+		{
+			name:    "conflicting nulls order, error",
+			builder: NewSearchQueryBuilder(ColumnsEvent).NullsFirst(),
+			other:   NewSearchQueryBuilder(ColumnsEvent).NullsLast(),
+			wantErr: true,
+		},
+		{
+			name:    "conflicting order, error",
+			builder: NewSearchQueryBuilder(ColumnsEvent),
+			other:   NewSearchQueryBuilder(ColumnsEvent).OrderDesc(),
+			wantErr: true,
+		},
+		{
+			name:    "conflicting columns, error",
+			builder: NewSearchQueryBuilder(ColumnsEvent),
+			other:   NewSearchQueryBuilder(ColumnsMaxSequence),
+			wantErr: true,
+		},
+		{
+			name: "compiled, error",
+			builder: NewSearchQueryBuilder(ColumnsEvent).
+				Compile().
+				Bind(nil),
+			other:   NewSearchQueryBuilder(ColumnsEvent),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Merge(tt.other)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Merge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Merge() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchQueryBuilder_Merge_conflictUnchanged(t *testing.T) {
+	builder := NewSearchQueryBuilder(ColumnsEvent).
+		InstanceID("instance").
+		Limit(10).
+		AddQuery().
+		AggregateTypes("user").
+		Builder()
+	other := NewSearchQueryBuilder(ColumnsEvent).
+		ResourceOwner("ro").
+		Limit(5).
+		OrderDesc().
+		AddQuery().
+		AggregateTypes("org").
+		Builder()
+
+	_, err := builder.Merge(other)
+	if err == nil {
+		t.Fatal("Merge() expected error for conflicting order")
+	}
+	want := NewSearchQueryBuilder(ColumnsEvent).
+		InstanceID("instance").
+		Limit(10).
+		AddQuery().
+		AggregateTypes("user").
+		Builder()
+	if !reflect.DeepEqual(builder, want) {
+		t.Errorf("Merge() changed the builder on error = %+v, want %+v", builder, want)
+	}
+}
+
+func TestSearchQueryBuilder_Merge_copiesOther(t *testing.T) {
+	builder := NewSearchQueryBuilder(ColumnsEvent)
+	other := NewSearchQueryBuilder(ColumnsEvent).
+		ResourceOwners("ro1", "ro2").
+		AddQuery().
+		AggregateTypes("user").
+		AggregateIDsOrdered("a", "b").
+		Builder()
+	if _, err := builder.Merge(other); err != nil {
+		t.Fatalf("Merge() unexpected error = %v", err)
+	}
+
+	other.resourceOwners[0] = "changed"
+	other.aggregateIDsOrder[0] = "changed"
+	other.queries[0].aggregateTypes[0] = "changed"
+	other.queries[0].EventTypes("changed")
+
+	want := NewSearchQueryBuilder(ColumnsEvent).
+		ResourceOwners("ro1", "ro2").
+		AddQuery().
+		AggregateTypes("user").
+		AggregateIDsOrdered("a", "b").
+		Builder()
+	if !reflect.DeepEqual(builder, want) {
+		t.Errorf("Merge() shares the filters of other = %+v, want %+v", builder, want)
+	}
+}
+
+func TestSearchQueryBuilder_Matches_ResourceOwners(t *testing.T) {
+	newCommand := func(id, resourceOwner string) Command {
+		event := newTestEvent(id, "", func() interface{} { return nil }, false)
+		event.BaseEvent.Agg.ResourceOwner = resourceOwner
+		return event
+	}
+	commands := []Command{
+		newCommand("1", "org1"),
+		newCommand("2", "org2"),
+		newCommand("3", "org3"),
+	}
+	tests := []struct {
+		name    string
+		builder *SearchQueryBuilder
+		want    []string
+	}{
+		{
+			name:    "no resource owner",
+			builder: NewSearchQueryBuilder(ColumnsEvent),
+			want:    []string{"1", "2", "3"},
+		},
+		{
+			name:    "single resource owner",
+			builder: NewSearchQueryBuilder(ColumnsEvent).ResourceOwner("org2"),
+			want:    []string{"2"},
+		},
+		{
+			name:    "multiple resource owners",
+			builder: NewSearchQueryBuilder(ColumnsEvent).ResourceOwners("org1", "org3"),
+			want:    []string{"1", "3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.builder.Matches(commands...)
+			ids := make([]string, len(got))
+			for i, command := range got {
+				ids[i] = command.Aggregate().ID
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("SearchQueryBuilder.Matches() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchQueryBuilder_Matches_InstanceIDs(t *testing.T) {
+	newCommand := func(id, instanceID string) Command {
+		event := newTestEvent(id, "", func() interface{} { return nil }, false)
+		event.BaseEvent.Agg.InstanceID = instanceID
+		return event
+	}
+	got := NewSearchQueryBuilder(ColumnsEvent).
+		InstanceIDs("instance1", "instance3").
+		Matches(
+			newCommand("1", "instance1"),
+			newCommand("2", "instance2"),
+			newCommand("3", "instance3"),
+		)
+	ids := make([]string, len(got))
+	for i, command := range got {
+		ids[i] = command.Aggregate().ID
+	}
+	if want := []string{"1", "3"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("SearchQueryBuilder.Matches() = %v, want %v", ids, want)
+	}
+}
+
+func TestSearchQueryBuilder_ensureInstanceID_instanceIDs(t *testing.T) {
+	builder := NewSearchQueryBuilder(ColumnsEvent).InstanceIDs("instance1", "instance2")
+	builder.ensureInstanceID(authz.WithInstanceID(context.Background(), "instance3"))
+	if builder.GetInstanceID() != nil {
+		t.Errorf("instance of the context must not be set, got %q", *builder.GetInstanceID())
+	}
+	if want := []string{"instance1", "instance2"}; !reflect.DeepEqual(builder.GetInstanceIDs(), want) {
+		t.Errorf("GetInstanceIDs() = %v, want %v", builder.GetInstanceIDs(), want)
+	}
+}
+
+func TestSearchQueryBuilder_Clone(t *testing.T) {
+	newBuilder := func() *SearchQueryBuilder {
+		return NewSearchQueryBuilder(ColumnsEvent).
+			InstanceID("instance").
+			InstanceIDs("instance", "other").
+			ResourceOwners("org1", "org2").
+			EditorUsers("user1").
+			Limit(10).
+			AddQuery().
+			AggregateTypes("user").
+			AggregateIDsOrdered("1", "2").
+			ExcludeAggregateIDs("3").
+			AggregateVersions("v1", "v2").
+			EventTypes("user.added").
+			ExcludeEventTypes("user.token.added").
+			EventData(map[string]interface{}{"key": "value"}).
+			EventDataMissingKey("missing").
+			SequenceGreater(1).
+			SequenceLess(5).
+			Builder()
+	}
+	original := newBuilder()
+	clone := original.Clone()
+	if !reflect.DeepEqual(original, clone) {
+		t.Fatalf("Clone() = %v, want %v", clone, original)
+	}
+	if clone.queries[0].builder != clone {
+		t.Errorf("sub query of the clone must point to the clone")
+	}
+
+	*clone.instanceID = "changed"
+	clone.Limit(1).InstanceIDs("changed")
+	clone.resourceOwners[0] = "changed"
+	clone.editorUsers[0] = "changed"
+	clone.aggregateIDsOrder[0] = "changed"
+	clone.queries[0].aggregateTypes[0] = "changed"
+	clone.queries[0].aggregateIDs[0] = "changed"
+	clone.queries[0].excludedAggregateIDs[0] = "changed"
+	clone.queries[0].aggregateVersions[0] = "changed"
+	clone.queries[0].eventTypes[0] = "changed"
+	clone.queries[0].excludedEventTypes[0] = "changed"
+	clone.queries[0].eventData["key"] = "changed"
+	clone.queries[0].eventDataMissingKeys[0] = "changed"
+	clone.queries[0].SequenceGreater(2).SequenceLess(3)
+	clone.AddQuery().AggregateTypes("org")
+
+	if want := newBuilder(); !reflect.DeepEqual(original, want) {
+		t.Errorf("original must not change, got %v, want %v", original, want)
+	}
+}
@@ -15,19 +15,21 @@ var (
 
 type Eventstore struct {
 	client *database.DB
+	// producerVersion is the version of ZITADEL stored on each pushed event
+	producerVersion string
 }
 
-func NewEventstore(client *database.DB) *Eventstore {
+func NewEventstore(client *database.DB, producerVersion string) *Eventstore {
 	switch client.Type() {
 	case "cockroach":
-		pushPlaceholderFmt = "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $%d)"
+		pushPlaceholderFmt = "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $%d, $%d, $%d)"
 		uniqueConstraintPlaceholderFmt = "('%s', '%s', '%s')"
 	case "postgres":
-		pushPlaceholderFmt = "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, statement_timestamp(), EXTRACT(EPOCH FROM clock_timestamp()), $%d)"
+		pushPlaceholderFmt = "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, statement_timestamp(), EXTRACT(EPOCH FROM clock_timestamp()), $%d, $%d, $%d)"
 		uniqueConstraintPlaceholderFmt = "(%s, %s, %s)"
 	}
 
-	return &Eventstore{client: client}
+	return &Eventstore{client: client, producerVersion: producerVersion}
 }
 
 func (es *Eventstore) Health(ctx context.Context) error {
@@ -0,0 +1,180 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach-go/v2/crdb"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+var _ eventstore.Importer = (*Eventstore)(nil)
+
+const (
+	importStmt = `INSERT INTO eventstore.events2 (instance_id, "owner", aggregate_type, aggregate_id, revision, creator, event_type, payload, "sequence", created_at, "position", in_tx_order, producer_version, maintenance_window) VALUES %s`
+
+	argsPerImportedEvent = 14
+	// importBatchSize keeps the arguments of a statement below the limit of 65535 parameters of postgres
+	importBatchSize = 4000
+
+	argsPerImportedConstraint = 3
+	// importConstraintsBatchSize keeps the arguments of a statement below the limit of 65535 parameters of postgres
+	importConstraintsBatchSize = 20000
+
+	instanceConstraintsStmt = `SELECT unique_type, unique_field FROM eventstore.unique_constraints WHERE instance_id = $1`
+	instanceFieldsStmt      = `SELECT resource_owner, aggregate_type, aggregate_id, object_type, object_id, object_revision, field_name, value, value_must_be_unique, should_index FROM eventstore.fields WHERE instance_id = $1`
+)
+
+// InstanceState implements [eventstore.Importer]
+func (es *Eventstore) InstanceState(ctx context.Context, instanceID string) (_ *eventstore.InstanceState, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	state := new(eventstore.InstanceState)
+	err = es.client.QueryContext(ctx,
+		func(rows *sql.Rows) error {
+			for rows.Next() {
+				constraint := &eventstore.UniqueConstraint{Action: eventstore.UniqueConstraintAdd}
+				if err := rows.Scan(&constraint.UniqueType, &constraint.UniqueField); err != nil {
+					return err
+				}
+				state.UniqueConstraints = append(state.UniqueConstraints, constraint)
+			}
+			return rows.Err()
+		},
+		instanceConstraintsStmt, instanceID,
+	)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "V3-Is3nd", "Errors.Internal")
+	}
+	err = es.client.QueryContext(ctx,
+		func(rows *sql.Rows) error {
+			for rows.Next() {
+				var value []byte
+				field := &eventstore.Field{Aggregate: &eventstore.Aggregate{InstanceID: instanceID}}
+				err := rows.Scan(
+					&field.Aggregate.ResourceOwner,
+					&field.Aggregate.Type,
+					&field.Aggregate.ID,
+					&field.Object.Type,
+					&field.Object.ID,
+					&field.Object.Revision,
+					&field.FieldName,
+					&value,
+					&field.Value.MustBeUnique,
+					&field.Value.ShouldIndex,
+				)
+				if err != nil {
+					return err
+				}
+				// the stored json is inserted as is by the import
+				field.Value.Value = json.RawMessage(value)
+				state.Fields = append(state.Fields, field)
+			}
+			return rows.Err()
+		},
+		instanceFieldsStmt, instanceID,
+	)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "V3-Is8fq", "Errors.Internal")
+	}
+	return state, nil
+}
+
+// Import inserts the events with their original creation date, sequence and position.
+// The events are inserted in batches of [importBatchSize] within a single transaction,
+// the producer version and maintenance window are kept if the events were queried using [eventstore.SearchQueryBuilder.IncludeOrigin].
+// The unique constraints and fields of the state are inserted in the same transaction,
+// the constraints of the instance of the first event are used for the constraints which aren't global.
+func (es *Eventstore) Import(ctx context.Context, state *eventstore.InstanceState, events ...eventstore.Event) (err error) {
+	if len(events) == 0 {
+		return nil
+	}
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	tx, err := es.client.BeginTx(ctx, nil)
+	if err != nil {
+		return zerrors.ThrowInternal(err, "V3-Im4xk", "Errors.Internal")
+	}
+	// tx is not closed because [crdb.ExecuteInTx] takes care of that
+	return crdb.ExecuteInTx(ctx, &transaction{tx}, func() error {
+		for start := 0; start < len(events); start += importBatchSize {
+			end := min(start+importBatchSize, len(events))
+			stmt, args := importBatch(events[start:end], start)
+			if _, err := tx.ExecContext(ctx, stmt, args...); err != nil {
+				return zerrors.ThrowInternal(err, "V3-Im7qa", "Errors.Internal")
+			}
+		}
+		if state == nil {
+			return nil
+		}
+		instanceID := events[0].Aggregate().InstanceID
+		for start := 0; start < len(state.UniqueConstraints); start += importConstraintsBatchSize {
+			end := min(start+importConstraintsBatchSize, len(state.UniqueConstraints))
+			stmt, args := importConstraintsBatch(instanceID, state.UniqueConstraints[start:end])
+			if _, err := tx.ExecContext(ctx, stmt, args...); err != nil {
+				return zerrors.ThrowAlreadyExists(err, "V3-Im2uc", "Errors.Instance.ImportConflict")
+			}
+		}
+		for _, field := range state.Fields {
+			if err := handleSearchInsert(ctx, tx, field); err != nil {
+				return zerrors.ThrowInternal(err, "V3-Im9fd", "Errors.Internal")
+			}
+		}
+		return nil
+	})
+}
+
+// importConstraintsBatch returns the insert statement of the unique constraints
+func importConstraintsBatch(instanceID string, constraints []*eventstore.UniqueConstraint) (string, []any) {
+	placeholders := make([]string, len(constraints))
+	args := make([]any, 0, len(constraints)*argsPerImportedConstraint)
+	for i, constraint := range constraints {
+		placeholders[i] = fmt.Sprintf("($%d, $%d, $%d)", len(args)+1, len(args)+2, len(args)+3)
+		constraintInstanceID := instanceID
+		if constraint.IsGlobal {
+			constraintInstanceID = ""
+		}
+		args = append(args, constraintInstanceID, constraint.UniqueType, strings.ToLower(constraint.UniqueField))
+	}
+	return fmt.Sprintf(addConstraintStmt, strings.Join(placeholders, ", ")), args
+}
+
+// importBatch returns the insert statement of the events,
+// offset is the index of the first event in the import to keep the order of events with the same position
+func importBatch(events []eventstore.Event, offset int) (string, []any) {
+	placeholders := make([]string, len(events))
+	args := make([]any, 0, len(events)*argsPerImportedEvent)
+	for i, event := range events {
+		params := make([]string, argsPerImportedEvent)
+		for j := range params {
+			params[j] = fmt.Sprintf("$%d", i*argsPerImportedEvent+j+1)
+		}
+		placeholders[i] = "(" + strings.Join(params, ", ") + ")"
+		producerVersion, maintenanceWindow := eventstore.EventOrigin(event)
+		args = append(args,
+			event.Aggregate().InstanceID,
+			event.Aggregate().ResourceOwner,
+			event.Aggregate().Type,
+			event.Aggregate().ID,
+			event.Revision(),
+			event.Creator(),
+			event.Type(),
+			event.DataAsBytes(),
+			event.Sequence(),
+			event.CreatedAt(),
+			event.Position(),
+			offset+i,
+			sql.NullString{String: producerVersion, Valid: producerVersion != ""},
+			sql.NullString{String: maintenanceWindow, Valid: maintenanceWindow != ""},
+		)
+	}
+	return fmt.Sprintf(importStmt, strings.Join(placeholders, ", ")), args
+}
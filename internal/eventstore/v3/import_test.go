@@ -0,0 +1,64 @@
+package eventstore
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+)
+
+func Test_importBatch(t *testing.T) {
+	createdAt := time.Now()
+	events := []eventstore.Event{
+		&repository.Event{
+			InstanceID:        "instance",
+			ResourceOwner:     sql.NullString{String: "org", Valid: true},
+			AggregateType:     "user",
+			AggregateID:       "user1",
+			EditorUser:        "creator",
+			Typ:               "user.added",
+			Data:              []byte(`{"userName":"hodor"}`),
+			Seq:               1,
+			CreationDate:      createdAt,
+			Pos:               1.5,
+			ProducerVersion:   sql.NullString{String: "v2.54.3", Valid: true},
+			MaintenanceWindow: sql.NullString{String: "window", Valid: true},
+		},
+		&repository.Event{
+			InstanceID:    "instance",
+			ResourceOwner: sql.NullString{String: "org", Valid: true},
+			AggregateType: "user",
+			AggregateID:   "user1",
+			Typ:           "user.changed",
+			Seq:           2,
+			CreationDate:  createdAt,
+			Pos:           1.5,
+		},
+	}
+
+	stmt, args := importBatch(events, 4000)
+	assert.True(t, strings.HasSuffix(stmt, "VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14), ($15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28)"), stmt)
+	assert.Equal(t, []any{
+		"instance", "org", eventstore.AggregateType("user"), "user1", uint16(0), "creator", eventstore.EventType("user.added"), []byte(`{"userName":"hodor"}`), uint64(1), createdAt, 1.5, 4000,
+		sql.NullString{String: "v2.54.3", Valid: true}, sql.NullString{String: "window", Valid: true},
+		"instance", "org", eventstore.AggregateType("user"), "user1", uint16(0), "", eventstore.EventType("user.changed"), []byte(nil), uint64(2), createdAt, 1.5, 4001,
+		sql.NullString{}, sql.NullString{},
+	}, args)
+}
+
+func Test_importConstraintsBatch(t *testing.T) {
+	stmt, args := importConstraintsBatch("instance", []*eventstore.UniqueConstraint{
+		eventstore.NewAddEventUniqueConstraint("usernames", "Hodor", ""),
+		eventstore.NewAddGlobalUniqueConstraint("instance_domain", "instance.domain", ""),
+	})
+	assert.True(t, strings.HasSuffix(stmt, "VALUES \n    ($1, $2, $3), ($4, $5, $6)"), stmt)
+	assert.Equal(t, []any{
+		"instance", "usernames", "hodor",
+		"", "instance_domain", "instance.domain",
+	}, args)
+}
@@ -19,6 +19,12 @@ import (
 )
 
 func (es *Eventstore) Push(ctx context.Context, commands ...eventstore.Command) (events []eventstore.Event, err error) {
+	return es.push(ctx, false, commands)
+}
+
+// push stores the commands in a single transaction,
+// if checkSeal is set the push fails if the instance of a command is sealed, see [eventstore.InstancesToCheckSeal]
+func (es *Eventstore) push(ctx context.Context, checkSeal bool, commands []eventstore.Command) (events []eventstore.Event, err error) {
 	ctx, spanBeginTx := tracing.NewNamedSpan(ctx, "db.BeginTx")
 	tx, err := es.client.BeginTx(ctx, nil)
 	spanBeginTx.EndWithError(err)
@@ -31,12 +37,18 @@ func (es *Eventstore) Push(ctx context.Context, commands ...eventstore.Command)
 	)
 
 	err = crdb.ExecuteInTx(ctx, &transaction{tx}, func() error {
+		if checkSeal {
+			if err = checkSealed(ctx, tx, eventstore.InstancesToCheckSeal(commands)); err != nil {
+				return err
+			}
+		}
+
 		sequences, err = latestSequences(ctx, tx, commands)
 		if err != nil {
 			return err
 		}
 
-		events, err = insertEvents(ctx, tx, sequences, commands)
+		events, err = insertEvents(ctx, tx, sequences, commands, es.producerVersion, eventstore.MaintenanceWindowFromContext(ctx))
 		if err != nil {
 			return err
 		}
@@ -67,8 +79,8 @@ func (es *Eventstore) Push(ctx context.Context, commands ...eventstore.Command)
 //go:embed push.sql
 var pushStmt string
 
-func insertEvents(ctx context.Context, tx *sql.Tx, sequences []*latestSequence, commands []eventstore.Command) ([]eventstore.Event, error) {
-	events, placeholders, args, err := mapCommands(commands, sequences)
+func insertEvents(ctx context.Context, tx *sql.Tx, sequences []*latestSequence, commands []eventstore.Command, producerVersion, maintenanceWindow string) ([]eventstore.Event, error) {
+	events, placeholders, args, err := mapCommands(commands, sequences, producerVersion, maintenanceWindow)
 	if err != nil {
 		return nil, err
 	}
@@ -104,9 +116,9 @@ func insertEvents(ctx context.Context, tx *sql.Tx, sequences []*latestSequence,
 	return events, nil
 }
 
-const argsPerCommand = 10
+const argsPerCommand = 12
 
-func mapCommands(commands []eventstore.Command, sequences []*latestSequence) (events []eventstore.Event, placeholders []string, args []any, err error) {
+func mapCommands(commands []eventstore.Command, sequences []*latestSequence, producerVersion, maintenanceWindow string) (events []eventstore.Event, placeholders []string, args []any, err error) {
 	events = make([]eventstore.Event, len(commands))
 	args = make([]any, 0, len(commands)*argsPerCommand)
 	placeholders = make([]string, len(commands))
@@ -140,6 +152,8 @@ func mapCommands(commands []eventstore.Command, sequences []*latestSequence) (ev
 			i*argsPerCommand+8,
 			i*argsPerCommand+9,
 			i*argsPerCommand+10,
+			i*argsPerCommand+11,
+			i*argsPerCommand+12,
 		)
 
 		revision, err := strconv.Atoi(strings.TrimPrefix(string(events[i].(*event).aggregate.Version), "v"))
@@ -157,6 +171,8 @@ func mapCommands(commands []eventstore.Command, sequences []*latestSequence) (ev
 			events[i].(*event).payload,
 			events[i].(*event).sequence,
 			i,
+			sql.NullString{String: producerVersion, Valid: producerVersion != ""},
+			sql.NullString{String: maintenanceWindow, Valid: maintenanceWindow != ""},
 		)
 	}
 
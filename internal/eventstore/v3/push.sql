@@ -13,6 +13,8 @@ INSERT INTO eventstore.events2 (
 
     , "position"
     , in_tx_order
+    , producer_version
+    , maintenance_window
 ) VALUES
     %s
 RETURNING created_at, "position";
\ No newline at end of file
@@ -1,6 +1,7 @@
 package eventstore
 
 import (
+	"database/sql"
 	_ "embed"
 	"testing"
 
@@ -65,7 +66,7 @@ func Test_mapCommands(t *testing.T) {
 					),
 				},
 				placeHolders: []string{
-					"($1, $2, $3, $4, $5, $6, $7, $8, $9, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $10)",
+					"($1, $2, $3, $4, $5, $6, $7, $8, $9, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $10, $11, $12)",
 				},
 				args: []any{
 					"instance",
@@ -78,6 +79,8 @@ func Test_mapCommands(t *testing.T) {
 					Payload(nil),
 					uint64(1),
 					0,
+					sql.NullString{String: "v2.54.3", Valid: true},
+					sql.NullString{},
 				},
 				err: func(t *testing.T, err error) {},
 			},
@@ -114,8 +117,8 @@ func Test_mapCommands(t *testing.T) {
 					),
 				},
 				placeHolders: []string{
-					"($1, $2, $3, $4, $5, $6, $7, $8, $9, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $10)",
-					"($11, $12, $13, $14, $15, $16, $17, $18, $19, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $20)",
+					"($1, $2, $3, $4, $5, $6, $7, $8, $9, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $10, $11, $12)",
+					"($13, $14, $15, $16, $17, $18, $19, $20, $21, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $22, $23, $24)",
 				},
 				args: []any{
 					// first event
@@ -129,6 +132,8 @@ func Test_mapCommands(t *testing.T) {
 					Payload(nil),
 					uint64(6),
 					0,
+					sql.NullString{String: "v2.54.3", Valid: true},
+					sql.NullString{},
 					// second event
 					"instance",
 					"ro",
@@ -140,6 +145,8 @@ func Test_mapCommands(t *testing.T) {
 					Payload(nil),
 					uint64(7),
 					1,
+					sql.NullString{String: "v2.54.3", Valid: true},
+					sql.NullString{},
 				},
 				err: func(t *testing.T, err error) {},
 			},
@@ -180,8 +187,8 @@ func Test_mapCommands(t *testing.T) {
 					),
 				},
 				placeHolders: []string{
-					"($1, $2, $3, $4, $5, $6, $7, $8, $9, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $10)",
-					"($11, $12, $13, $14, $15, $16, $17, $18, $19, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $20)",
+					"($1, $2, $3, $4, $5, $6, $7, $8, $9, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $10, $11, $12)",
+					"($13, $14, $15, $16, $17, $18, $19, $20, $21, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $22, $23, $24)",
 				},
 				args: []any{
 					// first event
@@ -195,6 +202,8 @@ func Test_mapCommands(t *testing.T) {
 					Payload(nil),
 					uint64(6),
 					0,
+					sql.NullString{String: "v2.54.3", Valid: true},
+					sql.NullString{},
 					// second event
 					"instance",
 					"ro",
@@ -206,6 +215,8 @@ func Test_mapCommands(t *testing.T) {
 					Payload(nil),
 					uint64(1),
 					1,
+					sql.NullString{String: "v2.54.3", Valid: true},
+					sql.NullString{},
 				},
 				err: func(t *testing.T, err error) {},
 			},
@@ -236,13 +247,13 @@ func Test_mapCommands(t *testing.T) {
 			}
 		}
 		// is used to set the the [pushPlaceholderFmt]
-		NewEventstore(&database.DB{Database: new(cockroach.Config)})
+		NewEventstore(&database.DB{Database: new(cockroach.Config)}, "")
 		t.Run(tt.name, func(t *testing.T) {
 			defer func() {
 				cause := recover()
 				assert.Equal(t, tt.want.shouldPanic, cause != nil)
 			}()
-			gotEvents, gotPlaceHolders, gotArgs, err := mapCommands(tt.args.commands, tt.args.sequences)
+			gotEvents, gotPlaceHolders, gotArgs, err := mapCommands(tt.args.commands, tt.args.sequences, "v2.54.3", "")
 			tt.want.err(t, err)
 
 			assert.ElementsMatch(t, tt.want.events, gotEvents)
@@ -0,0 +1,37 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+var _ eventstore.SealingPusher = (*Eventstore)(nil)
+
+const sealStmt = `SELECT event_type FROM eventstore.events2 WHERE instance_id = $1 AND aggregate_type = 'instance' AND aggregate_id = $1 AND event_type IN ($2, $3) ORDER BY "position" DESC, in_tx_order DESC LIMIT 1`
+
+// PushUnsealed implements [eventstore.SealingPusher]
+func (es *Eventstore) PushUnsealed(ctx context.Context, commands ...eventstore.Command) (events []eventstore.Event, err error) {
+	return es.push(ctx, true, commands)
+}
+
+// checkSealed returns an error if one of the instances is sealed by its latest seal event
+func checkSealed(ctx context.Context, tx *sql.Tx, instanceIDs []string) error {
+	for _, instanceID := range instanceIDs {
+		var eventType eventstore.EventType
+		err := tx.QueryRowContext(ctx, sealStmt, instanceID, eventstore.InstanceSealedType, eventstore.InstanceUnsealedType).Scan(&eventType)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return zerrors.ThrowInternal(err, "V3-Sl2vb", "Errors.Internal")
+		}
+		if eventType == eventstore.InstanceSealedType {
+			return zerrors.ThrowPreconditionFailed(nil, "V3-Sl6ke", "Errors.Instance.Sealed")
+		}
+	}
+	return nil
+}
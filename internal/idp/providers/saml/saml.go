@@ -124,13 +124,18 @@ func New(
 	if err != nil {
 		return nil, err
 	}
+	// the service provider of crewjam/saml only supports RSA keys
+	privateKey, ok := keyPair.PrivateKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, zerrors.ThrowInvalidArgument(nil, "SAML-Ec4ks", "Errors.Intent.IDPInvalid")
+	}
 	rootURL, err := url.Parse(rootURLStr)
 	if err != nil {
 		return nil, err
 	}
 	opts := samlsp.Options{
 		URL:         *rootURL,
-		Key:         keyPair.PrivateKey.(*rsa.PrivateKey),
+		Key:         privateKey,
 		Certificate: keyPair.Leaf,
 		IDPMetadata: entityDescriptor,
 		SignRequest: false,
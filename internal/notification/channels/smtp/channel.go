@@ -159,6 +159,26 @@ func (smtpConfig SMTP) smtpAuth(client *smtp.Client, host string) error {
 	return nil
 }
 
+// TestConnection connects and authenticates to the SMTP server without sending an email
+func TestConnection(cfg *Config) error {
+	client, err := cfg.SMTP.connectToSMTP(cfg.Tls)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return client.Quit()
+}
+
+// VerifyConfiguration checks the connection and authentication to the SMTP server.
+// If testEmail is set, a test email is sent to the address.
+func VerifyConfiguration(cfg *Config, testEmail string) error {
+	if testEmail == "" {
+		return TestConnection(cfg)
+	}
+	return TestConfiguration(cfg, testEmail)
+}
+
 func TestConfiguration(cfg *Config, testEmail string) error {
 	client, err := cfg.SMTP.connectToSMTP(cfg.Tls)
 	if err != nil {
@@ -31,3 +31,12 @@ func InitChannel(config Config) channels.NotificationChannel {
 		return nil
 	})
 }
+
+// TestConfiguration sends a test message to the recipient number using the config
+func TestConfiguration(cfg *Config, recipientNumber string) error {
+	return InitChannel(*cfg).HandleMessage(&messages.SMS{
+		SenderPhoneNumber:    cfg.SenderNumber,
+		RecipientPhoneNumber: recipientNumber,
+		Content:              "This is a test message to check if your SMS provider works fine",
+	})
+}
@@ -75,6 +75,10 @@ var (
 		name:  projection.AuthNKeyEnabledCol,
 		table: authNKeyTable,
 	}
+	AuthNKeyColumnLastUsed = Column{
+		name:  projection.AuthNKeyLastUsedCol,
+		table: authNKeyTable,
+	}
 )
 
 type AuthNKeys struct {
@@ -111,6 +115,23 @@ type AuthNKeyData struct {
 	PublicKey  []byte
 }
 
+// MachineKeyPublicKey is the public key of a machine key with the date of its last recorded usage
+type MachineKeyPublicKey struct {
+	PublicKey []byte
+	// LastUsed is nil if the key was never used
+	LastUsed *time.Time
+}
+
+// IdleMachineKey is a machine key which wasn't used for authentication since a cutoff date.
+type IdleMachineKey struct {
+	KeyID         string
+	UserID        string
+	ResourceOwner string
+	CreationDate  time.Time
+	// LastUsed is the date of the last recorded authentication using the key, nil if it was never used
+	LastUsed *time.Time
+}
+
 type AuthNKeySearchQueries struct {
 	SearchRequest
 	Queries []SearchQuery
@@ -237,6 +258,73 @@ func (q *Queries) GetAuthNKeyPublicKeyByIDAndIdentifier(ctx context.Context, id
 	return key, err
 }
 
+// GetMachineKeyPublicKeyByIDAndUserID returns the public key of an active machine key
+// together with its last recorded usage.
+func (q *Queries) GetMachineKeyPublicKeyByIDAndUserID(ctx context.Context, id, userID string) (key *MachineKeyPublicKey, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	stmt, scan := prepareMachineKeyPublicKeyQuery(ctx, q.client)
+	eq := sq.And{
+		sq.Eq{
+			AuthNKeyColumnID.identifier():         id,
+			AuthNKeyColumnIdentifier.identifier(): userID,
+			AuthNKeyColumnEnabled.identifier():    true,
+			AuthNKeyColumnInstanceID.identifier(): authz.GetInstance(ctx).InstanceID(),
+		},
+		sq.Gt{
+			AuthNKeyColumnExpiration.identifier(): time.Now(),
+		},
+	}
+	query, args, err := stmt.Where(eq).ToSql()
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Mk4pq", "Errors.Query.SQLStatement")
+	}
+
+	err = q.client.QueryRowContext(ctx, func(row *sql.Row) error {
+		key, err = scan(row)
+		return err
+	}, query, args...)
+	return key, err
+}
+
+// IdleMachineKeys returns the machine keys of the current instance
+// which were created before the cutoff and not used for authentication since then.
+// The usage of a key is only recorded periodically, so the result can be used to clean up unused keys.
+func (q *Queries) IdleMachineKeys(ctx context.Context, cutoff time.Time) (keys []*IdleMachineKey, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if cutoff.IsZero() {
+		return nil, zerrors.ThrowInvalidArgument(nil, "QUERY-Yb6ql", "Errors.InvalidArgument")
+	}
+	stmt, scan := prepareIdleMachineKeysQuery(ctx, q.client)
+	eq := sq.And{
+		sq.Eq{
+			AuthNKeyColumnInstanceID.identifier(): authz.GetInstance(ctx).InstanceID(),
+		},
+		// machine keys belong to the user, which is their aggregate as well as the object
+		sq.Expr(AuthNKeyColumnAggregateID.identifier() + " = " + AuthNKeyColumnObjectID.identifier()),
+		sq.Lt{
+			AuthNKeyColumnCreationDate.identifier(): cutoff,
+		},
+		sq.Or{
+			sq.Eq{AuthNKeyColumnLastUsed.identifier(): nil},
+			sq.Lt{AuthNKeyColumnLastUsed.identifier(): cutoff},
+		},
+	}
+	query, args, err := stmt.Where(eq).OrderBy(AuthNKeyColumnCreationDate.identifier()).ToSql()
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Pf4rw", "Errors.Query.SQLStatement")
+	}
+
+	err = q.client.QueryContext(ctx, func(rows *sql.Rows) error {
+		keys, err = scan(rows)
+		return err
+	}, query, args...)
+	return keys, err
+}
+
 func NewAuthNKeyResourceOwnerQuery(id string) (SearchQuery, error) {
 	return NewTextQuery(AuthNKeyColumnResourceOwner, id, TextEquals)
 }
@@ -347,6 +435,69 @@ func prepareAuthNKeyPublicKeyQuery(ctx context.Context, db prepareDatabase) (sq.
 		}
 }
 
+func prepareMachineKeyPublicKeyQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(row *sql.Row) (*MachineKeyPublicKey, error)) {
+	return sq.Select(
+			AuthNKeyColumnPublicKey.identifier(),
+			AuthNKeyColumnLastUsed.identifier(),
+		).From(authNKeyTable.identifier() + db.Timetravel(call.Took(ctx))).
+			PlaceholderFormat(sq.Dollar),
+		func(row *sql.Row) (*MachineKeyPublicKey, error) {
+			key := new(MachineKeyPublicKey)
+			var lastUsed sql.NullTime
+			err := row.Scan(
+				&key.PublicKey,
+				&lastUsed,
+			)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil, zerrors.ThrowNotFound(err, "QUERY-Mk5nf", "Errors.AuthNKey.NotFound")
+				}
+				return nil, zerrors.ThrowInternal(err, "QUERY-Mk6sc", "Errors.Internal")
+			}
+			if lastUsed.Valid {
+				key.LastUsed = &lastUsed.Time
+			}
+			return key, nil
+		}
+}
+
+func prepareIdleMachineKeysQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(rows *sql.Rows) ([]*IdleMachineKey, error)) {
+	return sq.Select(
+			AuthNKeyColumnID.identifier(),
+			AuthNKeyColumnAggregateID.identifier(),
+			AuthNKeyColumnResourceOwner.identifier(),
+			AuthNKeyColumnCreationDate.identifier(),
+			AuthNKeyColumnLastUsed.identifier(),
+		).From(authNKeyTable.identifier() + db.Timetravel(call.Took(ctx))).
+			PlaceholderFormat(sq.Dollar),
+		func(rows *sql.Rows) ([]*IdleMachineKey, error) {
+			keys := make([]*IdleMachineKey, 0)
+			for rows.Next() {
+				key := new(IdleMachineKey)
+				var lastUsed sql.NullTime
+				err := rows.Scan(
+					&key.KeyID,
+					&key.UserID,
+					&key.ResourceOwner,
+					&key.CreationDate,
+					&lastUsed,
+				)
+				if err != nil {
+					return nil, err
+				}
+				if lastUsed.Valid {
+					key.LastUsed = &lastUsed.Time
+				}
+				keys = append(keys, key)
+			}
+
+			if err := rows.Close(); err != nil {
+				return nil, zerrors.ThrowInternal(err, "QUERY-Id3cl", "Errors.Query.CloseRows")
+			}
+			return keys, nil
+		}
+}
+
 func prepareAuthNKeysDataQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(rows *sql.Rows) (*AuthNKeysData, error)) {
 	return sq.Select(
 			AuthNKeyColumnID.identifier(),
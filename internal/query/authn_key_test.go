@@ -13,15 +13,15 @@ import (
 )
 
 var (
-	prepareAuthNKeysStmt = `SELECT projections.authn_keys2.id,` +
-		` projections.authn_keys2.creation_date,` +
-		` projections.authn_keys2.change_date,` +
-		` projections.authn_keys2.resource_owner,` +
-		` projections.authn_keys2.sequence,` +
-		` projections.authn_keys2.expiration,` +
-		` projections.authn_keys2.type,` +
+	prepareAuthNKeysStmt = `SELECT projections.authn_keys3.id,` +
+		` projections.authn_keys3.creation_date,` +
+		` projections.authn_keys3.change_date,` +
+		` projections.authn_keys3.resource_owner,` +
+		` projections.authn_keys3.sequence,` +
+		` projections.authn_keys3.expiration,` +
+		` projections.authn_keys3.type,` +
 		` COUNT(*) OVER ()` +
-		` FROM projections.authn_keys2` +
+		` FROM projections.authn_keys3` +
 		` AS OF SYSTEM TIME '-1 ms'`
 	prepareAuthNKeysCols = []string{
 		"id",
@@ -34,17 +34,17 @@ var (
 		"count",
 	}
 
-	prepareAuthNKeysDataStmt = `SELECT projections.authn_keys2.id,` +
-		` projections.authn_keys2.creation_date,` +
-		` projections.authn_keys2.change_date,` +
-		` projections.authn_keys2.resource_owner,` +
-		` projections.authn_keys2.sequence,` +
-		` projections.authn_keys2.expiration,` +
-		` projections.authn_keys2.type,` +
-		` projections.authn_keys2.identifier,` +
-		` projections.authn_keys2.public_key,` +
+	prepareAuthNKeysDataStmt = `SELECT projections.authn_keys3.id,` +
+		` projections.authn_keys3.creation_date,` +
+		` projections.authn_keys3.change_date,` +
+		` projections.authn_keys3.resource_owner,` +
+		` projections.authn_keys3.sequence,` +
+		` projections.authn_keys3.expiration,` +
+		` projections.authn_keys3.type,` +
+		` projections.authn_keys3.identifier,` +
+		` projections.authn_keys3.public_key,` +
 		` COUNT(*) OVER ()` +
-		` FROM projections.authn_keys2` +
+		` FROM projections.authn_keys3` +
 		` AS OF SYSTEM TIME '-1 ms'`
 	prepareAuthNKeysDataCols = []string{
 		"id",
@@ -59,14 +59,14 @@ var (
 		"count",
 	}
 
-	prepareAuthNKeyStmt = `SELECT projections.authn_keys2.id,` +
-		` projections.authn_keys2.creation_date,` +
-		` projections.authn_keys2.change_date,` +
-		` projections.authn_keys2.resource_owner,` +
-		` projections.authn_keys2.sequence,` +
-		` projections.authn_keys2.expiration,` +
-		` projections.authn_keys2.type` +
-		` FROM projections.authn_keys2` +
+	prepareAuthNKeyStmt = `SELECT projections.authn_keys3.id,` +
+		` projections.authn_keys3.creation_date,` +
+		` projections.authn_keys3.change_date,` +
+		` projections.authn_keys3.resource_owner,` +
+		` projections.authn_keys3.sequence,` +
+		` projections.authn_keys3.expiration,` +
+		` projections.authn_keys3.type` +
+		` FROM projections.authn_keys3` +
 		` AS OF SYSTEM TIME '-1 ms'`
 	prepareAuthNKeyCols = []string{
 		"id",
@@ -78,12 +78,36 @@ var (
 		"type",
 	}
 
-	prepareAuthNKeyPublicKeyStmt = `SELECT projections.authn_keys2.public_key` +
-		` FROM projections.authn_keys2` +
+	prepareAuthNKeyPublicKeyStmt = `SELECT projections.authn_keys3.public_key` +
+		` FROM projections.authn_keys3` +
 		` AS OF SYSTEM TIME '-1 ms'`
 	prepareAuthNKeyPublicKeyCols = []string{
 		"public_key",
 	}
+
+	prepareMachineKeyPublicKeyStmt = `SELECT projections.authn_keys3.public_key,` +
+		` projections.authn_keys3.last_used` +
+		` FROM projections.authn_keys3` +
+		` AS OF SYSTEM TIME '-1 ms'`
+	prepareMachineKeyPublicKeyCols = []string{
+		"public_key",
+		"last_used",
+	}
+
+	prepareIdleMachineKeysStmt = `SELECT projections.authn_keys3.id,` +
+		` projections.authn_keys3.aggregate_id,` +
+		` projections.authn_keys3.resource_owner,` +
+		` projections.authn_keys3.creation_date,` +
+		` projections.authn_keys3.last_used` +
+		` FROM projections.authn_keys3` +
+		` AS OF SYSTEM TIME '-1 ms'`
+	prepareIdleMachineKeysCols = []string{
+		"id",
+		"aggregate_id",
+		"resource_owner",
+		"creation_date",
+		"last_used",
+	}
 )
 
 func Test_AuthNKeyPrepares(t *testing.T) {
@@ -463,6 +487,129 @@ func Test_AuthNKeyPrepares(t *testing.T) {
 			},
 			object: ([]byte)(nil),
 		},
+		{
+			name:    "prepareMachineKeyPublicKeyQuery no result",
+			prepare: prepareMachineKeyPublicKeyQuery,
+			want: want{
+				sqlExpectations: mockQueriesScanErr(
+					regexp.QuoteMeta(prepareMachineKeyPublicKeyStmt),
+					nil,
+					nil,
+				),
+				err: func(err error) (error, bool) {
+					if !zerrors.IsNotFound(err) {
+						return fmt.Errorf("err should be zitadel.NotFoundError got: %w", err), false
+					}
+					return nil, true
+				},
+			},
+			object: (*MachineKeyPublicKey)(nil),
+		},
+		{
+			name:    "prepareMachineKeyPublicKeyQuery never used",
+			prepare: prepareMachineKeyPublicKeyQuery,
+			want: want{
+				sqlExpectations: mockQuery(
+					regexp.QuoteMeta(prepareMachineKeyPublicKeyStmt),
+					prepareMachineKeyPublicKeyCols,
+					[]driver.Value{
+						[]byte("publicKey"),
+						nil,
+					},
+				),
+			},
+			object: &MachineKeyPublicKey{
+				PublicKey: []byte("publicKey"),
+			},
+		},
+		{
+			name:    "prepareMachineKeyPublicKeyQuery used",
+			prepare: prepareMachineKeyPublicKeyQuery,
+			want: want{
+				sqlExpectations: mockQuery(
+					regexp.QuoteMeta(prepareMachineKeyPublicKeyStmt),
+					prepareMachineKeyPublicKeyCols,
+					[]driver.Value{
+						[]byte("publicKey"),
+						testNow,
+					},
+				),
+			},
+			object: &MachineKeyPublicKey{
+				PublicKey: []byte("publicKey"),
+				LastUsed:  &testNow,
+			},
+		},
+		{
+			name:    "prepareIdleMachineKeysQuery no result",
+			prepare: prepareIdleMachineKeysQuery,
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(prepareIdleMachineKeysStmt),
+					nil,
+					nil,
+				),
+			},
+			object: []*IdleMachineKey{},
+		},
+		{
+			name:    "prepareIdleMachineKeysQuery multiple results",
+			prepare: prepareIdleMachineKeysQuery,
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(prepareIdleMachineKeysStmt),
+					prepareIdleMachineKeysCols,
+					[][]driver.Value{
+						{
+							"never-used",
+							"user1",
+							"org1",
+							testNow,
+							nil,
+						},
+						{
+							"used",
+							"user1",
+							"org1",
+							testNow,
+							testNow,
+						},
+					},
+				),
+			},
+			object: []*IdleMachineKey{
+				{
+					KeyID:         "never-used",
+					UserID:        "user1",
+					ResourceOwner: "org1",
+					CreationDate:  testNow,
+				},
+				{
+					KeyID:         "used",
+					UserID:        "user1",
+					ResourceOwner: "org1",
+					CreationDate:  testNow,
+					LastUsed:      &testNow,
+				},
+			},
+		},
+		{
+			name:    "prepareIdleMachineKeysQuery sql err",
+			prepare: prepareIdleMachineKeysQuery,
+			want: want{
+				sqlExpectations: mockQueryErr(
+					regexp.QuoteMeta(prepareIdleMachineKeysStmt),
+					sql.ErrConnDone,
+				),
+				err: func(err error) (error, bool) {
+					if !errors.Is(err, sql.ErrConnDone) {
+						return fmt.Errorf("err should be sql.ErrConnDone got: %w", err), false
+					}
+					return nil, true
+				},
+			},
+			object: ([]*IdleMachineKey)(nil),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
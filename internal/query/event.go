@@ -51,7 +51,8 @@ func (q *Queries) SearchEvents(ctx context.Context, query *eventstore.SearchQuer
 		query = filterAuditLogRetention(ctx, auditLogRetention, query)
 	}
 	reducer := &eventsReducer{ctx: ctx, q: q, editors: make(map[string]*EventEditor, query.GetLimit())}
-	if err = q.eventstore.FilterToReducer(ctx, query, reducer); err != nil {
+	// the result is truncated to the result cap of the instance, the eventstore logs the truncation
+	if _, err = q.eventstore.FilterToReducerCapped(ctx, query, reducer); err != nil {
 		return nil, err
 	}
 	return reducer.events, nil
@@ -158,6 +158,10 @@ var (
 		name:  projection.KeyPrivateColumnKey,
 		table: keyPrivateTable,
 	}
+	KeyPrivateColNotBefore = Column{
+		name:  projection.KeyPrivateColumnNotBefore,
+		table: keyPrivateTable,
+	}
 )
 
 var (
@@ -226,6 +230,11 @@ func (q *Queries) ActivePrivateSigningKey(ctx context.Context, t time.Time) (key
 				KeyColInstanceID.identifier(): authz.GetInstance(ctx).InstanceID(),
 			},
 			sq.Gt{KeyPrivateColExpiry.identifier(): t},
+			// keys generated ahead of a rotation are used from their switchover on
+			sq.Or{
+				sq.Eq{KeyPrivateColNotBefore.identifier(): nil},
+				sq.LtOrEq{KeyPrivateColNotBefore.identifier(): t},
+			},
 		}).OrderBy(KeyPrivateColExpiry.identifier()).ToSql()
 	if err != nil {
 		return nil, zerrors.ThrowInternal(err, "QUERY-SDff2", "Errors.Query.SQLStatement")
@@ -245,6 +254,52 @@ func (q *Queries) ActivePrivateSigningKey(ctx context.Context, t time.Time) (key
 	return keys, nil
 }
 
+// ActiveKeysByUsage returns the private keys of the usage which are active at t ordered by their creation date.
+// A key expiring exactly at t is already expired.
+// Revoked keys are removed from the projection and therefore never returned.
+func (q *Queries) ActiveKeysByUsage(ctx context.Context, usage domain.KeyUsage, t time.Time) (keys *PrivateKeys, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	stmt, scan := preparePrivateKeysQuery(ctx, q.client)
+	if t.IsZero() {
+		t = time.Now()
+	}
+	query, args, err := activeKeysByUsage(stmt, authz.GetInstance(ctx).InstanceID(), usage, t).ToSql()
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Ak4ue", "Errors.Query.SQLStatement")
+	}
+
+	err = q.client.QueryContext(ctx, func(rows *sql.Rows) error {
+		keys, err = scan(rows)
+		return err
+	}, query, args...)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Ak5uf", "Errors.Internal")
+	}
+	keys.State, err = q.latestState(ctx, keyTable)
+	if !zerrors.IsNotFound(err) {
+		return keys, err
+	}
+	return keys, nil
+}
+
+func activeKeysByUsage(query sq.SelectBuilder, instanceID string, usage domain.KeyUsage, t time.Time) sq.SelectBuilder {
+	return query.Where(
+		sq.And{
+			sq.Eq{
+				KeyColUse.identifier():        usage,
+				KeyColInstanceID.identifier(): instanceID,
+			},
+			// strictly greater, keys expiring at t are expired
+			sq.Gt{KeyPrivateColExpiry.identifier(): t},
+			sq.Or{
+				sq.Eq{KeyPrivateColNotBefore.identifier(): nil},
+				sq.LtOrEq{KeyPrivateColNotBefore.identifier(): t},
+			},
+		}).OrderBy(KeyColCreationDate.identifier())
+}
+
 func preparePublicKeysQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Rows) (*PublicKeys, error)) {
 	return sq.Select(
 			KeyColID.identifier(),
@@ -382,6 +437,9 @@ func (wm *PublicKeyReadModel) Reduce() error {
 			wm.Key = e.PublicKey.Key
 			wm.Expiry = e.PublicKey.Expiry
 			wm.Usage = e.Usage
+		case *keypair.RevokedEvent:
+			// revoked keys must not be used to verify tokens anymore
+			wm.Key = nil
 		default:
 		}
 	}
@@ -395,7 +453,7 @@ func (wm *PublicKeyReadModel) Query() *eventstore.SearchQueryBuilder {
 		AddQuery().
 		AggregateTypes(keypair.AggregateType).
 		AggregateIDs(wm.AggregateID).
-		EventTypes(keypair.AddedEventType).
+		EventTypes(keypair.AddedEventType, keypair.RevokedEventType).
 		Builder()
 }
 
@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -26,18 +27,18 @@ import (
 )
 
 var (
-	preparePublicKeysStmt = `SELECT projections.keys4.id,` +
-		` projections.keys4.creation_date,` +
-		` projections.keys4.change_date,` +
-		` projections.keys4.sequence,` +
-		` projections.keys4.resource_owner,` +
-		` projections.keys4.algorithm,` +
-		` projections.keys4.use,` +
-		` projections.keys4_public.expiry,` +
-		` projections.keys4_public.key,` +
+	preparePublicKeysStmt = `SELECT projections.keys5.id,` +
+		` projections.keys5.creation_date,` +
+		` projections.keys5.change_date,` +
+		` projections.keys5.sequence,` +
+		` projections.keys5.resource_owner,` +
+		` projections.keys5.algorithm,` +
+		` projections.keys5.use,` +
+		` projections.keys5_public.expiry,` +
+		` projections.keys5_public.key,` +
 		` COUNT(*) OVER ()` +
-		` FROM projections.keys4` +
-		` LEFT JOIN projections.keys4_public ON projections.keys4.id = projections.keys4_public.id AND projections.keys4.instance_id = projections.keys4_public.instance_id` +
+		` FROM projections.keys5` +
+		` LEFT JOIN projections.keys5_public ON projections.keys5.id = projections.keys5_public.id AND projections.keys5.instance_id = projections.keys5_public.instance_id` +
 		` AS OF SYSTEM TIME '-1 ms' `
 	preparePublicKeysCols = []string{
 		"id",
@@ -52,18 +53,18 @@ var (
 		"count",
 	}
 
-	preparePrivateKeysStmt = `SELECT projections.keys4.id,` +
-		` projections.keys4.creation_date,` +
-		` projections.keys4.change_date,` +
-		` projections.keys4.sequence,` +
-		` projections.keys4.resource_owner,` +
-		` projections.keys4.algorithm,` +
-		` projections.keys4.use,` +
-		` projections.keys4_private.expiry,` +
-		` projections.keys4_private.key,` +
+	preparePrivateKeysStmt = `SELECT projections.keys5.id,` +
+		` projections.keys5.creation_date,` +
+		` projections.keys5.change_date,` +
+		` projections.keys5.sequence,` +
+		` projections.keys5.resource_owner,` +
+		` projections.keys5.algorithm,` +
+		` projections.keys5.use,` +
+		` projections.keys5_private.expiry,` +
+		` projections.keys5_private.key,` +
 		` COUNT(*) OVER ()` +
-		` FROM projections.keys4` +
-		` LEFT JOIN projections.keys4_private ON projections.keys4.id = projections.keys4_private.id AND projections.keys4.instance_id = projections.keys4_private.instance_id` +
+		` FROM projections.keys5` +
+		` LEFT JOIN projections.keys5_private ON projections.keys5.id = projections.keys5_private.id AND projections.keys5.instance_id = projections.keys5_private.instance_id` +
 		` AS OF SYSTEM TIME '-1 ms' `
 )
 
@@ -225,6 +226,65 @@ func Test_KeyPrepares(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "preparePrivateKeysQuery active by usage",
+			prepare: func(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Rows) (*PrivateKeys, error)) {
+				query, scan := preparePrivateKeysQuery(ctx, db)
+				return activeKeysByUsage(query, "instance-id", domain.KeyUsageSAMLMetadataSigning, testNow), scan
+			},
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(preparePrivateKeysStmt+
+						`WHERE (projections.keys5.instance_id = $1 AND projections.keys5.use = $2`+
+						` AND projections.keys5_private.expiry > $3`+
+						` AND (projections.keys5_private.not_before IS NULL OR projections.keys5_private.not_before <= $4))`+
+						` ORDER BY projections.keys5.creation_date`),
+					preparePublicKeysCols,
+					[][]driver.Value{
+						{
+							"key-id",
+							testNow,
+							testNow,
+							uint64(20211109),
+							"ro",
+							"RS256",
+							domain.KeyUsageSAMLMetadataSigning,
+							testNow.Add(time.Hour),
+							[]byte(`{"Algorithm": "enc", "Crypted": "cHJpdmF0ZUtleQ==", "CryptoType": 0, "KeyID": "id"}`),
+						},
+					},
+					"instance-id",
+					domain.KeyUsageSAMLMetadataSigning,
+					testNow,
+					testNow,
+				),
+			},
+			object: &PrivateKeys{
+				SearchResponse: SearchResponse{
+					Count: 1,
+				},
+				Keys: []PrivateKey{
+					&privateKey{
+						key: key{
+							id:            "key-id",
+							creationDate:  testNow,
+							changeDate:    testNow,
+							sequence:      20211109,
+							resourceOwner: "ro",
+							algorithm:     "RS256",
+							use:           domain.KeyUsageSAMLMetadataSigning,
+						},
+						expiry: testNow.Add(time.Hour),
+						privateKey: &crypto.CryptoValue{
+							CryptoType: crypto.TypeEncryption,
+							Algorithm:  "enc",
+							KeyID:      "id",
+							Crypted:    []byte("privateKey"),
+						},
+					},
+				},
+			},
+		},
 		{
 			name:    "preparePrivateKeysQuery sql err",
 			prepare: preparePrivateKeysQuery,
@@ -294,6 +354,47 @@ func TestQueries_GetPublicKeyByID(t *testing.T) {
 			),
 			wantErr: zerrors.ThrowNotFound(nil, "QUERY-Ahf7x", "Errors.Key.NotFound"),
 		},
+		{
+			name: "revoked, not found error",
+			eventstore: expectEventstore(
+				expectFilter(
+					eventFromEventPusher(key_repo.NewAddedEvent(context.Background(),
+						&eventstore.Aggregate{
+							ID:            "keyID",
+							Type:          key_repo.AggregateType,
+							ResourceOwner: "instanceID",
+							InstanceID:    "instanceID",
+							Version:       key_repo.AggregateVersion,
+						},
+						domain.KeyUsageSigning, "alg",
+						&crypto.CryptoValue{
+							CryptoType: crypto.TypeEncryption,
+							Algorithm:  "alg",
+							KeyID:      "keyID",
+							Crypted:    []byte("private"),
+						},
+						&crypto.CryptoValue{
+							CryptoType: crypto.TypeEncryption,
+							Algorithm:  "alg",
+							KeyID:      "keyID",
+							Crypted:    []byte("public"),
+						},
+						future,
+						future,
+					)),
+					eventFromEventPusher(key_repo.NewRevokedEvent(context.Background(),
+						&eventstore.Aggregate{
+							ID:            "keyID",
+							Type:          key_repo.AggregateType,
+							ResourceOwner: "instanceID",
+							InstanceID:    "instanceID",
+							Version:       key_repo.AggregateVersion,
+						},
+					)),
+				),
+			),
+			wantErr: zerrors.ThrowNotFound(nil, "QUERY-Ahf7x", "Errors.Key.NotFound"),
+		},
 		{
 			name: "decrypt error",
 			eventstore: expectEventstore(
@@ -1,6 +1,7 @@
 package query
 
 import (
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
@@ -26,6 +27,30 @@ func NewMemberEmailSearchQuery(method TextComparison, value string) (SearchQuery
 	return NewTextQuery(HumanEmailCol, value, method)
 }
 
+// NewMemberVerifiedEmailSearchQuery matches the members with the verified email of the instance case-insensitive,
+// it uses the lower case verified email of the user notifications
+func NewMemberVerifiedEmailSearchQuery(instanceID, email string) (SearchQuery, error) {
+	instanceQuery, err := NewTextQuery(NotifyInstanceIDCol, instanceID, TextEquals)
+	if err != nil {
+		return nil, err
+	}
+	emailQuery, err := NewUserVerifiedEmailSearchQuery(email)
+	if err != nil {
+		return nil, err
+	}
+	userIDs, err := NewSubSelect(NotifyUserIDCol, []SearchQuery{instanceQuery, emailQuery})
+	if err != nil {
+		return nil, err
+	}
+	return NewListQuery(HumanUserIDCol, userIDs, ListIn)
+}
+
+// NewMemberUserNameLowerCaseSearchQuery matches the username of the members case-insensitive,
+// the username itself keeps its original case
+func NewMemberUserNameLowerCaseSearchQuery(value string) (SearchQuery, error) {
+	return NewTextQuery(UserUsernameLowerCaseCol, strings.ToLower(value), TextEquals)
+}
+
 func NewMemberFirstNameSearchQuery(method TextComparison, value string) (SearchQuery, error) {
 	return NewTextQuery(HumanFirstNameCol, value, method)
 }
@@ -52,8 +77,11 @@ type Member struct {
 	Sequence      uint64
 	ResourceOwner string
 
-	UserID             string
-	Roles              database.TextArray[string]
+	UserID string
+	Roles  database.TextArray[string]
+	// AddedBy is the id of the user who added the member,
+	// it's only set for org members added after it was tracked
+	AddedBy            string
 	PreferredLoginName string
 	Email              string
 	FirstName          string
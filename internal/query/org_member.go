@@ -3,12 +3,17 @@ package query
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/zitadel/logging"
 
 	"github.com/zitadel/zitadel/internal/api/authz"
 	"github.com/zitadel/zitadel/internal/api/call"
+	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/query/projection"
 	"github.com/zitadel/zitadel/internal/telemetry/tracing"
 	"github.com/zitadel/zitadel/internal/zerrors"
@@ -28,6 +33,10 @@ var (
 		name:  projection.MemberRolesCol,
 		table: orgMemberTable,
 	}
+	OrgMemberUserResourceOwner = Column{
+		name:  projection.MemberUserResourceOwner,
+		table: orgMemberTable,
+	}
 	OrgMemberCreationDate = Column{
 		name:  projection.MemberCreationDate,
 		table: orgMemberTable,
@@ -52,8 +61,36 @@ var (
 		name:  projection.OrgMemberOrgIDCol,
 		table: orgMemberTable,
 	}
+	OrgMemberState = Column{
+		name:  projection.OrgMemberStateCol,
+		table: orgMemberTable,
+	}
+	OrgMemberOrgName = Column{
+		name:  projection.OrgMemberOrgNameCol,
+		table: orgMemberTable,
+	}
+	OrgMemberAddedBy = Column{
+		name:  projection.OrgMemberAddedByCol,
+		table: orgMemberTable,
+	}
 )
 
+// OrgMemberRow is the row of a member in the org members projection, without the data of the user
+type OrgMemberRow struct {
+	CreationDate  time.Time
+	ChangeDate    time.Time
+	Sequence      uint64
+	ResourceOwner string
+
+	UserID            string
+	UserResourceOwner string
+	Roles             database.TextArray[string]
+	State             domain.MemberState
+	OrgName           string
+	// AddedBy is only set for members added after it was tracked
+	AddedBy string
+}
+
 type OrgMembersQuery struct {
 	MembersQuery
 	OrgID string
@@ -65,6 +102,11 @@ func (q *OrgMembersQuery) toQuery(query sq.SelectBuilder) sq.SelectBuilder {
 		Where(sq.Eq{OrgMemberOrgID.identifier(): q.OrgID})
 }
 
+// NewOrgMemberRoleSearchQuery matches the members whose roles contain the role
+func NewOrgMemberRoleSearchQuery(role string) (SearchQuery, error) {
+	return NewTextQuery(OrgMemberRoles, role, TextListContains)
+}
+
 func (q *Queries) OrgMembers(ctx context.Context, queries *OrgMembersQuery) (members *Members, err error) {
 	ctx, span := tracing.NewSpan(ctx)
 	defer func() { span.EndWithError(err) }()
@@ -93,6 +135,199 @@ func (q *Queries) OrgMembers(ctx context.Context, queries *OrgMembersQuery) (mem
 	return members, err
 }
 
+// OrgMemberByEmail returns the member of the org with the verified email, the email is compared case-insensitive.
+// As emails are not unique, an error is returned if multiple members have the email.
+func (q *Queries) OrgMemberByEmail(ctx context.Context, orgID, email string) (member *Member, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	emailQuery, err := NewMemberVerifiedEmailSearchQuery(authz.GetInstance(ctx).InstanceID(), email)
+	if err != nil {
+		return nil, err
+	}
+	members, err := q.OrgMembers(ctx, &OrgMembersQuery{
+		MembersQuery: MembersQuery{
+			Queries: []SearchQuery{emailQuery},
+		},
+		OrgID: orgID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch len(members.Members) {
+	case 0:
+		return nil, zerrors.ThrowNotFound(nil, "QUERY-Mb4eL", "Errors.Org.MemberNotFound")
+	case 1:
+		return members.Members[0], nil
+	default:
+		return nil, zerrors.ThrowPreconditionFailed(nil, "QUERY-Mb6rq", "Errors.Org.MemberEmailAmbiguous")
+	}
+}
+
+// OrgMembersByRole returns the members of the org holding the role, members without roles never match
+func (q *Queries) OrgMembersByRole(ctx context.Context, orgID, role string) (members *Members, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if role == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "QUERY-Rl5mN", "Errors.Org.InvalidMember")
+	}
+	roleQuery, err := NewOrgMemberRoleSearchQuery(role)
+	if err != nil {
+		return nil, err
+	}
+	return q.OrgMembers(ctx, &OrgMembersQuery{
+		MembersQuery: MembersQuery{
+			Queries: []SearchQuery{roleQuery},
+		},
+		OrgID: orgID,
+	})
+}
+
+// OrgMemberRow returns the row of the member in the org members projection as it is, without triggering the projection.
+// It's used to compare the projection with the events of the member, so the position of the last event reduced by the projection
+// is returned from the same snapshot. The row is nil if the projection contains no row for the member,
+// the position is nil if the projection didn't run for the instance yet.
+func (q *Queries) OrgMemberRow(ctx context.Context, orgID, userID string) (row *OrgMemberRow, reduced *projection.ReducedPosition, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	instanceID := authz.GetInstance(ctx).InstanceID()
+	query, scan := prepareOrgMemberRowQuery()
+	stmt, args, err := query.Where(sq.Eq{
+		OrgMemberInstanceID.identifier(): instanceID,
+		OrgMemberOrgID.identifier():      orgID,
+		OrgMemberUserID.identifier():     userID,
+	}).ToSql()
+	if err != nil {
+		return nil, nil, zerrors.ThrowInternal(err, "QUERY-Rw3mq", "Errors.Query.SQLStatement")
+	}
+
+	// both queries read the same snapshot
+	tx, err := q.client.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, nil, zerrors.ThrowInternal(err, "QUERY-Rw6tx", "Errors.Internal")
+	}
+	defer func() {
+		rollbackErr := tx.Rollback()
+		logging.OnError(rollbackErr).Debug("rollback failed")
+	}()
+
+	reduced, err = orgMemberReducedPosition(ctx, tx, instanceID, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	row, err = scan(tx.QueryRowContext(ctx, stmt, args...))
+	if zerrors.IsNotFound(err) {
+		return nil, reduced, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return row, reduced, nil
+}
+
+// ReprojectOrgMember replaces the row of the member in the org members projection by projecting its events again.
+// The projection of the instance is locked meanwhile, so the row can't be changed concurrently,
+// and only the events already reduced by the projection are projected.
+func (q *Queries) ReprojectOrgMember(ctx context.Context, orgID, userID string) (err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	instanceID := authz.GetInstance(ctx).InstanceID()
+	tx, err := q.client.BeginTx(ctx, nil)
+	if err != nil {
+		return zerrors.ThrowInternal(err, "QUERY-Rp5bt", "Errors.Internal")
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			logging.OnError(rollbackErr).Debug("rollback failed")
+			return
+		}
+		if commitErr := tx.Commit(); commitErr != nil {
+			err = zerrors.ThrowInternal(commitErr, "QUERY-Rp6cm", "Errors.Internal")
+		}
+	}()
+
+	reduced, err := orgMemberReducedPosition(ctx, tx, instanceID, true)
+	if err != nil {
+		return err
+	}
+	return projection.ReprojectOrgMember(ctx, q.eventstore, tx, reduced, instanceID, orgID, userID)
+}
+
+// orgMemberReducedPosition returns the position of the last event reduced by the org members projection of the instance,
+// nil is returned if the projection didn't run for the instance yet.
+// The current state of the projection is locked until the end of tx if forUpdate is set.
+func orgMemberReducedPosition(ctx context.Context, tx *sql.Tx, instanceID string, forUpdate bool) (*projection.ReducedPosition, error) {
+	stmt := "SELECT " + CurrentStateColPosition.name + ", " + CurrentStateColAggregateType.name + ", " + CurrentStateColAggregateID.name + ", " + CurrentStateColSequence.name +
+		" FROM " + currentStateTable.identifier() + " WHERE " + CurrentStateColInstanceID.name + " = $1 AND " + CurrentStateColProjectionName.name + " = $2"
+	if forUpdate {
+		stmt += " FOR UPDATE"
+	}
+	var (
+		position      sql.NullFloat64
+		aggregateType sql.NullString
+		aggregateID   sql.NullString
+		sequence      sql.NullInt64
+	)
+	err := tx.QueryRowContext(ctx, stmt, instanceID, projection.OrgMemberProjectionTable).Scan(&position, &aggregateType, &aggregateID, &sequence)
+	// the projection didn't run for the instance yet
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Rp7lk", "Errors.Internal")
+	}
+	return &projection.ReducedPosition{
+		Position:      position.Float64,
+		AggregateType: eventstore.AggregateType(aggregateType.String),
+		AggregateID:   aggregateID.String,
+		Sequence:      uint64(sequence.Int64),
+	}, nil
+}
+
+func prepareOrgMemberRowQuery() (sq.SelectBuilder, func(*sql.Row) (*OrgMemberRow, error)) {
+	return sq.Select(
+			OrgMemberCreationDate.identifier(),
+			OrgMemberChangeDate.identifier(),
+			OrgMemberSequence.identifier(),
+			OrgMemberResourceOwner.identifier(),
+			OrgMemberUserID.identifier(),
+			OrgMemberUserResourceOwner.identifier(),
+			OrgMemberRoles.identifier(),
+			OrgMemberState.identifier(),
+			OrgMemberOrgName.identifier(),
+			OrgMemberAddedBy.identifier(),
+		).From(orgMemberTable.identifier()).
+			PlaceholderFormat(sq.Dollar),
+		func(row *sql.Row) (*OrgMemberRow, error) {
+			member := new(OrgMemberRow)
+			var addedBy sql.NullString
+			err := row.Scan(
+				&member.CreationDate,
+				&member.ChangeDate,
+				&member.Sequence,
+				&member.ResourceOwner,
+				&member.UserID,
+				&member.UserResourceOwner,
+				&member.Roles,
+				&member.State,
+				&member.OrgName,
+				&addedBy,
+			)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil, zerrors.ThrowNotFound(err, "QUERY-Rw4nf", "Errors.Org.MemberNotFound")
+				}
+				return nil, zerrors.ThrowInternal(err, "QUERY-Rw5sc", "Errors.Internal")
+			}
+			member.AddedBy = addedBy.String
+			return member, nil
+		}
+}
+
 func prepareOrgMembersQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Rows) (*Members, error)) {
 	return sq.Select(
 			OrgMemberCreationDate.identifier(),
@@ -101,6 +336,7 @@ func prepareOrgMembersQuery(ctx context.Context, db prepareDatabase) (sq.SelectB
 			OrgMemberResourceOwner.identifier(),
 			OrgMemberUserID.identifier(),
 			OrgMemberRoles.identifier(),
+			OrgMemberAddedBy.identifier(),
 			LoginNameNameCol.identifier(),
 			HumanEmailCol.identifier(),
 			HumanFirstNameCol.identifier(),
@@ -126,6 +362,7 @@ func prepareOrgMembersQuery(ctx context.Context, db prepareDatabase) (sq.SelectB
 				member := new(Member)
 
 				var (
+					addedBy            = sql.NullString{}
 					preferredLoginName = sql.NullString{}
 					email              = sql.NullString{}
 					firstName          = sql.NullString{}
@@ -143,6 +380,7 @@ func prepareOrgMembersQuery(ctx context.Context, db prepareDatabase) (sq.SelectB
 					&member.ResourceOwner,
 					&member.UserID,
 					&member.Roles,
+					&addedBy,
 					&preferredLoginName,
 					&email,
 					&firstName,
@@ -159,6 +397,7 @@ func prepareOrgMembersQuery(ctx context.Context, db prepareDatabase) (sq.SelectB
 					return nil, err
 				}
 
+				member.AddedBy = addedBy.String
 				member.PreferredLoginName = preferredLoginName.String
 				member.Email = email.String
 				member.FirstName = firstName.String
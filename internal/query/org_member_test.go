@@ -1,6 +1,7 @@
 package query
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"errors"
@@ -8,8 +9,16 @@ import (
 	"regexp"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
 	"github.com/zitadel/zitadel/internal/database"
+	db_mock "github.com/zitadel/zitadel/internal/database/mock"
 	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
 var (
@@ -20,6 +29,7 @@ var (
 		", members.resource_owner" +
 		", members.user_id" +
 		", members.roles" +
+		", members.added_by" +
 		", projections.login_names3.login_name" +
 		", projections.users13_humans.email" +
 		", projections.users13_humans.first_name" +
@@ -29,7 +39,7 @@ var (
 		", projections.users13_humans.avatar_key" +
 		", projections.users13.type" +
 		", COUNT(*) OVER () " +
-		"FROM projections.org_members4 AS members " +
+		"FROM projections.org_members7 AS members " +
 		"LEFT JOIN projections.users13_humans " +
 		"ON members.user_id = projections.users13_humans.user_id " +
 		"AND members.instance_id = projections.users13_humans.instance_id " +
@@ -51,6 +61,7 @@ var (
 		"resource_owner",
 		"user_id",
 		"roles",
+		"added_by",
 		"login_name",
 		"email",
 		"first_name",
@@ -103,6 +114,7 @@ func Test_OrgMemberPrepares(t *testing.T) {
 							"ro",
 							"user-id",
 							database.TextArray[string]{"role-1", "role-2"},
+							"editor-user",
 							"gigi@caos-ag.zitadel.ch",
 							"gigi@caos.ch",
 							"first-name",
@@ -127,6 +139,188 @@ func Test_OrgMemberPrepares(t *testing.T) {
 						ResourceOwner:      "ro",
 						UserID:             "user-id",
 						Roles:              database.TextArray[string]{"role-1", "role-2"},
+						AddedBy:            "editor-user",
+						PreferredLoginName: "gigi@caos-ag.zitadel.ch",
+						Email:              "gigi@caos.ch",
+						FirstName:          "first-name",
+						LastName:           "last-name",
+						DisplayName:        "display name",
+						AvatarURL:          "",
+						UserType:           domain.UserTypeHuman,
+					},
+				},
+			},
+		},
+		{
+			name: "prepareOrgMembersQuery mixed case verified email",
+			prepare: func(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Rows) (*Members, error)) {
+				query, scan := prepareOrgMembersQuery(ctx, db)
+				emailQuery, err := NewMemberVerifiedEmailSearchQuery("instance-id", "Gigi@CAOS.ch")
+				if err != nil {
+					t.Fatal(err)
+				}
+				return emailQuery.toQuery(query), scan
+			},
+			want: want{
+				sqlExpectations: mockQueries(
+					orgMembersQuery+regexp.QuoteMeta(" AND projections.users13_humans.user_id IN ( SELECT projections.users13_notifications.user_id FROM projections.users13_notifications WHERE projections.users13_notifications.instance_id = $2 AND projections.users13_notifications.verified_email_lower = $3 )"),
+					orgMembersColumns,
+					[][]driver.Value{
+						{
+							testNow,
+							testNow,
+							uint64(20211206),
+							"ro",
+							"user-id",
+							database.TextArray[string]{"role-1"},
+							nil,
+							"gigi@caos-ag.zitadel.ch",
+							"gigi@caos.ch",
+							"first-name",
+							"last-name",
+							"display name",
+							nil,
+							nil,
+							domain.UserTypeHuman,
+						},
+					},
+					true,
+					"instance-id",
+					"gigi@caos.ch",
+				),
+			},
+			object: &Members{
+				SearchResponse: SearchResponse{
+					Count: 1,
+				},
+				Members: []*Member{
+					{
+						CreationDate:       testNow,
+						ChangeDate:         testNow,
+						Sequence:           20211206,
+						ResourceOwner:      "ro",
+						UserID:             "user-id",
+						Roles:              database.TextArray[string]{"role-1"},
+						PreferredLoginName: "gigi@caos-ag.zitadel.ch",
+						Email:              "gigi@caos.ch",
+						FirstName:          "first-name",
+						LastName:           "last-name",
+						DisplayName:        "display name",
+						AvatarURL:          "",
+						UserType:           domain.UserTypeHuman,
+					},
+				},
+			},
+		},
+		{
+			name: "prepareOrgMembersQuery mixed case username",
+			prepare: func(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Rows) (*Members, error)) {
+				query, scan := prepareOrgMembersQuery(ctx, db)
+				userNameQuery, err := NewMemberUserNameLowerCaseSearchQuery("Gigi")
+				if err != nil {
+					t.Fatal(err)
+				}
+				return userNameQuery.toQuery(query), scan
+			},
+			want: want{
+				sqlExpectations: mockQueries(
+					orgMembersQuery+regexp.QuoteMeta(" AND projections.users13.username_lower = $2"),
+					orgMembersColumns,
+					[][]driver.Value{
+						{
+							testNow,
+							testNow,
+							uint64(20211206),
+							"ro",
+							"user-id",
+							database.TextArray[string]{"role-1"},
+							nil,
+							"gigi@caos-ag.zitadel.ch",
+							"gigi@caos.ch",
+							"first-name",
+							"last-name",
+							"display name",
+							nil,
+							nil,
+							domain.UserTypeHuman,
+						},
+					},
+					true,
+					"gigi",
+				),
+			},
+			object: &Members{
+				SearchResponse: SearchResponse{
+					Count: 1,
+				},
+				Members: []*Member{
+					{
+						CreationDate:       testNow,
+						ChangeDate:         testNow,
+						Sequence:           20211206,
+						ResourceOwner:      "ro",
+						UserID:             "user-id",
+						Roles:              database.TextArray[string]{"role-1"},
+						PreferredLoginName: "gigi@caos-ag.zitadel.ch",
+						Email:              "gigi@caos.ch",
+						FirstName:          "first-name",
+						LastName:           "last-name",
+						DisplayName:        "display name",
+						AvatarURL:          "",
+						UserType:           domain.UserTypeHuman,
+					},
+				},
+			},
+		},
+		{
+			name: "prepareOrgMembersQuery by role with multiple roles",
+			prepare: func(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Rows) (*Members, error)) {
+				query, scan := prepareOrgMembersQuery(ctx, db)
+				roleQuery, err := NewOrgMemberRoleSearchQuery("ORG_OWNER")
+				if err != nil {
+					t.Fatal(err)
+				}
+				return roleQuery.toQuery(query), scan
+			},
+			want: want{
+				sqlExpectations: mockQueries(
+					orgMembersQuery+regexp.QuoteMeta(" AND members.roles @> $2"),
+					orgMembersColumns,
+					[][]driver.Value{
+						{
+							testNow,
+							testNow,
+							uint64(20211206),
+							"ro",
+							"user-id",
+							database.TextArray[string]{"ORG_USER_MANAGER", "ORG_OWNER"},
+							nil,
+							"gigi@caos-ag.zitadel.ch",
+							"gigi@caos.ch",
+							"first-name",
+							"last-name",
+							"display name",
+							nil,
+							nil,
+							domain.UserTypeHuman,
+						},
+					},
+					true,
+					[]interface{}{"ORG_OWNER"},
+				),
+			},
+			object: &Members{
+				SearchResponse: SearchResponse{
+					Count: 1,
+				},
+				Members: []*Member{
+					{
+						CreationDate:       testNow,
+						ChangeDate:         testNow,
+						Sequence:           20211206,
+						ResourceOwner:      "ro",
+						UserID:             "user-id",
+						Roles:              database.TextArray[string]{"ORG_USER_MANAGER", "ORG_OWNER"},
 						PreferredLoginName: "gigi@caos-ag.zitadel.ch",
 						Email:              "gigi@caos.ch",
 						FirstName:          "first-name",
@@ -153,6 +347,7 @@ func Test_OrgMemberPrepares(t *testing.T) {
 							"ro",
 							"user-id",
 							database.TextArray[string]{"role-1", "role-2"},
+							nil,
 							"machine@caos-ag.zitadel.ch",
 							nil,
 							nil,
@@ -203,6 +398,7 @@ func Test_OrgMemberPrepares(t *testing.T) {
 							"ro",
 							"user-id-1",
 							database.TextArray[string]{"role-1", "role-2"},
+							nil,
 							"gigi@caos-ag.zitadel.ch",
 							"gigi@caos.ch",
 							"first-name",
@@ -219,6 +415,7 @@ func Test_OrgMemberPrepares(t *testing.T) {
 							"ro",
 							"user-id-2",
 							database.TextArray[string]{"role-1", "role-2"},
+							nil,
 							"machine@caos-ag.zitadel.ch",
 							nil,
 							nil,
@@ -293,3 +490,202 @@ func Test_OrgMemberPrepares(t *testing.T) {
 		})
 	}
 }
+
+func TestQueries_OrgMembersByRole_emptyRole(t *testing.T) {
+	_, err := new(Queries).OrgMembersByRole(context.Background(), "org-id", "")
+	if !zerrors.IsErrorInvalidArgument(err) {
+		t.Errorf("expected invalid argument, got %v", err)
+	}
+}
+
+func Test_OrgMemberRowPrepare(t *testing.T) {
+	query := regexp.QuoteMeta("SELECT members.creation_date" +
+		", members.change_date" +
+		", members.sequence" +
+		", members.resource_owner" +
+		", members.user_id" +
+		", members.user_resource_owner" +
+		", members.roles" +
+		", members.state" +
+		", members.org_name" +
+		", members.added_by" +
+		" FROM projections.org_members7 AS members")
+	cols := []string{
+		"creation_date",
+		"change_date",
+		"sequence",
+		"resource_owner",
+		"user_id",
+		"user_resource_owner",
+		"roles",
+		"state",
+		"org_name",
+		"added_by",
+	}
+	type want struct {
+		sqlExpectations sqlExpectation
+		err             checkErr
+	}
+	tests := []struct {
+		name   string
+		want   want
+		object interface{}
+	}{
+		{
+			name: "not found",
+			want: want{
+				sqlExpectations: mockQueriesScanErr(query, nil, nil),
+				err: func(err error) (error, bool) {
+					if !zerrors.IsNotFound(err) {
+						return fmt.Errorf("err should be zitadel.NotFoundError got: %w", err), false
+					}
+					return nil, true
+				},
+			},
+			object: (*OrgMemberRow)(nil),
+		},
+		{
+			name: "found",
+			want: want{
+				sqlExpectations: mockQuery(
+					query,
+					cols,
+					[]driver.Value{
+						testNow,
+						testNow,
+						uint64(20211206),
+						"ro",
+						"user-id",
+						"user-ro",
+						database.TextArray[string]{"role-1", "role-2"},
+						domain.MemberStateInactive,
+						"org name",
+						nil,
+					},
+				),
+			},
+			object: &OrgMemberRow{
+				CreationDate:      testNow,
+				ChangeDate:        testNow,
+				Sequence:          20211206,
+				ResourceOwner:     "ro",
+				UserID:            "user-id",
+				UserResourceOwner: "user-ro",
+				Roles:             database.TextArray[string]{"role-1", "role-2"},
+				State:             domain.MemberStateInactive,
+				OrgName:           "org name",
+			},
+		},
+		{
+			name: "sql err",
+			want: want{
+				sqlExpectations: mockQueryErr(query, sql.ErrConnDone),
+				err: func(err error) (error, bool) {
+					if !errors.Is(err, sql.ErrConnDone) {
+						return fmt.Errorf("err should be sql.ErrConnDone got: %w", err), false
+					}
+					return nil, true
+				},
+			},
+			object: (*OrgMemberRow)(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPrepare(t, prepareOrgMemberRowQuery, tt.object, tt.want.sqlExpectations, tt.want.err)
+		})
+	}
+}
+
+func TestQueries_OrgMemberByEmail(t *testing.T) {
+	member := func(userID string) []driver.Value {
+		return []driver.Value{
+			testNow,
+			testNow,
+			uint64(20211206),
+			"ro",
+			userID,
+			database.TextArray[string]{"role-1"},
+			nil,
+			"gigi@caos-ag.zitadel.ch",
+			"gigi@caos.ch",
+			"first-name",
+			"last-name",
+			"display name",
+			nil,
+			nil,
+			domain.UserTypeHuman,
+		}
+	}
+	tests := []struct {
+		name    string
+		members [][]driver.Value
+		want    *Member
+		wantErr func(error) bool
+	}{
+		{
+			name:    "not found",
+			wantErr: zerrors.IsNotFound,
+		},
+		{
+			name:    "found",
+			members: [][]driver.Value{member("user-id")},
+			want: &Member{
+				CreationDate:       testNow,
+				ChangeDate:         testNow,
+				Sequence:           20211206,
+				ResourceOwner:      "ro",
+				UserID:             "user-id",
+				Roles:              database.TextArray[string]{"role-1"},
+				PreferredLoginName: "gigi@caos-ag.zitadel.ch",
+				Email:              "gigi@caos.ch",
+				FirstName:          "first-name",
+				LastName:           "last-name",
+				DisplayName:        "display name",
+				UserType:           domain.UserTypeHuman,
+			},
+		},
+		{
+			name:    "ambiguous",
+			members: [][]driver.Value{member("user-id"), member("user-id2")},
+			wantErr: zerrors.IsPreconditionFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New(sqlmock.ValueConverterOption(new(db_mock.TypeConverter)))
+			require.NoError(t, err)
+			defer client.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta("FROM projections.current_states")).
+				WillReturnRows(sqlmock.NewRows([]string{"event_date", "position", "last_updated"}))
+			mock.ExpectCommit()
+			mockQueries(
+				orgMembersQuery+regexp.QuoteMeta(" AND projections.users13_humans.user_id IN ( SELECT projections.users13_notifications.user_id FROM projections.users13_notifications WHERE projections.users13_notifications.instance_id = $2 AND projections.users13_notifications.verified_email_lower = $3 )"),
+				orgMembersColumns,
+				tt.members,
+				true,
+				"instance-id",
+				"gigi@caos.ch",
+				"org-id",
+				"instance-id",
+			)(mock)
+
+			q := &Queries{
+				client: &database.DB{
+					DB:       client,
+					Database: new(prepareDB),
+				},
+			}
+			got, err := q.OrgMemberByEmail(authz.WithInstanceID(context.Background(), "instance-id"), "org-id", "Gigi@CAOS.ch")
+			if tt.wantErr != nil {
+				require.Truef(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -16,7 +16,7 @@ import (
 )
 
 const (
-	AuthNKeyTable            = "projections.authn_keys2"
+	AuthNKeyTable            = "projections.authn_keys3"
 	AuthNKeyIDCol            = "id"
 	AuthNKeyCreationDateCol  = "creation_date"
 	AuthNKeyChangeDateCol    = "change_date"
@@ -30,6 +30,7 @@ const (
 	AuthNKeyPublicKeyCol     = "public_key"
 	AuthNKeyTypeCol          = "type"
 	AuthNKeyEnabledCol       = "enabled"
+	AuthNKeyLastUsedCol      = "last_used"
 )
 
 type authNKeyProjection struct{}
@@ -58,6 +59,7 @@ func (*authNKeyProjection) Init() *old_handler.Check {
 			handler.NewColumn(AuthNKeyPublicKeyCol, handler.ColumnTypeBytes),
 			handler.NewColumn(AuthNKeyEnabledCol, handler.ColumnTypeBool, handler.Default(true)),
 			handler.NewColumn(AuthNKeyTypeCol, handler.ColumnTypeEnum, handler.Default(0)),
+			handler.NewColumn(AuthNKeyLastUsedCol, handler.ColumnTypeTimestamp, handler.Nullable()),
 		},
 			handler.NewPrimaryKey(AuthNKeyInstanceIDCol, AuthNKeyIDCol),
 			handler.WithIndex(handler.NewIndex("enabled", []string{AuthNKeyEnabledCol})),
@@ -108,6 +110,10 @@ func (p *authNKeyProjection) Reducers() []handler.AggregateReducer {
 					Event:  user.MachineKeyRemovedEventType,
 					Reduce: p.reduceAuthNKeyRemoved,
 				},
+				{
+					Event:  user.MachineKeyUsedEventType,
+					Reduce: p.reduceMachineKeyUsed,
+				},
 				{
 					Event:  user.UserRemovedType,
 					Reduce: p.reduceAuthNKeyRemoved,
@@ -223,6 +229,23 @@ func (p *authNKeyProjection) reduceAuthNKeyEnabledChanged(event eventstore.Event
 	), nil
 }
 
+func (p *authNKeyProjection) reduceMachineKeyUsed(event eventstore.Event) (*handler.Statement, error) {
+	e, ok := event.(*user.MachineKeyUsedEvent)
+	if !ok {
+		return nil, zerrors.ThrowInvalidArgumentf(nil, "PROJE-Mk8us", "reduce.wrong.event.type %s", user.MachineKeyUsedEventType)
+	}
+	return handler.NewUpdateStatement(
+		e,
+		[]handler.Column{
+			handler.NewCol(AuthNKeyLastUsedCol, e.CreationDate()),
+		},
+		[]handler.Condition{
+			handler.NewCond(AuthNKeyIDCol, e.KeyID),
+			handler.NewCond(AuthNKeyInstanceIDCol, e.Aggregate().InstanceID),
+		},
+	), nil
+}
+
 func (p *authNKeyProjection) reduceAuthNKeyRemoved(event eventstore.Event) (*handler.Statement, error) {
 	var condition handler.Condition
 	switch e := event.(type) {
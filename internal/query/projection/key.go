@@ -14,7 +14,7 @@ import (
 )
 
 const (
-	KeyProjectionTable = "projections.keys4"
+	KeyProjectionTable = "projections.keys5"
 	KeyPrivateTable    = KeyProjectionTable + "_" + privateKeyTableSuffix
 	KeyPublicTable     = KeyProjectionTable + "_" + publicKeyTableSuffix
 	CertificateTable   = KeyProjectionTable + "_" + certificateTableSuffix
@@ -33,6 +33,7 @@ const (
 	KeyPrivateColumnInstanceID = "instance_id"
 	KeyPrivateColumnExpiry     = "expiry"
 	KeyPrivateColumnKey        = "key"
+	KeyPrivateColumnNotBefore  = "not_before"
 
 	publicKeyTableSuffix      = "public"
 	KeyPublicColumnID         = "id"
@@ -83,6 +84,7 @@ func (*keyProjection) Init() *old_handler.Check {
 			handler.NewColumn(KeyPrivateColumnInstanceID, handler.ColumnTypeText),
 			handler.NewColumn(KeyPrivateColumnExpiry, handler.ColumnTypeTimestamp),
 			handler.NewColumn(KeyPrivateColumnKey, handler.ColumnTypeJSONB),
+			handler.NewColumn(KeyPrivateColumnNotBefore, handler.ColumnTypeTimestamp, handler.Nullable()),
 		},
 			handler.NewPrimaryKey(KeyPrivateColumnInstanceID, KeyPrivateColumnID),
 			privateKeyTableSuffix,
@@ -124,6 +126,14 @@ func (p *keyProjection) Reducers() []handler.AggregateReducer {
 					Event:  keypair.AddedCertificateEventType,
 					Reduce: p.reduceCertificateAdded,
 				},
+				{
+					Event:  keypair.ExpiredEventType,
+					Reduce: p.reduceKeyPairExpired,
+				},
+				{
+					Event:  keypair.RevokedEventType,
+					Reduce: p.reduceKeyPairRevoked,
+				},
 			},
 		},
 		{
@@ -167,6 +177,7 @@ func (p *keyProjection) reduceKeyPairAdded(event eventstore.Event) (*handler.Sta
 				handler.NewCol(KeyPrivateColumnInstanceID, e.Aggregate().InstanceID),
 				handler.NewCol(KeyPrivateColumnExpiry, e.PrivateKey.Expiry),
 				handler.NewCol(KeyPrivateColumnKey, e.PrivateKey.Key),
+				handler.NewCol(KeyPrivateColumnNotBefore, e.NotBefore),
 			},
 			handler.WithTableSuffix(privateKeyTableSuffix),
 		))
@@ -217,3 +228,48 @@ func (p *keyProjection) reduceCertificateAdded(event eventstore.Event) (*handler
 
 	return handler.NewMultiStatement(e, creates...), nil
 }
+
+func (p *keyProjection) reduceKeyPairExpired(event eventstore.Event) (*handler.Statement, error) {
+	e, ok := event.(*keypair.ExpiredEvent)
+	if !ok {
+		return nil, zerrors.ThrowInvalidArgumentf(nil, "HANDL-Kx3pe", "reduce.wrong.event.type %s", keypair.ExpiredEventType)
+	}
+	return reduceKeyPairRemoved(e), nil
+}
+
+// reduceKeyPairRevoked removes the keys right away, so they are excluded from signing immediately
+func (p *keyProjection) reduceKeyPairRevoked(event eventstore.Event) (*handler.Statement, error) {
+	e, ok := event.(*keypair.RevokedEvent)
+	if !ok {
+		return nil, zerrors.ThrowInvalidArgumentf(nil, "HANDL-Kr4vo", "reduce.wrong.event.type %s", keypair.RevokedEventType)
+	}
+	return reduceKeyPairRemoved(e), nil
+}
+
+// reduceKeyPairRemoved removes the keys and the certificate of the key pair,
+// so they are neither used nor published anymore
+func reduceKeyPairRemoved(e eventstore.Event) *handler.Statement {
+	return handler.NewMultiStatement(e,
+		handler.AddDeleteStatement(
+			[]handler.Condition{
+				handler.NewCond(KeyPrivateColumnID, e.Aggregate().ID),
+				handler.NewCond(KeyPrivateColumnInstanceID, e.Aggregate().InstanceID),
+			},
+			handler.WithTableSuffix(privateKeyTableSuffix),
+		),
+		handler.AddDeleteStatement(
+			[]handler.Condition{
+				handler.NewCond(KeyPublicColumnID, e.Aggregate().ID),
+				handler.NewCond(KeyPublicColumnInstanceID, e.Aggregate().InstanceID),
+			},
+			handler.WithTableSuffix(publicKeyTableSuffix),
+		),
+		handler.AddDeleteStatement(
+			[]handler.Condition{
+				handler.NewCond(CertificateColumnID, e.Aggregate().ID),
+				handler.NewCond(CertificateColumnInstanceID, e.Aggregate().InstanceID),
+			},
+			handler.WithTableSuffix(certificateTableSuffix),
+		),
+	)
+}
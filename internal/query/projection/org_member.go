@@ -3,6 +3,7 @@ package projection
 import (
 	"context"
 
+	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	old_handler "github.com/zitadel/zitadel/internal/eventstore/handler"
 	"github.com/zitadel/zitadel/internal/eventstore/handler/v2"
@@ -13,8 +14,11 @@ import (
 )
 
 const (
-	OrgMemberProjectionTable = "projections.org_members4"
+	OrgMemberProjectionTable = "projections.org_members7"
 	OrgMemberOrgIDCol        = "org_id"
+	OrgMemberStateCol        = "state"
+	OrgMemberOrgNameCol      = "org_name"
+	OrgMemberAddedByCol      = "added_by"
 )
 
 type orgMemberProjection struct {
@@ -32,7 +36,13 @@ func (*orgMemberProjection) Name() string {
 func (*orgMemberProjection) Init() *old_handler.Check {
 	return handler.NewTableCheck(
 		handler.NewTable(
-			append(memberColumns, handler.NewColumn(OrgMemberOrgIDCol, handler.ColumnTypeText)),
+			append(memberColumns,
+				handler.NewColumn(OrgMemberOrgIDCol, handler.ColumnTypeText),
+				handler.NewColumn(OrgMemberStateCol, handler.ColumnTypeEnum, handler.Default(domain.MemberStateActive)),
+				handler.NewColumn(OrgMemberOrgNameCol, handler.ColumnTypeText, handler.Default("")),
+				// nullable for members added before the creator was tracked
+				handler.NewColumn(OrgMemberAddedByCol, handler.ColumnTypeText, handler.Nullable()),
+			),
 			handler.NewPrimaryKey(MemberInstanceID, OrgMemberOrgIDCol, MemberUserIDCol),
 			handler.WithIndex(handler.NewIndex("user_id", []string{MemberUserIDCol})),
 			handler.WithIndex(
@@ -71,6 +81,18 @@ func (p *orgMemberProjection) Reducers() []handler.AggregateReducer {
 					Event:  org.MemberRemovedEventType,
 					Reduce: p.reduceRemoved,
 				},
+				{
+					Event:  org.MemberDeactivatedEventType,
+					Reduce: p.reduceDeactivated,
+				},
+				{
+					Event:  org.MemberReactivatedEventType,
+					Reduce: p.reduceReactivated,
+				},
+				{
+					Event:  org.OrgChangedEventType,
+					Reduce: p.reduceOrgChanged,
+				},
 				{
 					Event:  org.OrgRemovedEventType,
 					Reduce: p.reduceOrgRemoved,
@@ -108,7 +130,44 @@ func (p *orgMemberProjection) reduceAdded(event eventstore.Event) (*handler.Stat
 	if err != nil {
 		return nil, err
 	}
-	return reduceMemberAdded(e.MemberAddedEvent, userOwner, withMemberCol(OrgMemberOrgIDCol, e.Aggregate().ID))
+	orgName, err := getOrgName(ctx, p.es, e.Aggregate().InstanceID, e.Aggregate().ID)
+	if err != nil {
+		return nil, err
+	}
+	return reduceMemberAdded(e.MemberAddedEvent, userOwner,
+		withMemberCol(OrgMemberOrgIDCol, e.Aggregate().ID),
+		withMemberCol(OrgMemberStateCol, domain.MemberStateActive),
+		withMemberCol(OrgMemberOrgNameCol, orgName),
+		withMemberCol(OrgMemberAddedByCol, e.Creator()),
+	)
+}
+
+// getOrgName returns the current name of the org
+func getOrgName(ctx context.Context, es handler.EventStore, instanceID, orgID string) (name string, err error) {
+	events, err := es.Filter(
+		ctx,
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			AwaitOpenTransactions().
+			InstanceID(instanceID).
+			AddQuery().
+			AggregateTypes(org.AggregateType).
+			AggregateIDs(orgID).
+			EventTypes(org.OrgAddedEventType, org.OrgChangedEventType).
+			Builder(),
+	)
+	if err != nil {
+		return "", err
+	}
+	// sorted ascending
+	for _, event := range events {
+		switch e := event.(type) {
+		case *org.OrgAddedEvent:
+			name = e.Name
+		case *org.OrgChangedEvent:
+			name = e.Name
+		}
+	}
+	return name, nil
 }
 
 func (p *orgMemberProjection) reduceChanged(event eventstore.Event) (*handler.Statement, error) {
@@ -138,6 +197,40 @@ func (p *orgMemberProjection) reduceRemoved(event eventstore.Event) (*handler.St
 	)
 }
 
+func (p *orgMemberProjection) reduceDeactivated(event eventstore.Event) (*handler.Statement, error) {
+	e, ok := event.(*org.MemberDeactivatedEvent)
+	if !ok {
+		return nil, zerrors.ThrowInvalidArgumentf(nil, "HANDL-Tz4mq", "reduce.wrong.event.type %s", org.MemberDeactivatedEventType)
+	}
+	return p.reduceState(e, e.UserID, domain.MemberStateInactive), nil
+}
+
+func (p *orgMemberProjection) reduceReactivated(event eventstore.Event) (*handler.Statement, error) {
+	e, ok := event.(*org.MemberReactivatedEvent)
+	if !ok {
+		return nil, zerrors.ThrowInvalidArgumentf(nil, "HANDL-Lc7rw", "reduce.wrong.event.type %s", org.MemberReactivatedEventType)
+	}
+	return p.reduceState(e, e.UserID, domain.MemberStateActive), nil
+}
+
+// reduceState sets the state of the membership.
+// Inactive memberships remain in the projection to keep their roles for the reactivation.
+func (p *orgMemberProjection) reduceState(e eventstore.Event, userID string, state domain.MemberState) *handler.Statement {
+	return handler.NewUpdateStatement(
+		e,
+		[]handler.Column{
+			handler.NewCol(OrgMemberStateCol, state),
+			handler.NewCol(MemberChangeDate, e.CreatedAt()),
+			handler.NewCol(MemberSequence, e.Sequence()),
+		},
+		[]handler.Condition{
+			handler.NewCond(MemberInstanceID, e.Aggregate().InstanceID),
+			handler.NewCond(MemberUserIDCol, userID),
+			handler.NewCond(OrgMemberOrgIDCol, e.Aggregate().ID),
+		},
+	)
+}
+
 func (p *orgMemberProjection) reduceUserRemoved(event eventstore.Event) (*handler.Statement, error) {
 	e, ok := event.(*user.UserRemovedEvent)
 	if !ok {
@@ -146,6 +239,27 @@ func (p *orgMemberProjection) reduceUserRemoved(event eventstore.Event) (*handle
 	return reduceMemberRemoved(e, withMemberCond(MemberUserIDCol, e.Aggregate().ID))
 }
 
+// reduceOrgChanged updates the org name of all memberships of the org
+func (p *orgMemberProjection) reduceOrgChanged(event eventstore.Event) (*handler.Statement, error) {
+	e, ok := event.(*org.OrgChangedEvent)
+	if !ok {
+		return nil, zerrors.ThrowInvalidArgumentf(nil, "HANDL-Wn5bd", "reduce.wrong.event.type %s", org.OrgChangedEventType)
+	}
+	if e.Name == "" {
+		return handler.NewNoOpStatement(e), nil
+	}
+	return handler.NewUpdateStatement(
+		e,
+		[]handler.Column{
+			handler.NewCol(OrgMemberOrgNameCol, e.Name),
+		},
+		[]handler.Condition{
+			handler.NewCond(MemberInstanceID, e.Aggregate().InstanceID),
+			handler.NewCond(OrgMemberOrgIDCol, e.Aggregate().ID),
+		},
+	), nil
+}
+
 func (p *orgMemberProjection) reduceOrgRemoved(event eventstore.Event) (*handler.Statement, error) {
 	e, ok := event.(*org.OrgRemovedEvent)
 	if !ok {
@@ -157,3 +271,93 @@ func (p *orgMemberProjection) reduceOrgRemoved(event eventstore.Event) (*handler
 		multiReduceMemberUserOwnerRemoved(e),
 	), nil
 }
+
+// ReducedPosition identifies the last event a projection reduced, it's stored in the current state of the projection
+type ReducedPosition struct {
+	Position      float64
+	AggregateType eventstore.AggregateType
+	AggregateID   string
+	Sequence      uint64
+}
+
+// Reduced returns true if the projection already reduced the event, it returns false for all events if p is nil.
+// Of the events at the position only the events of the same aggregate up to the sequence are known to be reduced,
+// the other events of the transaction are treated as not reduced so they are never projected ahead of the projection.
+func (p *ReducedPosition) Reduced(event eventstore.Event) bool {
+	if p == nil {
+		return false
+	}
+	if event.Position() != p.Position {
+		return event.Position() < p.Position
+	}
+	return event.Aggregate().Type == p.AggregateType && event.Aggregate().ID == p.AggregateID && event.Sequence() <= p.Sequence
+}
+
+// ReprojectOrgMember replaces the row of the org member by reducing its events again.
+// It repairs a single membership which diverged from the events without rebuilding the whole projection.
+// Only the events already reduced by the projection up to reduced are projected,
+// the later events are projected by the projection itself. reduced is nil if the projection didn't run yet.
+// The statements are executed on tx, which should be a transaction to replace the row atomically
+// while the current state of the projection is locked.
+func ReprojectOrgMember(ctx context.Context, es handler.EventStore, tx handler.Executer, reduced *ReducedPosition, instanceID, orgID, userID string) error {
+	events, err := es.Filter(
+		ctx,
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+			AwaitOpenTransactions().
+			InstanceID(instanceID).
+			AddQuery().
+			AggregateTypes(org.AggregateType).
+			AggregateIDs(orgID).
+			EventTypes(
+				org.MemberAddedEventType,
+				org.MemberChangedEventType,
+				org.MemberCascadeRemovedEventType,
+				org.MemberRemovedEventType,
+				org.MemberDeactivatedEventType,
+				org.MemberReactivatedEventType,
+			).
+			EventData(map[string]interface{}{
+				"userId": userID,
+			}).
+			Or().
+			AggregateTypes(org.AggregateType).
+			AggregateIDs(orgID).
+			EventTypes(org.OrgRemovedEventType).
+			Or().
+			AggregateTypes(user.AggregateType).
+			AggregateIDs(userID).
+			EventTypes(user.UserRemovedType).
+			Builder(),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM "+OrgMemberProjectionTable+" WHERE "+MemberInstanceID+" = $1 AND "+OrgMemberOrgIDCol+" = $2 AND "+MemberUserIDCol+" = $3", instanceID, orgID, userID)
+	if err != nil {
+		return zerrors.ThrowInternal(err, "PROJE-Rp4mq", "Errors.Internal")
+	}
+	p := &orgMemberProjection{es: es}
+	reducers := make(map[eventstore.EventType]handler.Reduce)
+	for _, aggregate := range p.Reducers() {
+		for _, reducer := range aggregate.EventReducers {
+			reducers[reducer.Event] = reducer.Reduce
+		}
+	}
+	// sorted ascending
+	for _, event := range events {
+		if !reduced.Reduced(event) {
+			break
+		}
+		statement, err := reducers[event.Type()](event)
+		if err != nil {
+			return err
+		}
+		if statement.Execute == nil {
+			continue
+		}
+		if err = statement.Execute(tx, OrgMemberProjectionTable); err != nil {
+			return err
+		}
+	}
+	return nil
+}
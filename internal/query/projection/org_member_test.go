@@ -5,12 +5,14 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"golang.org/x/text/language"
 
 	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/eventstore/handler/v2"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
 	"github.com/zitadel/zitadel/internal/repository/instance"
 	"github.com/zitadel/zitadel/internal/repository/org"
 	"github.com/zitadel/zitadel/internal/repository/user"
@@ -75,6 +77,9 @@ func TestOrgMemberProjection_reduces(t *testing.T) {
 						"email1",
 						true,
 					),
+				}).appendFilterResponse([]eventstore.Event{
+					org.NewOrgAddedEvent(context.Background(), &org.NewAggregate("agg-id").Aggregate, "org name"),
+					org.NewOrgChangedEvent(context.Background(), &org.NewAggregate("agg-id").Aggregate, "org name", "new org name"),
 				}),
 			}).reduceAdded,
 			want: wantReduce{
@@ -83,7 +88,7 @@ func TestOrgMemberProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "INSERT INTO projections.org_members4 (user_id, user_resource_owner, roles, creation_date, change_date, sequence, resource_owner, instance_id, org_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+							expectedStmt: "INSERT INTO projections.org_members7 (user_id, user_resource_owner, roles, creation_date, change_date, sequence, resource_owner, instance_id, org_id, state, org_name, added_by) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)",
 							expectedArgs: []interface{}{
 								"user-id",
 								"org1",
@@ -94,6 +99,9 @@ func TestOrgMemberProjection_reduces(t *testing.T) {
 								"ro-id",
 								"instance-id",
 								"agg-id",
+								domain.MemberStateActive,
+								"new org name",
+								"editor-user",
 							},
 						},
 					},
@@ -139,6 +147,9 @@ func TestOrgMemberProjection_reduces(t *testing.T) {
 						"email1",
 						true,
 					),
+				}).appendFilterResponse([]eventstore.Event{
+					org.NewOrgAddedEvent(context.Background(), &org.NewAggregate("agg-id").Aggregate, "org name"),
+					org.NewOrgChangedEvent(context.Background(), &org.NewAggregate("agg-id").Aggregate, "org name", "new org name"),
 				}),
 			}).reduceAdded,
 			want: wantReduce{
@@ -147,7 +158,7 @@ func TestOrgMemberProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "INSERT INTO projections.org_members4 (user_id, user_resource_owner, roles, creation_date, change_date, sequence, resource_owner, instance_id, org_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+							expectedStmt: "INSERT INTO projections.org_members7 (user_id, user_resource_owner, roles, creation_date, change_date, sequence, resource_owner, instance_id, org_id, state, org_name, added_by) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)",
 							expectedArgs: []interface{}{
 								"user-id",
 								"org1",
@@ -158,6 +169,9 @@ func TestOrgMemberProjection_reduces(t *testing.T) {
 								"ro-id",
 								"instance-id",
 								"agg-id",
+								domain.MemberStateActive,
+								"new org name",
+								"editor-user",
 							},
 						},
 					},
@@ -184,7 +198,7 @@ func TestOrgMemberProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "UPDATE projections.org_members4 SET (roles, change_date, sequence) = ($1, $2, $3) WHERE (instance_id = $4) AND (user_id = $5) AND (org_id = $6)",
+							expectedStmt: "UPDATE projections.org_members7 SET (roles, change_date, sequence) = ($1, $2, $3) WHERE (instance_id = $4) AND (user_id = $5) AND (org_id = $6)",
 							expectedArgs: []interface{}{
 								database.TextArray[string]{"role", "changed"},
 								anyArg{},
@@ -198,6 +212,72 @@ func TestOrgMemberProjection_reduces(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "org MemberDeactivatedType",
+			args: args{
+				event: getEvent(
+					testEvent(
+						org.MemberDeactivatedEventType,
+						org.AggregateType,
+						[]byte(`{
+					"userId": "user-id"
+				}`),
+					), org.MemberDeactivatedEventMapper),
+			},
+			reduce: (&orgMemberProjection{}).reduceDeactivated,
+			want: wantReduce{
+				aggregateType: org.AggregateType,
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "UPDATE projections.org_members7 SET (state, change_date, sequence) = ($1, $2, $3) WHERE (instance_id = $4) AND (user_id = $5) AND (org_id = $6)",
+							expectedArgs: []interface{}{
+								domain.MemberStateInactive,
+								anyArg{},
+								uint64(15),
+								"instance-id",
+								"user-id",
+								"agg-id",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "org MemberReactivatedType",
+			args: args{
+				event: getEvent(
+					testEvent(
+						org.MemberReactivatedEventType,
+						org.AggregateType,
+						[]byte(`{
+					"userId": "user-id"
+				}`),
+					), org.MemberReactivatedEventMapper),
+			},
+			reduce: (&orgMemberProjection{}).reduceReactivated,
+			want: wantReduce{
+				aggregateType: org.AggregateType,
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "UPDATE projections.org_members7 SET (state, change_date, sequence) = ($1, $2, $3) WHERE (instance_id = $4) AND (user_id = $5) AND (org_id = $6)",
+							expectedArgs: []interface{}{
+								domain.MemberStateActive,
+								anyArg{},
+								uint64(15),
+								"instance-id",
+								"user-id",
+								"agg-id",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "org MemberCascadeRemovedType",
 			args: args{
@@ -217,7 +297,7 @@ func TestOrgMemberProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "DELETE FROM projections.org_members4 WHERE (instance_id = $1) AND (user_id = $2) AND (org_id = $3)",
+							expectedStmt: "DELETE FROM projections.org_members7 WHERE (instance_id = $1) AND (user_id = $2) AND (org_id = $3)",
 							expectedArgs: []interface{}{
 								"instance-id",
 								"user-id",
@@ -247,7 +327,7 @@ func TestOrgMemberProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "DELETE FROM projections.org_members4 WHERE (instance_id = $1) AND (user_id = $2) AND (org_id = $3)",
+							expectedStmt: "DELETE FROM projections.org_members7 WHERE (instance_id = $1) AND (user_id = $2) AND (org_id = $3)",
 							expectedArgs: []interface{}{
 								"instance-id",
 								"user-id",
@@ -275,8 +355,36 @@ func TestOrgMemberProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "DELETE FROM projections.org_members4 WHERE (instance_id = $1) AND (user_id = $2)",
+							expectedStmt: "DELETE FROM projections.org_members7 WHERE (instance_id = $1) AND (user_id = $2)",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "org OrgChangedEventType",
+			args: args{
+				event: getEvent(
+					testEvent(
+						org.OrgChangedEventType,
+						org.AggregateType,
+						[]byte(`{"name": "new org name"}`),
+					), org.OrgChangedEventMapper),
+			},
+			reduce: (&orgMemberProjection{}).reduceOrgChanged,
+			want: wantReduce{
+				aggregateType: org.AggregateType,
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "UPDATE projections.org_members7 SET org_name = $1 WHERE (instance_id = $2) AND (org_id = $3)",
 							expectedArgs: []interface{}{
+								"new org name",
 								"instance-id",
 								"agg-id",
 							},
@@ -285,6 +393,25 @@ func TestOrgMemberProjection_reduces(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "org OrgChangedEventType, no name change",
+			args: args{
+				event: getEvent(
+					testEvent(
+						org.OrgChangedEventType,
+						org.AggregateType,
+						[]byte(`{}`),
+					), org.OrgChangedEventMapper),
+			},
+			reduce: (&orgMemberProjection{}).reduceOrgChanged,
+			want: wantReduce{
+				aggregateType: org.AggregateType,
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{},
+				},
+			},
+		},
 		{
 			name: "org OrgRemovedEventType",
 			args: args{
@@ -302,14 +429,14 @@ func TestOrgMemberProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "DELETE FROM projections.org_members4 WHERE (instance_id = $1) AND (resource_owner = $2)",
+							expectedStmt: "DELETE FROM projections.org_members7 WHERE (instance_id = $1) AND (resource_owner = $2)",
 							expectedArgs: []interface{}{
 								"instance-id",
 								"agg-id",
 							},
 						},
 						{
-							expectedStmt: "DELETE FROM projections.org_members4 WHERE (instance_id = $1) AND (user_resource_owner = $2)",
+							expectedStmt: "DELETE FROM projections.org_members7 WHERE (instance_id = $1) AND (user_resource_owner = $2)",
 							expectedArgs: []interface{}{
 								"instance-id",
 								"agg-id",
@@ -336,7 +463,7 @@ func TestOrgMemberProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "DELETE FROM projections.org_members4 WHERE (instance_id = $1)",
+							expectedStmt: "DELETE FROM projections.org_members7 WHERE (instance_id = $1)",
 							expectedArgs: []interface{}{
 								"agg-id",
 							},
@@ -360,3 +487,147 @@ func TestOrgMemberProjection_reduces(t *testing.T) {
 		})
 	}
 }
+
+func TestReprojectOrgMember(t *testing.T) {
+	positionedEvent := func(event *repository.Event, position float64) *repository.Event {
+		event.Pos = position
+		return event
+	}
+	memberAdded := getEvent(
+		positionedEvent(testEvent(
+			org.MemberAddedEventType,
+			org.AggregateType,
+			[]byte(`{"userId": "user-id", "roles": ["role"]}`),
+		), 1), org.MemberAddedEventMapper)
+	memberDeactivated := getEvent(
+		positionedEvent(testEvent(
+			org.MemberDeactivatedEventType,
+			org.AggregateType,
+			[]byte(`{"userId": "user-id"}`),
+		), 2), org.MemberDeactivatedEventMapper)
+	// not reduced by the projection yet
+	memberRemoved := getEvent(
+		positionedEvent(testEvent(
+			org.MemberRemovedEventType,
+			org.AggregateType,
+			[]byte(`{"userId": "user-id"}`),
+		), 3), org.MemberRemovedEventMapper)
+
+	es := newMockEventStore().
+		appendFilterResponse([]eventstore.Event{
+			memberAdded(t),
+			memberDeactivated(t),
+			memberRemoved(t),
+		}).
+		appendFilterResponse([]eventstore.Event{
+			user.NewMachineAddedEvent(context.Background(), &user.NewAggregate("user-id", "org1").Aggregate, "machine", "name", "", false, domain.OIDCTokenTypeBearer),
+		}).
+		appendFilterResponse([]eventstore.Event{
+			org.NewOrgAddedEvent(context.Background(), &org.NewAggregate("agg-id").Aggregate, "org name"),
+		})
+	executer := &testExecuter{
+		executions: []execution{
+			{
+				expectedStmt: "DELETE FROM projections.org_members7 WHERE instance_id = $1 AND org_id = $2 AND user_id = $3",
+				expectedArgs: []interface{}{
+					"instance-id",
+					"agg-id",
+					"user-id",
+				},
+			},
+			{
+				expectedStmt: "INSERT INTO projections.org_members7 (user_id, user_resource_owner, roles, creation_date, change_date, sequence, resource_owner, instance_id, org_id, state, org_name, added_by) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)",
+				expectedArgs: []interface{}{
+					"user-id",
+					"org1",
+					database.TextArray[string]{"role"},
+					anyArg{},
+					anyArg{},
+					uint64(15),
+					"ro-id",
+					"instance-id",
+					"agg-id",
+					domain.MemberStateActive,
+					"org name",
+					"editor-user",
+				},
+			},
+			{
+				expectedStmt: "UPDATE projections.org_members7 SET (state, change_date, sequence) = ($1, $2, $3) WHERE (instance_id = $4) AND (user_id = $5) AND (org_id = $6)",
+				expectedArgs: []interface{}{
+					domain.MemberStateInactive,
+					anyArg{},
+					uint64(15),
+					"instance-id",
+					"user-id",
+					"agg-id",
+				},
+			},
+		},
+	}
+	reduced := &ReducedPosition{
+		Position:      2,
+		AggregateType: org.AggregateType,
+		AggregateID:   "agg-id",
+		Sequence:      15,
+	}
+	err := ReprojectOrgMember(context.Background(), es, executer, reduced, "instance-id", "agg-id", "user-id")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	executer.Validate(t)
+}
+
+func TestReducedPosition_Reduced(t *testing.T) {
+	event := func(position float64, aggregateID string, sequence uint64) eventstore.Event {
+		return &repository.Event{
+			Pos:           position,
+			AggregateType: org.AggregateType,
+			AggregateID:   aggregateID,
+			Seq:           sequence,
+		}
+	}
+	reduced := &ReducedPosition{
+		Position:      2,
+		AggregateType: org.AggregateType,
+		AggregateID:   "org1",
+		Sequence:      5,
+	}
+	tests := []struct {
+		name  string
+		event eventstore.Event
+		want  bool
+	}{
+		{
+			name:  "before position",
+			event: event(1, "org2", 9),
+			want:  true,
+		},
+		{
+			name:  "after position",
+			event: event(3, "org1", 1),
+		},
+		{
+			name:  "same aggregate up to sequence",
+			event: event(2, "org1", 5),
+			want:  true,
+		},
+		{
+			name:  "same aggregate after sequence",
+			event: event(2, "org1", 6),
+		},
+		{
+			name:  "other aggregate at position",
+			event: event(2, "org2", 1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, reduced.Reduced(tt.event))
+		})
+	}
+	t.Run("projection didn't run", func(t *testing.T) {
+		var notRun *ReducedPosition
+		assert.False(t, notRun.Reduced(event(1, "org1", 1)))
+	})
+}
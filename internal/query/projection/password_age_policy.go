@@ -14,7 +14,7 @@ import (
 )
 
 const (
-	PasswordAgeTable = "projections.password_age_policies2"
+	PasswordAgeTable = "projections.password_age_policies3"
 
 	AgePolicyIDCol             = "id"
 	AgePolicyCreationDateCol   = "creation_date"
@@ -26,6 +26,7 @@ const (
 	AgePolicyInstanceIDCol     = "instance_id"
 	AgePolicyExpireWarnDaysCol = "expire_warn_days"
 	AgePolicyMaxAgeDaysCol     = "max_age_days"
+	AgePolicyHistoryCountCol   = "history_count"
 	AgePolicyOwnerRemovedCol   = "owner_removed"
 )
 
@@ -52,6 +53,7 @@ func (*passwordAgeProjection) Init() *old_handler.Check {
 			handler.NewColumn(AgePolicyInstanceIDCol, handler.ColumnTypeText),
 			handler.NewColumn(AgePolicyExpireWarnDaysCol, handler.ColumnTypeInt64),
 			handler.NewColumn(AgePolicyMaxAgeDaysCol, handler.ColumnTypeInt64),
+			handler.NewColumn(AgePolicyHistoryCountCol, handler.ColumnTypeInt64, handler.Default(0)),
 			handler.NewColumn(AgePolicyOwnerRemovedCol, handler.ColumnTypeBool, handler.Default(false)),
 		},
 			handler.NewPrimaryKey(AgePolicyInstanceIDCol, AgePolicyIDCol),
@@ -126,6 +128,7 @@ func (p *passwordAgeProjection) reduceAdded(event eventstore.Event) (*handler.St
 			handler.NewCol(AgePolicyStateCol, domain.PolicyStateActive),
 			handler.NewCol(AgePolicyExpireWarnDaysCol, policyEvent.ExpireWarnDays),
 			handler.NewCol(AgePolicyMaxAgeDaysCol, policyEvent.MaxAgeDays),
+			handler.NewCol(AgePolicyHistoryCountCol, policyEvent.HistoryCount),
 			handler.NewCol(AgePolicyIsDefaultCol, isDefault),
 			handler.NewCol(AgePolicyResourceOwnerCol, policyEvent.Aggregate().ResourceOwner),
 			handler.NewCol(AgePolicyInstanceIDCol, policyEvent.Aggregate().InstanceID),
@@ -152,6 +155,9 @@ func (p *passwordAgeProjection) reduceChanged(event eventstore.Event) (*handler.
 	if policyEvent.MaxAgeDays != nil {
 		cols = append(cols, handler.NewCol(AgePolicyMaxAgeDaysCol, *policyEvent.MaxAgeDays))
 	}
+	if policyEvent.HistoryCount != nil {
+		cols = append(cols, handler.NewCol(AgePolicyHistoryCountCol, *policyEvent.HistoryCount))
+	}
 	return handler.NewUpdateStatement(
 		&policyEvent,
 		cols,
@@ -0,0 +1,128 @@
+package projection
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	old_handler "github.com/zitadel/zitadel/internal/eventstore/handler"
+	"github.com/zitadel/zitadel/internal/eventstore/handler/v2"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/schedule"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+const (
+	// ScheduledActionTable only contains the pending scheduled actions,
+	// executed and cancelled actions are removed.
+	ScheduledActionTable           = "projections.scheduled_actions"
+	ScheduledActionIDCol           = "id"
+	ScheduledActionCreationDateCol = "creation_date"
+	ScheduledActionChangeDateCol   = "change_date"
+	ScheduledActionInstanceIDCol   = "instance_id"
+	ScheduledActionSequenceCol     = "sequence"
+	ScheduledActionActionCol       = "action"
+	ScheduledActionTargetIDCol     = "target_id"
+	ScheduledActionDueDateCol      = "due_date"
+	ScheduledActionParametersCol   = "parameters"
+)
+
+type scheduledActionProjection struct{}
+
+func newScheduledActionProjection(ctx context.Context, config handler.Config) *handler.Handler {
+	return handler.NewHandler(ctx, &config, new(scheduledActionProjection))
+}
+
+func (*scheduledActionProjection) Name() string {
+	return ScheduledActionTable
+}
+
+func (*scheduledActionProjection) Init() *old_handler.Check {
+	return handler.NewTableCheck(
+		handler.NewTable([]*handler.InitColumn{
+			handler.NewColumn(ScheduledActionIDCol, handler.ColumnTypeText),
+			handler.NewColumn(ScheduledActionCreationDateCol, handler.ColumnTypeTimestamp),
+			handler.NewColumn(ScheduledActionChangeDateCol, handler.ColumnTypeTimestamp),
+			handler.NewColumn(ScheduledActionInstanceIDCol, handler.ColumnTypeText),
+			handler.NewColumn(ScheduledActionSequenceCol, handler.ColumnTypeInt64),
+			handler.NewColumn(ScheduledActionActionCol, handler.ColumnTypeText),
+			handler.NewColumn(ScheduledActionTargetIDCol, handler.ColumnTypeText, handler.Nullable()),
+			handler.NewColumn(ScheduledActionDueDateCol, handler.ColumnTypeTimestamp),
+			handler.NewColumn(ScheduledActionParametersCol, handler.ColumnTypeJSONB, handler.Nullable()),
+		},
+			handler.NewPrimaryKey(ScheduledActionInstanceIDCol, ScheduledActionIDCol),
+			handler.WithIndex(handler.NewIndex("due_date", []string{ScheduledActionInstanceIDCol, ScheduledActionDueDateCol})),
+		),
+	)
+}
+
+func (p *scheduledActionProjection) Reducers() []handler.AggregateReducer {
+	return []handler.AggregateReducer{
+		{
+			Aggregate: schedule.AggregateType,
+			EventReducers: []handler.EventReducer{
+				{
+					Event:  schedule.ScheduledEventType,
+					Reduce: p.reduceScheduled,
+				},
+				{
+					Event:  schedule.ExecutedEventType,
+					Reduce: p.reduceCompleted,
+				},
+				{
+					Event:  schedule.CancelledEventType,
+					Reduce: p.reduceCompleted,
+				},
+			},
+		},
+		{
+			Aggregate: instance.AggregateType,
+			EventReducers: []handler.EventReducer{
+				{
+					Event:  instance.InstanceRemovedEventType,
+					Reduce: reduceInstanceRemovedHelper(ScheduledActionInstanceIDCol),
+				},
+			},
+		},
+	}
+}
+
+func (p *scheduledActionProjection) reduceScheduled(event eventstore.Event) (*handler.Statement, error) {
+	e, err := assertEvent[*schedule.ScheduledEvent](event)
+	if err != nil {
+		return nil, err
+	}
+	var parameters []byte
+	if len(e.Parameters) > 0 {
+		parameters = e.Parameters
+	}
+	return handler.NewCreateStatement(
+		e,
+		[]handler.Column{
+			handler.NewCol(ScheduledActionInstanceIDCol, e.Aggregate().InstanceID),
+			handler.NewCol(ScheduledActionIDCol, e.Aggregate().ID),
+			handler.NewCol(ScheduledActionCreationDateCol, e.CreationDate()),
+			handler.NewCol(ScheduledActionChangeDateCol, e.CreationDate()),
+			handler.NewCol(ScheduledActionSequenceCol, e.Sequence()),
+			handler.NewCol(ScheduledActionActionCol, e.Action),
+			handler.NewCol(ScheduledActionTargetIDCol, e.TargetID),
+			handler.NewCol(ScheduledActionDueDateCol, e.DueDate),
+			handler.NewCol(ScheduledActionParametersCol, parameters),
+		},
+	), nil
+}
+
+// reduceCompleted removes the executed or cancelled action
+func (p *scheduledActionProjection) reduceCompleted(event eventstore.Event) (*handler.Statement, error) {
+	switch event.(type) {
+	case *schedule.ExecutedEvent, *schedule.CancelledEvent:
+	default:
+		return nil, zerrors.ThrowInvalidArgumentf(nil, "HANDL-Sd3qa", "reduce.wrong.event.type %v", []eventstore.EventType{schedule.ExecutedEventType, schedule.CancelledEventType})
+	}
+	return handler.NewDeleteStatement(
+		event,
+		[]handler.Condition{
+			handler.NewCond(ScheduledActionInstanceIDCol, event.Aggregate().InstanceID),
+			handler.NewCond(ScheduledActionIDCol, event.Aggregate().ID),
+		},
+	), nil
+}
@@ -0,0 +1,159 @@
+package projection
+
+import (
+	"testing"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/handler/v2"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/repository/schedule"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestScheduledActionProjection_reduces(t *testing.T) {
+	type args struct {
+		event func(t *testing.T) eventstore.Event
+	}
+	tests := []struct {
+		name   string
+		args   args
+		reduce func(event eventstore.Event) (*handler.Statement, error)
+		want   wantReduce
+	}{
+		{
+			name: "reduceScheduled",
+			args: args{
+				event: getEvent(
+					testEvent(
+						schedule.ScheduledEventType,
+						schedule.AggregateType,
+						[]byte(`{"action": "org.deactivate", "targetId": "org-id", "dueDate": "2024-01-01T00:00:00Z", "parameters": {"reason": "test"}}`),
+					),
+					eventstore.GenericEventMapper[schedule.ScheduledEvent],
+				),
+			},
+			reduce: (&scheduledActionProjection{}).reduceScheduled,
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("schedule"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "INSERT INTO projections.scheduled_actions (instance_id, id, creation_date, change_date, sequence, action, target_id, due_date, parameters) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+								anyArg{},
+								anyArg{},
+								uint64(15),
+								"org.deactivate",
+								"org-id",
+								anyArg{},
+								[]byte(`{"reason": "test"}`),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "reduceCompleted executed",
+			args: args{
+				event: getEvent(
+					testEvent(
+						schedule.ExecutedEventType,
+						schedule.AggregateType,
+						nil,
+					),
+					eventstore.GenericEventMapper[schedule.ExecutedEvent],
+				),
+			},
+			reduce: (&scheduledActionProjection{}).reduceCompleted,
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("schedule"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "DELETE FROM projections.scheduled_actions WHERE (instance_id = $1) AND (id = $2)",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "reduceCompleted cancelled",
+			args: args{
+				event: getEvent(
+					testEvent(
+						schedule.CancelledEventType,
+						schedule.AggregateType,
+						nil,
+					),
+					eventstore.GenericEventMapper[schedule.CancelledEvent],
+				),
+			},
+			reduce: (&scheduledActionProjection{}).reduceCompleted,
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("schedule"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "DELETE FROM projections.scheduled_actions WHERE (instance_id = $1) AND (id = $2)",
+							expectedArgs: []interface{}{
+								"instance-id",
+								"agg-id",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "reduceInstanceRemoved",
+			args: args{
+				event: getEvent(
+					testEvent(
+						instance.InstanceRemovedEventType,
+						instance.AggregateType,
+						nil,
+					),
+					instance.InstanceRemovedEventMapper,
+				),
+			},
+			reduce: reduceInstanceRemovedHelper(ScheduledActionInstanceIDCol),
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("instance"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "DELETE FROM projections.scheduled_actions WHERE (instance_id = $1)",
+							expectedArgs: []interface{}{
+								"agg-id",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := baseEvent(t)
+			got, err := tt.reduce(event)
+			if ok := zerrors.IsErrorInvalidArgument(err); !ok {
+				t.Errorf("no wrong event mapping: %v, got: %v", err, got)
+			}
+
+			event = tt.args.event(t)
+			got, err = tt.reduce(event)
+			assertReduce(t, got, err, ScheduledActionTable, tt.want)
+		})
+	}
+}
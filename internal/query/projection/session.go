@@ -14,33 +14,35 @@ import (
 )
 
 const (
-	SessionsProjectionTable = "projections.sessions8"
-
-	SessionColumnID                     = "id"
-	SessionColumnCreationDate           = "creation_date"
-	SessionColumnChangeDate             = "change_date"
-	SessionColumnSequence               = "sequence"
-	SessionColumnState                  = "state"
-	SessionColumnResourceOwner          = "resource_owner"
-	SessionColumnInstanceID             = "instance_id"
-	SessionColumnCreator                = "creator"
-	SessionColumnUserID                 = "user_id"
-	SessionColumnUserResourceOwner      = "user_resource_owner"
-	SessionColumnUserCheckedAt          = "user_checked_at"
-	SessionColumnPasswordCheckedAt      = "password_checked_at"
-	SessionColumnIntentCheckedAt        = "intent_checked_at"
-	SessionColumnWebAuthNCheckedAt      = "webauthn_checked_at"
-	SessionColumnWebAuthNUserVerified   = "webauthn_user_verified"
-	SessionColumnTOTPCheckedAt          = "totp_checked_at"
-	SessionColumnOTPSMSCheckedAt        = "otp_sms_checked_at"
-	SessionColumnOTPEmailCheckedAt      = "otp_email_checked_at"
-	SessionColumnMetadata               = "metadata"
-	SessionColumnTokenID                = "token_id"
-	SessionColumnUserAgentFingerprintID = "user_agent_fingerprint_id"
-	SessionColumnUserAgentIP            = "user_agent_ip"
-	SessionColumnUserAgentDescription   = "user_agent_description"
-	SessionColumnUserAgentHeader        = "user_agent_header"
-	SessionColumnExpiration             = "expiration"
+	SessionsProjectionTable = "projections.sessions9"
+
+	SessionColumnID                        = "id"
+	SessionColumnCreationDate              = "creation_date"
+	SessionColumnChangeDate                = "change_date"
+	SessionColumnSequence                  = "sequence"
+	SessionColumnState                     = "state"
+	SessionColumnResourceOwner             = "resource_owner"
+	SessionColumnInstanceID                = "instance_id"
+	SessionColumnCreator                   = "creator"
+	SessionColumnUserID                    = "user_id"
+	SessionColumnUserResourceOwner         = "user_resource_owner"
+	SessionColumnUserCheckedAt             = "user_checked_at"
+	SessionColumnPasswordCheckedAt         = "password_checked_at"
+	SessionColumnIntentCheckedAt           = "intent_checked_at"
+	SessionColumnWebAuthNCheckedAt         = "webauthn_checked_at"
+	SessionColumnWebAuthNUserVerified      = "webauthn_user_verified"
+	SessionColumnTOTPCheckedAt             = "totp_checked_at"
+	SessionColumnOTPSMSCheckedAt           = "otp_sms_checked_at"
+	SessionColumnOTPEmailCheckedAt         = "otp_email_checked_at"
+	SessionColumnMetadata                  = "metadata"
+	SessionColumnTokenID                   = "token_id"
+	SessionColumnUserAgentFingerprintID    = "user_agent_fingerprint_id"
+	SessionColumnUserAgentIP               = "user_agent_ip"
+	SessionColumnUserAgentDescription      = "user_agent_description"
+	SessionColumnUserAgentHeader           = "user_agent_header"
+	SessionColumnExpiration                = "expiration"
+	SessionColumnImpersonatorID            = "impersonator_id"
+	SessionColumnImpersonatorResourceOwner = "impersonator_resource_owner"
 )
 
 type sessionProjection struct{}
@@ -81,6 +83,8 @@ func (*sessionProjection) Init() *old_handler.Check {
 			handler.NewColumn(SessionColumnUserAgentDescription, handler.ColumnTypeText, handler.Nullable()),
 			handler.NewColumn(SessionColumnUserAgentHeader, handler.ColumnTypeJSONB, handler.Nullable()),
 			handler.NewColumn(SessionColumnExpiration, handler.ColumnTypeTimestamp, handler.Nullable()),
+			handler.NewColumn(SessionColumnImpersonatorID, handler.ColumnTypeText, handler.Nullable()),
+			handler.NewColumn(SessionColumnImpersonatorResourceOwner, handler.ColumnTypeText, handler.Nullable()),
 		},
 			handler.NewPrimaryKey(SessionColumnInstanceID, SessionColumnID),
 			handler.WithIndex(handler.NewIndex(
@@ -140,6 +144,10 @@ func (p *sessionProjection) Reducers() []handler.AggregateReducer {
 					Event:  session.LifetimeSetType,
 					Reduce: p.reduceLifetimeSet,
 				},
+				{
+					Event:  session.ImpersonationStartedType,
+					Reduce: p.reduceImpersonationStarted,
+				},
 				{
 					Event:  session.TerminateType,
 					Reduce: p.reduceSessionTerminated,
@@ -405,6 +413,27 @@ func (p *sessionProjection) reduceLifetimeSet(event eventstore.Event) (*handler.
 	), nil
 }
 
+func (p *sessionProjection) reduceImpersonationStarted(event eventstore.Event) (*handler.Statement, error) {
+	e, err := assertEvent[*session.ImpersonationStartedEvent](event)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler.NewUpdateStatement(
+		e,
+		[]handler.Column{
+			handler.NewCol(SessionColumnChangeDate, e.CreationDate()),
+			handler.NewCol(SessionColumnSequence, e.Sequence()),
+			handler.NewCol(SessionColumnImpersonatorID, e.OperatorID),
+			handler.NewCol(SessionColumnImpersonatorResourceOwner, e.OperatorResourceOwner),
+		},
+		[]handler.Condition{
+			handler.NewCond(SessionColumnID, e.Aggregate().ID),
+			handler.NewCond(SessionColumnInstanceID, e.Aggregate().InstanceID),
+		},
+	), nil
+}
+
 func (p *sessionProjection) reduceSessionTerminated(event eventstore.Event) (*handler.Statement, error) {
 	e, ok := event.(*session.TerminateEvent)
 	if !ok {
@@ -29,6 +29,8 @@ const (
 	UserResourceOwnerCol = "resource_owner"
 	UserInstanceIDCol    = "instance_id"
 	UserUsernameCol      = "username"
+	// UserUsernameLowerCol is generated by the database from [UserUsernameCol], see setup step 35
+	UserUsernameLowerCol = "username_lower"
 	UserTypeCol          = "type"
 
 	UserHumanSuffix             = "humans"
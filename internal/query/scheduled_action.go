@@ -0,0 +1,130 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/eventstore/handler/v2"
+	"github.com/zitadel/zitadel/internal/query/projection"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+var (
+	scheduledActionTable = table{
+		name:          projection.ScheduledActionTable,
+		instanceIDCol: projection.ScheduledActionInstanceIDCol,
+	}
+	ScheduledActionColumnID = Column{
+		name:  projection.ScheduledActionIDCol,
+		table: scheduledActionTable,
+	}
+	ScheduledActionColumnInstanceID = Column{
+		name:  projection.ScheduledActionInstanceIDCol,
+		table: scheduledActionTable,
+	}
+	ScheduledActionColumnAction = Column{
+		name:  projection.ScheduledActionActionCol,
+		table: scheduledActionTable,
+	}
+	ScheduledActionColumnTargetID = Column{
+		name:  projection.ScheduledActionTargetIDCol,
+		table: scheduledActionTable,
+	}
+	ScheduledActionColumnDueDate = Column{
+		name:  projection.ScheduledActionDueDateCol,
+		table: scheduledActionTable,
+	}
+	ScheduledActionColumnParameters = Column{
+		name:  projection.ScheduledActionParametersCol,
+		table: scheduledActionTable,
+	}
+)
+
+// ScheduledAction is a pending action which is executed by a worker once its due date is reached
+type ScheduledAction struct {
+	ID         string
+	Action     string
+	TargetID   string
+	DueDate    time.Time
+	Parameters json.RawMessage
+}
+
+// DueScheduledActions returns the pending scheduled actions of the instance of the context
+// with a due date at or before now, the earliest due date first.
+// If limit is 0 all due actions are returned.
+// Workers are expected to poll it and pass the actions to [command.Commands.ExecuteScheduledAction],
+// which checks the state of the action on the eventstore.
+func (q *Queries) DueScheduledActions(ctx context.Context, now time.Time, limit uint64) (actions []*ScheduledAction, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	ctx, err = projection.ScheduledActionProjection.Trigger(ctx, handler.WithAwaitRunning())
+	logging.OnError(err).Debug("unable to trigger")
+
+	query, scan := prepareDueScheduledActionsQuery(ctx, q.client)
+	query = query.Where(sq.And{
+		sq.Eq{ScheduledActionColumnInstanceID.identifier(): authz.GetInstance(ctx).InstanceID()},
+		sq.LtOrEq{ScheduledActionColumnDueDate.identifier(): now},
+	})
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	stmt, args, err := query.ToSql()
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Sd4zu", "Errors.Query.SQLStatement")
+	}
+	err = q.client.QueryContext(ctx, func(rows *sql.Rows) error {
+		actions, err = scan(rows)
+		return err
+	}, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
+
+func prepareDueScheduledActionsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(*sql.Rows) ([]*ScheduledAction, error)) {
+	return sq.Select(
+			ScheduledActionColumnID.identifier(),
+			ScheduledActionColumnAction.identifier(),
+			ScheduledActionColumnTargetID.identifier(),
+			ScheduledActionColumnDueDate.identifier(),
+			ScheduledActionColumnParameters.identifier(),
+		).From(scheduledActionTable.identifier()).
+			OrderBy(ScheduledActionColumnDueDate.identifier()).
+			PlaceholderFormat(sq.Dollar),
+		func(rows *sql.Rows) ([]*ScheduledAction, error) {
+			actions := make([]*ScheduledAction, 0)
+			for rows.Next() {
+				action := new(ScheduledAction)
+				var (
+					targetID   sql.NullString
+					parameters []byte
+				)
+				err := rows.Scan(
+					&action.ID,
+					&action.Action,
+					&targetID,
+					&action.DueDate,
+					&parameters,
+				)
+				if err != nil {
+					return nil, zerrors.ThrowInternal(err, "QUERY-Sd6ni", "Errors.Internal")
+				}
+				action.TargetID = targetID.String
+				action.Parameters = parameters
+				actions = append(actions, action)
+			}
+			if err := rows.Close(); err != nil {
+				return nil, zerrors.ThrowInternal(err, "QUERY-Sd8oc", "Errors.Query.CloseRows")
+			}
+			return actions, nil
+		}
+}
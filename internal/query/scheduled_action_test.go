@@ -0,0 +1,116 @@
+package query
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+var (
+	prepareDueScheduledActionsStmt = `SELECT projections.scheduled_actions.id,` +
+		` projections.scheduled_actions.action,` +
+		` projections.scheduled_actions.target_id,` +
+		` projections.scheduled_actions.due_date,` +
+		` projections.scheduled_actions.parameters` +
+		` FROM projections.scheduled_actions` +
+		` ORDER BY projections.scheduled_actions.due_date`
+	prepareDueScheduledActionsCols = []string{
+		"id",
+		"action",
+		"target_id",
+		"due_date",
+		"parameters",
+	}
+)
+
+func Test_ScheduledActionPrepares(t *testing.T) {
+	type want struct {
+		sqlExpectations sqlExpectation
+		err             checkErr
+	}
+	tests := []struct {
+		name    string
+		prepare interface{}
+		want    want
+		object  interface{}
+	}{
+		{
+			name:    "prepareDueScheduledActionsQuery no result",
+			prepare: prepareDueScheduledActionsQuery,
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(prepareDueScheduledActionsStmt),
+					nil,
+					nil,
+				),
+			},
+			object: []*ScheduledAction{},
+		},
+		{
+			name:    "prepareDueScheduledActionsQuery multiple results",
+			prepare: prepareDueScheduledActionsQuery,
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(prepareDueScheduledActionsStmt),
+					prepareDueScheduledActionsCols,
+					[][]driver.Value{
+						{
+							"id1",
+							"org.deactivate",
+							"org1",
+							testNow,
+							[]byte(`{"reason":"test"}`),
+						},
+						{
+							"id2",
+							"instance.cleanup",
+							nil,
+							testNow,
+							nil,
+						},
+					},
+				),
+			},
+			object: []*ScheduledAction{
+				{
+					ID:         "id1",
+					Action:     "org.deactivate",
+					TargetID:   "org1",
+					DueDate:    testNow,
+					Parameters: json.RawMessage(`{"reason":"test"}`),
+				},
+				{
+					ID:      "id2",
+					Action:  "instance.cleanup",
+					DueDate: testNow,
+				},
+			},
+		},
+		{
+			name:    "prepareDueScheduledActionsQuery sql err",
+			prepare: prepareDueScheduledActionsQuery,
+			want: want{
+				sqlExpectations: mockQueryErr(
+					regexp.QuoteMeta(prepareDueScheduledActionsStmt),
+					sql.ErrConnDone,
+				),
+				err: func(err error) (error, bool) {
+					if !errors.Is(err, sql.ErrConnDone) {
+						return fmt.Errorf("err should be sql.ErrConnDone got: %w", err), false
+					}
+					return nil, true
+				},
+			},
+			object: ([]*ScheduledAction)(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPrepare(t, tt.prepare, tt.object, tt.want.sqlExpectations, tt.want.err, defaultPrepareArgs...)
+		})
+	}
+}
@@ -44,6 +44,7 @@ type Session struct {
 	Metadata       map[string][]byte
 	UserAgent      domain.UserAgent
 	Expiration     time.Time
+	Impersonator   SessionImpersonator
 }
 
 type SessionUserFactor struct {
@@ -54,6 +55,13 @@ type SessionUserFactor struct {
 	DisplayName   string
 }
 
+// SessionImpersonator is the user who started the impersonation of the session user,
+// it's empty if the session is not impersonated
+type SessionImpersonator struct {
+	UserID        string
+	ResourceOwner string
+}
+
 type SessionPasswordFactor struct {
 	PasswordCheckedAt time.Time
 }
@@ -193,6 +201,14 @@ var (
 		name:  projection.SessionColumnExpiration,
 		table: sessionsTable,
 	}
+	SessionColumnImpersonatorID = Column{
+		name:  projection.SessionColumnImpersonatorID,
+		table: sessionsTable,
+	}
+	SessionColumnImpersonatorResourceOwner = Column{
+		name:  projection.SessionColumnImpersonatorResourceOwner,
+		table: sessionsTable,
+	}
 )
 
 func (q *Queries) SessionByID(ctx context.Context, shouldTriggerBulk bool, id, sessionToken string) (session *Session, err error) {
@@ -308,6 +324,8 @@ func prepareSessionQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuil
 			SessionColumnUserAgentDescription.identifier(),
 			SessionColumnUserAgentHeader.identifier(),
 			SessionColumnExpiration.identifier(),
+			SessionColumnImpersonatorID.identifier(),
+			SessionColumnImpersonatorResourceOwner.identifier(),
 		).From(sessionsTable.identifier()).
 			LeftJoin(join(LoginNameUserIDCol, SessionColumnUserID)).
 			LeftJoin(join(HumanUserIDCol, SessionColumnUserID)).
@@ -333,6 +351,8 @@ func prepareSessionQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuil
 				userAgentIP         sql.NullString
 				userAgentHeader     database.Map[[]string]
 				expiration          sql.NullTime
+				impersonatorID      sql.NullString
+				impersonatorOwner   sql.NullString
 			)
 
 			err := row.Scan(
@@ -362,6 +382,8 @@ func prepareSessionQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuil
 				&session.UserAgent.Description,
 				&userAgentHeader,
 				&expiration,
+				&impersonatorID,
+				&impersonatorOwner,
 			)
 
 			if err != nil {
@@ -389,6 +411,8 @@ func prepareSessionQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuil
 				session.UserAgent.IP = net.ParseIP(userAgentIP.String)
 			}
 			session.Expiration = expiration.Time
+			session.Impersonator.UserID = impersonatorID.String
+			session.Impersonator.ResourceOwner = impersonatorOwner.String
 			return session, token.String, nil
 		}
 }
@@ -416,6 +440,8 @@ func prepareSessionsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBui
 			SessionColumnOTPEmailCheckedAt.identifier(),
 			SessionColumnMetadata.identifier(),
 			SessionColumnExpiration.identifier(),
+			SessionColumnImpersonatorID.identifier(),
+			SessionColumnImpersonatorResourceOwner.identifier(),
 			countColumn.identifier(),
 		).From(sessionsTable.identifier()).
 			LeftJoin(join(LoginNameUserIDCol, SessionColumnUserID)).
@@ -442,6 +468,8 @@ func prepareSessionsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBui
 					otpEmailCheckedAt   sql.NullTime
 					metadata            database.Map[[]byte]
 					expiration          sql.NullTime
+					impersonatorID      sql.NullString
+					impersonatorOwner   sql.NullString
 				)
 
 				err := rows.Scan(
@@ -466,6 +494,8 @@ func prepareSessionsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBui
 					&otpEmailCheckedAt,
 					&metadata,
 					&expiration,
+					&impersonatorID,
+					&impersonatorOwner,
 					&sessions.Count,
 				)
 
@@ -486,6 +516,8 @@ func prepareSessionsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBui
 				session.OTPEmailFactor.OTPCheckedAt = otpEmailCheckedAt.Time
 				session.Metadata = metadata
 				session.Expiration = expiration.Time
+				session.Impersonator.UserID = impersonatorID.String
+				session.Impersonator.ResourceOwner = impersonatorOwner.String
 
 				sessions.Sessions = append(sessions.Sessions, session)
 			}
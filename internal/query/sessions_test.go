@@ -20,63 +20,67 @@ import (
 )
 
 var (
-	expectedSessionQuery = regexp.QuoteMeta(`SELECT projections.sessions8.id,` +
-		` projections.sessions8.creation_date,` +
-		` projections.sessions8.change_date,` +
-		` projections.sessions8.sequence,` +
-		` projections.sessions8.state,` +
-		` projections.sessions8.resource_owner,` +
-		` projections.sessions8.creator,` +
-		` projections.sessions8.user_id,` +
-		` projections.sessions8.user_resource_owner,` +
-		` projections.sessions8.user_checked_at,` +
+	expectedSessionQuery = regexp.QuoteMeta(`SELECT projections.sessions9.id,` +
+		` projections.sessions9.creation_date,` +
+		` projections.sessions9.change_date,` +
+		` projections.sessions9.sequence,` +
+		` projections.sessions9.state,` +
+		` projections.sessions9.resource_owner,` +
+		` projections.sessions9.creator,` +
+		` projections.sessions9.user_id,` +
+		` projections.sessions9.user_resource_owner,` +
+		` projections.sessions9.user_checked_at,` +
 		` projections.login_names3.login_name,` +
 		` projections.users13_humans.display_name,` +
-		` projections.sessions8.password_checked_at,` +
-		` projections.sessions8.intent_checked_at,` +
-		` projections.sessions8.webauthn_checked_at,` +
-		` projections.sessions8.webauthn_user_verified,` +
-		` projections.sessions8.totp_checked_at,` +
-		` projections.sessions8.otp_sms_checked_at,` +
-		` projections.sessions8.otp_email_checked_at,` +
-		` projections.sessions8.metadata,` +
-		` projections.sessions8.token_id,` +
-		` projections.sessions8.user_agent_fingerprint_id,` +
-		` projections.sessions8.user_agent_ip,` +
-		` projections.sessions8.user_agent_description,` +
-		` projections.sessions8.user_agent_header,` +
-		` projections.sessions8.expiration` +
-		` FROM projections.sessions8` +
-		` LEFT JOIN projections.login_names3 ON projections.sessions8.user_id = projections.login_names3.user_id AND projections.sessions8.instance_id = projections.login_names3.instance_id` +
-		` LEFT JOIN projections.users13_humans ON projections.sessions8.user_id = projections.users13_humans.user_id AND projections.sessions8.instance_id = projections.users13_humans.instance_id` +
-		` LEFT JOIN projections.users13 ON projections.sessions8.user_id = projections.users13.id AND projections.sessions8.instance_id = projections.users13.instance_id` +
+		` projections.sessions9.password_checked_at,` +
+		` projections.sessions9.intent_checked_at,` +
+		` projections.sessions9.webauthn_checked_at,` +
+		` projections.sessions9.webauthn_user_verified,` +
+		` projections.sessions9.totp_checked_at,` +
+		` projections.sessions9.otp_sms_checked_at,` +
+		` projections.sessions9.otp_email_checked_at,` +
+		` projections.sessions9.metadata,` +
+		` projections.sessions9.token_id,` +
+		` projections.sessions9.user_agent_fingerprint_id,` +
+		` projections.sessions9.user_agent_ip,` +
+		` projections.sessions9.user_agent_description,` +
+		` projections.sessions9.user_agent_header,` +
+		` projections.sessions9.expiration,` +
+		` projections.sessions9.impersonator_id,` +
+		` projections.sessions9.impersonator_resource_owner` +
+		` FROM projections.sessions9` +
+		` LEFT JOIN projections.login_names3 ON projections.sessions9.user_id = projections.login_names3.user_id AND projections.sessions9.instance_id = projections.login_names3.instance_id` +
+		` LEFT JOIN projections.users13_humans ON projections.sessions9.user_id = projections.users13_humans.user_id AND projections.sessions9.instance_id = projections.users13_humans.instance_id` +
+		` LEFT JOIN projections.users13 ON projections.sessions9.user_id = projections.users13.id AND projections.sessions9.instance_id = projections.users13.instance_id` +
 		` AS OF SYSTEM TIME '-1 ms'`)
-	expectedSessionsQuery = regexp.QuoteMeta(`SELECT projections.sessions8.id,` +
-		` projections.sessions8.creation_date,` +
-		` projections.sessions8.change_date,` +
-		` projections.sessions8.sequence,` +
-		` projections.sessions8.state,` +
-		` projections.sessions8.resource_owner,` +
-		` projections.sessions8.creator,` +
-		` projections.sessions8.user_id,` +
-		` projections.sessions8.user_resource_owner,` +
-		` projections.sessions8.user_checked_at,` +
+	expectedSessionsQuery = regexp.QuoteMeta(`SELECT projections.sessions9.id,` +
+		` projections.sessions9.creation_date,` +
+		` projections.sessions9.change_date,` +
+		` projections.sessions9.sequence,` +
+		` projections.sessions9.state,` +
+		` projections.sessions9.resource_owner,` +
+		` projections.sessions9.creator,` +
+		` projections.sessions9.user_id,` +
+		` projections.sessions9.user_resource_owner,` +
+		` projections.sessions9.user_checked_at,` +
 		` projections.login_names3.login_name,` +
 		` projections.users13_humans.display_name,` +
-		` projections.sessions8.password_checked_at,` +
-		` projections.sessions8.intent_checked_at,` +
-		` projections.sessions8.webauthn_checked_at,` +
-		` projections.sessions8.webauthn_user_verified,` +
-		` projections.sessions8.totp_checked_at,` +
-		` projections.sessions8.otp_sms_checked_at,` +
-		` projections.sessions8.otp_email_checked_at,` +
-		` projections.sessions8.metadata,` +
-		` projections.sessions8.expiration,` +
+		` projections.sessions9.password_checked_at,` +
+		` projections.sessions9.intent_checked_at,` +
+		` projections.sessions9.webauthn_checked_at,` +
+		` projections.sessions9.webauthn_user_verified,` +
+		` projections.sessions9.totp_checked_at,` +
+		` projections.sessions9.otp_sms_checked_at,` +
+		` projections.sessions9.otp_email_checked_at,` +
+		` projections.sessions9.metadata,` +
+		` projections.sessions9.expiration,` +
+		` projections.sessions9.impersonator_id,` +
+		` projections.sessions9.impersonator_resource_owner,` +
 		` COUNT(*) OVER ()` +
-		` FROM projections.sessions8` +
-		` LEFT JOIN projections.login_names3 ON projections.sessions8.user_id = projections.login_names3.user_id AND projections.sessions8.instance_id = projections.login_names3.instance_id` +
-		` LEFT JOIN projections.users13_humans ON projections.sessions8.user_id = projections.users13_humans.user_id AND projections.sessions8.instance_id = projections.users13_humans.instance_id` +
-		` LEFT JOIN projections.users13 ON projections.sessions8.user_id = projections.users13.id AND projections.sessions8.instance_id = projections.users13.instance_id` +
+		` FROM projections.sessions9` +
+		` LEFT JOIN projections.login_names3 ON projections.sessions9.user_id = projections.login_names3.user_id AND projections.sessions9.instance_id = projections.login_names3.instance_id` +
+		` LEFT JOIN projections.users13_humans ON projections.sessions9.user_id = projections.users13_humans.user_id AND projections.sessions9.instance_id = projections.users13_humans.instance_id` +
+		` LEFT JOIN projections.users13 ON projections.sessions9.user_id = projections.users13.id AND projections.sessions9.instance_id = projections.users13.instance_id` +
 		` AS OF SYSTEM TIME '-1 ms'`)
 
 	sessionCols = []string{
@@ -106,6 +110,8 @@ var (
 		"user_agent_description",
 		"user_agent_header",
 		"expiration",
+		"impersonator_id",
+		"impersonator_resource_owner",
 	}
 
 	sessionsCols = []string{
@@ -130,6 +136,8 @@ var (
 		"otp_email_checked_at",
 		"metadata",
 		"expiration",
+		"impersonator_id",
+		"impersonator_resource_owner",
 		"count",
 	}
 )
@@ -187,6 +195,8 @@ func Test_SessionsPrepare(t *testing.T) {
 							testNow,
 							[]byte(`{"key": "dmFsdWU="}`),
 							testNow,
+							nil,
+							nil,
 						},
 					},
 				),
@@ -268,6 +278,8 @@ func Test_SessionsPrepare(t *testing.T) {
 							testNow,
 							[]byte(`{"key": "dmFsdWU="}`),
 							testNow,
+							nil,
+							nil,
 						},
 						{
 							"session-id2",
@@ -291,6 +303,8 @@ func Test_SessionsPrepare(t *testing.T) {
 							testNow,
 							[]byte(`{"key": "dmFsdWU="}`),
 							testNow,
+							nil,
+							nil,
 						},
 					},
 				),
@@ -469,6 +483,8 @@ func Test_SessionPrepare(t *testing.T) {
 						"agentDescription",
 						[]byte(`{"foo":["foo","bar"]}`),
 						testNow,
+						"impersonator-id",
+						"impersonator-resource-owner",
 					},
 				),
 			},
@@ -516,6 +532,10 @@ func Test_SessionPrepare(t *testing.T) {
 					Header:        http.Header{"foo": []string{"foo", "bar"}},
 				},
 				Expiration: testNow,
+				Impersonator: SessionImpersonator{
+					UserID:        "impersonator-id",
+					ResourceOwner: "impersonator-resource-owner",
+				},
 			},
 		},
 		{
@@ -189,6 +189,10 @@ var (
 		table:          userTable,
 		isOrderByLower: true,
 	}
+	UserUsernameLowerCaseCol = Column{
+		name:  projection.UserUsernameLowerCol,
+		table: userTable,
+	}
 	UserTypeCol = Column{
 		name:  projection.UserTypeCol,
 		table: userTable,
@@ -324,6 +328,10 @@ var (
 		name:  projection.NotifyUserIDCol,
 		table: notifyTable,
 	}
+	NotifyInstanceIDCol = Column{
+		name:  projection.NotifyInstanceIDCol,
+		table: notifyTable,
+	}
 	NotifyEmailCol = Column{
 		name:           projection.NotifyLastEmailCol,
 		table:          notifyTable,
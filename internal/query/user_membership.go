@@ -11,6 +11,7 @@ import (
 	"github.com/zitadel/zitadel/internal/api/authz"
 	"github.com/zitadel/zitadel/internal/api/call"
 	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore/handler/v2"
 	"github.com/zitadel/zitadel/internal/query/projection"
 	"github.com/zitadel/zitadel/internal/telemetry/tracing"
@@ -354,7 +355,9 @@ func prepareOrgMember(query *MembershipSearchQuery) (string, []interface{}) {
 		"NULL::TEXT AS "+membershipIAMID.name,
 		"NULL::TEXT AS "+membershipProjectID.name,
 		"NULL::TEXT AS "+membershipGrantID.name,
-	).From(orgMemberTable.identifier())
+	).From(orgMemberTable.identifier()).
+		// memberships of suspended orgs don't grant any permissions
+		Where(sq.NotEq{OrgMemberState.identifier(): domain.MemberStateInactive})
 
 	for _, q := range query.Queries {
 		if q.Col().table.name == membershipAlias.name || q.Col().table.name == orgMemberTable.name {
@@ -43,7 +43,8 @@ var (
 			", NULL::TEXT AS id" +
 			", NULL::TEXT AS project_id" +
 			", NULL::TEXT AS grant_id" +
-			" FROM projections.org_members4 AS members" +
+			" FROM projections.org_members7 AS members" +
+			" WHERE members.state <> $1" +
 			" UNION ALL " +
 			"SELECT members.user_id" +
 			", members.roles" +
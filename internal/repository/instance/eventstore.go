@@ -119,6 +119,8 @@ func init() {
 	eventstore.RegisterFilterEventMapper(AggregateType, InstanceAddedEventType, InstanceAddedEventMapper)
 	eventstore.RegisterFilterEventMapper(AggregateType, InstanceChangedEventType, InstanceChangedEventMapper)
 	eventstore.RegisterFilterEventMapper(AggregateType, InstanceRemovedEventType, InstanceRemovedEventMapper)
+	eventstore.RegisterFilterEventMapper(AggregateType, InstanceSealedEventType, eventstore.GenericEventMapper[InstanceSealedEvent])
+	eventstore.RegisterFilterEventMapper(AggregateType, InstanceUnsealedEventType, eventstore.GenericEventMapper[InstanceUnsealedEvent])
 	eventstore.RegisterFilterEventMapper(AggregateType, NotificationPolicyAddedEventType, NotificationPolicyAddedEventMapper)
 	eventstore.RegisterFilterEventMapper(AggregateType, NotificationPolicyChangedEventType, NotificationPolicyChangedEventMapper)
 }
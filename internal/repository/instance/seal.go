@@ -0,0 +1,55 @@
+package instance
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+const (
+	// the types are declared by the eventstore, which rejects the pushes to sealed instances
+	InstanceSealedEventType   = eventstore.InstanceSealedType
+	InstanceUnsealedEventType = eventstore.InstanceUnsealedType
+)
+
+// InstanceSealedEvent makes the instance read-only, e.g. to export its events for a migration
+type InstanceSealedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+}
+
+func (e *InstanceSealedEvent) SetBaseEvent(b *eventstore.BaseEvent) {
+	e.BaseEvent = *b
+}
+
+func (e *InstanceSealedEvent) Payload() any {
+	return nil
+}
+
+func (e *InstanceSealedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func NewInstanceSealedEvent(ctx context.Context, aggregate *eventstore.Aggregate) *InstanceSealedEvent {
+	return &InstanceSealedEvent{*eventstore.NewBaseEventForPush(ctx, aggregate, InstanceSealedEventType)}
+}
+
+// InstanceUnsealedEvent accepts commands for the sealed instance again
+type InstanceUnsealedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+}
+
+func (e *InstanceUnsealedEvent) SetBaseEvent(b *eventstore.BaseEvent) {
+	e.BaseEvent = *b
+}
+
+func (e *InstanceUnsealedEvent) Payload() any {
+	return nil
+}
+
+func (e *InstanceUnsealedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func NewInstanceUnsealedEvent(ctx context.Context, aggregate *eventstore.Aggregate) *InstanceUnsealedEvent {
+	return &InstanceUnsealedEvent{*eventstore.NewBaseEventForPush(ctx, aggregate, InstanceUnsealedEventType)}
+}
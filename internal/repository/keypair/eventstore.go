@@ -6,5 +6,7 @@ import (
 
 func init() {
 	eventstore.RegisterFilterEventMapper(AggregateType, AddedEventType, AddedEventMapper)
+	eventstore.RegisterFilterEventMapper(AggregateType, ExpiredEventType, ExpiredEventMapper)
+	eventstore.RegisterFilterEventMapper(AggregateType, RevokedEventType, RevokedEventMapper)
 	eventstore.RegisterFilterEventMapper(AggregateType, AddedCertificateEventType, AddedCertificateEventMapper)
 }
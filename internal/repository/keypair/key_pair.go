@@ -11,8 +11,10 @@ import (
 )
 
 const (
-	eventTypePrefix = eventstore.EventType("key_pair.")
-	AddedEventType  = eventTypePrefix + "added"
+	eventTypePrefix  = eventstore.EventType("key_pair.")
+	AddedEventType   = eventTypePrefix + "added"
+	ExpiredEventType = eventTypePrefix + "expired"
+	RevokedEventType = eventTypePrefix + "revoked"
 )
 
 type AddedEvent struct {
@@ -22,6 +24,9 @@ type AddedEvent struct {
 	Algorithm  string          `json:"algorithm"`
 	PrivateKey *Key            `json:"privateKey"`
 	PublicKey  *Key            `json:"publicKey"`
+	// NotBefore is set for keys generated ahead of a rotation,
+	// the private key must not be used before
+	NotBefore *time.Time `json:"notBefore,omitempty"`
 }
 
 type Key struct {
@@ -65,6 +70,23 @@ func NewAddedEvent(
 	}
 }
 
+// NewPendingAddedEvent creates a key pair which is only used after notBefore,
+// while the public key can already be published.
+func NewPendingAddedEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	usage domain.KeyUsage,
+	algorithm string,
+	privateCrypto,
+	publicCrypto *crypto.CryptoValue,
+	privateKeyExpiration,
+	publicKeyExpiration,
+	notBefore time.Time) *AddedEvent {
+	event := NewAddedEvent(ctx, aggregate, usage, algorithm, privateCrypto, publicCrypto, privateKeyExpiration, publicKeyExpiration)
+	event.NotBefore = &notBefore
+	return event
+}
+
 func AddedEventMapper(event eventstore.Event) (eventstore.Event, error) {
 	e := &AddedEvent{
 		BaseEvent: *eventstore.BaseEventFromRepo(event),
@@ -77,3 +99,71 @@ func AddedEventMapper(event eventstore.Event) (eventstore.Event, error) {
 
 	return e, nil
 }
+
+// ExpiredEvent ends the lifetime of the key pair,
+// its keys must neither be used nor published anymore.
+// Other than [RevokedEvent] it's part of the scheduled rotation of the keys.
+type ExpiredEvent struct {
+	eventstore.BaseEvent `json:"-"`
+}
+
+func (e *ExpiredEvent) Payload() interface{} {
+	return nil
+}
+
+func (e *ExpiredEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func NewExpiredEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+) *ExpiredEvent {
+	return &ExpiredEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			ExpiredEventType,
+		),
+	}
+}
+
+func ExpiredEventMapper(event eventstore.Event) (eventstore.Event, error) {
+	return &ExpiredEvent{
+		BaseEvent: *eventstore.BaseEventFromRepo(event),
+	}, nil
+}
+
+// RevokedEvent immediately invalidates the key pair, e.g. if its private key was compromised.
+// Other than the scheduled [ExpiredEvent] it's a security measure:
+// the keys must neither be used nor published anymore, even if signed tokens are still valid.
+type RevokedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+}
+
+func (e *RevokedEvent) Payload() interface{} {
+	return nil
+}
+
+func (e *RevokedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func NewRevokedEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+) *RevokedEvent {
+	return &RevokedEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			RevokedEventType,
+		),
+	}
+}
+
+func RevokedEventMapper(event eventstore.Event) (eventstore.Event, error) {
+	return &RevokedEvent{
+		BaseEvent: *eventstore.BaseEventFromRepo(event),
+	}, nil
+}
@@ -13,6 +13,8 @@ const (
 	ChangedEventType        = "member.changed"
 	RemovedEventType        = "member.removed"
 	CascadeRemovedEventType = "member.cascade.removed"
+	DeactivatedEventType    = "member.deactivated"
+	ReactivatedEventType    = "member.reactivated"
 )
 
 func NewAddMemberUniqueConstraint(aggregateID, userID string) *eventstore.UniqueConstraint {
@@ -185,3 +187,77 @@ func CascadeRemovedEventMapper(event eventstore.Event) (eventstore.Event, error)
 
 	return e, nil
 }
+
+type MemberDeactivatedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	UserID string `json:"userId"`
+}
+
+func (e *MemberDeactivatedEvent) Payload() interface{} {
+	return e
+}
+
+func (e *MemberDeactivatedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func NewDeactivatedEvent(
+	base *eventstore.BaseEvent,
+	userID string,
+) *MemberDeactivatedEvent {
+	return &MemberDeactivatedEvent{
+		BaseEvent: *base,
+		UserID:    userID,
+	}
+}
+
+func DeactivatedEventMapper(event eventstore.Event) (eventstore.Event, error) {
+	e := &MemberDeactivatedEvent{
+		BaseEvent: *eventstore.BaseEventFromRepo(event),
+	}
+
+	err := event.Unmarshal(e)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "MEMBER-Wq3bz", "unable to unmarshal member")
+	}
+
+	return e, nil
+}
+
+type MemberReactivatedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	UserID string `json:"userId"`
+}
+
+func (e *MemberReactivatedEvent) Payload() interface{} {
+	return e
+}
+
+func (e *MemberReactivatedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func NewReactivatedEvent(
+	base *eventstore.BaseEvent,
+	userID string,
+) *MemberReactivatedEvent {
+	return &MemberReactivatedEvent{
+		BaseEvent: *base,
+		UserID:    userID,
+	}
+}
+
+func ReactivatedEventMapper(event eventstore.Event) (eventstore.Event, error) {
+	e := &MemberReactivatedEvent{
+		BaseEvent: *eventstore.BaseEventFromRepo(event),
+	}
+
+	err := event.Unmarshal(e)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "MEMBER-Hn7xe", "unable to unmarshal member")
+	}
+
+	return e, nil
+}
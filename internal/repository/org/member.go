@@ -2,9 +2,16 @@ package org
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/repository/member"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+const (
+	UniqueMemberEmail = "org_member_email"
 )
 
 var (
@@ -12,10 +19,43 @@ var (
 	MemberChangedEventType        = orgEventTypePrefix + member.ChangedEventType
 	MemberRemovedEventType        = orgEventTypePrefix + member.RemovedEventType
 	MemberCascadeRemovedEventType = orgEventTypePrefix + member.CascadeRemovedEventType
+	MemberDeactivatedEventType    = orgEventTypePrefix + member.DeactivatedEventType
+	MemberReactivatedEventType    = orgEventTypePrefix + member.ReactivatedEventType
+	MemberEmailChangedEventType   = orgEventTypePrefix + "member.email.changed"
 )
 
+func NewAddMemberEmailUniqueConstraint(orgID, email string) *eventstore.UniqueConstraint {
+	return eventstore.NewAddEventUniqueConstraint(
+		UniqueMemberEmail,
+		fmt.Sprintf("%s:%s", orgID, strings.ToLower(email)),
+		"Errors.Org.MemberEmailAlreadyExists")
+}
+
+func NewRemoveMemberEmailUniqueConstraint(orgID, email string) *eventstore.UniqueConstraint {
+	return eventstore.NewRemoveUniqueConstraint(
+		UniqueMemberEmail,
+		fmt.Sprintf("%s:%s", orgID, strings.ToLower(email)),
+	)
+}
+
 type MemberAddedEvent struct {
 	member.MemberAddedEvent
+
+	// UniqueEmail is the email of the user reserved in the org,
+	// it's set if the domain policy of the org requires unique member emails
+	UniqueEmail string `json:"uniqueEmail,omitempty"`
+}
+
+func (e *MemberAddedEvent) Payload() interface{} {
+	return e
+}
+
+func (e *MemberAddedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	constraints := e.MemberAddedEvent.UniqueConstraints()
+	if e.UniqueEmail != "" {
+		constraints = append(constraints, NewAddMemberEmailUniqueConstraint(e.Aggregate().ID, e.UniqueEmail))
+	}
+	return constraints
 }
 
 func NewMemberAddedEvent(
@@ -38,12 +78,15 @@ func NewMemberAddedEvent(
 }
 
 func MemberAddedEventMapper(event eventstore.Event) (eventstore.Event, error) {
-	e, err := member.MemberAddedEventMapper(event)
-	if err != nil {
-		return nil, err
+	e := &MemberAddedEvent{
+		MemberAddedEvent: member.MemberAddedEvent{
+			BaseEvent: *eventstore.BaseEventFromRepo(event),
+		},
 	}
-
-	return &MemberAddedEvent{MemberAddedEvent: *e.(*member.MemberAddedEvent)}, nil
+	if err := event.Unmarshal(e); err != nil {
+		return nil, zerrors.ThrowInternal(err, "ORG-Mk3eq", "unable to unmarshal member added")
+	}
+	return e, nil
 }
 
 type MemberChangedEvent struct {
@@ -81,6 +124,17 @@ func MemberChangedEventMapper(event eventstore.Event) (eventstore.Event, error)
 
 type MemberRemovedEvent struct {
 	member.MemberRemovedEvent
+
+	// UniqueEmail is the email reserved by [MemberAddedEvent.UniqueEmail], the reservation is released
+	UniqueEmail string `json:"-"`
+}
+
+func (e *MemberRemovedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	constraints := e.MemberRemovedEvent.UniqueConstraints()
+	if e.UniqueEmail != "" {
+		constraints = append(constraints, NewRemoveMemberEmailUniqueConstraint(e.Aggregate().ID, e.UniqueEmail))
+	}
+	return constraints
 }
 
 func NewMemberRemovedEvent(
@@ -111,6 +165,17 @@ func MemberRemovedEventMapper(event eventstore.Event) (eventstore.Event, error)
 
 type MemberCascadeRemovedEvent struct {
 	member.MemberCascadeRemovedEvent
+
+	// UniqueEmail is the email reserved by [MemberAddedEvent.UniqueEmail], the reservation is released
+	UniqueEmail string `json:"-"`
+}
+
+func (e *MemberCascadeRemovedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	constraints := e.MemberCascadeRemovedEvent.UniqueConstraints()
+	if e.UniqueEmail != "" {
+		constraints = append(constraints, NewRemoveMemberEmailUniqueConstraint(e.Aggregate().ID, e.UniqueEmail))
+	}
+	return constraints
 }
 
 func NewMemberCascadeRemovedEvent(
@@ -138,3 +203,119 @@ func MemberCascadeRemovedEventMapper(event eventstore.Event) (eventstore.Event,
 
 	return &MemberCascadeRemovedEvent{MemberCascadeRemovedEvent: *e.(*member.MemberCascadeRemovedEvent)}, nil
 }
+
+type MemberDeactivatedEvent struct {
+	member.MemberDeactivatedEvent
+}
+
+func NewMemberDeactivatedEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	userID string,
+) *MemberDeactivatedEvent {
+	return &MemberDeactivatedEvent{
+		MemberDeactivatedEvent: *member.NewDeactivatedEvent(
+			eventstore.NewBaseEventForPush(
+				ctx,
+				aggregate,
+				MemberDeactivatedEventType,
+			),
+			userID,
+		),
+	}
+}
+
+func MemberDeactivatedEventMapper(event eventstore.Event) (eventstore.Event, error) {
+	e, err := member.DeactivatedEventMapper(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MemberDeactivatedEvent{MemberDeactivatedEvent: *e.(*member.MemberDeactivatedEvent)}, nil
+}
+
+type MemberReactivatedEvent struct {
+	member.MemberReactivatedEvent
+}
+
+func NewMemberReactivatedEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	userID string,
+) *MemberReactivatedEvent {
+	return &MemberReactivatedEvent{
+		MemberReactivatedEvent: *member.NewReactivatedEvent(
+			eventstore.NewBaseEventForPush(
+				ctx,
+				aggregate,
+				MemberReactivatedEventType,
+			),
+			userID,
+		),
+	}
+}
+
+func MemberReactivatedEventMapper(event eventstore.Event) (eventstore.Event, error) {
+	e, err := member.ReactivatedEventMapper(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MemberReactivatedEvent{MemberReactivatedEvent: *e.(*member.MemberReactivatedEvent)}, nil
+}
+
+// MemberEmailChangedEvent moves the email reserved by [MemberAddedEvent.UniqueEmail] after the user changed the email,
+// the reservation is released if UniqueEmail is empty
+type MemberEmailChangedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	UserID      string `json:"userId"`
+	UniqueEmail string `json:"uniqueEmail,omitempty"`
+
+	// PreviousEmail is the email reserved before, the reservation is released
+	PreviousEmail string `json:"-"`
+}
+
+func (e *MemberEmailChangedEvent) Payload() interface{} {
+	return e
+}
+
+func (e *MemberEmailChangedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	constraints := make([]*eventstore.UniqueConstraint, 0, 2)
+	if e.PreviousEmail != "" {
+		constraints = append(constraints, NewRemoveMemberEmailUniqueConstraint(e.Aggregate().ID, e.PreviousEmail))
+	}
+	if e.UniqueEmail != "" {
+		constraints = append(constraints, NewAddMemberEmailUniqueConstraint(e.Aggregate().ID, e.UniqueEmail))
+	}
+	return constraints
+}
+
+func NewMemberEmailChangedEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	userID,
+	uniqueEmail,
+	previousEmail string,
+) *MemberEmailChangedEvent {
+	return &MemberEmailChangedEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			MemberEmailChangedEventType,
+		),
+		UserID:        userID,
+		UniqueEmail:   uniqueEmail,
+		PreviousEmail: previousEmail,
+	}
+}
+
+func MemberEmailChangedEventMapper(event eventstore.Event) (eventstore.Event, error) {
+	e := &MemberEmailChangedEvent{
+		BaseEvent: *eventstore.BaseEventFromRepo(event),
+	}
+	if err := event.Unmarshal(e); err != nil {
+		return nil, zerrors.ThrowInternal(err, "ORG-Me4ch", "unable to unmarshal member email changed")
+	}
+	return e, nil
+}
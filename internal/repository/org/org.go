@@ -282,6 +282,7 @@ type OrgRemovedEvent struct {
 	domains              []string
 	externalIDPs         []*domain.UserIDPLink
 	samlEntityIDs        []string
+	memberEmails         []string
 }
 
 func (e *OrgRemovedEvent) Payload() interface{} {
@@ -304,6 +305,9 @@ func (e *OrgRemovedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
 	for _, entityID := range e.samlEntityIDs {
 		constraints = append(constraints, project.NewRemoveSAMLConfigEntityIDUniqueConstraint(entityID))
 	}
+	for _, email := range e.memberEmails {
+		constraints = append(constraints, NewRemoveMemberEmailUniqueConstraint(e.Aggregate().ID, email))
+	}
 	return constraints
 }
 
@@ -330,7 +334,7 @@ func (e *OrgRemovedEvent) Fields() []*eventstore.FieldOperation {
 	}
 }
 
-func NewOrgRemovedEvent(ctx context.Context, aggregate *eventstore.Aggregate, name string, usernames []string, loginMustBeDomain bool, domains []string, externalIDPs []*domain.UserIDPLink, samlEntityIDs []string) *OrgRemovedEvent {
+func NewOrgRemovedEvent(ctx context.Context, aggregate *eventstore.Aggregate, name string, usernames []string, loginMustBeDomain bool, domains []string, externalIDPs []*domain.UserIDPLink, samlEntityIDs, memberEmails []string) *OrgRemovedEvent {
 	return &OrgRemovedEvent{
 		BaseEvent: *eventstore.NewBaseEventForPush(
 			ctx,
@@ -342,6 +346,7 @@ func NewOrgRemovedEvent(ctx context.Context, aggregate *eventstore.Aggregate, na
 		domains:           domains,
 		externalIDPs:      externalIDPs,
 		samlEntityIDs:     samlEntityIDs,
+		memberEmails:      memberEmails,
 		loginMustBeDomain: loginMustBeDomain,
 	}
 }
@@ -0,0 +1,47 @@
+package org
+
+import (
+	"context"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+const (
+	MFAGracePeriodSetEventType = orgEventTypePrefix + "policy.mfa.grace_period.set"
+)
+
+// MFAGracePeriodSetEvent sets the duration after the creation of a user
+// in which the user has to enroll a multi factor, zero disables the grace period.
+type MFAGracePeriodSetEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	GracePeriod time.Duration `json:"gracePeriod"`
+}
+
+func (e *MFAGracePeriodSetEvent) Payload() interface{} {
+	return e
+}
+
+func (e *MFAGracePeriodSetEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func (e *MFAGracePeriodSetEvent) SetBaseEvent(base *eventstore.BaseEvent) {
+	e.BaseEvent = *base
+}
+
+func NewMFAGracePeriodSetEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	gracePeriod time.Duration,
+) *MFAGracePeriodSetEvent {
+	return &MFAGracePeriodSetEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			MFAGracePeriodSetEventType,
+		),
+		GracePeriod: gracePeriod,
+	}
+}
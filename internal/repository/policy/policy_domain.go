@@ -17,6 +17,7 @@ type DomainPolicyAddedEvent struct {
 	UserLoginMustBeDomain                  bool `json:"userLoginMustBeDomain,omitempty"`
 	ValidateOrgDomains                     bool `json:"validateOrgDomains,omitempty"`
 	SMTPSenderAddressMatchesInstanceDomain bool `json:"smtpSenderAddressMatchesInstanceDomain,omitempty"`
+	MemberEmailsUnique                     bool `json:"memberEmailsUnique,omitempty"`
 }
 
 func (e *DomainPolicyAddedEvent) Payload() interface{} {
@@ -61,6 +62,7 @@ type DomainPolicyChangedEvent struct {
 	UserLoginMustBeDomain                  *bool `json:"userLoginMustBeDomain,omitempty"`
 	ValidateOrgDomains                     *bool `json:"validateOrgDomains,omitempty"`
 	SMTPSenderAddressMatchesInstanceDomain *bool `json:"smtpSenderAddressMatchesInstanceDomain,omitempty"`
+	MemberEmailsUnique                     *bool `json:"memberEmailsUnique,omitempty"`
 }
 
 func (e *DomainPolicyChangedEvent) Payload() interface{} {
@@ -107,6 +109,12 @@ func ChangeSMTPSenderAddressMatchesInstanceDomain(smtpSenderAddressMatchesInstan
 	}
 }
 
+func ChangeMemberEmailsUnique(memberEmailsUnique bool) func(*DomainPolicyChangedEvent) {
+	return func(e *DomainPolicyChangedEvent) {
+		e.MemberEmailsUnique = &memberEmailsUnique
+	}
+}
+
 func DomainPolicyChangedEventMapper(event eventstore.Event) (eventstore.Event, error) {
 	e := &DomainPolicyChangedEvent{
 		BaseEvent: *eventstore.BaseEventFromRepo(event),
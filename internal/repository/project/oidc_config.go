@@ -17,6 +17,7 @@ const (
 	OIDCClientSecretCheckSucceededType = applicationEventTypePrefix + "oidc.secret.check.succeeded"
 	OIDCClientSecretCheckFailedType    = applicationEventTypePrefix + "oidc.secret.check.failed"
 	OIDCConfigSecretHashUpdatedType    = applicationEventTypePrefix + "config.oidc.secret.updated"
+	OIDCConfigClaimsMappingSetType     = applicationEventTypePrefix + "config.oidc.claims.mapping.set"
 )
 
 type OIDCConfigAddedEvent struct {
@@ -528,3 +529,43 @@ func (e *OIDCConfigSecretHashUpdatedEvent) Payload() interface{} {
 func (e *OIDCConfigSecretHashUpdatedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
 	return nil
 }
+
+// OIDCConfigClaimsMappingSetEvent replaces the custom claims mapping of the OIDC application
+type OIDCConfigClaimsMappingSetEvent struct {
+	*eventstore.BaseEvent `json:"-"`
+
+	AppID        string                     `json:"appId"`
+	UserSchemaID string                     `json:"userSchemaId,omitempty"`
+	Mappings     []*domain.OIDCClaimMapping `json:"mappings,omitempty"`
+}
+
+func NewOIDCConfigClaimsMappingSetEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	appID string,
+	userSchemaID string,
+	mappings []*domain.OIDCClaimMapping,
+) *OIDCConfigClaimsMappingSetEvent {
+	return &OIDCConfigClaimsMappingSetEvent{
+		BaseEvent: eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			OIDCConfigClaimsMappingSetType,
+		),
+		AppID:        appID,
+		UserSchemaID: userSchemaID,
+		Mappings:     mappings,
+	}
+}
+
+func (e *OIDCConfigClaimsMappingSetEvent) SetBaseEvent(b *eventstore.BaseEvent) {
+	e.BaseEvent = b
+}
+
+func (e *OIDCConfigClaimsMappingSetEvent) Payload() interface{} {
+	return e
+}
+
+func (e *OIDCConfigClaimsMappingSetEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
@@ -0,0 +1,19 @@
+package schedule
+
+import (
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+const (
+	// UniqueCompletion guarantees that a scheduled action is either executed or cancelled exactly once
+	UniqueCompletion    = "schedule_completion"
+	DuplicateCompletion = "Errors.Schedule.AlreadyCompleted"
+)
+
+func NewAddCompletionUniqueConstraint(id string) *eventstore.UniqueConstraint {
+	return eventstore.NewAddEventUniqueConstraint(
+		UniqueCompletion,
+		id,
+		DuplicateCompletion,
+	)
+}
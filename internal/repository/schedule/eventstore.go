@@ -0,0 +1,9 @@
+package schedule
+
+import "github.com/zitadel/zitadel/internal/eventstore"
+
+func init() {
+	eventstore.RegisterFilterEventMapper(AggregateType, ScheduledEventType, eventstore.GenericEventMapper[ScheduledEvent])
+	eventstore.RegisterFilterEventMapper(AggregateType, ExecutedEventType, eventstore.GenericEventMapper[ExecutedEvent])
+	eventstore.RegisterFilterEventMapper(AggregateType, CancelledEventType, eventstore.GenericEventMapper[CancelledEvent])
+}
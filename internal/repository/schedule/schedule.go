@@ -0,0 +1,95 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+const (
+	eventTypePrefix    eventstore.EventType = "schedule."
+	ScheduledEventType                      = eventTypePrefix + "scheduled"
+	ExecutedEventType                       = eventTypePrefix + "executed"
+	CancelledEventType                      = eventTypePrefix + "cancelled"
+)
+
+type ScheduledEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	// Action is the name of the action the worker executes, e.g. org.deactivate
+	Action string `json:"action"`
+	// TargetID is the id of the aggregate the action is executed on
+	TargetID string    `json:"targetId,omitempty"`
+	DueDate  time.Time `json:"dueDate"`
+	// Parameters are passed to the worker as is
+	Parameters json.RawMessage `json:"parameters,omitempty"`
+}
+
+func (e *ScheduledEvent) SetBaseEvent(b *eventstore.BaseEvent) {
+	e.BaseEvent = *b
+}
+
+func (e *ScheduledEvent) Payload() any {
+	return e
+}
+
+func (e *ScheduledEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func NewScheduledEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	action,
+	targetID string,
+	dueDate time.Time,
+	parameters json.RawMessage,
+) *ScheduledEvent {
+	return &ScheduledEvent{
+		*eventstore.NewBaseEventForPush(
+			ctx, aggregate, ScheduledEventType,
+		),
+		action, targetID, dueDate, parameters}
+}
+
+type ExecutedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+}
+
+func (e *ExecutedEvent) SetBaseEvent(b *eventstore.BaseEvent) {
+	e.BaseEvent = *b
+}
+
+func (e *ExecutedEvent) Payload() any {
+	return e
+}
+
+func (e *ExecutedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return []*eventstore.UniqueConstraint{NewAddCompletionUniqueConstraint(e.Aggregate().ID)}
+}
+
+func NewExecutedEvent(ctx context.Context, aggregate *eventstore.Aggregate) *ExecutedEvent {
+	return &ExecutedEvent{*eventstore.NewBaseEventForPush(ctx, aggregate, ExecutedEventType)}
+}
+
+type CancelledEvent struct {
+	eventstore.BaseEvent `json:"-"`
+}
+
+func (e *CancelledEvent) SetBaseEvent(b *eventstore.BaseEvent) {
+	e.BaseEvent = *b
+}
+
+func (e *CancelledEvent) Payload() any {
+	return e
+}
+
+func (e *CancelledEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return []*eventstore.UniqueConstraint{NewAddCompletionUniqueConstraint(e.Aggregate().ID)}
+}
+
+func NewCancelledEvent(ctx context.Context, aggregate *eventstore.Aggregate) *CancelledEvent {
+	return &CancelledEvent{*eventstore.NewBaseEventForPush(ctx, aggregate, CancelledEventType)}
+}
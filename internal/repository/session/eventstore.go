@@ -19,5 +19,6 @@ func init() {
 	eventstore.RegisterFilterEventMapper(AggregateType, TokenSetType, TokenSetEventMapper)
 	eventstore.RegisterFilterEventMapper(AggregateType, MetadataSetType, MetadataSetEventMapper)
 	eventstore.RegisterFilterEventMapper(AggregateType, LifetimeSetType, eventstore.GenericEventMapper[LifetimeSetEvent])
+	eventstore.RegisterFilterEventMapper(AggregateType, ImpersonationStartedType, eventstore.GenericEventMapper[ImpersonationStartedEvent])
 	eventstore.RegisterFilterEventMapper(AggregateType, TerminateType, TerminateEventMapper)
 }
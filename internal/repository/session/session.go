@@ -14,24 +14,25 @@ import (
 )
 
 const (
-	sessionEventPrefix     = "session."
-	AddedType              = sessionEventPrefix + "added"
-	UserCheckedType        = sessionEventPrefix + "user.checked"
-	PasswordCheckedType    = sessionEventPrefix + "password.checked"
-	IntentCheckedType      = sessionEventPrefix + "intent.checked"
-	WebAuthNChallengedType = sessionEventPrefix + "webAuthN.challenged"
-	WebAuthNCheckedType    = sessionEventPrefix + "webAuthN.checked"
-	TOTPCheckedType        = sessionEventPrefix + "totp.checked"
-	OTPSMSChallengedType   = sessionEventPrefix + "otp.sms.challenged"
-	OTPSMSSentType         = sessionEventPrefix + "otp.sms.sent"
-	OTPSMSCheckedType      = sessionEventPrefix + "otp.sms.checked"
-	OTPEmailChallengedType = sessionEventPrefix + "otp.email.challenged"
-	OTPEmailSentType       = sessionEventPrefix + "otp.email.sent"
-	OTPEmailCheckedType    = sessionEventPrefix + "otp.email.checked"
-	TokenSetType           = sessionEventPrefix + "token.set"
-	MetadataSetType        = sessionEventPrefix + "metadata.set"
-	LifetimeSetType        = sessionEventPrefix + "lifetime.set"
-	TerminateType          = sessionEventPrefix + "terminated"
+	sessionEventPrefix       = "session."
+	AddedType                = sessionEventPrefix + "added"
+	UserCheckedType          = sessionEventPrefix + "user.checked"
+	PasswordCheckedType      = sessionEventPrefix + "password.checked"
+	IntentCheckedType        = sessionEventPrefix + "intent.checked"
+	WebAuthNChallengedType   = sessionEventPrefix + "webAuthN.challenged"
+	WebAuthNCheckedType      = sessionEventPrefix + "webAuthN.checked"
+	TOTPCheckedType          = sessionEventPrefix + "totp.checked"
+	OTPSMSChallengedType     = sessionEventPrefix + "otp.sms.challenged"
+	OTPSMSSentType           = sessionEventPrefix + "otp.sms.sent"
+	OTPSMSCheckedType        = sessionEventPrefix + "otp.sms.checked"
+	OTPEmailChallengedType   = sessionEventPrefix + "otp.email.challenged"
+	OTPEmailSentType         = sessionEventPrefix + "otp.email.sent"
+	OTPEmailCheckedType      = sessionEventPrefix + "otp.email.checked"
+	TokenSetType             = sessionEventPrefix + "token.set"
+	MetadataSetType          = sessionEventPrefix + "metadata.set"
+	LifetimeSetType          = sessionEventPrefix + "lifetime.set"
+	ImpersonationStartedType = sessionEventPrefix + "impersonation.started"
+	TerminateType            = sessionEventPrefix + "terminated"
 )
 
 type AddedEvent struct {
@@ -649,6 +650,52 @@ func NewLifetimeSetEvent(
 	}
 }
 
+// ImpersonationStartedEvent records that an operator started the session to act as the user.
+type ImpersonationStartedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	UserID                string        `json:"userID"`
+	UserResourceOwner     string        `json:"userResourceOwner"`
+	OperatorID            string        `json:"operatorID"`
+	OperatorResourceOwner string        `json:"operatorResourceOwner"`
+	Lifetime              time.Duration `json:"lifetime"`
+}
+
+func (e *ImpersonationStartedEvent) Payload() interface{} {
+	return e
+}
+
+func (e *ImpersonationStartedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func (e *ImpersonationStartedEvent) SetBaseEvent(base *eventstore.BaseEvent) {
+	e.BaseEvent = *base
+}
+
+func NewImpersonationStartedEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	userID,
+	userResourceOwner,
+	operatorID,
+	operatorResourceOwner string,
+	lifetime time.Duration,
+) *ImpersonationStartedEvent {
+	return &ImpersonationStartedEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			ImpersonationStartedType,
+		),
+		UserID:                userID,
+		UserResourceOwner:     userResourceOwner,
+		OperatorID:            operatorID,
+		OperatorResourceOwner: operatorResourceOwner,
+		Lifetime:              lifetime,
+	}
+}
+
 type TerminateEvent struct {
 	eventstore.BaseEvent `json:"-"`
 }
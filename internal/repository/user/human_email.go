@@ -19,6 +19,8 @@ const (
 	HumanEmailCodeAddedType          = emailEventPrefix + "code.added"
 	HumanEmailCodeSentType           = emailEventPrefix + "code.sent"
 	HumanEmailConfirmURLAddedType    = emailEventPrefix + "confirm_url.added"
+	HumanEmailSwapRequestedType      = emailEventPrefix + "swap.requested"
+	HumanEmailSwapCancelledType      = emailEventPrefix + "swap.cancelled"
 )
 
 type HumanEmailChangedEvent struct {
@@ -215,3 +217,71 @@ func HumanEmailCodeSentEventMapper(event eventstore.Event) (eventstore.Event, er
 		BaseEvent: *eventstore.BaseEventFromRepo(event),
 	}, nil
 }
+
+// HumanEmailSwapRequestedEvent sets a pending email address with a verification code.
+// The primary email address stays unchanged until the code is verified.
+type HumanEmailSwapRequestedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	EmailAddress domain.EmailAddress `json:"email,omitempty"`
+	Code         *crypto.CryptoValue `json:"code,omitempty"`
+	Expiry       time.Duration       `json:"expiry,omitempty"`
+}
+
+func (e *HumanEmailSwapRequestedEvent) Payload() interface{} {
+	return e
+}
+
+func (e *HumanEmailSwapRequestedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func (e *HumanEmailSwapRequestedEvent) SetBaseEvent(base *eventstore.BaseEvent) {
+	e.BaseEvent = *base
+}
+
+func NewHumanEmailSwapRequestedEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	emailAddress domain.EmailAddress,
+	code *crypto.CryptoValue,
+	expiry time.Duration,
+) *HumanEmailSwapRequestedEvent {
+	return &HumanEmailSwapRequestedEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			HumanEmailSwapRequestedType,
+		),
+		EmailAddress: emailAddress,
+		Code:         code,
+		Expiry:       expiry,
+	}
+}
+
+// HumanEmailSwapCancelledEvent removes the pending email address, the primary email address stays unchanged.
+type HumanEmailSwapCancelledEvent struct {
+	eventstore.BaseEvent `json:"-"`
+}
+
+func (e *HumanEmailSwapCancelledEvent) Payload() interface{} {
+	return nil
+}
+
+func (e *HumanEmailSwapCancelledEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func (e *HumanEmailSwapCancelledEvent) SetBaseEvent(base *eventstore.BaseEvent) {
+	e.BaseEvent = *base
+}
+
+func NewHumanEmailSwapCancelledEvent(ctx context.Context, aggregate *eventstore.Aggregate) *HumanEmailSwapCancelledEvent {
+	return &HumanEmailSwapCancelledEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			HumanEmailSwapCancelledType,
+		),
+	}
+}
@@ -14,6 +14,7 @@ const (
 	machineKeyEventPrefix      = machineEventPrefix + "key."
 	MachineKeyAddedEventType   = machineKeyEventPrefix + "added"
 	MachineKeyRemovedEventType = machineKeyEventPrefix + "removed"
+	MachineKeyUsedEventType    = machineKeyEventPrefix + "used"
 )
 
 type MachineKeyAddedEvent struct {
@@ -111,3 +112,45 @@ func MachineKeyRemovedEventMapper(event eventstore.Event) (eventstore.Event, err
 
 	return machineRemoved, nil
 }
+
+// MachineKeyUsedEvent is pushed after a successful authentication using the key.
+type MachineKeyUsedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	KeyID string `json:"keyId,omitempty"`
+}
+
+func (e *MachineKeyUsedEvent) Payload() interface{} {
+	return e
+}
+
+func (e *MachineKeyUsedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func NewMachineKeyUsedEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	keyID string,
+) *MachineKeyUsedEvent {
+	return &MachineKeyUsedEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			MachineKeyUsedEventType,
+		),
+		KeyID: keyID,
+	}
+}
+
+func MachineKeyUsedEventMapper(event eventstore.Event) (eventstore.Event, error) {
+	machineKeyUsed := &MachineKeyUsedEvent{
+		BaseEvent: *eventstore.BaseEventFromRepo(event),
+	}
+	err := event.Unmarshal(machineKeyUsed)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "USER-Tq6vd", "unable to unmarshal machine key used")
+	}
+
+	return machineKeyUsed, nil
+}
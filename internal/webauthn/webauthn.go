@@ -5,6 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net/url"
+	"strings"
 
 	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/go-webauthn/webauthn/webauthn"
@@ -21,6 +23,55 @@ type Config struct {
 	ExternalSecure bool
 }
 
+// Validate checks the relying party configuration used for the external domain.
+// Without the check, an invalid configuration only fails once a user tries to register a token.
+func (w *Config) Validate(externalDomain string, externalPort uint16) error {
+	if strings.TrimSpace(w.DisplayName) == "" {
+		return zerrors.ThrowInvalidArgument(nil, "WEBAU-Rd3kq", "webauthn: no display name specified")
+	}
+	origin := http.BuildHTTP(externalDomain, externalPort, w.ExternalSecure)
+	if !http.IsOrigin(origin) {
+		return zerrors.ThrowInvalidArgumentf(nil, "WEBAU-Hx7mc", "webauthn: origin %q is not a valid url", origin)
+	}
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return zerrors.ThrowInvalidArgumentf(err, "WEBAU-Ze2wb", "webauthn: origin %q is not a valid url", origin)
+	}
+	// the RP ID is taken from the requested domain, which must not contain a scheme or port
+	if !matchesRPID(parsed.Hostname(), externalDomain) {
+		return zerrors.ThrowInvalidArgumentf(nil, "WEBAU-Vb8rn", "webauthn: external domain %q doesn't match the RP ID of origin %q", externalDomain, origin)
+	}
+	if !isHostname(externalDomain) {
+		return zerrors.ThrowInvalidArgumentf(nil, "WEBAU-Qm4tf", "webauthn: external domain %q is not a valid RP ID", externalDomain)
+	}
+	if _, err = webauthn.New(w.config(externalDomain, origin)); err != nil {
+		return zerrors.ThrowInvalidArgument(err, "WEBAU-Jc9sp", "webauthn: invalid configuration")
+	}
+	return nil
+}
+
+// matchesRPID checks if the host is the RP ID or a subdomain of it
+func matchesRPID(host, rpID string) bool {
+	return host == rpID || strings.HasSuffix(host, "."+rpID)
+}
+
+func isHostname(host string) bool {
+	if host == "" || len(host) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 type webUser struct {
 	*domain.Human
 	accountName string
@@ -69,3 +69,75 @@ func TestConfig_serverFromContext(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_Validate(t *testing.T) {
+	type fields struct {
+		displayName    string
+		externalSecure bool
+	}
+	type args struct {
+		externalDomain string
+		externalPort   uint16
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name:    "display name missing",
+			fields:  fields{displayName: " ", externalSecure: true},
+			args:    args{externalDomain: "example.com", externalPort: 443},
+			wantErr: true,
+		},
+		{
+			name:    "external domain with scheme",
+			fields:  fields{displayName: "ZITADEL", externalSecure: true},
+			args:    args{externalDomain: "https://example.com", externalPort: 443},
+			wantErr: true,
+		},
+		{
+			name:    "external domain with port, not matching rp id",
+			fields:  fields{displayName: "ZITADEL", externalSecure: true},
+			args:    args{externalDomain: "example.com:8080", externalPort: 443},
+			wantErr: true,
+		},
+		{
+			name:    "external domain with path",
+			fields:  fields{displayName: "ZITADEL", externalSecure: true},
+			args:    args{externalDomain: "example.com/path", externalPort: 443},
+			wantErr: true,
+		},
+		{
+			name:    "external domain invalid hostname",
+			fields:  fields{displayName: "ZITADEL", externalSecure: true},
+			args:    args{externalDomain: "-example.com", externalPort: 443},
+			wantErr: true,
+		},
+		{
+			name:   "valid",
+			fields: fields{displayName: "ZITADEL", externalSecure: true},
+			args:   args{externalDomain: "example.com", externalPort: 443},
+		},
+		{
+			name:   "valid localhost with port",
+			fields: fields{displayName: "ZITADEL", externalSecure: false},
+			args:   args{externalDomain: "localhost", externalPort: 8080},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Config{
+				DisplayName:    tt.fields.displayName,
+				ExternalSecure: tt.fields.externalSecure,
+			}
+			err := w.Validate(tt.args.externalDomain, tt.args.externalPort)
+			if tt.wantErr {
+				assert.True(t, zerrors.IsErrorInvalidArgument(err), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}